@@ -2,6 +2,7 @@ package apiv1
 
 import (
 	"context"
+	"net/http"
 	"net/url"
 
 	runtimeclient "github.com/go-openapi/runtime/client"
@@ -13,6 +14,7 @@ import (
 	"github.com/mittwald/goharbor-client/v5/apiv1/project"
 	"github.com/mittwald/goharbor-client/v5/apiv1/registry"
 	"github.com/mittwald/goharbor-client/v5/apiv1/replication"
+	"github.com/mittwald/goharbor-client/v5/apiv1/robot"
 	"github.com/mittwald/goharbor-client/v5/apiv1/system"
 	"github.com/mittwald/goharbor-client/v5/apiv1/user"
 
@@ -25,6 +27,7 @@ type Client interface {
 	project.Client
 	registry.Client
 	replication.Client
+	robot.Client
 	system.Client
 }
 
@@ -34,6 +37,7 @@ type RESTClient struct {
 	project     *project.RESTClient
 	registry    *registry.RESTClient
 	replication *replication.RESTClient
+	robot       *robot.RESTClient
 	system      *system.RESTClient
 }
 
@@ -44,6 +48,7 @@ func NewRESTClient(cl *client.Harbor, authInfo runtime.ClientAuthInfoWriter) *RE
 		project:     project.NewClient(cl, authInfo),
 		registry:    registry.NewClient(cl, authInfo),
 		replication: replication.NewClient(cl, authInfo),
+		robot:       robot.NewClient(cl, authInfo),
 		system:      system.NewClient(cl, authInfo),
 	}
 }
@@ -62,6 +67,21 @@ func NewRESTClientForHost(u, username, password string) (*RESTClient, error) {
 	return NewRESTClient(swaggerClient, authInfo), nil
 }
 
+// NewRESTClientForHostWithClient constructs a new REST client containing a swagger
+// API client using the defined host string + basePath as well as basic auth info while using provided http client.
+func NewRESTClientForHostWithClient(u, username, password string, cl *http.Client) (*RESTClient, error) {
+	harborURL, err := url.Parse(u)
+	if err != nil {
+		return nil, err
+	}
+
+	rt := runtimeclient.NewWithClient(harborURL.Host, harborURL.Path, []string{harborURL.Scheme}, cl)
+	swaggerClient := client.New(rt, strfmt.Default)
+	authInfo := runtimeclient.BasicAuth(username, password)
+
+	return NewRESTClient(swaggerClient, authInfo), nil
+}
+
 // User Client
 
 // NewUser wraps the NewUser method of the user sub-package.
@@ -74,6 +94,11 @@ func (c *RESTClient) GetUser(ctx context.Context, username string) (*model.User,
 	return c.user.GetUser(ctx, username)
 }
 
+// ListUsers wraps the ListUsers method of the user sub-package.
+func (c *RESTClient) ListUsers(ctx context.Context) ([]*model.User, error) {
+	return c.user.ListUsers(ctx)
+}
+
 // DeleteUser wraps the DeleteUser method of the user sub-package.
 func (c *RESTClient) DeleteUser(ctx context.Context, u *model.User) error {
 	return c.user.DeleteUser(ctx, u)
@@ -180,6 +205,11 @@ func (c *RESTClient) GetRegistry(ctx context.Context, name string) (*model.Regis
 	return c.registry.GetRegistry(ctx, name)
 }
 
+// ListRegistries wraps the ListRegistries method of the registry sub-package.
+func (c *RESTClient) ListRegistries(ctx context.Context) ([]*model.Registry, error) {
+	return c.registry.ListRegistries(ctx)
+}
+
 // DeleteRegistry wraps the DeleteRegistry method of the registry sub-package.
 func (c *RESTClient) DeleteRegistry(ctx context.Context, r *model.Registry) error {
 	return c.registry.DeleteRegistry(ctx, r)
@@ -211,6 +241,11 @@ func (c *RESTClient) GetReplicationPolicyByID(ctx context.Context, id int64) (*m
 	return c.replication.GetReplicationPolicyByID(ctx, id)
 }
 
+// ListReplicationPolicies wraps the ListReplicationPolicies method of the replication sub-package.
+func (c *RESTClient) ListReplicationPolicies(ctx context.Context) ([]*model.ReplicationPolicy, error) {
+	return c.replication.ListReplicationPolicies(ctx)
+}
+
 // DeleteReplicationPolicy wraps the DeleteReplicationPolicy method of the replication sub-package.
 func (c *RESTClient) DeleteReplicationPolicy(ctx context.Context, r *model.ReplicationPolicy) error {
 	return c.replication.DeleteReplicationPolicy(ctx, r)
@@ -238,6 +273,13 @@ func (c *RESTClient) GetReplicationExecutionByID(ctx context.Context, id int64)
 	return c.replication.GetReplicationExecutionByID(ctx, id)
 }
 
+// Robot Client
+
+// ListProjectRobots wraps the ListProjectRobots method of the robot sub-package.
+func (c *RESTClient) ListProjectRobots(ctx context.Context, p *model.Project) ([]*model.RobotAccount, error) {
+	return c.robot.ListProjectRobots(ctx, p)
+}
+
 // System Client
 
 // NewSystemGarbageCollection wraps the NewSystemGarbageCollection method of the system sub-package.
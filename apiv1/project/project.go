@@ -22,6 +22,9 @@ const (
 	ProjectMetadataKeyPreventVul           MetadataKey = "prevent_vul"
 )
 
+// DefaultPageSize is the number of projects requested per page when listing projects.
+const DefaultPageSize = 100
+
 // RESTClient is a subclient forhandling project related actions.
 type RESTClient struct {
 	// The swagger client
@@ -144,26 +147,42 @@ func (c *RESTClient) GetProject(ctx context.Context,
 	return nil, &ErrProjectNotFound{}
 }
 
-// ListProjects returns a list of projects based on a name filter.
+// ListProjects returns a list of projects based on a name filter, requesting them page by page.
 // Returns all projects if name is an empty string.
 // Returns an error if no projects were found.
 func (c *RESTClient) ListProjects(ctx context.Context,
 	nameFilter string,
 ) ([]*model.Project, error) {
-	resp, err := c.Client.Products.GetProjects(
-		&products.GetProjectsParams{
-			Name:    &nameFilter,
-			Context: ctx,
-		}, c.AuthInfo)
-	if err != nil {
-		return nil, handleSwaggerProjectErrors(err)
+	var projects []*model.Project
+
+	page, pageSize := int32(1), int32(DefaultPageSize)
+
+	for {
+		resp, err := c.Client.Products.GetProjects(
+			&products.GetProjectsParams{
+				Name:     &nameFilter,
+				Page:     &page,
+				PageSize: &pageSize,
+				Context:  ctx,
+			}, c.AuthInfo)
+		if err != nil {
+			return nil, handleSwaggerProjectErrors(err)
+		}
+
+		projects = append(projects, resp.Payload...)
+
+		if len(resp.Payload) < int(pageSize) {
+			break
+		}
+
+		page++
 	}
 
-	if len(resp.Payload) == 0 {
+	if len(projects) == 0 {
 		return nil, &ErrProjectNotFound{}
 	}
 
-	return resp.Payload, nil
+	return projects, nil
 }
 
 // UpdateProject updates a project with the specified data.
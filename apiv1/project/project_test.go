@@ -717,9 +717,13 @@ func TestRESTClient_ListProjects(t *testing.T) {
 
 	ctx := context.Background()
 
+	page, pageSize := int32(1), int32(DefaultPageSize)
+
 	getProjectParams := &products.GetProjectsParams{
-		Name:    &pReq.ProjectName,
-		Context: ctx,
+		Name:     &pReq.ProjectName,
+		Page:     &page,
+		PageSize: &pageSize,
+		Context:  ctx,
 	}
 
 	p.On("GetProjects", getProjectParams, mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
@@ -746,9 +750,13 @@ func TestRESTClient_ListProjectsErrProjectNotFound(t *testing.T) {
 
 	ctx := context.Background()
 
+	page, pageSize := int32(1), int32(DefaultPageSize)
+
 	getProjectParams := &products.GetProjectsParams{
-		Name:    &pReq.ProjectName,
-		Context: ctx,
+		Name:     &pReq.ProjectName,
+		Page:     &page,
+		PageSize: &pageSize,
+		Context:  ctx,
 	}
 
 	p.On("GetProjects", getProjectParams, mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
@@ -775,9 +783,13 @@ func TestRESTClient_ListProjects_ErrProjectUnknownResource(t *testing.T) {
 
 	ctx := context.Background()
 
+	page, pageSize := int32(1), int32(DefaultPageSize)
+
 	getProjectParams := &products.GetProjectsParams{
-		Name:    &pReq.ProjectName,
-		Context: ctx,
+		Name:     &pReq.ProjectName,
+		Page:     &page,
+		PageSize: &pageSize,
+		Context:  ctx,
 	}
 
 	p.On("GetProjects", getProjectParams, mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
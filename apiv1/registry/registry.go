@@ -30,6 +30,7 @@ type Client interface {
 	NewRegistry(ctx context.Context, name, registryType, url string,
 		credential *model.RegistryCredential, insecure bool) (*model.Registry, error)
 	GetRegistry(ctx context.Context, name string) (*model.Registry, error)
+	ListRegistries(ctx context.Context) ([]*model.Registry, error)
 	DeleteRegistry(ctx context.Context, r *model.Registry) error
 	UpdateRegistry(ctx context.Context, r *model.Registry) error
 }
@@ -91,6 +92,19 @@ func (c *RESTClient) GetRegistry(ctx context.Context, name string) (*model.Regis
 	return nil, &ErrRegistryNotFound{}
 }
 
+// ListRegistries returns all registries.
+func (c *RESTClient) ListRegistries(ctx context.Context) ([]*model.Registry, error) {
+	resp, err := c.Client.Products.GetRegistries(
+		&products.GetRegistriesParams{
+			Context: ctx,
+		}, c.AuthInfo)
+	if err != nil {
+		return nil, handleSwaggerRegistryErrors(err)
+	}
+
+	return resp.Payload, nil
+}
+
 // Delete deletes a registry.
 // Returns an error when no matching registry is found or when
 // having difficulties talking to the API.
@@ -693,3 +693,28 @@ func TestErrRegistryUnauthorized_Error(t *testing.T) {
 
 	assert.Equal(t, ErrRegistryUnauthorizedMsg, e.Error())
 }
+
+func TestRESTClient_ListRegistries(t *testing.T) {
+	p := &mocks.MockClientService{}
+
+	c := &client.Harbor{
+		Products:  p,
+		Transport: nil,
+	}
+
+	cl := NewClient(c, authInfo)
+
+	ctx := context.Background()
+
+	p.On("GetRegistries", &products.GetRegistriesParams{Context: ctx},
+		mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&products.GetRegistriesOK{Payload: []*model.Registry{registry}}, nil)
+
+	registries, err := cl.ListRegistries(ctx)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []*model.Registry{registry}, registries)
+	}
+
+	p.AssertExpectations(t)
+}
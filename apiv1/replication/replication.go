@@ -10,6 +10,9 @@ import (
 	model "github.com/mittwald/goharbor-client/v5/apiv1/model"
 )
 
+// DefaultPageSize is the number of replication policies requested per page when listing them.
+const DefaultPageSize = 100
+
 // RESTClient is a subclient for handling replication related actions.
 type RESTClient struct {
 	// The swagger client
@@ -33,6 +36,7 @@ type Client interface {
 		destNamespace, description, name string) (*model.ReplicationPolicy, error)
 	GetReplicationPolicy(ctx context.Context, name string) (*model.ReplicationPolicy, error)
 	GetReplicationPolicyByID(ctx context.Context, id int64) (*model.ReplicationPolicy, error)
+	ListReplicationPolicies(ctx context.Context) ([]*model.ReplicationPolicy, error)
 	DeleteReplicationPolicy(ctx context.Context, r *model.ReplicationPolicy) error
 	UpdateReplicationPolicy(ctx context.Context, r *model.ReplicationPolicy) error
 	TriggerReplicationExecution(ctx context.Context, r *model.ReplicationExecution) error
@@ -122,6 +126,33 @@ func (c *RESTClient) GetReplicationPolicyByID(ctx context.Context, id int64) (*m
 	return nil, &ErrReplicationNotFound{}
 }
 
+// ListReplicationPolicies returns all replication policies, requesting them page by page.
+func (c *RESTClient) ListReplicationPolicies(ctx context.Context) ([]*model.ReplicationPolicy, error) {
+	var policies []*model.ReplicationPolicy
+
+	page, pageSize := int32(1), int32(DefaultPageSize)
+
+	for {
+		resp, err := c.Client.Products.GetReplicationPolicies(
+			&products.GetReplicationPoliciesParams{
+				Page:     &page,
+				PageSize: &pageSize,
+				Context:  ctx,
+			}, c.AuthInfo)
+		if err != nil {
+			return nil, handleSwaggerReplicationErrors(err)
+		}
+
+		policies = append(policies, resp.Payload...)
+
+		if len(resp.Payload) < int(pageSize) {
+			return policies, nil
+		}
+
+		page++
+	}
+}
+
 // Delete deletes a replication.
 // Returns an error when no matching replication is found or when
 // having difficulties talking to the API.
@@ -773,3 +773,33 @@ func TestErrReplicationMismatch_Error(t *testing.T) {
 
 	assert.Equal(t, ErrReplicationMismatchMsg, e.Error())
 }
+
+func TestRESTClient_ListReplicationPolicies(t *testing.T) {
+	p := &mocks.MockClientService{}
+
+	c := &client.Harbor{
+		Products:  p,
+		Transport: nil,
+	}
+
+	cl := NewClient(c, authInfo)
+
+	ctx := context.Background()
+
+	page, pageSize := int32(1), int32(DefaultPageSize)
+
+	p.On("GetReplicationPolicies", &products.GetReplicationPoliciesParams{
+		Page:     &page,
+		PageSize: &pageSize,
+		Context:  ctx,
+	}, mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&products.GetReplicationPoliciesOK{Payload: []*model.ReplicationPolicy{replication}}, nil)
+
+	policies, err := cl.ListReplicationPolicies(ctx)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []*model.ReplicationPolicy{replication}, policies)
+	}
+
+	p.AssertExpectations(t)
+}
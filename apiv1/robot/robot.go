@@ -0,0 +1,84 @@
+package robot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+
+	"github.com/mittwald/goharbor-client/v5/apiv1/internal/api/client"
+	model "github.com/mittwald/goharbor-client/v5/apiv1/model"
+)
+
+// RESTClient is a subclient for handling robot account related actions.
+// The generated swagger client does not contain the robot account endpoints,
+// so the operations are submitted using the transport of the swagger client.
+type RESTClient struct {
+	// The swagger client
+	Client *client.Harbor
+
+	// AuthInfo contain auth information, which are provided on API calls.
+	AuthInfo runtime.ClientAuthInfoWriter
+}
+
+func NewClient(cl *client.Harbor, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
+	return &RESTClient{
+		Client:   cl,
+		AuthInfo: authInfo,
+	}
+}
+
+type Client interface {
+	ListProjectRobots(ctx context.Context, p *model.Project) ([]*model.RobotAccount, error)
+}
+
+// ListProjectRobots returns the robot accounts of the project 'p'.
+// Harbor 1.x does not return the access list of a robot account.
+func (c *RESTClient) ListProjectRobots(ctx context.Context, p *model.Project) ([]*model.RobotAccount, error) {
+	if p == nil {
+		return nil, &ErrProjectNotProvided{}
+	}
+
+	params := runtime.ClientRequestWriterFunc(func(r runtime.ClientRequest, _ strfmt.Registry) error {
+		return r.SetPathParam("project_id", swag.FormatInt32(p.ProjectID))
+	})
+
+	reader := runtime.ClientResponseReaderFunc(func(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+		if response.Code() != http.StatusOK {
+			return nil, runtime.NewAPIError("unexpected response listing robot accounts", response, response.Code())
+		}
+
+		var robots []*model.RobotAccount
+		if err := consumer.Consume(response.Body(), &robots); err != nil {
+			return nil, err
+		}
+
+		return robots, nil
+	})
+
+	result, err := c.Client.Transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetProjectsProjectIDRobots",
+		Method:             http.MethodGet,
+		PathPattern:        "/projects/{project_id}/robots",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http", "https"},
+		Params:             params,
+		Reader:             reader,
+		AuthInfo:           c.AuthInfo,
+		Context:            ctx,
+	})
+	if err != nil {
+		return nil, handleSwaggerRobotErrors(err)
+	}
+
+	robots, ok := result.([]*model.RobotAccount)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result listing robot accounts: %T", result)
+	}
+
+	return robots, nil
+}
@@ -0,0 +1,97 @@
+package robot
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+)
+
+const (
+	// ErrRobotIllegalIDFormat describes an illegal request format
+	ErrRobotIllegalIDFormatMsg = "illegal format of provided ID value"
+	// ErrRobotUnauthorized describes an unauthorized request
+	ErrRobotUnauthorizedMsg = "unauthorized"
+	// ErrRobotInternalErrors describes server-side internal errors
+	ErrRobotInternalErrorsMsg = "unexpected internal errors"
+	// ErrRobotNoPermission describes a request error without permission
+	ErrRobotNoPermissionMsg = "user does not have permission to the robot accounts"
+	// ErrRobotProjectNotFound describes an error
+	// when the project of the robot accounts is not found
+	ErrRobotProjectNotFoundMsg = "project not found on server side"
+	// ErrProjectNotProvidedMsg describes an error
+	// when no project was provided by the user
+	ErrProjectNotProvidedMsg = "no project provided"
+)
+
+// ErrRobotIllegalIDFormat describes an illegal request format.
+type ErrRobotIllegalIDFormat struct{}
+
+// Error returns the error message.
+func (e *ErrRobotIllegalIDFormat) Error() string {
+	return ErrRobotIllegalIDFormatMsg
+}
+
+// ErrRobotUnauthorized describes an unauthorized request.
+type ErrRobotUnauthorized struct{}
+
+// Error returns the error message.
+func (e *ErrRobotUnauthorized) Error() string {
+	return ErrRobotUnauthorizedMsg
+}
+
+// ErrRobotInternalErrors describes server-side internal errors.
+type ErrRobotInternalErrors struct{}
+
+// Error returns the error message.
+func (e *ErrRobotInternalErrors) Error() string {
+	return ErrRobotInternalErrorsMsg
+}
+
+// ErrRobotNoPermission describes a request error without permission.
+type ErrRobotNoPermission struct{}
+
+// Error returns the error message.
+func (e *ErrRobotNoPermission) Error() string {
+	return ErrRobotNoPermissionMsg
+}
+
+// ErrRobotProjectNotFound describes an error
+// when the project of the robot accounts is not found.
+type ErrRobotProjectNotFound struct{}
+
+// Error returns the error message.
+func (e *ErrRobotProjectNotFound) Error() string {
+	return ErrRobotProjectNotFoundMsg
+}
+
+// ErrProjectNotProvided describes an error
+// when no project was provided.
+type ErrProjectNotProvided struct{}
+
+// Error returns the error message.
+func (e *ErrProjectNotProvided) Error() string {
+	return ErrProjectNotProvidedMsg
+}
+
+// handleSwaggerRobotErrors takes an error returned by the swagger transport as input,
+// which usually does not contain any form of error message,
+// and outputs a new error with a proper message.
+func handleSwaggerRobotErrors(in error) error {
+	t, ok := in.(*runtime.APIError)
+	if ok {
+		switch t.Code {
+		case http.StatusBadRequest:
+			return &ErrRobotIllegalIDFormat{}
+		case http.StatusUnauthorized:
+			return &ErrRobotUnauthorized{}
+		case http.StatusForbidden:
+			return &ErrRobotNoPermission{}
+		case http.StatusNotFound:
+			return &ErrRobotProjectNotFound{}
+		case http.StatusInternalServerError:
+			return &ErrRobotInternalErrors{}
+		}
+	}
+
+	return in
+}
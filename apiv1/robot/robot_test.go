@@ -0,0 +1,78 @@
+//go:build !integration
+
+package robot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	runtimeclient "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv1/internal/api/client"
+	model "github.com/mittwald/goharbor-client/v5/apiv1/model"
+)
+
+var authInfo = runtimeclient.BasicAuth("foo", "bar")
+
+func newTestClient(t *testing.T, h http.HandlerFunc) *RESTClient {
+	t.Helper()
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL + "/api")
+	require.NoError(t, err)
+
+	swaggerClient := client.New(runtimeclient.New(u.Host, u.Path, []string{u.Scheme}), strfmt.Default)
+
+	return NewClient(swaggerClient, authInfo)
+}
+
+func TestRESTClient_ListProjectRobots(t *testing.T) {
+	robots := []*model.RobotAccount{{ID: 1, Name: "robot$ci", ProjectID: 3}}
+
+	cl := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/projects/3/robots", r.URL.Path)
+
+		user, _, _ := r.BasicAuth()
+		assert.Equal(t, "foo", user)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(robots)
+	})
+
+	res, err := cl.ListProjectRobots(context.Background(), &model.Project{ProjectID: 3})
+
+	require.NoError(t, err)
+	assert.Equal(t, robots, res)
+}
+
+func TestRESTClient_ListProjectRobots_ErrRobotProjectNotFound(t *testing.T) {
+	cl := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := cl.ListProjectRobots(context.Background(), &model.Project{ProjectID: 3})
+
+	if assert.Error(t, err) {
+		assert.IsType(t, &ErrRobotProjectNotFound{}, err)
+	}
+}
+
+func TestRESTClient_ListProjectRobots_ErrProjectNotProvided(t *testing.T) {
+	cl := NewClient(nil, authInfo)
+
+	_, err := cl.ListProjectRobots(context.Background(), nil)
+
+	if assert.Error(t, err) {
+		assert.IsType(t, &ErrProjectNotProvided{}, err)
+	}
+}
@@ -12,6 +12,9 @@ import (
 	model "github.com/mittwald/goharbor-client/v5/apiv1/model"
 )
 
+// DefaultPageSize is the number of users requested per page when listing users.
+const DefaultPageSize = 100
+
 // RESTClient is a subclient for handling user related actions.
 type RESTClient struct {
 	// The swagger client
@@ -31,6 +34,7 @@ func NewClient(cl *client.Harbor, authInfo runtime.ClientAuthInfoWriter) *RESTCl
 type Client interface {
 	NewUser(ctx context.Context, username, email, realname, password, comments string)
 	GetUser(ctx context.Context, username string) (*model.User, error)
+	ListUsers(ctx context.Context) ([]*model.User, error)
 	DeleteUser(ctx context.Context, u *model.User) error
 	UpdateUser(ctx context.Context, u *model.User) error
 	UpdateUserPassword(ctx context.Context, id int64, password *model.Password) error
@@ -92,6 +96,32 @@ func (c *RESTClient) GetUser(ctx context.Context, username string) (*model.User,
 	return nil, &ErrUserNotFound{}
 }
 
+// ListUsers returns all users, requesting them page by page.
+func (c *RESTClient) ListUsers(ctx context.Context) ([]*model.User, error) {
+	var users []*model.User
+
+	page, pageSize := int32(1), int32(DefaultPageSize)
+
+	for {
+		resp, err := c.Client.Products.GetUsers(&products.GetUsersParams{
+			Context:  ctx,
+			Page:     &page,
+			PageSize: &pageSize,
+		}, c.AuthInfo)
+		if err != nil {
+			return nil, handleSwaggerUserErrors(err)
+		}
+
+		users = append(users, resp.Payload...)
+
+		if len(resp.Payload) < int(pageSize) {
+			return users, nil
+		}
+
+		page++
+	}
+}
+
 // DeleteUser deletes the specified user.
 func (c *RESTClient) DeleteUser(ctx context.Context, u *model.User) error {
 	if u == nil {
@@ -793,3 +793,68 @@ func TestErrUserPasswordInvalid_Error(t *testing.T) {
 
 	assert.Equal(t, ErrUserPasswordInvalidMsg, e.Error())
 }
+
+func TestRESTClient_ListUsers(t *testing.T) {
+	p := &mocks.MockClientService{}
+
+	c := &client.Harbor{
+		Products:  p,
+		Transport: nil,
+	}
+
+	cl := NewClient(c, authInfo)
+
+	ctx := context.Background()
+
+	firstPage := make([]*model.User, DefaultPageSize)
+	for i := range firstPage {
+		firstPage[i] = &model.User{UserID: int64(i)}
+	}
+
+	pageSize := int32(DefaultPageSize)
+
+	for page, payload := range map[int32][]*model.User{
+		1: firstPage,
+		2: {{Username: exampleUser}},
+	} {
+		page := page
+
+		p.On("GetUsers", &products.GetUsersParams{
+			Context:  ctx,
+			Page:     &page,
+			PageSize: &pageSize,
+		}, mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+			Return(&products.GetUsersOK{Payload: payload}, nil)
+	}
+
+	users, err := cl.ListUsers(ctx)
+
+	assert.NoError(t, err)
+	assert.Len(t, users, DefaultPageSize+1)
+	assert.Equal(t, exampleUser, users[DefaultPageSize].Username)
+
+	p.AssertExpectations(t)
+}
+
+func TestRESTClient_ListUsers_ErrOnGET(t *testing.T) {
+	p := &mocks.MockClientService{}
+
+	c := &client.Harbor{
+		Products:  p,
+		Transport: nil,
+	}
+
+	cl := NewClient(c, authInfo)
+
+	ctx := context.Background()
+
+	p.On("GetUsers", mock.AnythingOfType("*products.GetUsersParams"),
+		mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(nil, &runtime.APIError{Code: http.StatusUnauthorized})
+
+	_, err := cl.ListUsers(ctx)
+
+	assert.Error(t, err)
+
+	p.AssertExpectations(t)
+}
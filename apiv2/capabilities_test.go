@@ -0,0 +1,130 @@
+//go:build !integration
+
+package apiv2_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2"
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/testing/fakeharbor"
+)
+
+// versionHandler serves the fake Harbor, reporting 'version' as Harbor version
+// and counting the requests to other endpoints.
+func versionHandler(fake *fakeharbor.Server, version string, systemInfo, other *int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != fakeharbor.BasePath+"/systeminfo" {
+			atomic.AddInt32(other, 1)
+			fake.ServeHTTP(w, r)
+
+			return
+		}
+
+		atomic.AddInt32(systemInfo, 1)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&model.GeneralInfo{HarborVersion: &version})
+	})
+}
+
+func TestCapabilities_UnsupportedServerVersion(t *testing.T) {
+	ctx := context.Background()
+
+	fake := fakeharbor.NewServer()
+	defer fake.Close()
+
+	var systemInfo, other int32
+
+	srv := httptest.NewServer(versionHandler(fake, "v2.1.4-c1b8d4e1", &systemInfo, &other))
+	defer srv.Close()
+
+	c, err := apiv2.New(srv.URL+"/api", apiv2.WithBasicAuth(fakeharbor.AdminUser, fakeharbor.AdminPassword))
+	require.NoError(t, err)
+
+	caps, err := c.Capabilities(ctx)
+	require.NoError(t, err)
+	require.True(t, caps.Preheat())
+	require.True(t, caps.RobotV1())
+	require.False(t, caps.SecurityHub())
+
+	_, err = c.ListRobotAccounts(ctx)
+	require.ErrorIs(t, err, &clienterrors.ErrUnsupportedServerVersion{})
+	require.Contains(t, err.Error(), "robot requires Harbor >= v2.2.0, server runs v2.1.4-c1b8d4e1")
+
+	require.ErrorIs(t, c.RunPurge(ctx, true), &clienterrors.ErrUnsupportedServerVersion{})
+
+	// Operations available in all versions are not affected.
+	_, err = c.GetProject(ctx, "library")
+	require.NoError(t, err)
+
+	// The version is requested once, unsupported operations are not sent.
+	require.EqualValues(t, 1, atomic.LoadInt32(&systemInfo))
+	require.EqualValues(t, 1, atomic.LoadInt32(&other))
+}
+
+func TestCapabilities_SupportedServerVersion(t *testing.T) {
+	ctx := context.Background()
+
+	fake := fakeharbor.NewServer()
+	defer fake.Close()
+
+	var systemInfo, other int32
+
+	srv := httptest.NewServer(versionHandler(fake, fakeharbor.Version, &systemInfo, &other))
+	defer srv.Close()
+
+	c, err := apiv2.New(srv.URL+"/api", apiv2.WithBasicAuth(fakeharbor.AdminUser, fakeharbor.AdminPassword))
+	require.NoError(t, err)
+
+	_, err = c.ListRobotAccounts(ctx)
+	require.NoError(t, err)
+
+	_, err = c.ListRobotAccounts(ctx)
+	require.NoError(t, err)
+
+	require.EqualValues(t, 1, atomic.LoadInt32(&systemInfo))
+	require.EqualValues(t, 2, atomic.LoadInt32(&other))
+}
+
+func TestCapabilities_DetectionFailure(t *testing.T) {
+	ctx := context.Background()
+
+	fake := fakeharbor.NewServer()
+	defer fake.Close()
+
+	var systemInfo int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == fakeharbor.BasePath+"/systeminfo" {
+			atomic.AddInt32(&systemInfo, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		fake.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	c, err := apiv2.New(srv.URL+"/api", apiv2.WithBasicAuth(fakeharbor.AdminUser, fakeharbor.AdminPassword))
+	require.NoError(t, err)
+
+	_, err = c.Capabilities(ctx)
+	require.Error(t, err)
+
+	// Operations depending on a feature are sent anyway if the version cannot be requested.
+	_, err = c.ListRobotAccounts(ctx)
+	require.NoError(t, err)
+
+	// Failed detections are retried.
+	require.EqualValues(t, 2, atomic.LoadInt32(&systemInfo))
+}
@@ -2,9 +2,11 @@ package apiv2
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/configure"
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/immutable"
@@ -16,6 +18,8 @@ import (
 
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/label"
 
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/auth"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/capabilities"
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/artifact"
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/repository"
 
@@ -34,6 +38,10 @@ import (
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/webhook"
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/common"
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+	imageref "github.com/mittwald/goharbor-client/v5/apiv2/pkg/reference"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/retry"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/telemetry"
 
 	"github.com/go-openapi/runtime"
 	runtimeclient "github.com/go-openapi/runtime/client"
@@ -79,7 +87,9 @@ type Client interface {
 	webhook.Client
 }
 
-// RESTClient implements the Client interface as a REST client
+// RESTClient implements the Client interface as a REST client.
+// It is safe for concurrent use; operations listing resources accept config.CallOptions
+// overriding the client's Options for a single call.
 type RESTClient struct {
 	auditlog    *auditlog.RESTClient
 	artifact    *artifact.RESTClient
@@ -105,15 +115,62 @@ type RESTClient struct {
 	systeminfo  *systeminfo.RESTClient
 	user        *user.RESTClient
 	webhook     *webhook.RESTClient
+
+	telemetry    *telemetry.Telemetry
+	capabilities *capabilities.Detector
 }
 
 // NewRESTClient constructs a new REST client containing each sub client.
+//
+// opts.Retry and opts.CircuitBreaker cannot be applied to the HTTP transport of an existing swagger client,
+// they require a client constructed from a host URL by New or NewRESTClientForHost.
+// If either is set, all operations fail with errors.ErrRetryUnsupported instead of silently sending requests
+// without them. Use NewRESTClientFromSwagger to have this reported on construction instead.
 func NewRESTClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
-	if opts == nil {
-		opts = config.Defaults()
+	c, err := NewRESTClientFromSwagger(v2Client, opts, authInfo)
+	if err != nil {
+		return newRESTClient(v2client.New(rejectingTransport{err: err}, strfmt.Default), opts, authInfo)
+	}
+
+	return c
+}
+
+// NewRESTClientFromSwagger constructs a new REST client containing each sub client, like NewRESTClient.
+// It returns an errors.ErrRetryUnsupported if opts.Retry or opts.CircuitBreaker is set,
+// which require a client constructed by New or NewRESTClientForHost.
+func NewRESTClientFromSwagger(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) (*RESTClient, error) {
+	if opts != nil && (opts.Retry != nil || opts.CircuitBreaker != nil) {
+		return nil, &clienterrors.ErrRetryUnsupported{}
 	}
 
-	return &RESTClient{
+	return newRESTClient(v2Client, opts, authInfo), nil
+}
+
+// rejectingTransport fails all operations with 'err'.
+type rejectingTransport struct {
+	err error
+}
+
+// Submit implements runtime.ClientTransport.
+func (t rejectingTransport) Submit(*runtime.ClientOperation) (interface{}, error) {
+	return nil, t.err
+}
+
+// newRESTClient constructs a new REST client from 'v2Client', whose transport already applies
+// opts.Retry and opts.CircuitBreaker if set.
+func newRESTClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
+	// The client keeps its own copy of 'opts', so that later changes by the caller do not race its operations.
+	opts = opts.Copy()
+
+	// Buffer error responses, so that their payload is available to errors.HarborError.
+	transport := clienterrors.WrapTransport(v2Client.Transport)
+	if opts.Telemetry != nil {
+		transport = opts.Telemetry.WrapTransport(transport)
+	}
+
+	v2Client = v2client.New(transport, strfmt.Default)
+
+	c := &RESTClient{
 		auditlog:    auditlog.NewClient(v2Client, opts, authInfo),
 		artifact:    artifact.NewClient(v2Client, opts, authInfo),
 		configure:   configure.NewClient(v2Client, opts, authInfo),
@@ -138,12 +195,33 @@ func NewRESTClient(v2Client *v2client.Harbor, opts *config.Options, authInfo run
 		systeminfo:  systeminfo.NewClient(v2Client, opts, authInfo),
 		user:        user.NewClient(v2Client, opts, authInfo),
 		webhook:     webhook.NewClient(v2Client, opts, authInfo),
+
+		telemetry: opts.Telemetry,
 	}
+
+	c.capabilities = capabilities.NewDetector(func(ctx context.Context) (string, error) {
+		info, err := c.systeminfo.GetSystemInfo(ctx)
+		if err != nil || info.HarborVersion == nil {
+			return "", err
+		}
+
+		return *info.HarborVersion, nil
+	})
+
+	return c
 }
 
-// NewRESTClientForHost constructs a new REST client containing a swagger API client using the defined
-// host string and basePath, the additional Harbor v2 API suffix as well as basic auth info.
-func NewRESTClientForHost(u, username, password string, opts *config.Options) (*RESTClient, error) {
+// New constructs a new REST client for the Harbor instance at 'u', e.g. 'https://harbor.example.com/api',
+// configured by 'opts'. The Harbor v2 API suffix is appended to 'u' if missing.
+func New(u string, opts ...Option) (*RESTClient, error) {
+	s := &settings{}
+
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+
 	if !strings.HasSuffix(u, v2URLSuffix) {
 		u += v2URLSuffix
 	}
@@ -153,51 +231,89 @@ func NewRESTClientForHost(u, username, password string, opts *config.Options) (*
 		return nil, err
 	}
 
-	v2SwaggerClient := v2client.New(runtimeclient.New(harborURL.Host, harborURL.Path, []string{harborURL.Scheme}), strfmt.Default)
-	authInfo := runtimeclient.BasicAuth(username, password)
+	transport, err := s.roundTripper()
+	if err != nil {
+		return nil, err
+	}
 
-	return NewRESTClient(v2SwaggerClient, opts, authInfo), nil
-}
+	if s.options != nil && (s.options.Retry != nil || s.options.CircuitBreaker != nil) {
+		transport = retry.NewTransport(transport, s.options.Retry, s.options.CircuitBreaker)
+	}
 
-// NewRESTClientForHostWithClient constructs a new REST client containing a swagger API client using the defined
-// host string and basePath, the additional Harbor v2 API suffix as well as basic auth info while using provided http client.
-func NewRESTClientForHostWithClient(u, username, password string, opts *config.Options, client *http.Client) (*RESTClient, error) {
-	if !strings.HasSuffix(u, v2URLSuffix) {
-		u += v2URLSuffix
+	if tp, ok := s.auth.(auth.TransportProvider); ok {
+		transport = tp.WrapTransport(transport)
 	}
 
-	harborURL, err := url.Parse(u)
-	if err != nil {
-		return nil, err
+	client := &http.Client{}
+	if s.httpClient != nil {
+		c := *s.httpClient
+		client = &c
 	}
 
-	v2SwaggerClient := v2client.New(runtimeclient.NewWithClient(harborURL.Host, harborURL.Path, []string{harborURL.Scheme}, client), strfmt.Default)
-	authInfo := runtimeclient.BasicAuth(username, password)
+	client.Transport = transport
+
+	rt := runtimeclient.NewWithClient(harborURL.Host, harborURL.Path, []string{harborURL.Scheme}, client)
+
+	return newRESTClient(v2client.New(rt, strfmt.Default), s.options, s.auth), nil
+}
 
-	return NewRESTClient(v2SwaggerClient, opts, authInfo), nil
+// NewRESTClientForHost constructs a new REST client containing a swagger API client using the defined
+// host string and basePath, the additional Harbor v2 API suffix as well as basic auth info.
+func NewRESTClientForHost(u, username, password string, opts *config.Options) (*RESTClient, error) {
+	return New(u, WithBasicAuth(username, password), WithOptions(opts))
+}
+
+// NewRESTClientForHostWithClient constructs a new REST client containing a swagger API client using the defined
+// host string and basePath, the additional Harbor v2 API suffix as well as basic auth info while using provided http client.
+func NewRESTClientForHostWithClient(u, username, password string, opts *config.Options, client *http.Client) (*RESTClient, error) {
+	return New(u, WithBasicAuth(username, password), WithOptions(opts), WithHTTPClient(client))
 }
 
 // NewRESTClientWithAuthFunc constructs a new REST client containing a swagger API client using the defined
 // host string and basePath, the additional Harbor v2 API suffix as well as a custom auth func, e.g. basic auth or token auth.
 func NewRESTClientWithAuthFunc(u string, authFunc runtime.ClientAuthInfoWriterFunc, opts *config.Options) (*RESTClient, error) {
-	if !strings.HasSuffix(u, v2URLSuffix) {
-		u += v2URLSuffix
-	}
+	return New(u, WithAuth(authFunc), WithOptions(opts))
+}
 
-	harborURL, err := url.Parse(u)
-	if err != nil {
-		return nil, err
-	}
+// NewRESTClientWithAuth constructs a new REST client containing a swagger API client using the defined
+// host string and basePath, the additional Harbor v2 API suffix as well as an auth.Provider, e.g. a bearer token,
+// robot account credentials or a Harbor UI session.
+func NewRESTClientWithAuth(u string, provider auth.Provider, opts *config.Options) (*RESTClient, error) {
+	return New(u, WithAuth(provider), WithOptions(opts))
+}
 
-	v2SwaggerClient := v2client.New(runtimeclient.New(harborURL.Host, harborURL.Path, []string{harborURL.Scheme}), strfmt.Default)
+// AuditLog Client
 
-	return NewRESTClient(v2SwaggerClient, opts, authFunc), nil
+func (c *RESTClient) ListAuditLogs(ctx context.Context, opts ...config.CallOption) ([]*modelv2.AuditLog, error) {
+	ctx, end := c.telemetry.Start(ctx, "auditlog.ListAuditLogs")
+	res, err := c.auditlog.ListAuditLogs(ctx, opts...)
+
+	return res, end(err)
 }
 
-// AuditLog Client
+func (c *RESTClient) QueryAuditLogs(ctx context.Context, q *auditlog.Query, opts ...config.CallOption) ([]*modelv2.AuditLog, error) {
+	ctx, end := c.telemetry.Start(ctx, "auditlog.QueryAuditLogs")
+	res, err := c.auditlog.QueryAuditLogs(ctx, q, opts...)
+
+	return res, end(err)
+}
 
-func (c *RESTClient) ListAuditLogs(ctx context.Context) ([]*modelv2.AuditLog, error) {
-	return c.auditlog.ListAuditLogs(ctx)
+func (c *RESTClient) StreamAuditLogs(ctx context.Context, q *auditlog.Query, fn func(*modelv2.AuditLog) error, opts ...config.CallOption) error {
+	ctx, end := c.telemetry.Start(ctx, "auditlog.StreamAuditLogs")
+
+	return end(c.auditlog.StreamAuditLogs(ctx, q, fn, opts...))
+}
+
+// Follow is not traced, as it keeps polling until 'ctx' is done.
+func (c *RESTClient) Follow(ctx context.Context, q *auditlog.Query, interval time.Duration, opts ...auditlog.FollowOption) <-chan auditlog.AuditEvent {
+	return c.auditlog.Follow(ctx, q, interval, opts...)
+}
+
+func (c *RESTClient) ExportAuditLogs(ctx context.Context, w io.Writer, f auditlog.Format, q *auditlog.Query, opts ...config.CallOption) (int, error) {
+	ctx, end := c.telemetry.Start(ctx, "auditlog.ExportAuditLogs")
+	res, err := c.auditlog.ExportAuditLogs(ctx, w, f, q, opts...)
+
+	return res, end(err)
 }
 
 // Artifact Client
@@ -212,261 +328,541 @@ func (c *RESTClient) ListAuditLogs(ctx context.Context) ([]*modelv2.AuditLog, er
 //}
 
 func (c *RESTClient) AddArtifactLabel(ctx context.Context, projectName, repositoryName, reference string, label *modelv2.Label) error {
-	return c.artifact.AddArtifactLabel(ctx, projectName, repositoryName, reference, label)
+	ctx, end := c.telemetry.Start(ctx, "artifact.AddArtifactLabel", telemetry.Project(projectName), telemetry.Repository(repositoryName), telemetry.Reference(reference))
+
+	return end(c.artifact.AddArtifactLabel(ctx, projectName, repositoryName, reference, label))
 }
 
 func (c *RESTClient) CopyArtifact(ctx context.Context, from *artifact.CopyReference, projectName, repositoryName string) error {
-	return c.artifact.CopyArtifact(ctx, from, projectName, repositoryName)
+	ctx, end := c.telemetry.Start(ctx, "artifact.CopyArtifact", telemetry.Project(projectName), telemetry.Repository(repositoryName))
+
+	return end(c.artifact.CopyArtifact(ctx, from, projectName, repositoryName))
 }
 
 func (c *RESTClient) CreateTag(ctx context.Context, projectName, repositoryName, reference string, tag *modelv2.Tag) error {
-	return c.artifact.CreateTag(ctx, projectName, repositoryName, reference, tag)
+	ctx, end := c.telemetry.Start(ctx, "artifact.CreateTag", telemetry.Project(projectName), telemetry.Repository(repositoryName), telemetry.Reference(reference))
+
+	return end(c.artifact.CreateTag(ctx, projectName, repositoryName, reference, tag))
 }
 
 func (c *RESTClient) DeleteTag(ctx context.Context, projectName, repositoryName, reference, tagName string) error {
-	return c.artifact.DeleteTag(ctx, projectName, repositoryName, reference, tagName)
+	ctx, end := c.telemetry.Start(ctx, "artifact.DeleteTag", telemetry.Project(projectName), telemetry.Repository(repositoryName), telemetry.Reference(reference))
+
+	return end(c.artifact.DeleteTag(ctx, projectName, repositoryName, reference, tagName))
 }
 
 func (c *RESTClient) GetArtifact(ctx context.Context, projectName, repositoryName, reference string) (*modelv2.Artifact, error) {
-	return c.artifact.GetArtifact(ctx, projectName, repositoryName, reference)
+	ctx, end := c.telemetry.Start(ctx, "artifact.GetArtifact", telemetry.Project(projectName), telemetry.Repository(repositoryName), telemetry.Reference(reference))
+	res, err := c.artifact.GetArtifact(ctx, projectName, repositoryName, reference)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) DeleteArtifact(ctx context.Context, projectName, repositoryName, reference string) error {
-	return c.artifact.DeleteArtifact(ctx, projectName, repositoryName, reference)
+	ctx, end := c.telemetry.Start(ctx, "artifact.DeleteArtifact", telemetry.Project(projectName), telemetry.Repository(repositoryName), telemetry.Reference(reference))
+
+	return end(c.artifact.DeleteArtifact(ctx, projectName, repositoryName, reference))
+}
+
+func (c *RESTClient) ListArtifacts(ctx context.Context, projectName, repositoryName string, opts ...config.CallOption) ([]*modelv2.Artifact, error) {
+	ctx, end := c.telemetry.Start(ctx, "artifact.ListArtifacts", telemetry.Project(projectName), telemetry.Repository(repositoryName))
+	res, err := c.artifact.ListArtifacts(ctx, projectName, repositoryName, opts...)
+
+	return res, end(err)
 }
 
-func (c *RESTClient) ListArtifacts(ctx context.Context, projectName, repositoryName string) ([]*modelv2.Artifact, error) {
-	return c.artifact.ListArtifacts(ctx, projectName, repositoryName)
+func (c *RESTClient) ListAccessories(ctx context.Context, projectName, repositoryName, reference string, opts ...config.CallOption) ([]*modelv2.Accessory, error) {
+	ctx, end := c.telemetry.Start(ctx, "artifact.ListAccessories", telemetry.Project(projectName), telemetry.Repository(repositoryName), telemetry.Reference(reference))
+	res, err := c.artifact.ListAccessories(ctx, projectName, repositoryName, reference, opts...)
+
+	return res, end(err)
 }
 
-func (c *RESTClient) ListTags(ctx context.Context, projectName, repositoryName, reference string) ([]*modelv2.Tag, error) {
-	return c.artifact.ListTags(ctx, projectName, repositoryName, reference)
+func (c *RESTClient) DeleteAccessory(ctx context.Context, projectName, repositoryName string, accessory *modelv2.Accessory) error {
+	ctx, end := c.telemetry.Start(ctx, "artifact.DeleteAccessory", telemetry.Project(projectName), telemetry.Repository(repositoryName))
+
+	return end(c.artifact.DeleteAccessory(ctx, projectName, repositoryName, accessory))
+}
+
+func (c *RESTClient) ListTags(ctx context.Context, projectName, repositoryName, reference string, opts ...config.CallOption) ([]*modelv2.Tag, error) {
+	ctx, end := c.telemetry.Start(ctx, "artifact.ListTags", telemetry.Project(projectName), telemetry.Repository(repositoryName), telemetry.Reference(reference))
+	res, err := c.artifact.ListTags(ctx, projectName, repositoryName, reference, opts...)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) RemoveLabel(ctx context.Context, projectName, repositoryName, reference string, id int64) error {
-	return c.artifact.RemoveLabel(ctx, projectName, repositoryName, reference, id)
+	ctx, end := c.telemetry.Start(ctx, "artifact.RemoveLabel", telemetry.Project(projectName), telemetry.Repository(repositoryName), telemetry.Reference(reference))
+
+	return end(c.artifact.RemoveLabel(ctx, projectName, repositoryName, reference, id))
+}
+
+func (c *RESTClient) GetArtifactByReference(ctx context.Context, ref *imageref.Reference) (*modelv2.Artifact, error) {
+	ctx, end := c.telemetry.Start(ctx, "artifact.GetArtifactByReference", telemetry.ArtifactReference(ref)...)
+	res, err := c.artifact.GetArtifactByReference(ctx, ref)
+
+	return res, end(err)
+}
+
+func (c *RESTClient) GetArtifactWithDetails(ctx context.Context, projectName, repositoryName, reference string, details artifact.ArtifactDetails) (*modelv2.Artifact, error) {
+	ctx, end := c.telemetry.Start(ctx, "artifact.GetArtifactWithDetails", telemetry.Project(projectName), telemetry.Repository(repositoryName), telemetry.Reference(reference))
+	res, err := c.artifact.GetArtifactWithDetails(ctx, projectName, repositoryName, reference, details)
+
+	return res, end(err)
+}
+
+func (c *RESTClient) ListArtifactsWithOptions(ctx context.Context, projectName, repositoryName string, listOpts *artifact.ListArtifactsOptions, opts ...config.CallOption) ([]*modelv2.Artifact, error) {
+	ctx, end := c.telemetry.Start(ctx, "artifact.ListArtifactsWithOptions", telemetry.Project(projectName), telemetry.Repository(repositoryName))
+	res, err := c.artifact.ListArtifactsWithOptions(ctx, projectName, repositoryName, listOpts, opts...)
+
+	return res, end(err)
+}
+
+func (c *RESTClient) ListArtifactsByReferenceWithOptions(ctx context.Context, ref *imageref.Reference, listOpts *artifact.ListArtifactsOptions, opts ...config.CallOption) ([]*modelv2.Artifact, error) {
+	ctx, end := c.telemetry.Start(ctx, "artifact.ListArtifactsByReferenceWithOptions", telemetry.ArtifactReference(ref)...)
+	res, err := c.artifact.ListArtifactsByReferenceWithOptions(ctx, ref, listOpts, opts...)
+
+	return res, end(err)
+}
+
+func (c *RESTClient) DeleteArtifactByReference(ctx context.Context, ref *imageref.Reference) error {
+	ctx, end := c.telemetry.Start(ctx, "artifact.DeleteArtifactByReference", telemetry.ArtifactReference(ref)...)
+
+	return end(c.artifact.DeleteArtifactByReference(ctx, ref))
+}
+
+func (c *RESTClient) ListArtifactsByReference(ctx context.Context, ref *imageref.Reference, opts ...config.CallOption) ([]*modelv2.Artifact, error) {
+	ctx, end := c.telemetry.Start(ctx, "artifact.ListArtifactsByReference", telemetry.ArtifactReference(ref)...)
+	res, err := c.artifact.ListArtifactsByReference(ctx, ref, opts...)
+
+	return res, end(err)
+}
+
+func (c *RESTClient) CreateTagByReference(ctx context.Context, ref *imageref.Reference, tag *modelv2.Tag) error {
+	ctx, end := c.telemetry.Start(ctx, "artifact.CreateTagByReference", telemetry.ArtifactReference(ref)...)
+
+	return end(c.artifact.CreateTagByReference(ctx, ref, tag))
+}
+
+func (c *RESTClient) ListTagsByReference(ctx context.Context, ref *imageref.Reference, opts ...config.CallOption) ([]*modelv2.Tag, error) {
+	ctx, end := c.telemetry.Start(ctx, "artifact.ListTagsByReference", telemetry.ArtifactReference(ref)...)
+	res, err := c.artifact.ListTagsByReference(ctx, ref, opts...)
+
+	return res, end(err)
+}
+
+func (c *RESTClient) ListAccessoriesByReference(ctx context.Context, ref *imageref.Reference, opts ...config.CallOption) ([]*modelv2.Accessory, error) {
+	ctx, end := c.telemetry.Start(ctx, "artifact.ListAccessoriesByReference", telemetry.ArtifactReference(ref)...)
+	res, err := c.artifact.ListAccessoriesByReference(ctx, ref, opts...)
+
+	return res, end(err)
+}
+
+func (c *RESTClient) CopyArtifactByReference(ctx context.Context, from, to *imageref.Reference) error {
+	ctx, end := c.telemetry.Start(ctx, "artifact.CopyArtifactByReference", telemetry.ArtifactReference(to)...)
+
+	return end(c.artifact.CopyArtifactByReference(ctx, from, to))
 }
 
 // Configure Client
 
 func (c *RESTClient) GetConfigs(ctx context.Context) (*modelv2.ConfigurationsResponse, error) {
-	return c.configure.GetConfigs(ctx)
+	ctx, end := c.telemetry.Start(ctx, "configure.GetConfigs")
+	res, err := c.configure.GetConfigs(ctx)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) UpdateConfigs(ctx context.Context, cfg *modelv2.Configurations) error {
-	return c.configure.UpdateConfigs(ctx, cfg)
+	ctx, end := c.telemetry.Start(ctx, "configure.UpdateConfigs")
+
+	return end(c.configure.UpdateConfigs(ctx, cfg))
+}
+
+func (c *RESTClient) PatchConfigs(ctx context.Context, desired *modelv2.Configurations, opts *configure.PatchOptions) ([]configure.Change, error) {
+	ctx, end := c.telemetry.Start(ctx, "configure.PatchConfigs")
+	res, err := c.configure.PatchConfigs(ctx, desired, opts)
+
+	return res, end(err)
+}
+
+func (c *RESTClient) ValidateAuthConfigs(ctx context.Context, proposed *modelv2.Configurations) (*configure.AuthDiagnostics, error) {
+	ctx, end := c.telemetry.Start(ctx, "configure.ValidateAuthConfigs")
+	res, err := c.configure.ValidateAuthConfigs(ctx, proposed)
+
+	return res, end(err)
 }
 
 // GC Client
 
 func (c *RESTClient) NewGarbageCollection(ctx context.Context, gcSchedule *modelv2.Schedule) error {
-	return c.gc.NewGarbageCollection(ctx, gcSchedule)
+	ctx, end := c.telemetry.Start(ctx, "gc.NewGarbageCollection")
+
+	return end(c.gc.NewGarbageCollection(ctx, gcSchedule))
 }
 
 func (c *RESTClient) UpdateGarbageCollection(ctx context.Context, newGCSchedule *modelv2.Schedule) error {
-	return c.gc.UpdateGarbageCollection(ctx, newGCSchedule)
+	ctx, end := c.telemetry.Start(ctx, "gc.UpdateGarbageCollection")
+
+	return end(c.gc.UpdateGarbageCollection(ctx, newGCSchedule))
 }
 
-func (c *RESTClient) GetGarbageCollectionExecutions(ctx context.Context) ([]*modelv2.GCHistory, error) {
-	return c.gc.GetGarbageCollectionExecutions(ctx)
+func (c *RESTClient) GetGarbageCollectionExecutions(ctx context.Context, opts ...config.CallOption) ([]*modelv2.GCHistory, error) {
+	ctx, end := c.telemetry.Start(ctx, "gc.GetGarbageCollectionExecutions")
+	res, err := c.gc.GetGarbageCollectionExecutions(ctx, opts...)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) GetGarbageCollectionExecution(ctx context.Context, id int64) (*modelv2.GCHistory, error) {
-	return c.gc.GetGarbageCollectionExecution(ctx, id)
+	ctx, end := c.telemetry.Start(ctx, "gc.GetGarbageCollectionExecution")
+	res, err := c.gc.GetGarbageCollectionExecution(ctx, id)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) GetGarbageCollectionSchedule(ctx context.Context) (*modelv2.GCHistory, error) {
-	return c.gc.GetGarbageCollectionSchedule(ctx)
+	ctx, end := c.telemetry.Start(ctx, "gc.GetGarbageCollectionSchedule")
+	res, err := c.gc.GetGarbageCollectionSchedule(ctx)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) ResetGarbageCollection(ctx context.Context) error {
-	return c.gc.ResetGarbageCollection(ctx)
+	ctx, end := c.telemetry.Start(ctx, "gc.ResetGarbageCollection")
+
+	return end(c.gc.ResetGarbageCollection(ctx))
 }
 
 // Health Client
 
 func (c *RESTClient) GetHealth(ctx context.Context) (*modelv2.OverallHealthStatus, error) {
-	return c.health.GetHealth(ctx)
+	ctx, end := c.telemetry.Start(ctx, "health.GetHealth")
+	res, err := c.health.GetHealth(ctx)
+
+	return res, end(err)
+}
+
+func (c *RESTClient) WaitUntilHealthy(ctx context.Context, components ...string) error {
+	ctx, end := c.telemetry.Start(ctx, "health.WaitUntilHealthy")
+
+	return end(c.health.WaitUntilHealthy(ctx, components...))
 }
 
 // Immutable Client
 
 func (c *RESTClient) CreateImmuRule(ctx context.Context, projectNameOrID string, immutableRule *modelv2.ImmutableRule) error {
-	return c.immutable.CreateImmuRule(ctx, projectNameOrID, immutableRule)
+	ctx, end := c.telemetry.Start(ctx, "immutable.CreateImmuRule", telemetry.Project(projectNameOrID))
+
+	return end(c.immutable.CreateImmuRule(ctx, projectNameOrID, immutableRule))
 }
 
 func (c *RESTClient) UpdateImmuRule(ctx context.Context, projectNameOrID string, immutableRule *modelv2.ImmutableRule, immutableRuleID int64) error {
-	return c.immutable.UpdateImmuRule(ctx, projectNameOrID, immutableRule, immutableRuleID)
+	ctx, end := c.telemetry.Start(ctx, "immutable.UpdateImmuRule", telemetry.Project(projectNameOrID))
+
+	return end(c.immutable.UpdateImmuRule(ctx, projectNameOrID, immutableRule, immutableRuleID))
 }
 
 func (c *RESTClient) DeleteImmuRule(ctx context.Context, projectNameOrID string, immutableRuleID int64) error {
-	return c.immutable.DeleteImmuRule(ctx, projectNameOrID, immutableRuleID)
+	ctx, end := c.telemetry.Start(ctx, "immutable.DeleteImmuRule", telemetry.Project(projectNameOrID))
+
+	return end(c.immutable.DeleteImmuRule(ctx, projectNameOrID, immutableRuleID))
 }
 
-func (c *RESTClient) ListImmuRules(ctx context.Context, projectNameOrID string) ([]*modelv2.ImmutableRule, error) {
-	return c.immutable.ListImmuRules(ctx, projectNameOrID)
+func (c *RESTClient) ListImmuRules(ctx context.Context, projectNameOrID string, opts ...config.CallOption) ([]*modelv2.ImmutableRule, error) {
+	ctx, end := c.telemetry.Start(ctx, "immutable.ListImmuRules", telemetry.Project(projectNameOrID))
+	res, err := c.immutable.ListImmuRules(ctx, projectNameOrID, opts...)
+
+	return res, end(err)
 }
 
 // Label Client
 
 func (c *RESTClient) CreateLabel(ctx context.Context, l *modelv2.Label) error {
-	return c.label.CreateLabel(ctx, l)
+	ctx, end := c.telemetry.Start(ctx, "label.CreateLabel")
+
+	return end(c.label.CreateLabel(ctx, l))
 }
 
 func (c *RESTClient) GetLabelByID(ctx context.Context, id int64) (*modelv2.Label, error) {
-	return c.label.GetLabelByID(ctx, id)
+	ctx, end := c.telemetry.Start(ctx, "label.GetLabelByID")
+	res, err := c.label.GetLabelByID(ctx, id)
+
+	return res, end(err)
 }
 
-func (c *RESTClient) ListLabels(ctx context.Context, name string, projectID *int64, scope label.Scope) ([]*modelv2.Label, error) {
-	return c.label.ListLabels(ctx, name, projectID)
+func (c *RESTClient) ListLabels(ctx context.Context, name string, projectID *int64, scope label.Scope, opts ...config.CallOption) ([]*modelv2.Label, error) {
+	ctx, end := c.telemetry.Start(ctx, "label.ListLabels")
+	res, err := c.label.ListLabels(ctx, name, projectID, opts...)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) DeleteLabel(ctx context.Context, id int64) error {
-	return c.label.DeleteLabel(ctx, id)
+	ctx, end := c.telemetry.Start(ctx, "label.DeleteLabel")
+
+	return end(c.label.DeleteLabel(ctx, id))
 }
 
 func (c *RESTClient) UpdateLabel(ctx context.Context, id int64, l *modelv2.Label) error {
-	return c.label.UpdateLabel(ctx, id, l)
+	ctx, end := c.telemetry.Start(ctx, "label.UpdateLabel")
+
+	return end(c.label.UpdateLabel(ctx, id, l))
 }
 
 // Member Client
 
 func (c *RESTClient) AddProjectMember(ctx context.Context, projectNameOrID string, m *modelv2.ProjectMember) error {
-	return c.member.AddProjectMember(ctx, projectNameOrID, m)
+	ctx, end := c.telemetry.Start(ctx, "member.AddProjectMember", telemetry.Project(projectNameOrID))
+
+	return end(c.member.AddProjectMember(ctx, projectNameOrID, m))
 }
 
-func (c *RESTClient) ListProjectMembers(ctx context.Context, projectNameOrID, memberQuery string) ([]*modelv2.ProjectMemberEntity, error) {
-	return c.member.ListProjectMembers(ctx, projectNameOrID, memberQuery)
+func (c *RESTClient) ListProjectMembers(ctx context.Context, projectNameOrID, memberQuery string, opts ...config.CallOption) ([]*modelv2.ProjectMemberEntity, error) {
+	ctx, end := c.telemetry.Start(ctx, "member.ListProjectMembers", telemetry.Project(projectNameOrID))
+	res, err := c.member.ListProjectMembers(ctx, projectNameOrID, memberQuery, opts...)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) UpdateProjectMember(ctx context.Context, projectNameOrID string, m *modelv2.ProjectMember) error {
-	return c.member.UpdateProjectMember(ctx, projectNameOrID, m)
+	ctx, end := c.telemetry.Start(ctx, "member.UpdateProjectMember", telemetry.Project(projectNameOrID))
+
+	return end(c.member.UpdateProjectMember(ctx, projectNameOrID, m))
 }
 
 func (c *RESTClient) DeleteProjectMember(ctx context.Context, projectNameOrID string, m *modelv2.ProjectMember) error {
-	return c.member.DeleteProjectMember(ctx, projectNameOrID, m)
+	ctx, end := c.telemetry.Start(ctx, "member.DeleteProjectMember", telemetry.Project(projectNameOrID))
+
+	return end(c.member.DeleteProjectMember(ctx, projectNameOrID, m))
 }
 
 // Project Client
 
 func (c *RESTClient) NewProject(ctx context.Context, projectRequest *modelv2.ProjectReq) error {
-	return c.project.NewProject(ctx, projectRequest)
+	ctx, end := c.telemetry.Start(ctx, "project.NewProject")
+
+	return end(c.project.NewProject(ctx, projectRequest))
 }
 
 func (c *RESTClient) DeleteProject(ctx context.Context, nameOrID string) error {
-	return c.project.DeleteProject(ctx, nameOrID)
+	ctx, end := c.telemetry.Start(ctx, "project.DeleteProject")
+
+	return end(c.project.DeleteProject(ctx, nameOrID))
 }
 
 func (c *RESTClient) GetProject(ctx context.Context, nameOrID string) (*modelv2.Project, error) {
-	return c.project.GetProject(ctx, nameOrID)
+	ctx, end := c.telemetry.Start(ctx, "project.GetProject")
+	res, err := c.project.GetProject(ctx, nameOrID)
+
+	return res, end(err)
 }
 
-func (c *RESTClient) ListProjects(ctx context.Context, nameFilter string) ([]*modelv2.Project, error) {
-	return c.project.ListProjects(ctx, nameFilter)
+func (c *RESTClient) ListProjects(ctx context.Context, nameFilter string, opts ...config.CallOption) ([]*modelv2.Project, error) {
+	ctx, end := c.telemetry.Start(ctx, "project.ListProjects")
+	res, err := c.project.ListProjects(ctx, nameFilter, opts...)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) UpdateProject(ctx context.Context, p *modelv2.Project, storageLimit *int64) error {
-	return c.project.UpdateProject(ctx, p, storageLimit)
+	ctx, end := c.telemetry.Start(ctx, "project.UpdateProject")
+
+	return end(c.project.UpdateProject(ctx, p, storageLimit))
 }
 
 func (c *RESTClient) ProjectExists(ctx context.Context, nameOrID string) (bool, error) {
-	return c.project.ProjectExists(ctx, nameOrID)
+	ctx, end := c.telemetry.Start(ctx, "project.ProjectExists")
+	res, err := c.project.ProjectExists(ctx, nameOrID)
+
+	return res, end(err)
 }
 
 // Projectmeta Client
 
 func (c *RESTClient) AddProjectMetadata(ctx context.Context, projectNameOrID string, key common.MetadataKey, value string) error {
-	return c.projectmeta.AddProjectMetadata(ctx, projectNameOrID, key, value)
+	ctx, end := c.telemetry.Start(ctx, "projectmeta.AddProjectMetadata", telemetry.Project(projectNameOrID))
+
+	return end(c.projectmeta.AddProjectMetadata(ctx, projectNameOrID, key, value))
 }
 
 func (c *RESTClient) GetProjectMetadataValue(ctx context.Context, projectNameOrID string, key common.MetadataKey) (string, error) {
-	return c.projectmeta.GetProjectMetadataValue(ctx, projectNameOrID, key)
+	ctx, end := c.telemetry.Start(ctx, "projectmeta.GetProjectMetadataValue", telemetry.Project(projectNameOrID))
+	res, err := c.projectmeta.GetProjectMetadataValue(ctx, projectNameOrID, key)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) ListProjectMetadata(ctx context.Context, projectNameOrID string) (map[string]string, error) {
-	return c.projectmeta.ListProjectMetadata(ctx, projectNameOrID)
+	ctx, end := c.telemetry.Start(ctx, "projectmeta.ListProjectMetadata", telemetry.Project(projectNameOrID))
+	res, err := c.projectmeta.ListProjectMetadata(ctx, projectNameOrID)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) UpdateProjectMetadata(ctx context.Context, projectNameOrID string, key common.MetadataKey, value string) error {
-	return c.projectmeta.UpdateProjectMetadata(ctx, projectNameOrID, key, value)
+	ctx, end := c.telemetry.Start(ctx, "projectmeta.UpdateProjectMetadata", telemetry.Project(projectNameOrID))
+
+	return end(c.projectmeta.UpdateProjectMetadata(ctx, projectNameOrID, key, value))
 }
 
 func (c *RESTClient) DeleteProjectMetadataValue(ctx context.Context, projectNameOrID string, key common.MetadataKey) error {
-	return c.projectmeta.DeleteProjectMetadataValue(ctx, projectNameOrID, key)
+	ctx, end := c.telemetry.Start(ctx, "projectmeta.DeleteProjectMetadataValue", telemetry.Project(projectNameOrID))
+
+	return end(c.projectmeta.DeleteProjectMetadataValue(ctx, projectNameOrID, key))
 }
 
 // Purge Client
 
 func (c *RESTClient) CreatePurgeSchedule(ctx context.Context, schedule *modelv2.Schedule) error {
-	return c.purge.CreatePurgeSchedule(ctx, schedule)
+	ctx, end := c.telemetry.Start(ctx, "purge.CreatePurgeSchedule")
+
+	if err := c.capabilities.Require(ctx, capabilities.FeaturePurge); err != nil {
+		return end(err)
+	}
+
+	return end(c.purge.CreatePurgeSchedule(ctx, schedule))
 }
 
 func (c *RESTClient) RunPurge(ctx context.Context, dryRun bool) error {
-	return c.purge.RunPurge(ctx, dryRun)
+	ctx, end := c.telemetry.Start(ctx, "purge.RunPurge")
+
+	if err := c.capabilities.Require(ctx, capabilities.FeaturePurge); err != nil {
+		return end(err)
+	}
+
+	return end(c.purge.RunPurge(ctx, dryRun))
 }
 
-func (c *RESTClient) ListPurgeHistory(ctx context.Context) ([]*modelv2.ExecHistory, error) {
-	return c.purge.ListPurgeHistory(ctx)
+func (c *RESTClient) ListPurgeHistory(ctx context.Context, opts ...config.CallOption) ([]*modelv2.ExecHistory, error) {
+	ctx, end := c.telemetry.Start(ctx, "purge.ListPurgeHistory")
+
+	if err := c.capabilities.Require(ctx, capabilities.FeaturePurge); err != nil {
+		return nil, end(err)
+	}
+
+	res, err := c.purge.ListPurgeHistory(ctx, opts...)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) GetPurgeJob(ctx context.Context, id int64) (*modelv2.ExecHistory, error) {
-	return c.purge.GetPurgeJob(ctx, id)
+	ctx, end := c.telemetry.Start(ctx, "purge.GetPurgeJob")
+
+	if err := c.capabilities.Require(ctx, capabilities.FeaturePurge); err != nil {
+		return nil, end(err)
+	}
+
+	res, err := c.purge.GetPurgeJob(ctx, id)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) GetPurgeJobLog(ctx context.Context, id int64) (string, error) {
-	return c.purge.GetPurgeJobLog(ctx, id)
+	ctx, end := c.telemetry.Start(ctx, "purge.GetPurgeJobLog")
+
+	if err := c.capabilities.Require(ctx, capabilities.FeaturePurge); err != nil {
+		return "", end(err)
+	}
+
+	res, err := c.purge.GetPurgeJobLog(ctx, id)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) GetPurgeSchedule(ctx context.Context) (*modelv2.ExecHistory, error) {
-	return c.purge.GetPurgeSchedule(ctx)
+	ctx, end := c.telemetry.Start(ctx, "purge.GetPurgeSchedule")
+
+	if err := c.capabilities.Require(ctx, capabilities.FeaturePurge); err != nil {
+		return nil, end(err)
+	}
+
+	res, err := c.purge.GetPurgeSchedule(ctx)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) StopPurge(ctx context.Context, id int64) error {
-	return c.purge.StopPurge(ctx, id)
+	ctx, end := c.telemetry.Start(ctx, "purge.StopPurge")
+
+	if err := c.capabilities.Require(ctx, capabilities.FeaturePurge); err != nil {
+		return end(err)
+	}
+
+	return end(c.purge.StopPurge(ctx, id))
 }
 
 func (c *RESTClient) UpdatePurgeSchedule(ctx context.Context, schedule *modelv2.Schedule) error {
-	return c.purge.UpdatePurgeSchedule(ctx, schedule)
+	ctx, end := c.telemetry.Start(ctx, "purge.UpdatePurgeSchedule")
+
+	if err := c.capabilities.Require(ctx, capabilities.FeaturePurge); err != nil {
+		return end(err)
+	}
+
+	return end(c.purge.UpdatePurgeSchedule(ctx, schedule))
 }
 
 // Quota Client
 
-func (c *RESTClient) ListQuotas(ctx context.Context, referenceType, referenceID *string) ([]*modelv2.Quota, error) {
-	return c.quota.ListQuotas(ctx, referenceType, referenceID)
+func (c *RESTClient) ListQuotas(ctx context.Context, referenceType, referenceID *string, opts ...config.CallOption) ([]*modelv2.Quota, error) {
+	ctx, end := c.telemetry.Start(ctx, "quota.ListQuotas")
+	res, err := c.quota.ListQuotas(ctx, referenceType, referenceID, opts...)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) GetQuotaByProjectID(ctx context.Context, projectID int64) (*modelv2.Quota, error) {
-	return c.quota.GetQuotaByProjectID(ctx, projectID)
+	ctx, end := c.telemetry.Start(ctx, "quota.GetQuotaByProjectID", telemetry.ProjectID(projectID))
+	res, err := c.quota.GetQuotaByProjectID(ctx, projectID)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) UpdateStorageQuotaByProjectID(ctx context.Context, projectID int64, storageLimit int64) error {
-	return c.quota.UpdateStorageQuotaByProjectID(ctx, projectID, storageLimit)
+	ctx, end := c.telemetry.Start(ctx, "quota.UpdateStorageQuotaByProjectID", telemetry.ProjectID(projectID))
+
+	return end(c.quota.UpdateStorageQuotaByProjectID(ctx, projectID, storageLimit))
 }
 
 // Registry Client
 
 func (c *RESTClient) NewRegistry(ctx context.Context, reg *modelv2.Registry) error {
-	return c.registry.NewRegistry(ctx, reg)
+	ctx, end := c.telemetry.Start(ctx, "registry.NewRegistry")
+
+	return end(c.registry.NewRegistry(ctx, reg))
 }
 
 func (c *RESTClient) GetRegistryByID(ctx context.Context, id int64) (*modelv2.Registry, error) {
-	return c.registry.GetRegistryByID(ctx, id)
+	ctx, end := c.telemetry.Start(ctx, "registry.GetRegistryByID")
+	res, err := c.registry.GetRegistryByID(ctx, id)
+
+	return res, end(err)
 }
 
-func (c *RESTClient) GetRegistryByName(ctx context.Context, name string) (*modelv2.Registry, error) {
-	return c.registry.GetRegistryByName(ctx, name)
+func (c *RESTClient) GetRegistryByName(ctx context.Context, name string, opts ...config.CallOption) (*modelv2.Registry, error) {
+	ctx, end := c.telemetry.Start(ctx, "registry.GetRegistryByName")
+	res, err := c.registry.GetRegistryByName(ctx, name, opts...)
+
+	return res, end(err)
 }
 
-func (c *RESTClient) ListRegistries(ctx context.Context) ([]*modelv2.Registry, error) {
-	return c.registry.ListRegistries(ctx)
+func (c *RESTClient) ListRegistries(ctx context.Context, opts ...config.CallOption) ([]*modelv2.Registry, error) {
+	ctx, end := c.telemetry.Start(ctx, "registry.ListRegistries")
+	res, err := c.registry.ListRegistries(ctx, opts...)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) DeleteRegistryByID(ctx context.Context, id int64) error {
-	return c.registry.DeleteRegistryByID(ctx, id)
+	ctx, end := c.telemetry.Start(ctx, "registry.DeleteRegistryByID")
+
+	return end(c.registry.DeleteRegistryByID(ctx, id))
 }
 
 func (c *RESTClient) UpdateRegistry(ctx context.Context, u *modelv2.RegistryUpdate, id int64) error {
-	return c.registry.UpdateRegistry(ctx, u, id)
+	ctx, end := c.telemetry.Start(ctx, "registry.UpdateRegistry")
+
+	return end(c.registry.UpdateRegistry(ctx, u, id))
 }
 
 // Replication Client
@@ -476,239 +872,463 @@ func (c *RESTClient) NewReplicationPolicy(ctx context.Context, destRegistry, src
 	filters []*modelv2.ReplicationFilter, trigger *modelv2.ReplicationTrigger,
 	destNamespace, description, name string,
 ) error {
-	return c.replication.NewReplicationPolicy(ctx, destRegistry, srcRegistry,
+	ctx, end := c.telemetry.Start(ctx, "replication.NewReplicationPolicy")
+
+	return end(c.replication.NewReplicationPolicy(ctx, destRegistry, srcRegistry,
 		replicateDeletion, override, enablePolicy,
-		filters, trigger, destNamespace, description, name)
+		filters, trigger, destNamespace, description, name))
 }
 
-func (c *RESTClient) GetReplicationPolicyByName(ctx context.Context, name string) (*modelv2.ReplicationPolicy, error) {
-	return c.replication.GetReplicationPolicyByName(ctx, name)
+func (c *RESTClient) GetReplicationPolicyByName(ctx context.Context, name string, opts ...config.CallOption) (*modelv2.ReplicationPolicy, error) {
+	ctx, end := c.telemetry.Start(ctx, "replication.GetReplicationPolicyByName")
+	res, err := c.replication.GetReplicationPolicyByName(ctx, name, opts...)
+
+	return res, end(err)
 }
 
-func (c *RESTClient) ListReplicationPolicies(ctx context.Context) ([]*modelv2.ReplicationPolicy, error) {
-	return c.replication.ListReplicationPolicies(ctx)
+func (c *RESTClient) ListReplicationPolicies(ctx context.Context, opts ...config.CallOption) ([]*modelv2.ReplicationPolicy, error) {
+	ctx, end := c.telemetry.Start(ctx, "replication.ListReplicationPolicies")
+	res, err := c.replication.ListReplicationPolicies(ctx, opts...)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) GetReplicationPolicyByID(ctx context.Context, id int64) (*modelv2.ReplicationPolicy, error) {
-	return c.replication.GetReplicationPolicyByID(ctx, id)
+	ctx, end := c.telemetry.Start(ctx, "replication.GetReplicationPolicyByID")
+	res, err := c.replication.GetReplicationPolicyByID(ctx, id)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) DeleteReplicationPolicyByID(ctx context.Context, id int64) error {
-	return c.replication.DeleteReplicationPolicyByID(ctx, id)
+	ctx, end := c.telemetry.Start(ctx, "replication.DeleteReplicationPolicyByID")
+
+	return end(c.replication.DeleteReplicationPolicyByID(ctx, id))
 }
 
 func (c *RESTClient) UpdateReplicationPolicy(ctx context.Context, r *modelv2.ReplicationPolicy, id int64) error {
-	return c.replication.UpdateReplicationPolicy(ctx, r, id)
+	ctx, end := c.telemetry.Start(ctx, "replication.UpdateReplicationPolicy")
+
+	return end(c.replication.UpdateReplicationPolicy(ctx, r, id))
 }
 
 func (c *RESTClient) TriggerReplicationExecution(ctx context.Context, r *modelv2.StartReplicationExecution) error {
-	return c.replication.TriggerReplicationExecution(ctx, r)
+	ctx, end := c.telemetry.Start(ctx, "replication.TriggerReplicationExecution")
+
+	return end(c.replication.TriggerReplicationExecution(ctx, r))
 }
 
-func (c *RESTClient) ListReplicationExecutions(ctx context.Context, policyID *int64, status, trigger *string) ([]*modelv2.ReplicationExecution, error) {
-	return c.replication.ListReplicationExecutions(ctx, policyID, status, trigger)
+func (c *RESTClient) ListReplicationExecutions(ctx context.Context, policyID *int64, status, trigger *string, opts ...config.CallOption) ([]*modelv2.ReplicationExecution, error) {
+	ctx, end := c.telemetry.Start(ctx, "replication.ListReplicationExecutions")
+	res, err := c.replication.ListReplicationExecutions(ctx, policyID, status, trigger, opts...)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) GetReplicationExecutionByID(ctx context.Context, id int64) (*modelv2.ReplicationExecution, error) {
-	return c.replication.GetReplicationExecutionByID(ctx, id)
+	ctx, end := c.telemetry.Start(ctx, "replication.GetReplicationExecutionByID")
+	res, err := c.replication.GetReplicationExecutionByID(ctx, id)
+
+	return res, end(err)
 }
 
 // Repository Client
 
 func (c *RESTClient) GetRepository(ctx context.Context, projectName, repositoryName string) (*modelv2.Repository, error) {
-	return c.repository.GetRepository(ctx, projectName, repositoryName)
+	ctx, end := c.telemetry.Start(ctx, "repository.GetRepository", telemetry.Project(projectName), telemetry.Repository(repositoryName))
+	res, err := c.repository.GetRepository(ctx, projectName, repositoryName)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) UpdateRepository(ctx context.Context, projectName, repositoryName string, update *modelv2.Repository) error {
-	return c.repository.UpdateRepository(ctx, projectName, repositoryName, update)
+	ctx, end := c.telemetry.Start(ctx, "repository.UpdateRepository", telemetry.Project(projectName), telemetry.Repository(repositoryName))
+
+	return end(c.repository.UpdateRepository(ctx, projectName, repositoryName, update))
 }
 
-func (c *RESTClient) ListAllRepositories(ctx context.Context) ([]*modelv2.Repository, error) {
-	return c.repository.ListAllRepositories(ctx)
+func (c *RESTClient) ListAllRepositories(ctx context.Context, opts ...config.CallOption) ([]*modelv2.Repository, error) {
+	ctx, end := c.telemetry.Start(ctx, "repository.ListAllRepositories")
+	res, err := c.repository.ListAllRepositories(ctx, opts...)
+
+	return res, end(err)
 }
 
-func (c *RESTClient) ListRepositories(ctx context.Context, projectName string) ([]*modelv2.Repository, error) {
-	return c.repository.ListRepositories(ctx, projectName)
+func (c *RESTClient) ListRepositories(ctx context.Context, projectName string, opts ...config.CallOption) ([]*modelv2.Repository, error) {
+	ctx, end := c.telemetry.Start(ctx, "repository.ListRepositories", telemetry.Project(projectName))
+	res, err := c.repository.ListRepositories(ctx, projectName, opts...)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) DeleteRepository(ctx context.Context, projectName, repositoryName string) error {
-	return c.repository.DeleteRepository(ctx, projectName, repositoryName)
+	ctx, end := c.telemetry.Start(ctx, "repository.DeleteRepository", telemetry.Project(projectName), telemetry.Repository(repositoryName))
+
+	return end(c.repository.DeleteRepository(ctx, projectName, repositoryName))
+}
+
+func (c *RESTClient) GetRepositoryByReference(ctx context.Context, ref *imageref.Reference) (*modelv2.Repository, error) {
+	ctx, end := c.telemetry.Start(ctx, "repository.GetRepositoryByReference", telemetry.ArtifactReference(ref)...)
+	res, err := c.repository.GetRepositoryByReference(ctx, ref)
+
+	return res, end(err)
+}
+
+func (c *RESTClient) DeleteRepositoryByReference(ctx context.Context, ref *imageref.Reference) error {
+	ctx, end := c.telemetry.Start(ctx, "repository.DeleteRepositoryByReference", telemetry.ArtifactReference(ref)...)
+
+	return end(c.repository.DeleteRepositoryByReference(ctx, ref))
 }
 
 // Retention Client
 
 func (c *RESTClient) NewRetentionPolicy(ctx context.Context, ret *modelv2.RetentionPolicy) error {
-	return c.retention.NewRetentionPolicy(ctx, ret)
+	ctx, end := c.telemetry.Start(ctx, "retention.NewRetentionPolicy")
+
+	return end(c.retention.NewRetentionPolicy(ctx, ret))
 }
 
 func (c *RESTClient) GetRetentionPolicyByProject(ctx context.Context, projectNameOrID string) (*modelv2.RetentionPolicy, error) {
-	return c.retention.GetRetentionPolicyByProject(ctx, projectNameOrID)
+	ctx, end := c.telemetry.Start(ctx, "retention.GetRetentionPolicyByProject", telemetry.Project(projectNameOrID))
+	res, err := c.retention.GetRetentionPolicyByProject(ctx, projectNameOrID)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) GetRetentionPolicyByID(ctx context.Context, id int64) (*modelv2.RetentionPolicy, error) {
-	return c.retention.GetRetentionPolicyByID(ctx, id)
+	ctx, end := c.telemetry.Start(ctx, "retention.GetRetentionPolicyByID")
+	res, err := c.retention.GetRetentionPolicyByID(ctx, id)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) DeleteRetentionPolicyByID(ctx context.Context, id int64) error {
-	return c.retention.DeleteRetentionPolicyByID(ctx, id)
+	ctx, end := c.telemetry.Start(ctx, "retention.DeleteRetentionPolicyByID")
+
+	return end(c.retention.DeleteRetentionPolicyByID(ctx, id))
 }
 
 func (c *RESTClient) UpdateRetentionPolicy(ctx context.Context, ret *modelv2.RetentionPolicy) error {
-	return c.retention.UpdateRetentionPolicy(ctx, ret)
+	ctx, end := c.telemetry.Start(ctx, "retention.UpdateRetentionPolicy")
+
+	return end(c.retention.UpdateRetentionPolicy(ctx, ret))
 }
 
 // Robot Client
 
-func (c *RESTClient) ListRobotAccounts(ctx context.Context) ([]*modelv2.Robot, error) {
-	return c.robot.ListRobotAccounts(ctx)
+func (c *RESTClient) ListRobotAccounts(ctx context.Context, opts ...config.CallOption) ([]*modelv2.Robot, error) {
+	ctx, end := c.telemetry.Start(ctx, "robot.ListRobotAccounts")
+
+	if err := c.capabilities.Require(ctx, capabilities.FeatureRobot); err != nil {
+		return nil, end(err)
+	}
+
+	res, err := c.robot.ListRobotAccounts(ctx, opts...)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) GetRobotAccountByName(ctx context.Context, name string) (*modelv2.Robot, error) {
-	return c.robot.GetRobotAccountByName(ctx, name)
+	ctx, end := c.telemetry.Start(ctx, "robot.GetRobotAccountByName")
+
+	if err := c.capabilities.Require(ctx, capabilities.FeatureRobot); err != nil {
+		return nil, end(err)
+	}
+
+	res, err := c.robot.GetRobotAccountByName(ctx, name)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) GetRobotAccountByID(ctx context.Context, id int64) (*modelv2.Robot, error) {
-	return c.robot.GetRobotAccountByID(ctx, id)
+	ctx, end := c.telemetry.Start(ctx, "robot.GetRobotAccountByID")
+
+	if err := c.capabilities.Require(ctx, capabilities.FeatureRobot); err != nil {
+		return nil, end(err)
+	}
+
+	res, err := c.robot.GetRobotAccountByID(ctx, id)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) NewRobotAccount(ctx context.Context, r *modelv2.RobotCreate) (*modelv2.RobotCreated, error) {
-	return c.robot.NewRobotAccount(ctx, r)
+	ctx, end := c.telemetry.Start(ctx, "robot.NewRobotAccount")
+
+	if err := c.capabilities.Require(ctx, capabilities.FeatureRobot); err != nil {
+		return nil, end(err)
+	}
+
+	res, err := c.robot.NewRobotAccount(ctx, r)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) DeleteRobotAccountByName(ctx context.Context, name string) error {
-	return c.robot.DeleteRobotAccountByName(ctx, name)
+	ctx, end := c.telemetry.Start(ctx, "robot.DeleteRobotAccountByName")
+
+	if err := c.capabilities.Require(ctx, capabilities.FeatureRobot); err != nil {
+		return end(err)
+	}
+
+	return end(c.robot.DeleteRobotAccountByName(ctx, name))
 }
 
 func (c *RESTClient) DeleteRobotAccountByID(ctx context.Context, id int64) error {
-	return c.robot.DeleteRobotAccountByID(ctx, id)
+	ctx, end := c.telemetry.Start(ctx, "robot.DeleteRobotAccountByID")
+
+	if err := c.capabilities.Require(ctx, capabilities.FeatureRobot); err != nil {
+		return end(err)
+	}
+
+	return end(c.robot.DeleteRobotAccountByID(ctx, id))
 }
 
 func (c *RESTClient) UpdateRobotAccount(ctx context.Context, r *modelv2.Robot) error {
-	return c.robot.UpdateRobotAccount(ctx, r)
+	ctx, end := c.telemetry.Start(ctx, "robot.UpdateRobotAccount")
+
+	if err := c.capabilities.Require(ctx, capabilities.FeatureRobot); err != nil {
+		return end(err)
+	}
+
+	return end(c.robot.UpdateRobotAccount(ctx, r))
 }
 
 func (c *RESTClient) RefreshRobotAccountSecretByID(ctx context.Context, id int64, sec string) (*modelv2.RobotSec, error) {
-	return c.robot.RefreshRobotAccountSecretByID(ctx, id, sec)
+	ctx, end := c.telemetry.Start(ctx, "robot.RefreshRobotAccountSecretByID")
+
+	if err := c.capabilities.Require(ctx, capabilities.FeatureRobot); err != nil {
+		return nil, end(err)
+	}
+
+	res, err := c.robot.RefreshRobotAccountSecretByID(ctx, id, sec)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) RefreshRobotAccountSecretByName(ctx context.Context, name string, sec string) (*modelv2.RobotSec, error) {
-	return c.robot.RefreshRobotAccountSecretByName(ctx, name, sec)
+	ctx, end := c.telemetry.Start(ctx, "robot.RefreshRobotAccountSecretByName")
+
+	if err := c.capabilities.Require(ctx, capabilities.FeatureRobot); err != nil {
+		return nil, end(err)
+	}
+
+	res, err := c.robot.RefreshRobotAccountSecretByName(ctx, name, sec)
+
+	return res, end(err)
 }
 
 // RobotV1 Client
 
-func (c *RESTClient) ListProjectRobotsV1(ctx context.Context, projectNameOrID string) ([]*modelv2.Robot, error) {
-	return c.robotv1.ListProjectRobotsV1(ctx, projectNameOrID)
+func (c *RESTClient) ListProjectRobotsV1(ctx context.Context, projectNameOrID string, opts ...config.CallOption) ([]*modelv2.Robot, error) {
+	ctx, end := c.telemetry.Start(ctx, "robotv1.ListProjectRobotsV1", telemetry.Project(projectNameOrID))
+
+	if err := c.capabilities.Require(ctx, capabilities.FeatureRobotV1); err != nil {
+		return nil, end(err)
+	}
+
+	res, err := c.robotv1.ListProjectRobotsV1(ctx, projectNameOrID, opts...)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) AddProjectRobotV1(ctx context.Context, projectNameOrID string, r *modelv2.RobotCreateV1) error {
-	return c.robotv1.AddProjectRobotV1(ctx, projectNameOrID, r)
+	ctx, end := c.telemetry.Start(ctx, "robotv1.AddProjectRobotV1", telemetry.Project(projectNameOrID))
+
+	if err := c.capabilities.Require(ctx, capabilities.FeatureRobotV1); err != nil {
+		return end(err)
+	}
+
+	return end(c.robotv1.AddProjectRobotV1(ctx, projectNameOrID, r))
 }
 
 func (c *RESTClient) UpdateProjectRobotV1(ctx context.Context, projectNameOrID string, robotID int64, r *modelv2.Robot) error {
-	return c.robotv1.UpdateProjectRobotV1(ctx, projectNameOrID, robotID, r)
+	ctx, end := c.telemetry.Start(ctx, "robotv1.UpdateProjectRobotV1", telemetry.Project(projectNameOrID))
+
+	if err := c.capabilities.Require(ctx, capabilities.FeatureRobotV1); err != nil {
+		return end(err)
+	}
+
+	return end(c.robotv1.UpdateProjectRobotV1(ctx, projectNameOrID, robotID, r))
 }
 
 func (c *RESTClient) DeleteProjectRobotV1(ctx context.Context, projectNameOrID string, robotID int64) error {
-	return c.robotv1.DeleteProjectRobotV1(ctx, projectNameOrID, robotID)
+	ctx, end := c.telemetry.Start(ctx, "robotv1.DeleteProjectRobotV1", telemetry.Project(projectNameOrID))
+
+	if err := c.capabilities.Require(ctx, capabilities.FeatureRobotV1); err != nil {
+		return end(err)
+	}
+
+	return end(c.robotv1.DeleteProjectRobotV1(ctx, projectNameOrID, robotID))
 }
 
 // Statistic Client
 
 func (c *RESTClient) GetStatistic(ctx context.Context) (*modelv2.Statistic, error) {
-	return c.statistic.GetStatistic(ctx)
+	ctx, end := c.telemetry.Start(ctx, "statistic.GetStatistic")
+	res, err := c.statistic.GetStatistic(ctx)
+
+	return res, end(err)
 }
 
 // Scanall Client
 
 func (c *RESTClient) CreateScanAllSchedule(ctx context.Context, schedule *modelv2.Schedule) error {
-	return c.scanall.CreateScanAllSchedule(ctx, schedule)
+	ctx, end := c.telemetry.Start(ctx, "scanall.CreateScanAllSchedule")
+
+	return end(c.scanall.CreateScanAllSchedule(ctx, schedule))
 }
 
 func (c *RESTClient) GetScanAllSchedule(ctx context.Context) (*modelv2.Schedule, error) {
-	return c.scanall.GetScanAllSchedule(ctx)
+	ctx, end := c.telemetry.Start(ctx, "scanall.GetScanAllSchedule")
+	res, err := c.scanall.GetScanAllSchedule(ctx)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) UpdateScanAllSchedule(ctx context.Context, schedule *modelv2.Schedule) error {
-	return c.scanall.UpdateScanAllSchedule(ctx, schedule)
+	ctx, end := c.telemetry.Start(ctx, "scanall.UpdateScanAllSchedule")
+
+	return end(c.scanall.UpdateScanAllSchedule(ctx, schedule))
+}
+
+// Capabilities returns the features provided by the Harbor server, based on the version reported by
+// GetSystemInfo. The version is requested on first use, i.e. on the first call of Capabilities or of an
+// operation depending on a feature not provided by all Harbor versions, and cached afterwards.
+// Such operations return an errors.ErrUnsupportedServerVersion instead of sending a request
+// if the server does not provide the feature. If the version cannot be requested, they are sent anyway.
+func (c *RESTClient) Capabilities(ctx context.Context) (*capabilities.Capabilities, error) {
+	return c.capabilities.Capabilities(ctx)
 }
 
 // Systeminfo Client
 
 func (c *RESTClient) GetSystemInfo(ctx context.Context) (*modelv2.GeneralInfo, error) {
-	return c.systeminfo.GetSystemInfo(ctx)
+	ctx, end := c.telemetry.Start(ctx, "systeminfo.GetSystemInfo")
+	res, err := c.systeminfo.GetSystemInfo(ctx)
+
+	return res, end(err)
 }
 
 // User Client
 
 func (c *RESTClient) NewUser(ctx context.Context, username, email, realname, password, comments string) error {
-	return c.user.NewUser(ctx, username, email, realname, password, comments)
+	ctx, end := c.telemetry.Start(ctx, "user.NewUser")
+
+	return end(c.user.NewUser(ctx, username, email, realname, password, comments))
 }
 
-func (c *RESTClient) GetUserByName(ctx context.Context, username string) (*modelv2.UserResp, error) {
-	return c.user.GetUserByName(ctx, username)
+func (c *RESTClient) GetUserByName(ctx context.Context, username string, opts ...config.CallOption) (*modelv2.UserResp, error) {
+	ctx, end := c.telemetry.Start(ctx, "user.GetUserByName")
+	res, err := c.user.GetUserByName(ctx, username, opts...)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) GetUserByID(ctx context.Context, id int64) (*modelv2.UserResp, error) {
-	return c.user.GetUserByID(ctx, id)
+	ctx, end := c.telemetry.Start(ctx, "user.GetUserByID")
+	res, err := c.user.GetUserByID(ctx, id)
+
+	return res, end(err)
 }
 
-func (c *RESTClient) ListUsers(ctx context.Context) ([]*modelv2.UserResp, error) {
-	return c.user.ListUsers(ctx)
+func (c *RESTClient) ListUsers(ctx context.Context, opts ...config.CallOption) ([]*modelv2.UserResp, error) {
+	ctx, end := c.telemetry.Start(ctx, "user.ListUsers")
+	res, err := c.user.ListUsers(ctx, opts...)
+
+	return res, end(err)
 }
 
-func (c *RESTClient) SearchUsers(ctx context.Context, name string) ([]*modelv2.UserSearchRespItem, error) {
-	return c.user.SearchUsers(ctx, name)
+func (c *RESTClient) SearchUsers(ctx context.Context, name string, opts ...config.CallOption) ([]*modelv2.UserSearchRespItem, error) {
+	ctx, end := c.telemetry.Start(ctx, "user.SearchUsers")
+	res, err := c.user.SearchUsers(ctx, name, opts...)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) GetCurrentUserInfo(ctx context.Context) (*modelv2.UserResp, error) {
-	return c.user.GetCurrentUserInfo(ctx)
+	ctx, end := c.telemetry.Start(ctx, "user.GetCurrentUserInfo")
+	res, err := c.user.GetCurrentUserInfo(ctx)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) GetCurrentUserPermisisons(ctx context.Context, relative bool, scope string) ([]*modelv2.Permission, error) {
-	return c.user.GetCurrentUserPermisisons(ctx, relative, scope)
+	ctx, end := c.telemetry.Start(ctx, "user.GetCurrentUserPermisisons")
+	res, err := c.user.GetCurrentUserPermisisons(ctx, relative, scope)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) SetUserSysAdmin(ctx context.Context, id int64, admin bool) error {
-	return c.user.SetUserSysAdmin(ctx, id, admin)
+	ctx, end := c.telemetry.Start(ctx, "user.SetUserSysAdmin")
+
+	return end(c.user.SetUserSysAdmin(ctx, id, admin))
 }
 
 func (c *RESTClient) DeleteUser(ctx context.Context, id int64) error {
-	return c.user.DeleteUser(ctx, id)
+	ctx, end := c.telemetry.Start(ctx, "user.DeleteUser")
+
+	return end(c.user.DeleteUser(ctx, id))
 }
 
 func (c *RESTClient) UpdateUserProfile(ctx context.Context, id int64, profile *modelv2.UserProfile) error {
-	return c.user.UpdateUserProfile(ctx, id, profile)
+	ctx, end := c.telemetry.Start(ctx, "user.UpdateUserProfile")
+
+	return end(c.user.UpdateUserProfile(ctx, id, profile))
 }
 
 func (c *RESTClient) UpdateUserPassword(ctx context.Context, userID int64, passwordRequest *modelv2.PasswordReq) error {
-	return c.user.UpdateUserPassword(ctx, userID, passwordRequest)
+	ctx, end := c.telemetry.Start(ctx, "user.UpdateUserPassword")
+
+	return end(c.user.UpdateUserPassword(ctx, userID, passwordRequest))
+}
+
+func (c *RESTClient) SetCLISecret(ctx context.Context, userID int64, secret string) error {
+	ctx, end := c.telemetry.Start(ctx, "user.SetCLISecret")
+
+	return end(c.user.SetCLISecret(ctx, userID, secret))
 }
 
 func (c *RESTClient) UserExists(ctx context.Context, idOrName intstr.IntOrString) (bool, error) {
-	return c.user.UserExists(ctx, idOrName)
+	ctx, end := c.telemetry.Start(ctx, "user.UserExists")
+	res, err := c.user.UserExists(ctx, idOrName)
+
+	return res, end(err)
 }
 
 // Webhook Client
 
-func (c *RESTClient) ListProjectWebhookPolicies(ctx context.Context, projectID int) ([]*modelv2.WebhookPolicy, error) {
-	return c.webhook.ListProjectWebhookPolicies(ctx, projectID)
+func (c *RESTClient) ListProjectWebhookPolicies(ctx context.Context, projectID int, opts ...config.CallOption) ([]*modelv2.WebhookPolicy, error) {
+	ctx, end := c.telemetry.Start(ctx, "webhook.ListProjectWebhookPolicies", telemetry.ProjectID(int64(projectID)))
+	res, err := c.webhook.ListProjectWebhookPolicies(ctx, projectID, opts...)
+
+	return res, end(err)
 }
 
 func (c *RESTClient) AddProjectWebhookPolicy(ctx context.Context, projectID int, policy *modelv2.WebhookPolicy) error {
-	return c.webhook.AddProjectWebhookPolicy(ctx, projectID, policy)
+	ctx, end := c.telemetry.Start(ctx, "webhook.AddProjectWebhookPolicy", telemetry.ProjectID(int64(projectID)))
+
+	return end(c.webhook.AddProjectWebhookPolicy(ctx, projectID, policy))
 }
 
 func (c *RESTClient) UpdateProjectWebhookPolicy(ctx context.Context, projectID int, policy *modelv2.WebhookPolicy) error {
-	return c.webhook.UpdateProjectWebhookPolicy(ctx, projectID, policy)
+	ctx, end := c.telemetry.Start(ctx, "webhook.UpdateProjectWebhookPolicy", telemetry.ProjectID(int64(projectID)))
+
+	return end(c.webhook.UpdateProjectWebhookPolicy(ctx, projectID, policy))
 }
 
 func (c *RESTClient) DeleteProjectWebhookPolicy(ctx context.Context, projectID int, policyID int64) error {
-	return c.webhook.DeleteProjectWebhookPolicy(ctx, projectID, policyID)
+	ctx, end := c.telemetry.Start(ctx, "webhook.DeleteProjectWebhookPolicy", telemetry.ProjectID(int64(projectID)))
+
+	return end(c.webhook.DeleteProjectWebhookPolicy(ctx, projectID, policyID))
 }
 
 // Ping Client
 
 func (c *RESTClient) GetPing(ctx context.Context) (string, error) {
-	return c.ping.GetPing(ctx)
+	ctx, end := c.telemetry.Start(ctx, "ping.GetPing")
+	res, err := c.ping.GetPing(ctx)
+
+	return res, end(err)
 }
@@ -4,7 +4,9 @@ package apiv2
 
 import (
 	"context"
+	"errors"
 	"net/url"
+	"time"
 
 	"github.com/go-openapi/runtime"
 
@@ -14,7 +16,10 @@ import (
 	"github.com/go-openapi/strfmt"
 
 	v2client "github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/auth"
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/retry"
 )
 
 var (
@@ -140,6 +145,106 @@ func ExampleNewRESTClient_withOptions() {
 	}
 }
 
+func ExampleNewRESTClientForHost_withRetries() {
+	// This example constructs a new goharbor-client retrying idempotent requests
+	// that failed due to transient errors, which fails fast after 5 consecutive failures.
+	apiURL := "harbor.mydomain.com/api"
+	username := "user"
+	password := "password"
+
+	options := config.Defaults().
+		WithRetry(retry.DefaultPolicy().WithMaxRetries(5)).
+		WithCircuitBreaker(retry.NewCircuitBreaker(5, 30*time.Second))
+
+	harborClient, err := NewRESTClientForHost(apiURL, username, password, options)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = harborClient.ListProjects(ctx, "")
+	if errors.As(err, new(*clienterrors.ErrCircuitOpen)) {
+		// Harbor is considered unavailable, try again later.
+		return
+	}
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func ExampleNew() {
+	// This example constructs a new goharbor-client for a Harbor instance using a private CA,
+	// which is reached via a proxy, and lists all projects.
+	harborClient, err := New("https://harbor.mydomain.com/api",
+		WithBasicAuth("user", "password"),
+		WithCAFile("/etc/ssl/certs/my-ca.pem"),
+		WithProxyURL("http://proxy.mydomain.com:3128"),
+		WithUserAgent("my-operator/1.0"),
+		WithOptions(config.Defaults().WithPageSize(100)))
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = harborClient.ListProjects(ctx, "")
+	if err != nil {
+		panic(err)
+	}
+}
+
+func ExampleNewRESTClientWithAuth() {
+	// This example constructs a new goharbor-client authenticating as a robot account,
+	// and one using a Harbor UI session, which logs in on first use.
+	apiURL := "harbor.mydomain.com/api"
+
+	robotClient, err := NewRESTClientWithAuth(apiURL, auth.Robot("robot$my-project+ci", "secret"), nil)
+	if err != nil {
+		panic(err)
+	}
+
+	if _, err = robotClient.ListRepositories(ctx, "my-project"); err != nil {
+		panic(err)
+	}
+
+	session, err := auth.NewSession(apiURL, "user", "password", nil)
+	if err != nil {
+		panic(err)
+	}
+
+	defer session.Logout(ctx)
+
+	sessionClient, err := NewRESTClientWithAuth(apiURL, session, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	if _, err = sessionClient.ListProjects(ctx, ""); err != nil {
+		panic(err)
+	}
+}
+
+func ExampleRESTClient_Capabilities() {
+	// This example only schedules purging audit logs if the Harbor instance supports it.
+	caps, err := harborClient.Capabilities(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	if caps.Purge() {
+		err = harborClient.UpdatePurgeSchedule(ctx, &model.Schedule{
+			Schedule: &model.ScheduleObj{Type: "Daily"},
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	// Operations depending on a feature the server does not provide are not sent.
+	_, err = harborClient.ListProjectRobotsV1(ctx, "library")
+	if errors.Is(err, &clienterrors.ErrUnsupportedServerVersion{}) {
+		panic("Harbor version does not support robot v1 accounts")
+	}
+}
+
 func ExampleRESTClient_NewUser() {
 	err := harborClient.NewUser(ctx, "test-user", "foo@example.com", "test user", "password", "a test user")
 	if err != nil {
@@ -0,0 +1,211 @@
+//go:build !integration
+
+package apiv2_test
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	runtimeclient "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2"
+	v2client "github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/retry"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/testing/fakeharbor"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// recordingHandler serves the fake Harbor, recording the requests it receives.
+func recordingHandler(fake *fakeharbor.Server) (http.Handler, func() []*http.Request) {
+	var (
+		mu       sync.Mutex
+		requests []*http.Request
+	)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Clone(context.Background()))
+		mu.Unlock()
+
+		fake.ServeHTTP(w, r)
+	})
+
+	return handler, func() []*http.Request {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return requests
+	}
+}
+
+func TestNew_UserAgentAndMiddleware(t *testing.T) {
+	fake := fakeharbor.NewServer()
+	defer fake.Close()
+
+	handler, requests := recordingHandler(fake)
+
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	var order []string
+
+	middleware := func(name string) func(http.RoundTripper) http.RoundTripper {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				req.Header.Add("X-Middleware", name)
+
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	c, err := apiv2.New(srv.URL+"/api",
+		apiv2.WithBasicAuth(fakeharbor.AdminUser, fakeharbor.AdminPassword),
+		apiv2.WithUserAgent("harbor-operator/1.0"),
+		apiv2.WithMiddleware(middleware("outer"), middleware("inner")))
+	require.NoError(t, err)
+
+	_, err = c.GetProject(context.Background(), "library")
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"outer", "inner"}, order)
+	require.Len(t, requests(), 1)
+	require.Equal(t, "harbor-operator/1.0", requests()[0].UserAgent())
+	require.Equal(t, []string{"outer", "inner"}, requests()[0].Header.Values("X-Middleware"))
+}
+
+func TestNew_TLS(t *testing.T) {
+	fake := fakeharbor.NewServer()
+	defer fake.Close()
+
+	srv := httptest.NewTLSServer(fake)
+	defer srv.Close()
+
+	get := func(opts ...apiv2.Option) error {
+		opts = append(opts, apiv2.WithBasicAuth(fakeharbor.AdminUser, fakeharbor.AdminPassword))
+
+		c, err := apiv2.New(srv.URL+"/api", opts...)
+		require.NoError(t, err)
+
+		_, err = c.GetProject(context.Background(), "library")
+
+		return err
+	}
+
+	require.ErrorContains(t, get(), "certificate")
+
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	require.NoError(t, get(apiv2.WithCACerts(ca)))
+
+	require.NoError(t, get(apiv2.WithInsecureSkipVerify()))
+
+	_, err := apiv2.New(srv.URL, apiv2.WithCACerts([]byte("invalid")))
+	require.Error(t, err)
+
+	require.NoError(t, get(apiv2.WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}), apiv2.WithCACerts(ca)))
+
+	_, err = apiv2.New(srv.URL, apiv2.WithCACerts(ca), apiv2.WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}))
+	require.ErrorContains(t, err, "must precede")
+}
+
+func TestNew_Proxy(t *testing.T) {
+	fake := fakeharbor.NewServer()
+	defer fake.Close()
+
+	handler, requests := recordingHandler(fake)
+
+	proxy := httptest.NewServer(handler)
+	defer proxy.Close()
+
+	// The host can only be reached via the proxy.
+	c, err := apiv2.New("http://harbor.invalid/api",
+		apiv2.WithBasicAuth(fakeharbor.AdminUser, fakeharbor.AdminPassword),
+		apiv2.WithProxyURL(proxy.URL))
+	require.NoError(t, err)
+
+	_, err = c.GetProject(context.Background(), "library")
+	require.NoError(t, err)
+
+	require.Len(t, requests(), 1)
+	require.Equal(t, "harbor.invalid", requests()[0].Host)
+}
+
+func TestNew_TransportOptions(t *testing.T) {
+	custom := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, http.ErrNotSupported
+	})
+
+	_, err := apiv2.New("https://harbor.example.com/api", apiv2.WithTransport(custom), apiv2.WithInsecureSkipVerify())
+	require.ErrorContains(t, err, "require a *http.Transport")
+
+	c, err := apiv2.New("https://harbor.example.com/api", apiv2.WithTransport(custom))
+	require.NoError(t, err)
+
+	_, err = c.GetProject(context.Background(), "library")
+	require.ErrorIs(t, err, http.ErrNotSupported)
+}
+
+func TestNewRESTClient_RejectsRetry(t *testing.T) {
+	fake := fakeharbor.NewServer()
+	defer fake.Close()
+
+	u, err := url.Parse(fake.URL + "/api/v2.0")
+	require.NoError(t, err)
+
+	v2Client := v2client.New(runtimeclient.New(u.Host, u.Path, []string{u.Scheme}), strfmt.Default)
+	authInfo := runtimeclient.BasicAuth(fakeharbor.AdminUser, fakeharbor.AdminPassword)
+
+	c := apiv2.NewRESTClient(v2Client, config.Defaults(), authInfo)
+
+	_, err = c.GetProject(context.Background(), "library")
+	require.NoError(t, err)
+
+	c = apiv2.NewRESTClient(v2Client, config.Defaults().WithRetry(retry.DefaultPolicy()), authInfo)
+
+	_, err = c.GetProject(context.Background(), "library")
+	require.ErrorAs(t, err, new(*clienterrors.ErrRetryUnsupported))
+
+	// The misconfiguration is reported on construction.
+	_, err = apiv2.NewRESTClientFromSwagger(v2Client, config.Defaults().WithRetry(retry.DefaultPolicy()), authInfo)
+	require.ErrorAs(t, err, new(*clienterrors.ErrRetryUnsupported))
+
+	_, err = apiv2.NewRESTClientFromSwagger(v2Client, config.Defaults().WithCircuitBreaker(retry.NewCircuitBreaker(5, time.Second)), authInfo)
+	require.ErrorAs(t, err, new(*clienterrors.ErrRetryUnsupported))
+
+	c, err = apiv2.NewRESTClientFromSwagger(v2Client, config.Defaults(), authInfo)
+	require.NoError(t, err)
+
+	_, err = c.GetProject(context.Background(), "library")
+	require.NoError(t, err)
+
+	c, err = apiv2.New(fake.URL+"/api", apiv2.WithBasicAuth(fakeharbor.AdminUser, fakeharbor.AdminPassword),
+		apiv2.WithOptions(config.Defaults().WithRetry(retry.DefaultPolicy())))
+	require.NoError(t, err)
+
+	_, err = c.GetProject(context.Background(), "library")
+	require.NoError(t, err)
+
+	c, err = apiv2.NewRESTClientForHost(fake.URL+"/api", fakeharbor.AdminUser, fakeharbor.AdminPassword,
+		config.Defaults().WithRetry(retry.DefaultPolicy()))
+	require.NoError(t, err)
+
+	_, err = c.GetProject(context.Background(), "library")
+	require.NoError(t, err)
+}
@@ -0,0 +1,260 @@
+package apiv2
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/auth"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+)
+
+// Option configures a RESTClient constructed by New.
+type Option func(*settings) error
+
+// settings collects the configuration of a RESTClient constructed by New.
+type settings struct {
+	options    *config.Options
+	auth       runtime.ClientAuthInfoWriter
+	httpClient *http.Client
+	transport  http.RoundTripper
+	middleware []func(http.RoundTripper) http.RoundTripper
+	userAgent  string
+
+	// tlsConfig and proxy are applied to the base transport, which must be a *http.Transport if they are set.
+	tlsConfig *tls.Config
+	proxy     func(*http.Request) (*url.URL, error)
+}
+
+// tls returns the TLS configuration to be applied to the base transport, creating it if necessary.
+func (s *settings) tls() *tls.Config {
+	if s.tlsConfig == nil {
+		s.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	return s.tlsConfig
+}
+
+// WithOptions sets the Options used for client operations. config.Defaults are used if not set.
+func WithOptions(opts *config.Options) Option {
+	return func(s *settings) error {
+		s.options = opts
+		return nil
+	}
+}
+
+// WithBasicAuth authenticates requests using basic auth.
+func WithBasicAuth(username, password string) Option {
+	return WithAuth(auth.Basic(username, password))
+}
+
+// WithAuth authenticates requests using 'provider', e.g. one of the providers of the auth package
+// or a runtime.ClientAuthInfoWriterFunc. Requests are not authenticated if no provider is set.
+func WithAuth(provider auth.Provider) Option {
+	return func(s *settings) error {
+		s.auth = provider
+		return nil
+	}
+}
+
+// WithHTTPClient sends requests using a shallow copy of 'client'.
+// Its transport is used as the base transport, unless one is set using WithTransport.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *settings) error {
+		s.httpClient = client
+		return nil
+	}
+}
+
+// WithTransport sets the base transport requests are sent with, instead of http.DefaultTransport.
+// TLS and proxy options can only be combined with a *http.Transport, which is cloned before applying them.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(s *settings) error {
+		s.transport = transport
+		return nil
+	}
+}
+
+// WithMiddleware wraps the base transport using 'middleware', e.g. for logging or adding headers.
+// The first middleware is the outermost one. Retries wrap all middleware, so each attempt passes through it.
+func WithMiddleware(middleware ...func(http.RoundTripper) http.RoundTripper) Option {
+	return func(s *settings) error {
+		s.middleware = append(s.middleware, middleware...)
+		return nil
+	}
+}
+
+// WithUserAgent sets the 'User-Agent' header of all requests.
+func WithUserAgent(userAgent string) Option {
+	return func(s *settings) error {
+		s.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of the base transport.
+// It is extended by other TLS options, so WithTLSConfig must precede them;
+// an error is returned otherwise, as their settings would be discarded.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(s *settings) error {
+		if s.tlsConfig != nil {
+			return errors.New("WithTLSConfig must precede the other TLS options")
+		}
+
+		s.tlsConfig = cfg.Clone()
+
+		return nil
+	}
+}
+
+// WithCACerts trusts the PEM encoded CA certificates 'pem' in addition to the system's root CAs.
+func WithCACerts(pem []byte) Option {
+	return func(s *settings) error {
+		cfg := s.tls()
+
+		if cfg.RootCAs == nil {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+
+			cfg.RootCAs = pool
+		}
+
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return errors.New("no valid CA certificates found")
+		}
+
+		return nil
+	}
+}
+
+// WithCAFile trusts the PEM encoded CA certificates in the file 'path', e.g. a CA bundle,
+// in addition to the system's root CAs.
+func WithCAFile(path string) Option {
+	return func(s *settings) error {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading CA file: %w", err)
+		}
+
+		return WithCACerts(pem)(s)
+	}
+}
+
+// WithClientCertificate authenticates the client via mTLS using 'cert'.
+func WithClientCertificate(cert tls.Certificate) Option {
+	return func(s *settings) error {
+		cfg := s.tls()
+		cfg.Certificates = append(cfg.Certificates, cert)
+
+		return nil
+	}
+}
+
+// WithClientCertificateFiles authenticates the client via mTLS using the PEM encoded
+// certificate and private key in 'certFile' and 'keyFile'.
+func WithClientCertificateFiles(certFile, keyFile string) Option {
+	return func(s *settings) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("loading client certificate: %w", err)
+		}
+
+		return WithClientCertificate(cert)(s)
+	}
+}
+
+// WithInsecureSkipVerify disables the verification of Harbor's certificate.
+// It should only be used for testing or lab setups, as it makes the connection vulnerable to interception.
+func WithInsecureSkipVerify() Option {
+	return func(s *settings) error {
+		s.tls().InsecureSkipVerify = true
+		return nil
+	}
+}
+
+// WithProxy sends requests via the proxy returned by 'proxy', e.g. http.ProxyURL.
+// By default, the proxy is determined by the environment, see http.ProxyFromEnvironment.
+func WithProxy(proxy func(*http.Request) (*url.URL, error)) Option {
+	return func(s *settings) error {
+		s.proxy = proxy
+		return nil
+	}
+}
+
+// WithProxyURL sends all requests via the proxy at 'proxyURL', e.g. 'http://proxy.example.com:3128'.
+func WithProxyURL(proxyURL string) Option {
+	return func(s *settings) error {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return fmt.Errorf("parsing proxy URL: %w", err)
+		}
+
+		s.proxy = http.ProxyURL(u)
+
+		return nil
+	}
+}
+
+// roundTripper returns the transport requests are sent with, consisting of the base transport
+// and the configured middleware.
+func (s *settings) roundTripper() (http.RoundTripper, error) {
+	base := s.transport
+	if base == nil && s.httpClient != nil {
+		base = s.httpClient.Transport
+	}
+
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if s.tlsConfig != nil || s.proxy != nil {
+		t, ok := base.(*http.Transport)
+		if !ok {
+			return nil, fmt.Errorf("TLS and proxy options require a *http.Transport, got %T", base)
+		}
+
+		t = t.Clone()
+
+		if s.tlsConfig != nil {
+			t.TLSClientConfig = s.tlsConfig
+		}
+
+		if s.proxy != nil {
+			t.Proxy = s.proxy
+		}
+
+		base = t
+	}
+
+	if s.userAgent != "" {
+		base = &userAgentTransport{next: base, userAgent: s.userAgent}
+	}
+
+	for i := len(s.middleware) - 1; i >= 0; i-- {
+		base = s.middleware[i](base)
+	}
+
+	return base, nil
+}
+
+// userAgentTransport sets the 'User-Agent' header of requests.
+type userAgentTransport struct {
+	next      http.RoundTripper
+	userAgent string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+
+	return t.next.RoundTrip(req)
+}
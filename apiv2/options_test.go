@@ -0,0 +1,81 @@
+//go:build !integration
+
+package apiv2_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2"
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/testing/fakeharbor"
+)
+
+func TestCallOptions_Concurrent(t *testing.T) {
+	srv := fakeharbor.NewServer()
+	defer srv.Close()
+
+	opts := config.Defaults().WithPageSize(2)
+
+	c, err := apiv2.NewRESTClientForHost(srv.Host(), fakeharbor.AdminUser, fakeharbor.AdminPassword, opts)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, c.NewProject(ctx, &model.ProjectReq{ProjectName: fmt.Sprintf("project-%d", i)}))
+		require.NoError(t, c.NewRegistry(ctx, &model.Registry{
+			Name: fmt.Sprintf("registry-%d", i),
+			Type: "docker-hub",
+			URL:  "https://hub.docker.com",
+		}))
+	}
+
+	// Changes to the Options after constructing the client do not affect it.
+	opts.WithQuery("name=project-0")
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 5; i++ {
+		i := i
+
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			projects, err := c.ListProjects(ctx, "", config.WithQuery(fmt.Sprintf("name=project-%d", i)))
+			require.NoError(t, err)
+			require.Len(t, projects, 1)
+			require.Equal(t, fmt.Sprintf("project-%d", i), projects[0].Name)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			reg, err := c.GetRegistryByName(ctx, fmt.Sprintf("registry-%d", i))
+			require.NoError(t, err)
+			require.Equal(t, fmt.Sprintf("registry-%d", i), reg.Name)
+		}()
+	}
+
+	wg.Wait()
+
+	// Neither per-call options nor lookups by name leak into subsequent calls.
+	projects, err := c.ListProjects(ctx, "project-")
+	require.NoError(t, err)
+	require.Len(t, projects, 5)
+
+	registries, err := c.ListRegistries(ctx)
+	require.NoError(t, err)
+	require.Len(t, registries, 5)
+
+	registries, err = c.ListRegistries(ctx, config.WithPage(2), config.WithPageSize(4))
+	require.NoError(t, err)
+	require.Len(t, registries, 1)
+}
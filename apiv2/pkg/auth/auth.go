@@ -0,0 +1,60 @@
+// Package auth provides providers authenticating the requests of the Harbor API clients.
+//
+// Each Provider implements runtime.ClientAuthInfoWriter, so it can be passed to apiv2.NewRESTClient
+// or, adapted by Func, to apiv2.NewRESTClientWithAuthFunc. Providers implementing TransportProvider
+// additionally observe the HTTP responses of the client when used with apiv2.NewRESTClientWithAuth,
+// e.g. to refresh expired credentials.
+package auth
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+	runtimeclient "github.com/go-openapi/runtime/client"
+)
+
+// Provider authenticates requests to the Harbor API.
+type Provider interface {
+	runtime.ClientAuthInfoWriter
+}
+
+// TransportProvider is a Provider that needs to observe the HTTP round trips of the client,
+// e.g. to maintain a session or to discard credentials rejected by Harbor.
+type TransportProvider interface {
+	Provider
+
+	// WrapTransport returns a http.RoundTripper observing the round trips of 'next'.
+	WrapTransport(next http.RoundTripper) http.RoundTripper
+}
+
+// Func adapts 'p' to a runtime.ClientAuthInfoWriterFunc.
+func Func(p Provider) runtime.ClientAuthInfoWriterFunc {
+	return p.AuthenticateRequest
+}
+
+type writer struct {
+	runtime.ClientAuthInfoWriter
+}
+
+// Basic returns a Provider authenticating requests using basic auth.
+func Basic(username, password string) Provider {
+	return &writer{runtimeclient.BasicAuth(username, password)}
+}
+
+// Bearer returns a Provider authenticating requests using the static bearer token 'token'.
+func Bearer(token string) Provider {
+	return &writer{runtimeclient.BearerToken(token)}
+}
+
+// Robot returns a Provider authenticating requests as the robot account 'name' using its secret.
+// 'name' is the full name of the robot account as returned by Harbor, e.g. 'robot$project+ci'.
+func Robot(name, secret string) Provider {
+	return Basic(name, secret)
+}
+
+// OIDCCLISecret returns a Provider authenticating requests as the OIDC user 'username' using its CLI secret.
+// OIDC users cannot authenticate against the API using a password. Their CLI secret is shown in the
+// user profile of the Harbor UI and can be set using the SetCLISecret operation of the user client.
+func OIDCCLISecret(username, cliSecret string) Provider {
+	return Basic(username, cliSecret)
+}
@@ -0,0 +1,110 @@
+//go:build !integration
+
+package auth_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2"
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/auth"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/testing/fakeharbor"
+)
+
+var ctx = context.Background()
+
+// recordingServer serves 'handler', recording the 'Authorization' header of each request.
+func recordingServer(t *testing.T, handler http.Handler) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var (
+		mu      sync.Mutex
+		headers []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		headers = append(headers, r.Header.Get("Authorization"))
+		mu.Unlock()
+
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return append([]string(nil), headers...)
+	}
+}
+
+func basic(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+func TestProviders(t *testing.T) {
+	srv, headers := recordingServer(t, http.NotFoundHandler())
+
+	for provider, header := range map[auth.Provider]string{
+		auth.Basic("admin", "Harbor12345"):        basic("admin", "Harbor12345"),
+		auth.Bearer("token"):                      "Bearer token",
+		auth.Robot("robot$library+ci", "secret"):  basic("robot$library+ci", "secret"),
+		auth.OIDCCLISecret("oidc-user", "secret"): basic("oidc-user", "secret"),
+	} {
+		c, err := apiv2.NewRESTClientWithAuth(srv.URL+"/api", provider, config.Defaults())
+		require.NoError(t, err)
+
+		_, _ = c.GetProject(ctx, "library")
+
+		h := headers()
+		require.Equal(t, header, h[len(h)-1])
+	}
+}
+
+func TestFunc(t *testing.T) {
+	srv, headers := recordingServer(t, http.NotFoundHandler())
+
+	c, err := apiv2.NewRESTClientWithAuthFunc(srv.URL+"/api", auth.Func(auth.Bearer("token")), config.Defaults())
+	require.NoError(t, err)
+
+	_, _ = c.GetProject(ctx, "library")
+
+	require.Equal(t, []string{"Bearer token"}, headers())
+}
+
+func TestRobot(t *testing.T) {
+	srv := fakeharbor.NewServer()
+	defer srv.Close()
+
+	admin, err := apiv2.NewRESTClientWithAuth(srv.Host(), auth.Basic(fakeharbor.AdminUser, fakeharbor.AdminPassword), config.Defaults())
+	require.NoError(t, err)
+
+	robot, err := admin.NewRobotAccount(ctx, &model.RobotCreate{
+		Name:     "ci",
+		Level:    "system",
+		Duration: -1,
+	})
+	require.NoError(t, err)
+
+	c, err := apiv2.NewRESTClientWithAuth(srv.Host(), auth.Robot(robot.Name, robot.Secret), config.Defaults())
+	require.NoError(t, err)
+
+	_, err = c.GetProject(ctx, "library")
+	require.NoError(t, err)
+
+	c, err = apiv2.NewRESTClientWithAuth(srv.Host(), auth.Robot(robot.Name, "invalid"), config.Defaults())
+	require.NoError(t, err)
+
+	_, err = c.GetProject(ctx, "library")
+	require.ErrorIs(t, err, &clienterrors.ErrUnauthorized{})
+}
@@ -0,0 +1,333 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+const (
+	// CSRFTokenHeader is the header Harbor sends CSRF tokens in, and expects them
+	// in for requests modifying resources.
+	CSRFTokenHeader = "X-Harbor-CSRF-Token"
+
+	apiPath    = "/api/v2.0"
+	loginPath  = "/c/login"
+	logoutPath = "/c/log_out"
+
+	// defaultLoginTimeout limits login and logout requests if the login client has no timeout.
+	defaultLoginTimeout = 30 * time.Second
+)
+
+// Session is a TransportProvider authenticating requests using a Harbor UI session, like the Harbor UI does.
+// It logs in on first use, or after the session expired, and sends Harbor's CSRF token with requests
+// modifying resources. A Session is safe for concurrent use, concurrent requests share a single login.
+type Session struct {
+	baseURL  *url.URL
+	username string
+	password string
+	client   *http.Client
+
+	mu          sync.Mutex
+	csrfToken   string
+	established bool
+	// pending is the login in progress, if any.
+	pending *login
+	// wrapped is set once WrapTransport was called, from then on requests log in
+	// in the returned RoundTripper instead of AuthenticateRequest.
+	wrapped bool
+}
+
+// login is a login in progress, whose result is shared by all requests waiting for it.
+type login struct {
+	done chan struct{}
+	err  error
+}
+
+// NewSession returns a Session logging in to the Harbor instance at 'harborURL' as 'username'.
+// 'harborURL' may contain the API path, e.g. 'https://harbor.example.com/api'.
+// Login requests are sent using 'client', or http.DefaultClient if nil. Its cookie jar is used
+// to store the session cookies, if set. Without a timeout of 'client', login requests time out after 30 seconds.
+func NewSession(harborURL, username, password string, client *http.Client) (*Session, error) {
+	base := strings.TrimSuffix(harborURL, "/")
+	base = strings.TrimSuffix(base, "/v2.0")
+	base = strings.TrimSuffix(base, "/api")
+
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	c := *client
+
+	if c.Timeout == 0 {
+		c.Timeout = defaultLoginTimeout
+	}
+
+	if c.Jar == nil {
+		if c.Jar, err = cookiejar.New(nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Session{
+		baseURL:  u,
+		username: username,
+		password: password,
+		client:   &c,
+	}, nil
+}
+
+// Login establishes a new session. It is called implicitly for the first request,
+// and for the first request after Harbor rejected the session.
+func (s *Session) Login(ctx context.Context) error {
+	s.mu.Lock()
+	s.established = false
+	s.mu.Unlock()
+
+	return s.ensure(ctx)
+}
+
+// ensure logs in unless a session is established, or waits for the login in progress.
+// The lock is not held while logging in, so that requests using an established session are not blocked.
+func (s *Session) ensure(ctx context.Context) error {
+	s.mu.Lock()
+
+	if s.established {
+		s.mu.Unlock()
+		return nil
+	}
+
+	if l := s.pending; l != nil {
+		s.mu.Unlock()
+
+		select {
+		case <-l.done:
+			return l.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	l := &login{done: make(chan struct{})}
+	s.pending = l
+	s.mu.Unlock()
+
+	token, err := s.login(ctx)
+
+	s.mu.Lock()
+	s.pending = nil
+
+	if err == nil {
+		s.csrfToken, s.established = token, true
+	}
+	s.mu.Unlock()
+
+	l.err = err
+	close(l.done)
+
+	return err
+}
+
+// login logs in and returns the CSRF token of the new session.
+func (s *Session) login(ctx context.Context) (string, error) {
+	// The login form is protected against CSRF, so a token is obtained first.
+	resp, err := s.do(ctx, http.MethodGet, apiPath+"/systeminfo", nil, "")
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("obtaining CSRF token: unexpected status %d", resp.StatusCode)
+	}
+
+	token := resp.Header.Get(CSRFTokenHeader)
+	form := url.Values{"principal": {s.username}, "password": {s.password}}
+
+	resp, err = s.do(ctx, http.MethodPost, loginPath, strings.NewReader(form.Encode()), token)
+	if err != nil {
+		return "", err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return "", &clienterrors.ErrUnauthorized{}
+	default:
+		return "", fmt.Errorf("logging in: unexpected status %d", resp.StatusCode)
+	}
+
+	if t := resp.Header.Get(CSRFTokenHeader); t != "" {
+		token = t
+	}
+
+	return token, nil
+}
+
+// Logout ends the session.
+func (s *Session) Logout(ctx context.Context) error {
+	s.mu.Lock()
+	established := s.established
+	s.established = false
+	s.mu.Unlock()
+
+	if !established {
+		return nil
+	}
+
+	resp, err := s.do(ctx, http.MethodGet, logoutPath, nil, "")
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("logging out: unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// url returns the URL of 'path' on the Harbor instance.
+func (s *Session) url(path string) *url.URL {
+	u := *s.baseURL
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
+	u.RawPath = ""
+
+	return &u
+}
+
+// do sends a request to 'path' using the login client, discarding the response body.
+// Requests with a body carry the CSRF token 'csrfToken'.
+func (s *Session) do(ctx context.Context, method, path string, body io.Reader, csrfToken string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.url(path).String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set(CSRFTokenHeader, csrfToken)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return resp, resp.Body.Close()
+}
+
+// AuthenticateRequest implements runtime.ClientAuthInfoWriter. Unless the Session is used with WrapTransport,
+// which logs in using the context of the request, it logs in if no session is established,
+// bounded by the timeout of the login client.
+func (s *Session) AuthenticateRequest(req runtime.ClientRequest, _ strfmt.Registry) error {
+	s.mu.Lock()
+	wrapped := s.wrapped
+	s.mu.Unlock()
+
+	if wrapped {
+		return nil
+	}
+
+	if err := s.ensure(context.Background()); err != nil {
+		return err
+	}
+
+	cookie, csrfToken := s.credentials(req.GetMethod())
+
+	if err := req.SetHeaderParam("Cookie", cookie); err != nil {
+		return err
+	}
+
+	if csrfToken != "" {
+		return req.SetHeaderParam(CSRFTokenHeader, csrfToken)
+	}
+
+	return nil
+}
+
+// credentials returns the Cookie header and, for requests modifying resources, the CSRF token
+// of requests using the 'method'.
+func (s *Session) credentials(method string) (string, string) {
+	cookies := s.client.Jar.Cookies(s.url(apiPath))
+
+	values := make([]string, 0, len(cookies))
+	for _, c := range cookies {
+		values = append(values, c.String())
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return strings.Join(values, "; "), ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return strings.Join(values, "; "), s.csrfToken
+}
+
+// WrapTransport implements TransportProvider. The returned RoundTripper logs in using the context of the
+// request if no session is established and adds the session cookies and CSRF token to the request.
+// It stores the cookies and CSRF tokens sent by Harbor, and ends the session if Harbor rejects it,
+// so that the next request logs in again.
+func (s *Session) WrapTransport(next http.RoundTripper) http.RoundTripper {
+	s.mu.Lock()
+	s.wrapped = true
+	s.mu.Unlock()
+
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if err := s.ensure(req.Context()); err != nil {
+			return nil, err
+		}
+
+		cookie, csrfToken := s.credentials(req.Method)
+
+		// A RoundTripper must not modify the request.
+		req = req.Clone(req.Context())
+		req.Header.Set("Cookie", cookie)
+
+		if csrfToken != "" {
+			req.Header.Set(CSRFTokenHeader, csrfToken)
+		}
+
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if cookies := resp.Cookies(); len(cookies) > 0 {
+			s.client.Jar.SetCookies(req.URL, cookies)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if token := resp.Header.Get(CSRFTokenHeader); token != "" {
+			s.csrfToken = token
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized {
+			s.established = false
+		}
+
+		return resp, nil
+	})
+}
@@ -0,0 +1,217 @@
+//go:build !integration
+
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2"
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/auth"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+const csrfCookie = "_gorilla_csrf"
+
+// sessionServer imitates the session and CSRF handling of Harbor.
+// Each session is identified by a 'sid' cookie, CSRF tokens must match the CSRF cookie.
+type sessionServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	sessions map[string]bool
+	logins   int
+	projects int
+}
+
+func newSessionServer(t *testing.T) *sessionServer {
+	t.Helper()
+
+	s := &sessionServer{sessions: map[string]bool{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/systeminfo", s.handleSystemInfo)
+	mux.HandleFunc("/c/login", s.handleLogin)
+	mux.HandleFunc("/c/log_out", s.handleLogout)
+	mux.HandleFunc("/api/v2.0/projects", s.handleProjects)
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// expire ends all sessions.
+func (s *sessionServer) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions = map[string]bool{}
+}
+
+func (s *sessionServer) handleSystemInfo(w http.ResponseWriter, r *http.Request) {
+	token := "token"
+	if c, err := r.Cookie(csrfCookie); err == nil {
+		token = c.Value
+	} else {
+		http.SetCookie(w, &http.Cookie{Name: csrfCookie, Value: token, Path: "/"})
+	}
+
+	w.Header().Set(auth.CSRFTokenHeader, token)
+	w.WriteHeader(http.StatusOK)
+}
+
+// validCSRF reports whether the CSRF token of 'r' matches its CSRF cookie.
+func validCSRF(r *http.Request) bool {
+	c, err := r.Cookie(csrfCookie)
+	return err == nil && c.Value == r.Header.Get(auth.CSRFTokenHeader)
+}
+
+func (s *sessionServer) handleLogin(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method != http.MethodPost || !validCSRF(r) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	if r.FormValue("principal") != "admin" || r.FormValue("password") != "Harbor12345" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	s.logins++
+	sid := strconv.Itoa(s.logins)
+	s.sessions[sid] = true
+
+	http.SetCookie(w, &http.Cookie{Name: "sid", Value: sid, Path: "/"})
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *sessionServer) handleLogout(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, err := r.Cookie("sid"); err == nil {
+		delete(s.sessions, c.Value)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *sessionServer) handleProjects(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, err := r.Cookie("sid"); err != nil || !s.sessions[c.Value] {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("X-Total-Count", "0")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("[]"))
+	case http.MethodPost:
+		if !validCSRF(r) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		s.projects++
+		w.Header().Set("Location", "/api/v2.0/projects/"+strconv.Itoa(s.projects))
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func TestSession(t *testing.T) {
+	srv := newSessionServer(t)
+
+	session, err := auth.NewSession(srv.URL+"/api/v2.0", "admin", "Harbor12345", srv.Client())
+	require.NoError(t, err)
+
+	c, err := apiv2.NewRESTClientWithAuth(srv.URL+"/api", session, config.Defaults())
+	require.NoError(t, err)
+
+	// The first request logs in.
+	_, err = c.ListProjects(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, srv.logins)
+
+	// Requests modifying resources carry the CSRF token.
+	require.NoError(t, c.NewProject(ctx, &model.ProjectReq{ProjectName: "foo"}))
+	require.Equal(t, 1, srv.logins)
+	require.Equal(t, 1, srv.projects)
+
+	// After the session expired, the rejected request fails and the next one logs in again.
+	srv.expire()
+
+	_, err = c.ListProjects(ctx, "")
+	require.Error(t, err)
+
+	_, err = c.ListProjects(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, 2, srv.logins)
+
+	require.NoError(t, session.Logout(ctx))
+	require.Empty(t, srv.sessions)
+}
+
+func TestSession_InvalidCredentials(t *testing.T) {
+	srv := newSessionServer(t)
+
+	session, err := auth.NewSession(srv.URL, "admin", "invalid", nil)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, session.Login(ctx), &clienterrors.ErrUnauthorized{})
+
+	c, err := apiv2.NewRESTClientWithAuth(srv.URL+"/api", session, config.Defaults())
+	require.NoError(t, err)
+
+	_, err = c.ListProjects(ctx, "")
+	require.ErrorIs(t, err, &clienterrors.ErrUnauthorized{})
+}
+
+func TestSession_LoginUsesRequestContext(t *testing.T) {
+	srv := newSessionServer(t)
+
+	session, err := auth.NewSession(srv.URL, "admin", "Harbor12345", srv.Client())
+	require.NoError(t, err)
+
+	c, err := apiv2.NewRESTClientWithAuth(srv.URL+"/api", session, config.Defaults())
+	require.NoError(t, err)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	_, err = c.ListProjects(canceled, "")
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 0, srv.logins)
+
+	// Concurrent requests share a single login.
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := c.ListProjects(ctx, "")
+			require.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+	require.Equal(t, 1, srv.logins)
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+)
+
+// expiryDelta is the time before their expiry at which tokens are refreshed,
+// so that they do not expire while a request is in flight.
+const expiryDelta = 10 * time.Second
+
+// Token is an access token used as a bearer token.
+type Token struct {
+	// AccessToken is the token sent in the 'Authorization' header.
+	AccessToken string
+	// Expiry is the time the token expires at. A zero Expiry means the token does not expire.
+	Expiry time.Time
+}
+
+// valid reports whether the token is set and does not expire within expiryDelta of 'now'.
+func (t *Token) valid(now time.Time) bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+
+	return t.Expiry.IsZero() || now.Add(expiryDelta).Before(t.Expiry)
+}
+
+// TokenSource returns tokens, e.g. by exchanging a refresh token with an identity provider.
+type TokenSource interface {
+	Token() (*Token, error)
+}
+
+// TokenSourceFunc adapts a function to a TokenSource.
+type TokenSourceFunc func() (*Token, error)
+
+// Token implements TokenSource.
+func (f TokenSourceFunc) Token() (*Token, error) {
+	return f()
+}
+
+// RefreshingToken is a TransportProvider authenticating requests using bearer tokens obtained from a TokenSource.
+// Tokens are reused until shortly before they expire, or until Harbor rejects them.
+// A RefreshingToken is safe for concurrent use.
+type RefreshingToken struct {
+	source TokenSource
+
+	mu    sync.Mutex
+	token *Token
+}
+
+// NewRefreshingToken returns a RefreshingToken obtaining tokens from 'source'.
+func NewRefreshingToken(source TokenSource) *RefreshingToken {
+	return &RefreshingToken{source: source}
+}
+
+// Token returns the current token, obtaining a new one from the TokenSource if it is missing or about to expire.
+func (r *RefreshingToken) Token() (*Token, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.token.valid(time.Now()) {
+		return r.token, nil
+	}
+
+	token, err := r.source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	r.token = token
+
+	return token, nil
+}
+
+// Invalidate discards the current token, so that a new one is obtained for the next request.
+func (r *RefreshingToken) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.token = nil
+}
+
+// AuthenticateRequest implements runtime.ClientAuthInfoWriter.
+func (r *RefreshingToken) AuthenticateRequest(req runtime.ClientRequest, _ strfmt.Registry) error {
+	token, err := r.Token()
+	if err != nil {
+		return err
+	}
+
+	return req.SetHeaderParam(runtime.HeaderAuthorization, "Bearer "+token.AccessToken)
+}
+
+// WrapTransport implements TransportProvider. The current token is invalidated if Harbor rejects a request
+// authenticated with it.
+func (r *RefreshingToken) WrapTransport(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err == nil && resp.StatusCode == http.StatusUnauthorized {
+			r.invalidate(req.Header.Get(runtime.HeaderAuthorization))
+		}
+
+		return resp, err
+	})
+}
+
+// invalidate discards the current token if 'authorization' is the header value it was sent with.
+// A token obtained while the request was in flight is kept.
+func (r *RefreshingToken) invalidate(authorization string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.token != nil && authorization == "Bearer "+r.token.AccessToken {
+		r.token = nil
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,90 @@
+//go:build !integration
+
+package auth_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/auth"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+)
+
+// countingSource returns the tokens 'token-1', 'token-2', ..., each expiring after 'ttl'.
+func countingSource(ttl time.Duration) (auth.TokenSource, *int) {
+	n := 0
+
+	return auth.TokenSourceFunc(func() (*auth.Token, error) {
+		n++
+
+		return &auth.Token{AccessToken: fmt.Sprintf("token-%d", n), Expiry: time.Now().Add(ttl)}, nil
+	}), &n
+}
+
+func TestRefreshingToken_Expiry(t *testing.T) {
+	source, n := countingSource(time.Hour)
+	rt := auth.NewRefreshingToken(source)
+
+	for i := 0; i < 3; i++ {
+		token, err := rt.Token()
+		require.NoError(t, err)
+		require.Equal(t, "token-1", token.AccessToken)
+	}
+
+	// Tokens about to expire are refreshed.
+	source, n = countingSource(time.Second)
+	rt = auth.NewRefreshingToken(source)
+
+	for i := 1; i <= 3; i++ {
+		token, err := rt.Token()
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("token-%d", i), token.AccessToken)
+	}
+
+	require.Equal(t, 3, *n)
+}
+
+func TestRefreshingToken_Error(t *testing.T) {
+	rt := auth.NewRefreshingToken(auth.TokenSourceFunc(func() (*auth.Token, error) {
+		return nil, fmt.Errorf("identity provider unavailable")
+	}))
+
+	srv, headers := recordingServer(t, http.NotFoundHandler())
+
+	c, err := apiv2.NewRESTClientWithAuth(srv.URL+"/api", rt, config.Defaults())
+	require.NoError(t, err)
+
+	_, err = c.GetProject(ctx, "library")
+	require.ErrorContains(t, err, "identity provider unavailable")
+	require.Empty(t, headers())
+}
+
+func TestRefreshingToken_Rejected(t *testing.T) {
+	source, n := countingSource(time.Hour)
+	rt := auth.NewRefreshingToken(source)
+
+	// Only the second token is accepted.
+	srv, headers := recordingServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	c, err := apiv2.NewRESTClientWithAuth(srv.URL+"/api", rt, config.Defaults())
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, _ = c.GetProject(ctx, "library")
+	}
+
+	require.Equal(t, []string{"Bearer token-1", "Bearer token-2", "Bearer token-2"}, headers())
+	require.Equal(t, 2, *n)
+}
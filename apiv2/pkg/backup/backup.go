@@ -0,0 +1,294 @@
+// Package backup exports the configuration of a Harbor instance into a versioned JSON snapshot
+// and restores such snapshots onto another (usually freshly installed) instance.
+//
+// Snapshots contain all non-image state reachable through the sub-clients:
+// projects and their metadata, quotas, members, labels, robot accounts, immutability rules,
+// retention and webhook policies, as well as registries, replication policies,
+// the system configuration and the GC, purge and scan-all schedules.
+//
+// Secrets are never part of a snapshot, as Harbor does not return them:
+// robot accounts receive new secrets on restore, registry credentials and
+// configuration passwords (e.g. the LDAP search password) must be set again afterwards.
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/configure"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/gc"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/immutable"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/label"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/member"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/project"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/projectmeta"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/purge"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/quota"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/registry"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/replication"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/retention"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/robot"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/scanall"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/webhook"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/common"
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+// Version is the snapshot format version written by Export.
+const Version = 1
+
+// Client is the subset of the apiv2.Client used to export and restore snapshots.
+type Client interface {
+	configure.Client
+	gc.Client
+	immutable.Client
+	label.Client
+	member.Client
+	project.Client
+	projectmeta.Client
+	purge.Client
+	quota.Client
+	registry.Client
+	replication.Client
+	retention.Client
+	robot.Client
+	scanall.Client
+	webhook.Client
+}
+
+// Snapshot is the versioned representation of a Harbor instance's configuration.
+type Snapshot struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Configurations      *model.ConfigurationsResponse `json:"configurations,omitempty"`
+	Schedules           Schedules                     `json:"schedules"`
+	Registries          []*model.Registry             `json:"registries"`
+	Labels              []*model.Label                `json:"labels"`
+	Projects            []*ProjectSnapshot            `json:"projects"`
+	Robots              []*model.Robot                `json:"robots"`
+	ReplicationPolicies []*model.ReplicationPolicy    `json:"replication_policies"`
+}
+
+// ProjectSnapshot contains a project and its project-scoped resources.
+type ProjectSnapshot struct {
+	Project        *model.Project               `json:"project"`
+	Metadata       map[string]string            `json:"metadata,omitempty"`
+	StorageLimit   *int64                       `json:"storage_limit,omitempty"`
+	Members        []*model.ProjectMemberEntity `json:"members"`
+	Labels         []*model.Label               `json:"labels"`
+	ImmutableRules []*model.ImmutableRule       `json:"immutable_rules"`
+	Retention      *model.RetentionPolicy       `json:"retention,omitempty"`
+	Webhooks       []*model.WebhookPolicy       `json:"webhooks"`
+}
+
+// Schedules contains the system job schedules. Undefined schedules are nil.
+type Schedules struct {
+	GC      *model.Schedule `json:"gc,omitempty"`
+	Purge   *model.Schedule `json:"purge,omitempty"`
+	ScanAll *model.Schedule `json:"scan_all,omitempty"`
+}
+
+// Export takes a snapshot of the Harbor instance behind 'client' and writes it to 'w' as JSON.
+func Export(ctx context.Context, client Client, w io.Writer) error {
+	snapshot, err := NewSnapshot(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(snapshot)
+}
+
+// Decode reads a snapshot written by Export from 'r'.
+func Decode(r io.Reader) (*Snapshot, error) {
+	snapshot := &Snapshot{}
+
+	if err := json.NewDecoder(r).Decode(snapshot); err != nil {
+		return nil, fmt.Errorf("decoding snapshot: %w", err)
+	}
+
+	if snapshot.Version != Version {
+		return nil, fmt.Errorf("unsupported snapshot version %d, expected %d", snapshot.Version, Version)
+	}
+
+	return snapshot, nil
+}
+
+// NewSnapshot reads the configuration of the Harbor instance behind 'client'.
+func NewSnapshot(ctx context.Context, client Client) (*Snapshot, error) {
+	var err error
+
+	s := &Snapshot{
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if s.Configurations, err = client.GetConfigs(ctx); err != nil {
+		return nil, fmt.Errorf("exporting configurations: %w", err)
+	}
+
+	if s.Schedules, err = exportSchedules(ctx, client); err != nil {
+		return nil, err
+	}
+
+	if s.Registries, err = client.ListRegistries(ctx); err != nil {
+		return nil, fmt.Errorf("exporting registries: %w", err)
+	}
+
+	for _, reg := range s.Registries {
+		if reg.Credential != nil {
+			reg.Credential.AccessSecret = ""
+		}
+	}
+
+	if s.Labels, err = client.ListLabels(ctx, "", nil, label.ScopeGlobal); err != nil {
+		return nil, fmt.Errorf("exporting labels: %w", err)
+	}
+
+	projects, err := client.ListProjects(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("exporting projects: %w", err)
+	}
+
+	for _, p := range projects {
+		ps, err := exportProject(ctx, client, p)
+		if err != nil {
+			return nil, fmt.Errorf("exporting project %q: %w", p.Name, err)
+		}
+
+		s.Projects = append(s.Projects, ps)
+	}
+
+	if s.Robots, err = client.ListRobotAccounts(ctx); err != nil {
+		return nil, fmt.Errorf("exporting robot accounts: %w", err)
+	}
+
+	for _, r := range s.Robots {
+		r.Secret = ""
+	}
+
+	if s.ReplicationPolicies, err = client.ListReplicationPolicies(ctx); err != nil {
+		return nil, fmt.Errorf("exporting replication policies: %w", err)
+	}
+
+	return s, nil
+}
+
+func exportProject(ctx context.Context, client Client, p *model.Project) (*ProjectSnapshot, error) {
+	var err error
+
+	ps := &ProjectSnapshot{Project: p}
+	projectID := int64(p.ProjectID)
+
+	if ps.Metadata, err = client.ListProjectMetadata(ctx, p.Name); err != nil {
+		return nil, err
+	}
+
+	q, err := client.GetQuotaByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if storage, ok := q.Hard["storage"]; ok {
+		ps.StorageLimit = &storage
+	}
+
+	if ps.Members, err = client.ListProjectMembers(ctx, p.Name, ""); err != nil {
+		return nil, err
+	}
+
+	if ps.Labels, err = client.ListLabels(ctx, "", &projectID, label.ScopeProject); err != nil {
+		return nil, err
+	}
+
+	if ps.ImmutableRules, err = client.ListImmuRules(ctx, p.Name); err != nil {
+		return nil, err
+	}
+
+	if ps.Metadata[common.ProjectMetadataKeyRetentionID.String()] != "" {
+		if ps.Retention, err = client.GetRetentionPolicyByProject(ctx, p.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	if ps.Webhooks, err = client.ListProjectWebhookPolicies(ctx, int(p.ProjectID)); err != nil {
+		return nil, err
+	}
+
+	return ps, nil
+}
+
+func exportSchedules(ctx context.Context, client Client) (Schedules, error) {
+	var s Schedules
+
+	var (
+		gcUndefined *clienterrors.ErrSystemGcScheduleUndefined
+		notFound    *clienterrors.ErrNotFound
+	)
+
+	gcSchedule, err := client.GetGarbageCollectionSchedule(ctx)
+	if err != nil && !errors.As(err, &gcUndefined) {
+		return s, fmt.Errorf("exporting gc schedule: %w", err)
+	}
+
+	if gcSchedule != nil {
+		if s.GC, err = newSchedule(gcSchedule.Schedule, gcSchedule.JobParameters); err != nil {
+			return s, fmt.Errorf("exporting gc schedule: %w", err)
+		}
+	}
+
+	purgeSchedule, err := client.GetPurgeSchedule(ctx)
+	if err != nil {
+		return s, fmt.Errorf("exporting purge schedule: %w", err)
+	}
+
+	if purgeSchedule != nil {
+		if s.Purge, err = newSchedule(purgeSchedule.Schedule, purgeSchedule.JobParameters); err != nil {
+			return s, fmt.Errorf("exporting purge schedule: %w", err)
+		}
+	}
+
+	scanAllSchedule, err := client.GetScanAllSchedule(ctx)
+	if err != nil && !errors.As(err, &notFound) {
+		return s, fmt.Errorf("exporting scan all schedule: %w", err)
+	}
+
+	if scanAllSchedule != nil {
+		if s.ScanAll, _ = newSchedule(scanAllSchedule.Schedule, ""); s.ScanAll != nil {
+			s.ScanAll.Parameters = scanAllSchedule.Parameters
+		}
+	}
+
+	return s, nil
+}
+
+// newSchedule converts a job schedule and its JSON-encoded parameters into a model.Schedule.
+// Undefined schedules result in nil.
+func newSchedule(obj *model.ScheduleObj, jobParameters string) (*model.Schedule, error) {
+	if obj == nil || obj.Type == "" || obj.Type == "None" {
+		return nil, nil
+	}
+
+	s := &model.Schedule{
+		Schedule: &model.ScheduleObj{
+			Type: obj.Type,
+			Cron: obj.Cron,
+		},
+	}
+
+	if jobParameters != "" {
+		if err := json.Unmarshal([]byte(jobParameters), &s.Parameters); err != nil {
+			return nil, fmt.Errorf("invalid job parameters: %w", err)
+		}
+	}
+
+	return s, nil
+}
@@ -0,0 +1,359 @@
+//go:build !integration
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/label"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+var ctx = context.Background()
+
+// fakeClient keeps a minimal in-memory Harbor state. IDs are assigned starting at 'nextID'.
+type fakeClient struct {
+	Client
+
+	nextID int64
+
+	configs      *model.ConfigurationsResponse
+	updatedCfg   *model.Configurations
+	registries   []*model.Registry
+	labels       []*model.Label
+	projects     []*model.Project
+	metadata     map[string]map[string]string
+	members      map[string][]*model.ProjectMemberEntity
+	immuRules    map[string][]*model.ImmutableRule
+	retentions   map[int64]*model.RetentionPolicy
+	webhooks     map[int][]*model.WebhookPolicy
+	robots       []*model.Robot
+	replications []*model.ReplicationPolicy
+	gcSchedule   *model.Schedule
+}
+
+func newFakeClient(nextID int64) *fakeClient {
+	return &fakeClient{
+		nextID:     nextID,
+		configs:    &model.ConfigurationsResponse{},
+		metadata:   map[string]map[string]string{},
+		members:    map[string][]*model.ProjectMemberEntity{},
+		immuRules:  map[string][]*model.ImmutableRule{},
+		retentions: map[int64]*model.RetentionPolicy{},
+		webhooks:   map[int][]*model.WebhookPolicy{},
+	}
+}
+
+func (f *fakeClient) id() int64 {
+	f.nextID++
+	return f.nextID
+}
+
+func (f *fakeClient) GetConfigs(context.Context) (*model.ConfigurationsResponse, error) {
+	return f.configs, nil
+}
+
+func (f *fakeClient) UpdateConfigs(_ context.Context, cfg *model.Configurations) error {
+	f.updatedCfg = cfg
+	return nil
+}
+
+func (f *fakeClient) GetGarbageCollectionSchedule(context.Context) (*model.GCHistory, error) {
+	return &model.GCHistory{
+		Schedule:      &model.ScheduleObj{Type: "Daily", Cron: "0 0 0 * * *"},
+		JobParameters: `{"delete_untagged":true}`,
+	}, nil
+}
+
+func (f *fakeClient) NewGarbageCollection(_ context.Context, s *model.Schedule) error {
+	f.gcSchedule = s
+	return nil
+}
+
+func (f *fakeClient) GetPurgeSchedule(context.Context) (*model.ExecHistory, error) {
+	return &model.ExecHistory{Schedule: &model.ScheduleObj{Type: "None"}}, nil
+}
+
+func (f *fakeClient) GetScanAllSchedule(context.Context) (*model.Schedule, error) {
+	return nil, &errors.ErrNotFound{}
+}
+
+func (f *fakeClient) ListRegistries(context.Context, ...config.CallOption) ([]*model.Registry, error) {
+	return f.registries, nil
+}
+
+func (f *fakeClient) NewRegistry(_ context.Context, reg *model.Registry) error {
+	reg.ID = f.id()
+	f.registries = append(f.registries, reg)
+	return nil
+}
+
+func (f *fakeClient) GetRegistryByName(_ context.Context, name string, _ ...config.CallOption) (*model.Registry, error) {
+	for _, r := range f.registries {
+		if r.Name == name {
+			return r, nil
+		}
+	}
+
+	return nil, &errors.ErrRegistryNotFound{}
+}
+
+func (f *fakeClient) ListLabels(_ context.Context, name string, projectID *int64, _ label.Scope, _ ...config.CallOption) ([]*model.Label, error) {
+	var labels []*model.Label
+
+	for _, l := range f.labels {
+		if (projectID == nil && l.ProjectID == 0 || projectID != nil && l.ProjectID == *projectID) &&
+			(name == "" || l.Name == name) {
+			labels = append(labels, l)
+		}
+	}
+
+	return labels, nil
+}
+
+func (f *fakeClient) CreateLabel(_ context.Context, l *model.Label) error {
+	l.ID = f.id()
+	f.labels = append(f.labels, l)
+	return nil
+}
+
+func (f *fakeClient) ListProjects(context.Context, string, ...config.CallOption) ([]*model.Project, error) {
+	return f.projects, nil
+}
+
+func (f *fakeClient) ProjectExists(_ context.Context, name string) (bool, error) {
+	_, err := f.GetProject(ctx, name)
+	return err == nil, nil
+}
+
+func (f *fakeClient) GetProject(_ context.Context, name string) (*model.Project, error) {
+	for _, p := range f.projects {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+
+	return nil, &errors.ErrProjectNotFound{}
+}
+
+func (f *fakeClient) NewProject(_ context.Context, req *model.ProjectReq) error {
+	p := &model.Project{Name: req.ProjectName, ProjectID: int32(f.id()), Metadata: req.Metadata}
+	if req.RegistryID != nil {
+		p.RegistryID = *req.RegistryID
+	}
+
+	f.projects = append(f.projects, p)
+	f.members[p.Name] = []*model.ProjectMemberEntity{{EntityName: "admin", EntityType: "u", RoleID: 1}}
+
+	return nil
+}
+
+func (f *fakeClient) ListProjectMetadata(_ context.Context, name string) (map[string]string, error) {
+	return f.metadata[name], nil
+}
+
+func (f *fakeClient) GetQuotaByProjectID(context.Context, int64) (*model.Quota, error) {
+	return &model.Quota{Hard: model.ResourceList{"storage": 1024}}, nil
+}
+
+func (f *fakeClient) ListProjectMembers(_ context.Context, project, _ string, _ ...config.CallOption) ([]*model.ProjectMemberEntity, error) {
+	return f.members[project], nil
+}
+
+func (f *fakeClient) AddProjectMember(_ context.Context, project string, m *model.ProjectMember) error {
+	e := &model.ProjectMemberEntity{RoleID: m.RoleID, EntityType: "u"}
+	if m.MemberGroup != nil {
+		e.EntityType, e.EntityName = "g", m.MemberGroup.GroupName
+	} else {
+		e.EntityName = m.MemberUser.Username
+	}
+
+	f.members[project] = append(f.members[project], e)
+
+	return nil
+}
+
+func (f *fakeClient) ListImmuRules(_ context.Context, project string, _ ...config.CallOption) ([]*model.ImmutableRule, error) {
+	return f.immuRules[project], nil
+}
+
+func (f *fakeClient) CreateImmuRule(_ context.Context, project string, rule *model.ImmutableRule) error {
+	f.immuRules[project] = append(f.immuRules[project], rule)
+	return nil
+}
+
+func (f *fakeClient) GetRetentionPolicyByProject(_ context.Context, project string) (*model.RetentionPolicy, error) {
+	p, _ := f.GetProject(ctx, project)
+	return f.retentions[int64(p.ProjectID)], nil
+}
+
+func (f *fakeClient) NewRetentionPolicy(_ context.Context, ret *model.RetentionPolicy) error {
+	f.retentions[ret.Scope.Ref] = ret
+	return nil
+}
+
+func (f *fakeClient) ListProjectWebhookPolicies(_ context.Context, projectID int, _ ...config.CallOption) ([]*model.WebhookPolicy, error) {
+	return f.webhooks[projectID], nil
+}
+
+func (f *fakeClient) AddProjectWebhookPolicy(_ context.Context, projectID int, policy *model.WebhookPolicy) error {
+	f.webhooks[projectID] = append(f.webhooks[projectID], policy)
+	return nil
+}
+
+func (f *fakeClient) ListRobotAccounts(context.Context, ...config.CallOption) ([]*model.Robot, error) {
+	return f.robots, nil
+}
+
+func (f *fakeClient) NewRobotAccount(_ context.Context, r *model.RobotCreate) (*model.RobotCreated, error) {
+	created := &model.RobotCreated{ID: f.id(), Name: "robot$" + r.Name, Secret: "new-secret"}
+	f.robots = append(f.robots, &model.Robot{ID: created.ID, Name: created.Name, Level: r.Level, Permissions: r.Permissions})
+
+	return created, nil
+}
+
+func (f *fakeClient) ListReplicationPolicies(context.Context, ...config.CallOption) ([]*model.ReplicationPolicy, error) {
+	return f.replications, nil
+}
+
+func (f *fakeClient) NewReplicationPolicy(_ context.Context, dest, src *model.Registry, _, _, enabled bool,
+	_ []*model.ReplicationFilter, _ *model.ReplicationTrigger, _, _, name string,
+) error {
+	f.replications = append(f.replications, &model.ReplicationPolicy{
+		ID: f.id(), Name: name, SrcRegistry: src, DestRegistry: dest, Enabled: enabled,
+	})
+
+	return nil
+}
+
+func (f *fakeClient) GetReplicationPolicyByName(_ context.Context, name string, _ ...config.CallOption) (*model.ReplicationPolicy, error) {
+	for _, rp := range f.replications {
+		if rp.Name == name {
+			return rp, nil
+		}
+	}
+
+	return nil, &errors.ErrNotFound{}
+}
+
+func newSourceClient() *fakeClient {
+	src := newFakeClient(100)
+
+	src.configs = &model.ConfigurationsResponse{
+		AuthMode:         &model.StringConfigItem{Editable: true, Value: "oidc_auth"},
+		OIDCEndpoint:     &model.StringConfigItem{Editable: true, Value: "https://sso.example.com"},
+		SelfRegistration: &model.BoolConfigItem{Editable: false, Value: true},
+	}
+	src.registries = []*model.Registry{{
+		ID: 1, Name: "dockerhub", Type: "docker-hub", URL: "https://hub.docker.com",
+		Credential: &model.RegistryCredential{AccessKey: "user", AccessSecret: "*****"},
+	}}
+	src.labels = []*model.Label{
+		{ID: 2, Name: "approved", Scope: "g"},
+		{ID: 3, Name: "team", Scope: "p", ProjectID: 10},
+	}
+	src.projects = []*model.Project{{Name: "team-a", ProjectID: 10, RegistryID: 1}}
+	src.metadata["team-a"] = map[string]string{"public": "true", "retention_id": "7"}
+	src.members["team-a"] = []*model.ProjectMemberEntity{
+		{EntityName: "admin", EntityType: "u", RoleID: 1},
+		{EntityName: "devs", EntityType: "g", RoleID: 2},
+	}
+	src.immuRules["team-a"] = []*model.ImmutableRule{{ID: 4, Action: "immutable"}}
+	src.retentions[10] = &model.RetentionPolicy{ID: 7, Algorithm: "or", Scope: &model.RetentionPolicyScope{Level: "project", Ref: 10}}
+	src.webhooks[10] = []*model.WebhookPolicy{{ID: 5, Name: "notify", ProjectID: 10}}
+	src.robots = []*model.Robot{
+		{ID: 6, Name: "robot$team-a+ci", Level: "project", Secret: "s3cr3t", Duration: 30},
+		{ID: 7, Name: "robot$ops", Level: "system", Secret: "s3cr3t", Duration: -1},
+	}
+	src.replications = []*model.ReplicationPolicy{{
+		ID: 8, Name: "mirror", Enabled: true,
+		SrcRegistry:  &model.Registry{ID: 1, Name: "dockerhub"},
+		DestRegistry: &model.Registry{ID: 0, Name: "Local"},
+	}}
+
+	return src
+}
+
+func TestExport(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, Export(ctx, newSourceClient(), &buf))
+	require.NotContains(t, buf.String(), "s3cr3t")
+	require.NotContains(t, buf.String(), "*****")
+
+	snapshot, err := Decode(&buf)
+	require.NoError(t, err)
+
+	require.Equal(t, Version, snapshot.Version)
+	require.Len(t, snapshot.Projects, 1)
+	require.Len(t, snapshot.Projects[0].Labels, 1)
+	require.Equal(t, int64(1024), *snapshot.Projects[0].StorageLimit)
+	require.NotNil(t, snapshot.Projects[0].Retention)
+	require.Len(t, snapshot.Robots, 2)
+
+	require.Equal(t, &model.Schedule{
+		Schedule:   &model.ScheduleObj{Type: "Daily", Cron: "0 0 0 * * *"},
+		Parameters: map[string]interface{}{"delete_untagged": true},
+	}, snapshot.Schedules.GC)
+	require.Nil(t, snapshot.Schedules.Purge)
+	require.Nil(t, snapshot.Schedules.ScanAll)
+}
+
+func TestDecode_UnsupportedVersion(t *testing.T) {
+	_, err := Decode(strings.NewReader(`{"version": 99}`))
+	require.Error(t, err)
+}
+
+func TestRestore(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, Export(ctx, newSourceClient(), &buf))
+
+	dst := newFakeClient(1000)
+
+	res, err := Restore(ctx, dst, &buf)
+	require.NoError(t, err)
+
+	// Only editable configuration items are restored.
+	cfg, err := json.Marshal(dst.updatedCfg)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"auth_mode": "oidc_auth", "oidc_endpoint": "https://sso.example.com"}`, string(cfg))
+
+	require.Equal(t, IDMap{1: 1001}, res.Registries)
+	require.Equal(t, IDMap{10: 1003}, res.Projects)
+	require.Equal(t, IDMap{2: 1002, 3: 1004}, res.Labels)
+	require.Equal(t, IDMap{6: 1005, 7: 1006}, res.Robots)
+	require.Equal(t, IDMap{8: 1007}, res.ReplicationPolicies)
+	require.Equal(t, map[string]string{"robot$ci": "new-secret", "robot$ops": "new-secret"}, res.RobotSecrets)
+
+	project := dst.projects[0]
+	require.Equal(t, int64(1001), project.RegistryID)
+	require.Equal(t, "true", project.Metadata.Public)
+	require.Nil(t, project.Metadata.RetentionID)
+
+	require.Equal(t, int64(1003), dst.labels[1].ProjectID)
+	require.Len(t, dst.members["team-a"], 2)
+	require.Equal(t, "devs", dst.members["team-a"][1].EntityName)
+	require.Zero(t, dst.immuRules["team-a"][0].ID)
+	require.Equal(t, int64(1003), dst.retentions[1003].Scope.Ref)
+	require.Equal(t, int64(1003), dst.webhooks[1003][0].ProjectID)
+
+	require.Equal(t, int64(1001), dst.replications[0].SrcRegistry.ID)
+	require.Equal(t, int64(0), dst.replications[0].DestRegistry.ID)
+
+	require.NotNil(t, dst.gcSchedule)
+}
+
+func TestRobotName(t *testing.T) {
+	require.Equal(t, "ci", robotName(&model.Robot{Name: "robot$team-a+ci", Level: "project"}))
+	require.Equal(t, "ops", robotName(&model.Robot{Name: "robot$ops", Level: "system"}))
+	require.Equal(t, "ci", robotName(&model.Robot{Name: "harbor-robot$team-a+ci", Level: "project"}))
+}
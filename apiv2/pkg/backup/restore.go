@@ -0,0 +1,419 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/configure"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/label"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/common"
+)
+
+// IDMap maps the IDs of resources in a snapshot to the IDs of their restored counterparts.
+type IDMap map[int64]int64
+
+// Result describes the outcome of a Restore.
+type Result struct {
+	Registries          IDMap
+	Labels              IDMap
+	Projects            IDMap
+	Robots              IDMap
+	ReplicationPolicies IDMap
+	// RobotSecrets contains the secrets of the restored robot accounts, keyed by their full name.
+	RobotSecrets map[string]string
+}
+
+func newResult() *Result {
+	return &Result{
+		Registries:          IDMap{},
+		Labels:              IDMap{},
+		Projects:            IDMap{},
+		Robots:              IDMap{},
+		ReplicationPolicies: IDMap{},
+		RobotSecrets:        map[string]string{},
+	}
+}
+
+// Restore reads a snapshot written by Export from 'r' and recreates its resources using 'client'.
+// See RestoreSnapshot for details.
+func Restore(ctx context.Context, client Client, r io.Reader) (*Result, error) {
+	snapshot, err := Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return RestoreSnapshot(ctx, client, snapshot)
+}
+
+// RestoreSnapshot recreates the resources of 'snapshot' on the Harbor instance behind 'client',
+// which is expected to be freshly installed. References between resources are remapped to the IDs
+// assigned by the target instance, the mapping is part of the returned Result.
+// Projects that already exist (e.g. "library") are updated instead, and existing project members are kept.
+// Restoring stops at the first error; the Result then contains the resources restored so far.
+func RestoreSnapshot(ctx context.Context, client Client, snapshot *Snapshot) (*Result, error) {
+	res := newResult()
+
+	if snapshot.Configurations != nil {
+		cfg, err := configure.ToConfigurations(snapshot.Configurations)
+		if err != nil {
+			return res, fmt.Errorf("restoring configurations: %w", err)
+		}
+
+		if err := client.UpdateConfigs(ctx, cfg); err != nil {
+			return res, fmt.Errorf("restoring configurations: %w", err)
+		}
+	}
+
+	for _, reg := range snapshot.Registries {
+		if err := restoreRegistry(ctx, client, reg, res); err != nil {
+			return res, fmt.Errorf("restoring registry %q: %w", reg.Name, err)
+		}
+	}
+
+	for _, l := range snapshot.Labels {
+		if err := restoreLabel(ctx, client, l, nil, res); err != nil {
+			return res, fmt.Errorf("restoring label %q: %w", l.Name, err)
+		}
+	}
+
+	for _, ps := range snapshot.Projects {
+		if err := restoreProject(ctx, client, ps, res); err != nil {
+			return res, fmt.Errorf("restoring project %q: %w", ps.Project.Name, err)
+		}
+	}
+
+	for _, r := range snapshot.Robots {
+		if err := restoreRobot(ctx, client, r, res); err != nil {
+			return res, fmt.Errorf("restoring robot account %q: %w", r.Name, err)
+		}
+	}
+
+	for _, rp := range snapshot.ReplicationPolicies {
+		if err := restoreReplicationPolicy(ctx, client, rp, res); err != nil {
+			return res, fmt.Errorf("restoring replication policy %q: %w", rp.Name, err)
+		}
+	}
+
+	if err := restoreSchedules(ctx, client, snapshot.Schedules); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func restoreRegistry(ctx context.Context, client Client, reg *model.Registry, res *Result) error {
+	if err := client.NewRegistry(ctx, &model.Registry{
+		Credential:  reg.Credential,
+		Description: reg.Description,
+		Insecure:    reg.Insecure,
+		Name:        reg.Name,
+		Type:        reg.Type,
+		URL:         reg.URL,
+	}); err != nil {
+		return err
+	}
+
+	restored, err := client.GetRegistryByName(ctx, reg.Name)
+	if err != nil {
+		return err
+	}
+
+	res.Registries[reg.ID] = restored.ID
+
+	return nil
+}
+
+// restoreLabel creates a global label, or a project label if 'projectID' is set.
+func restoreLabel(ctx context.Context, client Client, l *model.Label, projectID *int64, res *Result) error {
+	scope := label.ScopeGlobal
+
+	restored := &model.Label{
+		Color:       l.Color,
+		Description: l.Description,
+		Name:        l.Name,
+	}
+
+	if projectID != nil {
+		scope = label.ScopeProject
+		restored.ProjectID = *projectID
+	}
+
+	restored.Scope = scope.String()
+
+	if err := client.CreateLabel(ctx, restored); err != nil {
+		return err
+	}
+
+	labels, err := client.ListLabels(ctx, l.Name, projectID, scope)
+	if err != nil {
+		return err
+	}
+
+	for _, candidate := range labels {
+		if candidate.Name == l.Name {
+			res.Labels[l.ID] = candidate.ID
+			return nil
+		}
+	}
+
+	return fmt.Errorf("label not found after creation")
+}
+
+func restoreProject(ctx context.Context, client Client, ps *ProjectSnapshot, res *Result) error {
+	p := ps.Project
+
+	metadata, err := projectMetadata(ps.Metadata)
+	if err != nil {
+		return err
+	}
+
+	exists, err := client.ProjectExists(ctx, p.Name)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		req := &model.ProjectReq{
+			CVEAllowlist: p.CVEAllowlist,
+			Metadata:     metadata,
+			ProjectName:  p.Name,
+			StorageLimit: ps.StorageLimit,
+		}
+
+		if p.RegistryID != 0 {
+			registryID, ok := res.Registries[p.RegistryID]
+			if !ok {
+				return fmt.Errorf("unknown proxy cache registry %d", p.RegistryID)
+			}
+
+			req.RegistryID = &registryID
+		}
+
+		if err := client.NewProject(ctx, req); err != nil {
+			return err
+		}
+	}
+
+	restored, err := client.GetProject(ctx, p.Name)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		restored.Metadata = metadata
+		restored.CVEAllowlist = p.CVEAllowlist
+
+		if err := client.UpdateProject(ctx, restored, ps.StorageLimit); err != nil {
+			return err
+		}
+	}
+
+	projectID := int64(restored.ProjectID)
+	res.Projects[int64(p.ProjectID)] = projectID
+
+	if err := restoreMembers(ctx, client, p.Name, ps.Members); err != nil {
+		return err
+	}
+
+	for _, l := range ps.Labels {
+		if err := restoreLabel(ctx, client, l, &projectID, res); err != nil {
+			return fmt.Errorf("restoring label %q: %w", l.Name, err)
+		}
+	}
+
+	for _, rule := range ps.ImmutableRules {
+		r := *rule
+		r.ID = 0
+
+		if err := client.CreateImmuRule(ctx, p.Name, &r); err != nil {
+			return fmt.Errorf("restoring immutable rule: %w", err)
+		}
+	}
+
+	if ps.Retention != nil {
+		policy := *ps.Retention
+		policy.ID = 0
+		policy.Scope = &model.RetentionPolicyScope{
+			Level: "project",
+			Ref:   projectID,
+		}
+
+		if err := client.NewRetentionPolicy(ctx, &policy); err != nil {
+			return fmt.Errorf("restoring retention policy: %w", err)
+		}
+	}
+
+	for _, webhook := range ps.Webhooks {
+		w := *webhook
+		w.ID = 0
+		w.ProjectID = projectID
+
+		if err := client.AddProjectWebhookPolicy(ctx, int(projectID), &w); err != nil {
+			return fmt.Errorf("restoring webhook policy %q: %w", w.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// projectMetadata converts exported metadata values into a model.ProjectMetadata.
+// The retention ID is omitted, as it refers to the retention policy of the exported instance.
+func projectMetadata(values map[string]string) (*model.ProjectMetadata, error) {
+	filtered := make(map[string]string, len(values))
+
+	for k, v := range values {
+		if k != common.ProjectMetadataKeyRetentionID.String() {
+			filtered[k] = v
+		}
+	}
+
+	raw, err := json.Marshal(filtered)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &model.ProjectMetadata{}
+
+	return meta, json.Unmarshal(raw, meta)
+}
+
+func restoreMembers(ctx context.Context, client Client, projectName string, members []*model.ProjectMemberEntity) error {
+	existing, err := client.ListProjectMembers(ctx, projectName, "")
+	if err != nil {
+		return err
+	}
+
+	present := map[string]bool{}
+	for _, m := range existing {
+		present[m.EntityType+"/"+m.EntityName] = true
+	}
+
+	for _, m := range members {
+		if present[m.EntityType+"/"+m.EntityName] {
+			continue
+		}
+
+		pm := &model.ProjectMember{RoleID: m.RoleID}
+
+		if m.EntityType == "g" {
+			pm.MemberGroup = &model.UserGroup{GroupName: m.EntityName}
+		} else {
+			pm.MemberUser = &model.UserEntity{Username: m.EntityName}
+		}
+
+		if err := client.AddProjectMember(ctx, projectName, pm); err != nil {
+			return fmt.Errorf("restoring member %q: %w", m.EntityName, err)
+		}
+	}
+
+	return nil
+}
+
+func restoreRobot(ctx context.Context, client Client, r *model.Robot, res *Result) error {
+	created, err := client.NewRobotAccount(ctx, &model.RobotCreate{
+		Description: r.Description,
+		Disable:     r.Disable,
+		Duration:    r.Duration,
+		Level:       r.Level,
+		Name:        robotName(r),
+		Permissions: r.Permissions,
+	})
+	if err != nil {
+		return err
+	}
+
+	res.Robots[r.ID] = created.ID
+	res.RobotSecrets[created.Name] = created.Secret
+
+	return nil
+}
+
+// robotName returns the name used to create a robot account,
+// i.e. its full name without the 'robot$' and 'project+' prefixes.
+func robotName(r *model.Robot) string {
+	name := r.Name
+
+	if i := strings.Index(name, "$"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	if r.Level == "project" {
+		if i := strings.LastIndex(name, "+"); i >= 0 {
+			name = name[i+1:]
+		}
+	}
+
+	return name
+}
+
+func restoreReplicationPolicy(ctx context.Context, client Client, rp *model.ReplicationPolicy, res *Result) error {
+	src, err := remapRegistry(rp.SrcRegistry, res)
+	if err != nil {
+		return err
+	}
+
+	dest, err := remapRegistry(rp.DestRegistry, res)
+	if err != nil {
+		return err
+	}
+
+	if err := client.NewReplicationPolicy(ctx, dest, src,
+		rp.ReplicateDeletion, rp.Override, rp.Enabled,
+		rp.Filters, rp.Trigger,
+		rp.DestNamespace, rp.Description, rp.Name); err != nil {
+		return err
+	}
+
+	restored, err := client.GetReplicationPolicyByName(ctx, rp.Name)
+	if err != nil {
+		return err
+	}
+
+	res.ReplicationPolicies[rp.ID] = restored.ID
+
+	return nil
+}
+
+// remapRegistry returns 'reg' with its ID replaced by the ID of the restored registry.
+// The local registry (ID 0) is returned unchanged.
+func remapRegistry(reg *model.Registry, res *Result) (*model.Registry, error) {
+	if reg == nil || reg.ID == 0 {
+		return reg, nil
+	}
+
+	id, ok := res.Registries[reg.ID]
+	if !ok {
+		return nil, fmt.Errorf("unknown registry %q (%d)", reg.Name, reg.ID)
+	}
+
+	remapped := *reg
+	remapped.ID = id
+
+	return &remapped, nil
+}
+
+func restoreSchedules(ctx context.Context, client Client, s Schedules) error {
+	if s.GC != nil {
+		if err := client.NewGarbageCollection(ctx, s.GC); err != nil {
+			return fmt.Errorf("restoring gc schedule: %w", err)
+		}
+	}
+
+	if s.Purge != nil {
+		if err := client.CreatePurgeSchedule(ctx, s.Purge); err != nil {
+			return fmt.Errorf("restoring purge schedule: %w", err)
+		}
+	}
+
+	if s.ScanAll != nil {
+		if err := client.CreateScanAllSchedule(ctx, s.ScanAll); err != nil {
+			return fmt.Errorf("restoring scan all schedule: %w", err)
+		}
+	}
+
+	return nil
+}
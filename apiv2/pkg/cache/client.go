@@ -0,0 +1,264 @@
+// Package cache provides a read-through cache for frequently read Harbor resources.
+//
+// A Client wraps an apiv2.Client, caching the projects, registries and robot accounts returned by its
+// get operations for a TTL and sharing a single request between concurrent calls reading the same resource.
+// Mutating operations called through the Client invalidate the cache of the affected resource type:
+//
+//	c := cache.New(client, cache.WithTTL(cache.ResourceRobot, time.Minute))
+//	p, err := c.GetProject(ctx, "library")
+//
+// Changes made by other clients are observed once the cached values expire; Invalidate removes them
+// earlier. Values are copied when returned, so that callers may modify them.
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2"
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/common"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+)
+
+// DefaultTTL is the time resources are cached for unless configured otherwise using WithTTL.
+const DefaultTTL = 30 * time.Second
+
+// Resource is a type of resource cached by a Client.
+type Resource string
+
+const (
+	// ResourceProject caches GetProject.
+	ResourceProject Resource = "project"
+	// ResourceRegistry caches GetRegistryByID and GetRegistryByName.
+	ResourceRegistry Resource = "registry"
+	// ResourceRobot caches GetRobotAccountByID and GetRobotAccountByName.
+	ResourceRobot Resource = "robot"
+)
+
+// Option configures a Client.
+type Option func(*options)
+
+type options struct {
+	ttls map[Resource]time.Duration
+	now  func() time.Time
+}
+
+// WithTTL caches resources of type 'r' for 'ttl'. A TTL of zero disables caching of 'r',
+// while concurrent calls reading the same resource are still coalesced.
+func WithTTL(r Resource, ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttls[r] = ttl
+	}
+}
+
+// This ensures Client implements the apiv2.Client interface.
+var _ apiv2.Client = (*Client)(nil)
+
+// Client is an apiv2.Client caching the results of get operations. It is safe for concurrent use.
+// Operations that are not cached are passed to the wrapped client.
+type Client struct {
+	apiv2.Client
+
+	projects   *store[*model.Project]
+	registries *store[*model.Registry]
+	robots     *store[*model.Robot]
+}
+
+// New returns a Client wrapping 'c', caching all resources for DefaultTTL unless configured otherwise.
+func New(c apiv2.Client, opts ...Option) *Client {
+	o := &options{
+		ttls: map[Resource]time.Duration{},
+		now:  time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	ttl := func(r Resource) time.Duration {
+		if d, ok := o.ttls[r]; ok {
+			return d
+		}
+
+		return DefaultTTL
+	}
+
+	return &Client{
+		Client:     c,
+		projects:   newStore[*model.Project](ttl(ResourceProject), o.now),
+		registries: newStore[*model.Registry](ttl(ResourceRegistry), o.now),
+		robots:     newStore[*model.Robot](ttl(ResourceRobot), o.now),
+	}
+}
+
+// Invalidate removes the cached resources of the given types, or of all types if none are given.
+// Use it after modifying resources without going through the Client.
+func (c *Client) Invalidate(resources ...Resource) {
+	if len(resources) == 0 {
+		resources = []Resource{ResourceProject, ResourceRegistry, ResourceRobot}
+	}
+
+	for _, r := range resources {
+		switch r {
+		case ResourceProject:
+			c.projects.invalidate()
+		case ResourceRegistry:
+			c.registries.invalidate()
+		case ResourceRobot:
+			c.robots.invalidate()
+		}
+	}
+}
+
+// Projects
+
+// GetProject returns the project identified by 'nameOrID' from the cache.
+// Projects are cached by the given name or ID, so a project read by both is fetched twice.
+func (c *Client) GetProject(ctx context.Context, nameOrID string) (*model.Project, error) {
+	return c.projects.get(ctx, nameOrID, func(ctx context.Context) (*model.Project, error) {
+		return c.Client.GetProject(ctx, nameOrID)
+	})
+}
+
+func (c *Client) NewProject(ctx context.Context, projectRequest *model.ProjectReq) error {
+	defer c.projects.invalidate()
+
+	return c.Client.NewProject(ctx, projectRequest)
+}
+
+func (c *Client) DeleteProject(ctx context.Context, nameOrID string) error {
+	defer c.projects.invalidate()
+
+	return c.Client.DeleteProject(ctx, nameOrID)
+}
+
+func (c *Client) UpdateProject(ctx context.Context, p *model.Project, storageLimit *int64) error {
+	defer c.projects.invalidate()
+
+	return c.Client.UpdateProject(ctx, p, storageLimit)
+}
+
+func (c *Client) AddProjectMetadata(ctx context.Context, projectNameOrID string, key common.MetadataKey, value string) error {
+	defer c.projects.invalidate()
+
+	return c.Client.AddProjectMetadata(ctx, projectNameOrID, key, value)
+}
+
+func (c *Client) UpdateProjectMetadata(ctx context.Context, projectNameOrID string, key common.MetadataKey, value string) error {
+	defer c.projects.invalidate()
+
+	return c.Client.UpdateProjectMetadata(ctx, projectNameOrID, key, value)
+}
+
+func (c *Client) DeleteProjectMetadataValue(ctx context.Context, projectNameOrID string, key common.MetadataKey) error {
+	defer c.projects.invalidate()
+
+	return c.Client.DeleteProjectMetadataValue(ctx, projectNameOrID, key)
+}
+
+// Registries
+
+// GetRegistryByID returns the registry identified by 'id' from the cache.
+func (c *Client) GetRegistryByID(ctx context.Context, id int64) (*model.Registry, error) {
+	return c.registries.get(ctx, "id:"+strconv.FormatInt(id, 10), func(ctx context.Context) (*model.Registry, error) {
+		return c.Client.GetRegistryByID(ctx, id)
+	})
+}
+
+// GetRegistryByName returns the registry named 'name' from the cache.
+// The CallOptions are passed to the listing on a cache miss.
+func (c *Client) GetRegistryByName(ctx context.Context, name string, opts ...config.CallOption) (*model.Registry, error) {
+	return c.registries.get(ctx, "name:"+name, func(ctx context.Context) (*model.Registry, error) {
+		return c.Client.GetRegistryByName(ctx, name, opts...)
+	})
+}
+
+func (c *Client) NewRegistry(ctx context.Context, reg *model.Registry) error {
+	defer c.registries.invalidate()
+
+	return c.Client.NewRegistry(ctx, reg)
+}
+
+func (c *Client) DeleteRegistryByID(ctx context.Context, id int64) error {
+	defer c.registries.invalidate()
+
+	return c.Client.DeleteRegistryByID(ctx, id)
+}
+
+func (c *Client) UpdateRegistry(ctx context.Context, u *model.RegistryUpdate, id int64) error {
+	defer c.registries.invalidate()
+
+	return c.Client.UpdateRegistry(ctx, u, id)
+}
+
+// Robot accounts
+
+// GetRobotAccountByID returns the robot account identified by 'id' from the cache.
+func (c *Client) GetRobotAccountByID(ctx context.Context, id int64) (*model.Robot, error) {
+	return c.robots.get(ctx, "id:"+strconv.FormatInt(id, 10), func(ctx context.Context) (*model.Robot, error) {
+		return c.Client.GetRobotAccountByID(ctx, id)
+	})
+}
+
+// GetRobotAccountByName returns the robot account named 'name' from the cache.
+func (c *Client) GetRobotAccountByName(ctx context.Context, name string) (*model.Robot, error) {
+	return c.robots.get(ctx, "name:"+name, func(ctx context.Context) (*model.Robot, error) {
+		return c.Client.GetRobotAccountByName(ctx, name)
+	})
+}
+
+func (c *Client) NewRobotAccount(ctx context.Context, r *model.RobotCreate) (*model.RobotCreated, error) {
+	defer c.robots.invalidate()
+
+	return c.Client.NewRobotAccount(ctx, r)
+}
+
+func (c *Client) DeleteRobotAccountByName(ctx context.Context, name string) error {
+	defer c.robots.invalidate()
+
+	return c.Client.DeleteRobotAccountByName(ctx, name)
+}
+
+func (c *Client) DeleteRobotAccountByID(ctx context.Context, id int64) error {
+	defer c.robots.invalidate()
+
+	return c.Client.DeleteRobotAccountByID(ctx, id)
+}
+
+func (c *Client) UpdateRobotAccount(ctx context.Context, r *model.Robot) error {
+	defer c.robots.invalidate()
+
+	return c.Client.UpdateRobotAccount(ctx, r)
+}
+
+func (c *Client) RefreshRobotAccountSecretByID(ctx context.Context, id int64, sec string) (*model.RobotSec, error) {
+	defer c.robots.invalidate()
+
+	return c.Client.RefreshRobotAccountSecretByID(ctx, id, sec)
+}
+
+func (c *Client) RefreshRobotAccountSecretByName(ctx context.Context, name string, sec string) (*model.RobotSec, error) {
+	defer c.robots.invalidate()
+
+	return c.Client.RefreshRobotAccountSecretByName(ctx, name, sec)
+}
+
+func (c *Client) AddProjectRobotV1(ctx context.Context, projectNameOrID string, r *model.RobotCreateV1) error {
+	defer c.robots.invalidate()
+
+	return c.Client.AddProjectRobotV1(ctx, projectNameOrID, r)
+}
+
+func (c *Client) UpdateProjectRobotV1(ctx context.Context, projectNameOrID string, robotID int64, r *model.Robot) error {
+	defer c.robots.invalidate()
+
+	return c.Client.UpdateProjectRobotV1(ctx, projectNameOrID, robotID, r)
+}
+
+func (c *Client) DeleteProjectRobotV1(ctx context.Context, projectNameOrID string, robotID int64) error {
+	defer c.robots.invalidate()
+
+	return c.Client.DeleteProjectRobotV1(ctx, projectNameOrID, robotID)
+}
@@ -0,0 +1,272 @@
+//go:build !integration
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2"
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+)
+
+var ctx = context.Background()
+
+// fakeClient counts the calls of the operations cached by Client.
+// Calling any other operation panics, as the embedded interface is nil.
+type fakeClient struct {
+	apiv2.Client
+
+	calls   atomic.Int32
+	release chan struct{}
+	err     error
+}
+
+func (f *fakeClient) wait() {
+	if f.release != nil {
+		<-f.release
+	}
+}
+
+func (f *fakeClient) GetProject(_ context.Context, nameOrID string) (*model.Project, error) {
+	f.calls.Add(1)
+	f.wait()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &model.Project{Name: nameOrID, Metadata: &model.ProjectMetadata{Public: "true"}}, nil
+}
+
+func (f *fakeClient) UpdateProject(context.Context, *model.Project, *int64) error {
+	return nil
+}
+
+func (f *fakeClient) GetRegistryByName(_ context.Context, name string, _ ...config.CallOption) (*model.Registry, error) {
+	f.calls.Add(1)
+
+	return &model.Registry{Name: name}, nil
+}
+
+func (f *fakeClient) GetRobotAccountByName(_ context.Context, name string) (*model.Robot, error) {
+	f.calls.Add(1)
+
+	return &model.Robot{Name: name}, nil
+}
+
+func (f *fakeClient) DeleteRobotAccountByID(context.Context, int64) error {
+	return nil
+}
+
+// clock is a manually advanced clock.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func withClock(c *clock) Option {
+	return func(o *options) {
+		o.now = c.Now
+	}
+}
+
+func TestClient_TTL(t *testing.T) {
+	f := &fakeClient{}
+	clk := &clock{now: time.Now()}
+	c := New(f, withClock(clk), WithTTL(ResourceRegistry, time.Minute), WithTTL(ResourceRobot, 0))
+
+	for i := 0; i < 3; i++ {
+		p, err := c.GetProject(ctx, "library")
+		require.NoError(t, err)
+		require.Equal(t, "library", p.Name)
+	}
+
+	require.EqualValues(t, 1, f.calls.Load())
+
+	clk.Advance(DefaultTTL)
+
+	_, err := c.GetProject(ctx, "library")
+	require.NoError(t, err)
+	require.EqualValues(t, 2, f.calls.Load(), "expired projects are fetched again")
+
+	_, err = c.GetRegistryByName(ctx, "hub")
+	require.NoError(t, err)
+	clk.Advance(DefaultTTL)
+	_, err = c.GetRegistryByName(ctx, "hub")
+	require.NoError(t, err)
+	require.EqualValues(t, 3, f.calls.Load(), "registries are cached for their own TTL")
+
+	_, err = c.GetRobotAccountByName(ctx, "robot$ci")
+	require.NoError(t, err)
+	_, err = c.GetRobotAccountByName(ctx, "robot$ci")
+	require.NoError(t, err)
+	require.EqualValues(t, 5, f.calls.Load(), "robots are not cached with a TTL of zero")
+}
+
+func TestClient_Copies(t *testing.T) {
+	c := New(&fakeClient{})
+
+	p, err := c.GetProject(ctx, "library")
+	require.NoError(t, err)
+
+	p.Name = "modified"
+	p.Metadata.Public = "false"
+
+	p, err = c.GetProject(ctx, "library")
+	require.NoError(t, err)
+	require.Equal(t, "library", p.Name)
+	require.Equal(t, "true", p.Metadata.Public)
+}
+
+func TestClient_Invalidate(t *testing.T) {
+	f := &fakeClient{}
+	c := New(f)
+
+	p, err := c.GetProject(ctx, "library")
+	require.NoError(t, err)
+
+	require.NoError(t, c.UpdateProject(ctx, p, nil))
+
+	_, err = c.GetProject(ctx, "library")
+	require.NoError(t, err)
+	require.EqualValues(t, 2, f.calls.Load(), "mutations invalidate the cache")
+
+	_, err = c.GetRobotAccountByName(ctx, "robot$ci")
+	require.NoError(t, err)
+	require.NoError(t, c.DeleteRobotAccountByID(ctx, 1))
+	_, err = c.GetRobotAccountByName(ctx, "robot$ci")
+	require.NoError(t, err)
+	require.EqualValues(t, 4, f.calls.Load())
+
+	_, err = c.GetProject(ctx, "library")
+	require.NoError(t, err)
+	require.EqualValues(t, 4, f.calls.Load(), "robot mutations do not invalidate projects")
+
+	c.Invalidate()
+
+	_, err = c.GetProject(ctx, "library")
+	require.NoError(t, err)
+	require.EqualValues(t, 5, f.calls.Load())
+}
+
+func TestClient_Coalescing(t *testing.T) {
+	f := &fakeClient{release: make(chan struct{})}
+	c := New(f)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			p, err := c.GetProject(ctx, "library")
+			require.NoError(t, err)
+			require.Equal(t, "library", p.Name)
+		}()
+	}
+
+	require.Eventually(t, func() bool { return f.calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	_, err := c.GetProject(cancelled, "library")
+	require.ErrorIs(t, err, context.Canceled, "waiting callers return when their context is done")
+
+	close(f.release)
+	wg.Wait()
+
+	require.EqualValues(t, 1, f.calls.Load())
+}
+
+func TestClient_ErrorsAreNotCached(t *testing.T) {
+	f := &fakeClient{err: errors.New("unavailable")}
+	c := New(f)
+
+	_, err := c.GetProject(ctx, "library")
+	require.ErrorIs(t, err, f.err)
+
+	f.err = nil
+
+	_, err = c.GetProject(ctx, "library")
+	require.NoError(t, err)
+	require.EqualValues(t, 2, f.calls.Load())
+}
+
+func TestStore_InvalidateDuringFetch(t *testing.T) {
+	s := newStore[string](time.Minute, time.Now)
+
+	var fetches atomic.Int32
+
+	release := []chan struct{}{make(chan struct{}), make(chan struct{})}
+
+	fetch := func(context.Context) (string, error) {
+		if n := fetches.Add(1); int(n) <= len(release) {
+			<-release[n-1]
+		}
+
+		return "value", nil
+	}
+
+	get := func() <-chan struct{} {
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			v, err := s.get(ctx, "key", fetch)
+			require.NoError(t, err)
+			require.Equal(t, "value", v)
+		}()
+
+		return done
+	}
+
+	first := get()
+	require.Eventually(t, func() bool { return fetches.Load() == 1 }, time.Second, time.Millisecond)
+
+	s.invalidate()
+
+	second := get()
+	require.Eventually(t, func() bool { return fetches.Load() == 2 }, time.Second, time.Millisecond)
+
+	// Completing the fetch started before invalidate must not discard the one started after it,
+	// so that later callers keep sharing it.
+	close(release[0])
+	<-first
+
+	s.mu.Lock()
+	pending := s.calls["key"]
+	s.mu.Unlock()
+
+	require.NotNil(t, pending, "the second fetch is still shared")
+
+	close(release[1])
+	<-second
+
+	require.EqualValues(t, 2, fetches.Load())
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// store caches values of type V by key for a fixed TTL and coalesces concurrent fetches of the same key.
+type store[V any] struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]entry[V]
+	calls   map[string]*call[V]
+	// generation is incremented by invalidate, so that fetches started before do not populate the cache.
+	generation uint64
+}
+
+type entry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// call is a fetch in flight. 'done' is closed once 'value' and 'err' are set.
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+func newStore[V any](ttl time.Duration, now func() time.Time) *store[V] {
+	return &store[V]{
+		ttl:     ttl,
+		now:     now,
+		entries: map[string]entry[V]{},
+		calls:   map[string]*call[V]{},
+	}
+}
+
+// get returns a copy of the cached value of 'key', calling 'fetch' if it is not cached or has expired.
+// Concurrent calls for the same key share a single fetch. The fetch is not cancelled if 'ctx' is done,
+// so that the callers waiting for it are not affected, but get returns immediately with the context's error.
+// Errors are not cached.
+func (s *store[V]) get(ctx context.Context, key string, fetch func(ctx context.Context) (V, error)) (V, error) {
+	s.mu.Lock()
+
+	if e, ok := s.entries[key]; ok && s.now().Before(e.expires) {
+		s.mu.Unlock()
+
+		return clone(e.value)
+	}
+
+	c, ok := s.calls[key]
+	if !ok {
+		c = &call[V]{done: make(chan struct{})}
+		s.calls[key] = c
+
+		go s.fetch(context.WithoutCancel(ctx), key, c, s.generation, fetch)
+	}
+
+	s.mu.Unlock()
+
+	select {
+	case <-c.done:
+		if c.err != nil {
+			var zero V
+			return zero, c.err
+		}
+
+		return clone(c.value)
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+func (s *store[V]) fetch(ctx context.Context, key string, c *call[V], generation uint64, fetch func(ctx context.Context) (V, error)) {
+	c.value, c.err = fetch(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// invalidate may have replaced the call by a newer one, which must stay shared until it completes.
+	if s.calls[key] == c {
+		delete(s.calls, key)
+	}
+
+	if c.err == nil && s.ttl > 0 && generation == s.generation {
+		s.entries[key] = entry[V]{value: c.value, expires: s.now().Add(s.ttl)}
+	}
+
+	close(c.done)
+}
+
+// invalidate removes all cached values. Fetches in flight are not cached once they complete.
+func (s *store[V]) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = map[string]entry[V]{}
+	s.calls = map[string]*call[V]{}
+	s.generation++
+}
+
+// clone returns a deep copy of 'v', so that callers may modify the values they get without affecting the cache.
+func clone[V any](v V) (V, error) {
+	var res V
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return res, err
+	}
+
+	err = json.Unmarshal(b, &res)
+
+	return res, err
+}
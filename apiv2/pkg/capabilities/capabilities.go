@@ -0,0 +1,221 @@
+// Package capabilities determines which features a Harbor server provides based on its version.
+//
+// The client is generated against a single Harbor release but is used against older and newer servers.
+// Operations depending on a feature the server does not provide are rejected with an
+// errors.ErrUnsupportedServerVersion instead of failing with a 404 response.
+package capabilities
+
+import (
+	"context"
+	"sync"
+
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+// Feature is a Harbor feature only provided by some Harbor versions.
+type Feature string
+
+const (
+	// FeaturePreheat is P2P preheating of artifacts via preheat instances and policies.
+	FeaturePreheat Feature = "preheat"
+	// FeatureRobot is the robot account API supporting system and multi-project robot accounts.
+	FeatureRobot Feature = "robot"
+	// FeatureRobotV1 is the project scoped robot account API.
+	FeatureRobotV1 Feature = "robot_v1"
+	// FeaturePurge is purging audit logs via purge schedules.
+	FeaturePurge Feature = "purge"
+	// FeatureScanDataExport is exporting vulnerability data as CSV.
+	FeatureScanDataExport Feature = "scan_data_export"
+	// FeatureJobServiceDashboard is inspecting and controlling job service queues, pools and workers.
+	FeatureJobServiceDashboard Feature = "jobservice_dashboard"
+	// FeatureSecurityHub is the system-wide vulnerability summary and search.
+	FeatureSecurityHub Feature = "security_hub"
+)
+
+// requirement describes the Harbor versions providing a feature.
+type requirement struct {
+	// min is the first version providing the feature.
+	min Version
+	// removed is the first version no longer providing the feature, if any.
+	removed *Version
+}
+
+func (r requirement) supports(v Version) bool {
+	return v.AtLeast(r.min) && (r.removed == nil || !v.AtLeast(*r.removed))
+}
+
+func (r requirement) String() string {
+	if r.removed == nil {
+		return ">= " + r.min.String()
+	}
+
+	return ">= " + r.min.String() + ", < " + r.removed.String()
+}
+
+var requirements = map[Feature]requirement{
+	FeaturePreheat:             {min: Version{2, 1, 0}},
+	FeatureRobot:               {min: Version{2, 2, 0}},
+	FeatureRobotV1:             {min: Version{2, 0, 0}},
+	FeaturePurge:               {min: Version{2, 6, 0}},
+	FeatureScanDataExport:      {min: Version{2, 6, 0}},
+	FeatureJobServiceDashboard: {min: Version{2, 8, 0}},
+	FeatureSecurityHub:         {min: Version{2, 9, 0}},
+}
+
+// Capabilities describes the features provided by a Harbor server.
+type Capabilities struct {
+	// RawVersion is the Harbor version as reported by the server, e.g. 'v2.9.1-2b28a9c2'.
+	RawVersion string
+	// Version is the parsed Harbor version. It is nil if RawVersion could not be parsed,
+	// e.g. for development builds, in which case all features are considered available.
+	Version *Version
+}
+
+// New returns the Capabilities of a server reporting the Harbor version 'rawVersion'.
+func New(rawVersion string) *Capabilities {
+	c := &Capabilities{RawVersion: rawVersion}
+
+	if v, err := ParseVersion(rawVersion); err == nil {
+		c.Version = &v
+	}
+
+	return c
+}
+
+// Supports reports whether the server provides 'feature'. Unknown features are considered available.
+func (c *Capabilities) Supports(feature Feature) bool {
+	r, ok := requirements[feature]
+	if !ok || c.Version == nil {
+		return true
+	}
+
+	return r.supports(*c.Version)
+}
+
+// Require returns an errors.ErrUnsupportedServerVersion if the server does not provide 'feature'.
+func (c *Capabilities) Require(feature Feature) error {
+	if c.Supports(feature) {
+		return nil
+	}
+
+	return &clienterrors.ErrUnsupportedServerVersion{
+		Feature:       string(feature),
+		ServerVersion: c.RawVersion,
+		Supported:     requirements[feature].String(),
+	}
+}
+
+// Preheat reports whether the server provides FeaturePreheat.
+func (c *Capabilities) Preheat() bool { return c.Supports(FeaturePreheat) }
+
+// Robot reports whether the server provides FeatureRobot.
+func (c *Capabilities) Robot() bool { return c.Supports(FeatureRobot) }
+
+// RobotV1 reports whether the server provides FeatureRobotV1.
+func (c *Capabilities) RobotV1() bool { return c.Supports(FeatureRobotV1) }
+
+// Purge reports whether the server provides FeaturePurge.
+func (c *Capabilities) Purge() bool { return c.Supports(FeaturePurge) }
+
+// ScanDataExport reports whether the server provides FeatureScanDataExport.
+func (c *Capabilities) ScanDataExport() bool { return c.Supports(FeatureScanDataExport) }
+
+// JobServiceDashboard reports whether the server provides FeatureJobServiceDashboard.
+func (c *Capabilities) JobServiceDashboard() bool { return c.Supports(FeatureJobServiceDashboard) }
+
+// SecurityHub reports whether the server provides FeatureSecurityHub.
+func (c *Capabilities) SecurityHub() bool { return c.Supports(FeatureSecurityHub) }
+
+// VersionFunc returns the Harbor version reported by a server, e.g. using the systeminfo API.
+type VersionFunc func(ctx context.Context) (string, error)
+
+// Detector lazily determines the Capabilities of a server on first use and caches them.
+// Failed detections are not cached, so that they are retried on the next use.
+// Concurrent callers share a single detection. A Detector is safe for concurrent use.
+type Detector struct {
+	version VersionFunc
+
+	mu           sync.Mutex
+	capabilities *Capabilities
+	detection    *detection
+}
+
+// detection is a request of the server version shared by concurrent callers.
+type detection struct {
+	done         chan struct{}
+	capabilities *Capabilities
+	err          error
+}
+
+// NewDetector returns a Detector obtaining the server version using 'version'.
+func NewDetector(version VersionFunc) *Detector {
+	return &Detector{version: version}
+}
+
+// Capabilities returns the Capabilities of the server, detecting them if they are not cached yet.
+// The server version is requested without holding a lock, so that callers waiting for a detection
+// started by another caller return as soon as their own 'ctx' is done.
+func (d *Detector) Capabilities(ctx context.Context) (*Capabilities, error) {
+	d.mu.Lock()
+	if c := d.capabilities; c != nil {
+		d.mu.Unlock()
+		return c, nil
+	}
+
+	det := d.detection
+	if det == nil {
+		det = &detection{done: make(chan struct{})}
+		d.detection = det
+
+		// The detection is shared, so it must not be aborted when the caller starting it gives up.
+		go d.detect(context.WithoutCancel(ctx), det)
+	}
+	d.mu.Unlock()
+
+	select {
+	case <-det.done:
+		return det.capabilities, det.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (d *Detector) detect(ctx context.Context, det *detection) {
+	v, err := d.version(ctx)
+	if err == nil {
+		det.capabilities = New(v)
+	} else {
+		det.err = err
+	}
+
+	d.mu.Lock()
+	if d.detection == det {
+		d.detection = nil
+		d.capabilities = det.capabilities
+	}
+	d.mu.Unlock()
+
+	close(det.done)
+}
+
+// Require returns an errors.ErrUnsupportedServerVersion if the server does not provide 'feature'.
+// If the Capabilities cannot be detected, the feature is considered available, like for
+// unparseable versions, and the operation is left to fail on its own.
+// Only if 'ctx' is done, its error is returned.
+func (d *Detector) Require(ctx context.Context, feature Feature) error {
+	c, err := d.Capabilities(ctx)
+	if err != nil {
+		return ctx.Err()
+	}
+
+	return c.Require(feature)
+}
+
+// Reset discards the cached Capabilities, e.g. after the server has been upgraded.
+func (d *Detector) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.capabilities = nil
+	d.detection = nil
+}
@@ -0,0 +1,152 @@
+//go:build !integration
+
+package capabilities
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+func TestParseVersion(t *testing.T) {
+	for in, want := range map[string]Version{
+		"v2.9.1":            {2, 9, 1},
+		"2.9.1":             {2, 9, 1},
+		"v2.10.0-2b28a9c2":  {2, 10, 0},
+		"v2.1":              {2, 1, 0},
+		" v2.3.4+build.5 ":  {2, 3, 4},
+		"v2.8.4-rc1-0ab1c2": {2, 8, 4},
+	} {
+		got, err := ParseVersion(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, got, in)
+	}
+
+	for _, in := range []string{"", "dev", "v2", "v2.x.1", "v2.9.1.0", "v-1.2.3"} {
+		_, err := ParseVersion(in)
+		require.Error(t, err, in)
+	}
+}
+
+func TestVersion_Compare(t *testing.T) {
+	require.Equal(t, 0, Version{2, 9, 1}.Compare(Version{2, 9, 1}))
+	require.Equal(t, -1, Version{2, 9, 1}.Compare(Version{2, 10, 0}))
+	require.Equal(t, 1, Version{3, 0, 0}.Compare(Version{2, 99, 99}))
+	require.True(t, Version{2, 2, 0}.AtLeast(Version{2, 2, 0}))
+	require.False(t, Version{2, 1, 9}.AtLeast(Version{2, 2, 0}))
+	require.Equal(t, "v2.9.1", Version{2, 9, 1}.String())
+}
+
+func TestCapabilities(t *testing.T) {
+	c := New("v2.1.4-abcdef")
+	require.True(t, c.Preheat())
+	require.False(t, c.Robot())
+	require.True(t, c.RobotV1())
+	require.False(t, c.SecurityHub())
+
+	err := c.Require(FeatureRobot)
+	require.ErrorIs(t, err, &clienterrors.ErrUnsupportedServerVersion{})
+
+	var unsupported *clienterrors.ErrUnsupportedServerVersion
+	require.True(t, errors.As(err, &unsupported))
+	require.Equal(t, "robot", unsupported.Feature)
+	require.Equal(t, "v2.1.4-abcdef", unsupported.ServerVersion)
+	require.Equal(t, ">= v2.2.0", unsupported.Supported)
+
+	require.True(t, New("v2.0.0").RobotV1(), "robot accounts v1 are available since Harbor 2.0")
+
+	c = New("v2.9.1")
+	for feature := range requirements {
+		require.NoError(t, c.Require(feature), feature)
+	}
+
+	// Unparsable versions, e.g. of development builds, do not restrict any feature.
+	c = New("dev")
+	require.Nil(t, c.Version)
+	require.True(t, c.SecurityHub())
+}
+
+func TestRequirement_Removed(t *testing.T) {
+	r := requirement{min: Version{2, 2, 0}, removed: &Version{2, 10, 0}}
+
+	require.False(t, r.supports(Version{2, 1, 0}))
+	require.True(t, r.supports(Version{2, 9, 1}))
+	require.False(t, r.supports(Version{2, 10, 0}))
+	require.Equal(t, ">= v2.2.0, < v2.10.0", r.String())
+}
+
+func TestDetector(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	fail := true
+
+	d := NewDetector(func(context.Context) (string, error) {
+		calls++
+		if fail {
+			return "", errors.New("unavailable")
+		}
+
+		return "v2.5.0", nil
+	})
+
+	// Failed detections are not cached, and do not prevent operations.
+	_, err := d.Capabilities(ctx)
+	require.EqualError(t, err, "unavailable")
+	require.NoError(t, d.Require(ctx, FeaturePurge))
+
+	fail = false
+
+	require.NoError(t, d.Require(ctx, FeaturePreheat))
+	require.ErrorIs(t, d.Require(ctx, FeaturePurge), &clienterrors.ErrUnsupportedServerVersion{})
+	require.Equal(t, 3, calls)
+
+	d.Reset()
+
+	c, err := d.Capabilities(ctx)
+	require.NoError(t, err)
+	require.Equal(t, &Version{2, 5, 0}, c.Version)
+	require.Equal(t, 4, calls)
+}
+
+func TestDetector_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+	var calls atomic.Int32
+
+	d := NewDetector(func(context.Context) (string, error) {
+		calls.Add(1)
+		<-release
+
+		return "v2.5.0", nil
+	})
+
+	started := make(chan struct{})
+	errs := make(chan error, 1)
+
+	go func() {
+		close(started)
+		errs <- d.Require(ctx, FeaturePurge)
+	}()
+
+	<-started
+
+	// A caller waiting for the detection of another caller is not blocked beyond its own deadline.
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+
+	_, err := d.Capabilities(waitCtx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, d.Require(waitCtx, FeaturePurge), context.DeadlineExceeded)
+
+	close(release)
+
+	require.ErrorIs(t, <-errs, &clienterrors.ErrUnsupportedServerVersion{})
+	require.NoError(t, d.Require(ctx, FeaturePreheat))
+	require.Equal(t, int32(1), calls.Load())
+}
@@ -0,0 +1,63 @@
+package capabilities
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a Harbor release version.
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// ParseVersion parses a Harbor version as reported by the systeminfo API, e.g. 'v2.9.1-2b28a9c2'.
+// The leading 'v' and any build or pre-release suffix are ignored, a missing patch version is read as 0.
+func ParseVersion(s string) (Version, error) {
+	v := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(v, "-+ "); i >= 0 {
+		v = v[:i]
+	}
+
+	parts := strings.Split(v, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid Harbor version %q", s)
+	}
+
+	numbers := make([]int, 3)
+
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid Harbor version %q", s)
+		}
+
+		numbers[i] = n
+	}
+
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// Compare returns -1, 0 or 1 if 'v' is lower than, equal to or greater than 'other'.
+func (v Version) Compare(other Version) int {
+	for _, d := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		switch {
+		case d < 0:
+			return -1
+		case d > 0:
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// AtLeast reports whether 'v' is equal to or greater than 'other'.
+func (v Version) AtLeast(other Version) bool {
+	return v.Compare(other) >= 0
+}
+
+// String returns the version in Harbor's notation, e.g. 'v2.9.1'.
+func (v Version) String() string {
+	return fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
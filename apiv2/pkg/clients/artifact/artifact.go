@@ -8,6 +8,8 @@ import (
 	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/artifact"
 	"github.com/mittwald/goharbor-client/v5/apiv2/model"
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+	imageref "github.com/mittwald/goharbor-client/v5/apiv2/pkg/reference"
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/util"
 )
 
@@ -25,7 +27,7 @@ type RESTClient struct {
 
 func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
 	return &RESTClient{
-		Options:  opts,
+		Options:  opts.Copy(),
 		V2Client: v2Client,
 		AuthInfo: authInfo,
 	}
@@ -38,9 +40,21 @@ type Client interface {
 	DeleteTag(ctx context.Context, projectName, repositoryName, reference, tagName string) error
 	GetArtifact(ctx context.Context, projectName, repositoryName, reference string) (*model.Artifact, error)
 	DeleteArtifact(ctx context.Context, projectName, repositoryName, reference string) error
-	ListArtifacts(ctx context.Context, projectName, repositoryName string) ([]*model.Artifact, error)
-	ListTags(ctx context.Context, projectName, repositoryName, reference string) ([]*model.Tag, error)
+	ListArtifacts(ctx context.Context, projectName, repositoryName string, opts ...config.CallOption) ([]*model.Artifact, error)
+	ListArtifactsWithOptions(ctx context.Context, projectName, repositoryName string, listOpts *ListArtifactsOptions, opts ...config.CallOption) ([]*model.Artifact, error)
+	GetArtifactWithDetails(ctx context.Context, projectName, repositoryName, reference string, details ArtifactDetails) (*model.Artifact, error)
+	ListAccessories(ctx context.Context, projectName, repositoryName, reference string, opts ...config.CallOption) ([]*model.Accessory, error)
+	DeleteAccessory(ctx context.Context, projectName, repositoryName string, accessory *model.Accessory) error
+	ListTags(ctx context.Context, projectName, repositoryName, reference string, opts ...config.CallOption) ([]*model.Tag, error)
 	RemoveLabel(ctx context.Context, projectName, repositoryName, reference string, id int64) error
+	GetArtifactByReference(ctx context.Context, ref *imageref.Reference) (*model.Artifact, error)
+	DeleteArtifactByReference(ctx context.Context, ref *imageref.Reference) error
+	ListArtifactsByReference(ctx context.Context, ref *imageref.Reference, opts ...config.CallOption) ([]*model.Artifact, error)
+	ListArtifactsByReferenceWithOptions(ctx context.Context, ref *imageref.Reference, listOpts *ListArtifactsOptions, opts ...config.CallOption) ([]*model.Artifact, error)
+	CreateTagByReference(ctx context.Context, ref *imageref.Reference, tag *model.Tag) error
+	ListTagsByReference(ctx context.Context, ref *imageref.Reference, opts ...config.CallOption) ([]*model.Tag, error)
+	ListAccessoriesByReference(ctx context.Context, ref *imageref.Reference, opts ...config.CallOption) ([]*model.Accessory, error)
+	CopyArtifactByReference(ctx context.Context, from, to *imageref.Reference) error
 	// TODO: Introduce this, once https://github.com/goharbor/harbor/issues/13468 is resolved.
 	// GetAddition(ctx context.Context, projectName, repositoryName, reference string, addition Addition) (string, error)
 	// GetVulnerabilitiesAddition(ctx context.Context, projectName, repositoryName, reference string) (string, error)
@@ -83,6 +97,56 @@ const (
 	AdditionDependencies Addition = "dependencies"
 )
 
+// AccessoryType defines the kind of an artifact accessory, e.g. a signature or an SBOM.
+type AccessoryType string
+
+const (
+	AccessoryTypeCosignSignature   AccessoryType = "signature.cosign"
+	AccessoryTypeNotationSignature AccessoryType = "signature.notation"
+	AccessoryTypeNydus             AccessoryType = "accelerator.nydus"
+	AccessoryTypeSBOM              AccessoryType = "harbor.sbom"
+)
+
+func (in AccessoryType) String() string {
+	return string(in)
+}
+
+// IsSignature returns true if the accessory type describes an artifact signature.
+func (in AccessoryType) IsSignature() bool {
+	return in == AccessoryTypeCosignSignature || in == AccessoryTypeNotationSignature
+}
+
+// HasSignature returns true if at least one of the provided accessories is a signature.
+func HasSignature(accessories []*model.Accessory) bool {
+	for _, a := range accessories {
+		if a != nil && AccessoryType(a.Type).IsSignature() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FilterAccessories returns the accessories matching one of the provided types.
+func FilterAccessories(accessories []*model.Accessory, types ...AccessoryType) []*model.Accessory {
+	var filtered []*model.Accessory
+
+	for _, a := range accessories {
+		if a == nil {
+			continue
+		}
+
+		for _, t := range types {
+			if AccessoryType(a.Type) == t {
+				filtered = append(filtered, a)
+				break
+			}
+		}
+	}
+
+	return filtered
+}
+
 // TODO: Introduce this, once https://github.com/goharbor/harbor/issues/13468 is resolved.
 //func (in Addition) string() string {
 //	return string(in)
@@ -93,7 +157,7 @@ func (c *RESTClient) AddArtifactLabel(ctx context.Context, projectName, reposito
 		Label:          label,
 		ProjectName:    projectName,
 		Reference:      reference,
-		RepositoryName: repositoryName,
+		RepositoryName: imageref.EscapeRepositoryName(repositoryName),
 		Context:        ctx,
 	}
 
@@ -113,7 +177,7 @@ func (c *RESTClient) CopyArtifact(ctx context.Context, from *CopyReference, proj
 	params := &artifact.CopyArtifactParams{
 		From:           f,
 		ProjectName:    projectName,
-		RepositoryName: repositoryName,
+		RepositoryName: imageref.EscapeRepositoryName(repositoryName),
 		Context:        ctx,
 	}
 
@@ -128,7 +192,7 @@ func (c *RESTClient) CreateTag(ctx context.Context, projectName, repositoryName,
 	params := &artifact.CreateTagParams{
 		ProjectName:    projectName,
 		Reference:      reference,
-		RepositoryName: repositoryName,
+		RepositoryName: imageref.EscapeRepositoryName(repositoryName),
 		Tag:            tag,
 		Context:        ctx,
 	}
@@ -144,7 +208,7 @@ func (c *RESTClient) DeleteTag(ctx context.Context, projectName, repositoryName,
 	params := &artifact.DeleteTagParams{
 		ProjectName:    projectName,
 		Reference:      reference,
-		RepositoryName: repositoryName,
+		RepositoryName: imageref.EscapeRepositoryName(repositoryName),
 		TagName:        tagName,
 		Context:        ctx,
 	}
@@ -161,7 +225,7 @@ func (c *RESTClient) GetArtifact(ctx context.Context, projectName, repositoryNam
 	params.WithPage(&c.Options.Page)
 	params.WithPageSize(&c.Options.PageSize)
 	params.WithProjectName(projectName)
-	params.WithRepositoryName(repositoryName)
+	params.WithRepositoryName(imageref.EscapeRepositoryName(repositoryName))
 	params.WithReference(reference)
 	params.WithContext(ctx)
 	params.WithWithLabel(util.BoolPtr(true))
@@ -179,7 +243,7 @@ func (c *RESTClient) DeleteArtifact(ctx context.Context, projectName, repository
 	params := artifact.NewDeleteArtifactParams()
 	params.WithTimeout(c.Options.Timeout)
 	params.WithProjectName(projectName)
-	params.WithRepositoryName(repositoryName)
+	params.WithRepositoryName(imageref.EscapeRepositoryName(repositoryName))
 	params.WithReference(reference)
 	params.WithContext(ctx)
 
@@ -191,21 +255,28 @@ func (c *RESTClient) DeleteArtifact(ctx context.Context, projectName, repository
 	return nil
 }
 
-func (c *RESTClient) ListArtifacts(ctx context.Context, projectName, repositoryName string) ([]*model.Artifact, error) {
-	var artifacts []*model.Artifact
-	page := c.Options.Page
+func (c *RESTClient) ListArtifacts(ctx context.Context, projectName, repositoryName string, opts ...config.CallOption) ([]*model.Artifact, error) {
+	o := c.Options.Apply(opts...)
 
 	params := artifact.NewListArtifactsParams()
 	params.WithContext(ctx)
-	params.WithTimeout(c.Options.Timeout)
-	params.Page = &page
-	params.PageSize = &c.Options.PageSize
-	params.Q = &c.Options.Query
-	params.Sort = &c.Options.Sort
+	params.WithTimeout(o.Timeout)
+	params.PageSize = &o.PageSize
+	params.Q = &o.Query
+	params.Sort = &o.Sort
 	params.WithProjectName(projectName)
-	params.WithRepositoryName(repositoryName)
+	params.WithRepositoryName(imageref.EscapeRepositoryName(repositoryName))
 	params.WithWithLabel(util.BoolPtr(true))
 
+	return c.listArtifacts(params, o.Page)
+}
+
+// listArtifacts lists the artifacts using 'params', starting at 'page' and continuing until all were listed.
+func (c *RESTClient) listArtifacts(params *artifact.ListArtifactsParams, page int64) ([]*model.Artifact, error) {
+	var artifacts []*model.Artifact
+
+	params.Page = &page
+
 	for {
 		resp, err := c.V2Client.Artifact.ListArtifacts(params, c.AuthInfo)
 		if err != nil {
@@ -230,20 +301,75 @@ func (c *RESTClient) ListArtifacts(ctx context.Context, projectName, repositoryN
 	return artifacts, nil
 }
 
-func (c *RESTClient) ListTags(ctx context.Context, projectName, repositoryName, reference string) ([]*model.Tag, error) {
+// ListAccessories returns the accessories (signatures, SBOMs, ...) attached to the artifact identified by 'reference'.
+func (c *RESTClient) ListAccessories(ctx context.Context, projectName, repositoryName, reference string, opts ...config.CallOption) ([]*model.Accessory, error) {
+	o := c.Options.Apply(opts...)
+
+	var accessories []*model.Accessory
+	page := o.Page
+
+	params := artifact.NewListAccessoriesParams()
+	params.Page = &page
+	params.PageSize = &o.PageSize
+	params.WithProjectName(projectName)
+	params.WithRepositoryName(imageref.EscapeRepositoryName(repositoryName))
+	params.WithReference(reference)
+	params.Q = &o.Query
+	params.Sort = &o.Sort
+	params.WithContext(ctx)
+	params.WithTimeout(o.Timeout)
+
+	for {
+		resp, err := c.V2Client.Artifact.ListAccessories(params, c.AuthInfo)
+		if err != nil {
+			return nil, handleSwaggerArtifactErrors(err)
+		}
+
+		if len(resp.Payload) == 0 {
+			break
+		}
+
+		totalCount := resp.XTotalCount
+
+		accessories = append(accessories, resp.Payload...)
+
+		if int64(len(accessories)) >= totalCount {
+			break
+		}
+
+		page++
+	}
+
+	return accessories, nil
+}
+
+// DeleteAccessory deletes the provided accessory.
+// Accessories are stored as artifacts inside the repository of their subject artifact,
+// thus they are deleted by their digest.
+func (c *RESTClient) DeleteAccessory(ctx context.Context, projectName, repositoryName string, accessory *model.Accessory) error {
+	if accessory == nil || accessory.Digest == "" {
+		return &errors.ErrArtifactAccessoryNotProvided{}
+	}
+
+	return c.DeleteArtifact(ctx, projectName, repositoryName, accessory.Digest)
+}
+
+func (c *RESTClient) ListTags(ctx context.Context, projectName, repositoryName, reference string, opts ...config.CallOption) ([]*model.Tag, error) {
+	o := c.Options.Apply(opts...)
+
 	var tags []*model.Tag
-	page := c.Options.Page
+	page := o.Page
 
 	params := artifact.NewListTagsParams()
 	params.Page = &page
-	params.PageSize = &c.Options.PageSize
+	params.PageSize = &o.PageSize
 	params.WithProjectName(projectName)
-	params.WithRepositoryName(repositoryName)
+	params.WithRepositoryName(imageref.EscapeRepositoryName(repositoryName))
 	params.WithReference(reference)
-	params.Q = &c.Options.Query
-	params.Sort = &c.Options.Sort
+	params.Q = &o.Query
+	params.Sort = &o.Sort
 	params.WithContext(ctx)
-	params.WithTimeout(c.Options.Timeout)
+	params.WithTimeout(o.Timeout)
 
 	for {
 		resp, err := c.V2Client.Artifact.ListTags(params, c.AuthInfo)
@@ -273,7 +399,7 @@ func (c *RESTClient) RemoveLabel(ctx context.Context, projectName, repositoryNam
 	params := &artifact.RemoveLabelParams{
 		LabelID:        id,
 		ProjectName:    projectName,
-		RepositoryName: repositoryName,
+		RepositoryName: imageref.EscapeRepositoryName(repositoryName),
 		Reference:      reference,
 		Context:        ctx,
 	}
@@ -288,6 +414,81 @@ func (c *RESTClient) RemoveLabel(ctx context.Context, projectName, repositoryNam
 	return nil
 }
 
+// GetArtifactByReference returns the artifact identified by 'ref', see reference.Reference.ArtifactReference.
+func (c *RESTClient) GetArtifactByReference(ctx context.Context, ref *imageref.Reference) (*model.Artifact, error) {
+	if err := ref.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c.GetArtifact(ctx, ref.Project, ref.Repository, ref.ArtifactReference())
+}
+
+// DeleteArtifactByReference deletes the artifact identified by 'ref', see reference.Reference.ArtifactReference.
+func (c *RESTClient) DeleteArtifactByReference(ctx context.Context, ref *imageref.Reference) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+
+	return c.DeleteArtifact(ctx, ref.Project, ref.Repository, ref.ArtifactReference())
+}
+
+// ListArtifactsByReference lists the artifacts of the repository referenced by 'ref'. Its tag and digest are ignored.
+func (c *RESTClient) ListArtifactsByReference(ctx context.Context, ref *imageref.Reference, opts ...config.CallOption) ([]*model.Artifact, error) {
+	if err := ref.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c.ListArtifacts(ctx, ref.Project, ref.Repository, opts...)
+}
+
+// CreateTagByReference adds 'tag' to the artifact identified by 'ref'.
+func (c *RESTClient) CreateTagByReference(ctx context.Context, ref *imageref.Reference, tag *model.Tag) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+
+	return c.CreateTag(ctx, ref.Project, ref.Repository, ref.ArtifactReference(), tag)
+}
+
+// ListTagsByReference lists the tags of the artifact identified by 'ref'.
+func (c *RESTClient) ListTagsByReference(ctx context.Context, ref *imageref.Reference, opts ...config.CallOption) ([]*model.Tag, error) {
+	if err := ref.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c.ListTags(ctx, ref.Project, ref.Repository, ref.ArtifactReference(), opts...)
+}
+
+// ListAccessoriesByReference lists the accessories attached to the artifact identified by 'ref'.
+func (c *RESTClient) ListAccessoriesByReference(ctx context.Context, ref *imageref.Reference, opts ...config.CallOption) ([]*model.Accessory, error) {
+	if err := ref.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c.ListAccessories(ctx, ref.Project, ref.Repository, ref.ArtifactReference(), opts...)
+}
+
+// CopyArtifactByReference copies the artifact identified by 'from' into the repository referenced by 'to'.
+// The artifact is copied by its digest if set, its tag otherwise. The tag and digest of 'to' are ignored.
+func (c *RESTClient) CopyArtifactByReference(ctx context.Context, from, to *imageref.Reference) error {
+	if err := from.Validate(); err != nil {
+		return err
+	}
+
+	if err := to.Validate(); err != nil {
+		return err
+	}
+
+	src := &CopyReference{ProjectName: from.Project, RepositoryName: from.Repository}
+	if from.Digest != "" {
+		src.Digest = from.Digest
+	} else {
+		src.Tag = from.ArtifactReference()
+	}
+
+	return c.CopyArtifact(ctx, src, to.Project, to.Repository)
+}
+
 // TODO: Introduce this, once https://github.com/goharbor/harbor/issues/13468 is resolved.
 //func (c *RESTClient) GetAddition(ctx context.Context, projectName, repositoryName, reference string, addition Addition) (interface{}, error) {
 //	params := &artifact.GetAdditionParams{
@@ -10,6 +10,7 @@ import (
 	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/artifact"
 	"github.com/mittwald/goharbor-client/v5/apiv2/mocks"
 	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
 	clienttesting "github.com/mittwald/goharbor-client/v5/apiv2/pkg/testing"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -308,3 +309,57 @@ func TestRESTClient_RemoveLabel(t *testing.T) {
 
 	mockClient.Artifact.AssertExpectations(t)
 }
+
+func TestRESTClient_ListAccessories(t *testing.T) {
+	apiClient, mockClient := APIandMockClientsForTests()
+
+	listParams := artifact.NewListAccessoriesParams()
+	listParams.WithProjectName(projectName)
+	listParams.WithRepositoryName(repositoryName)
+	listParams.WithReference(reference)
+	listParams.WithTimeout(apiClient.Options.Timeout)
+	listParams.WithContext(ctx)
+	listParams.WithPage(&apiClient.Options.Page)
+	listParams.WithPageSize(&apiClient.Options.PageSize)
+	listParams.WithSort(&apiClient.Options.Sort)
+	listParams.WithQ(&apiClient.Options.Query)
+
+	accessories := []*model.Accessory{
+		{Digest: "sha256:sbom", Type: AccessoryTypeSBOM.String()},
+		{Digest: "sha256:sig", Type: AccessoryTypeCosignSignature.String()},
+	}
+
+	mockClient.Artifact.On("ListAccessories", listParams, mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&artifact.ListAccessoriesOK{Payload: accessories, XTotalCount: 2}, nil)
+
+	resp, err := apiClient.ListAccessories(ctx, projectName, repositoryName, reference)
+	require.NoError(t, err)
+	require.Len(t, resp, 2)
+	require.True(t, HasSignature(resp))
+	require.Len(t, FilterAccessories(resp, AccessoryTypeSBOM), 1)
+	require.False(t, HasSignature(FilterAccessories(resp, AccessoryTypeSBOM, AccessoryTypeNydus)))
+
+	mockClient.Artifact.AssertExpectations(t)
+}
+
+func TestRESTClient_DeleteAccessory(t *testing.T) {
+	apiClient, mockClient := APIandMockClientsForTests()
+
+	deleteParams := artifact.NewDeleteArtifactParams()
+	deleteParams.WithTimeout(apiClient.Options.Timeout)
+	deleteParams.WithProjectName(projectName)
+	deleteParams.WithRepositoryName(repositoryName)
+	deleteParams.WithReference("sha256:sig")
+	deleteParams.WithContext(ctx)
+
+	mockClient.Artifact.On("DeleteArtifact", deleteParams, mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&artifact.DeleteArtifactOK{}, nil)
+
+	err := apiClient.DeleteAccessory(ctx, projectName, repositoryName, &model.Accessory{Digest: "sha256:sig"})
+	require.NoError(t, err)
+
+	err = apiClient.DeleteAccessory(ctx, projectName, repositoryName, &model.Accessory{})
+	require.IsType(t, &errors.ErrArtifactAccessoryNotProvided{}, err)
+
+	mockClient.Artifact.AssertExpectations(t)
+}
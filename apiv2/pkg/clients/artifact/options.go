@@ -0,0 +1,275 @@
+package artifact
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/artifact"
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/query"
+	imageref "github.com/mittwald/goharbor-client/v5/apiv2/pkg/reference"
+)
+
+// ArtifactType defines the kind of an artifact.
+type ArtifactType string
+
+const (
+	ArtifactTypeImage ArtifactType = "IMAGE"
+	ArtifactTypeChart ArtifactType = "CHART"
+	ArtifactTypeCNAB  ArtifactType = "CNAB"
+	ArtifactTypeSBOM  ArtifactType = "SBOM"
+)
+
+func (in ArtifactType) String() string {
+	return string(in)
+}
+
+// MIME types of the scan reports requested by ArtifactDetails.AcceptVulnerabilities.
+const (
+	MimeTypeGenericVulnerabilityReport = "application/vnd.security.vulnerability.report; version=1.1"
+	MimeTypeHarborVulnerabilityReport  = "application/vnd.scanner.adapter.vuln.report.harbor+json; version=1.0"
+	MimeTypeSBOMReport                 = "application/vnd.security.sbom.report+json; version=1.0"
+)
+
+// ArtifactDetails selects the details included in the artifacts returned by the Harbor API.
+// Omitting details reduces the cost of listing large repositories.
+type ArtifactDetails struct {
+	// Tags includes the tags of the artifacts.
+	Tags bool
+	// Labels includes the labels added to the artifacts.
+	Labels bool
+	// ScanOverview includes the summary of the latest scan of the artifacts.
+	ScanOverview bool
+	// Signatures includes the signature status inside the tags. It requires Tags.
+	Signatures bool
+	// ImmutableStatus includes whether the tags are immutable. It requires Tags.
+	ImmutableStatus bool
+	// Accessories includes the accessories attached to the artifacts, e.g. signatures and SBOMs.
+	Accessories bool
+	// AcceptVulnerabilities are the MIME types of the scan reports included in the scan overview,
+	// in order of preference, e.g. MimeTypeGenericVulnerabilityReport. Harbor's default is used if empty.
+	AcceptVulnerabilities []string
+}
+
+// FullArtifactDetails returns ArtifactDetails including every detail.
+func FullArtifactDetails() ArtifactDetails {
+	return ArtifactDetails{
+		Tags:            true,
+		Labels:          true,
+		ScanOverview:    true,
+		Signatures:      true,
+		ImmutableStatus: true,
+		Accessories:     true,
+	}
+}
+
+func (d *ArtifactDetails) acceptVulnerabilities() *string {
+	if len(d.AcceptVulnerabilities) == 0 {
+		return nil
+	}
+
+	s := strings.Join(d.AcceptVulnerabilities, ", ")
+
+	return &s
+}
+
+// ListArtifactsOptions selects the details and filters the artifacts returned by ListArtifactsWithOptions.
+// The zero value lists all artifacts without any details.
+type ListArtifactsOptions struct {
+	ArtifactDetails
+
+	// Types limits the artifacts to the given types.
+	Types []ArtifactType
+
+	// TagPattern limits the artifacts to those having at least one tag matching the pattern,
+	// using the syntax of path.Match, e.g. "v1.*". The tags are requested regardless of ArtifactDetails.Tags.
+	// Harbor narrows the artifacts down to those having a tag containing the literal prefix of the pattern,
+	// the pattern itself is matched by the client. A malformed pattern is reported as path.ErrBadPattern.
+	TagPattern string
+
+	// PushedAfter and PushedBefore limit the artifacts to those pushed in the given range, including both.
+	// Either may be the zero time for an open range.
+	PushedAfter, PushedBefore time.Time
+
+	// PulledAfter and PulledBefore limit the artifacts to those last pulled in the given range, including both.
+	// Either may be the zero time for an open range.
+	PulledAfter, PulledBefore time.Time
+}
+
+// query returns the query filtering the artifacts on the server, prepended to the query 'q' of the call.
+func (o *ListArtifactsOptions) query(q string) (string, error) {
+	b := query.Artifacts()
+
+	switch len(o.Types) {
+	case 0:
+	case 1:
+		b.Exact(query.ArtifactType, o.Types[0])
+	default:
+		types := make([]any, len(o.Types))
+		for i, t := range o.Types {
+			types[i] = t
+		}
+
+		b.Union(query.ArtifactType, types...)
+	}
+
+	if prefix, exact := tagPrefix(o.TagPattern); exact {
+		b.Exact(query.ArtifactTags, prefix)
+	} else if prefix != "" {
+		b.Fuzzy(query.ArtifactTags, prefix)
+	}
+
+	if !o.PushedAfter.IsZero() || !o.PushedBefore.IsZero() {
+		b.TimeRange(query.ArtifactPushTime, o.PushedAfter, o.PushedBefore)
+	}
+
+	if !o.PulledAfter.IsZero() || !o.PulledBefore.IsZero() {
+		b.TimeRange(query.ArtifactPullTime, o.PulledAfter, o.PulledBefore)
+	}
+
+	filter, err := b.Query()
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case filter == "":
+		return q, nil
+	case q == "":
+		return filter, nil
+	default:
+		return filter + "," + q, nil
+	}
+}
+
+// matches reports whether 'a' has a tag matching the TagPattern, if set.
+func (o *ListArtifactsOptions) matches(a *model.Artifact) bool {
+	if o.TagPattern == "" {
+		return true
+	}
+
+	for _, t := range a.Tags {
+		if t == nil {
+			continue
+		}
+
+		if ok, _ := path.Match(o.TagPattern, t.Name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tagPrefix returns the literal prefix of the tag pattern 'pattern',
+// and whether the pattern is a literal tag name without any special characters.
+func tagPrefix(pattern string) (string, bool) {
+	i := strings.IndexAny(pattern, `*?[\`)
+	if i < 0 {
+		return pattern, pattern != ""
+	}
+
+	return pattern[:i], false
+}
+
+// GetArtifactWithDetails returns the artifact identified by 'reference', including the selected 'details'.
+func (c *RESTClient) GetArtifactWithDetails(ctx context.Context, projectName, repositoryName, reference string, details ArtifactDetails) (*model.Artifact, error) {
+	params := artifact.NewGetArtifactParams()
+	params.WithTimeout(c.Options.Timeout)
+	params.WithProjectName(projectName)
+	params.WithRepositoryName(imageref.EscapeRepositoryName(repositoryName))
+	params.WithReference(reference)
+	params.WithContext(ctx)
+	params.WithWithTag(&details.Tags)
+	params.WithWithLabel(&details.Labels)
+	params.WithWithScanOverview(&details.ScanOverview)
+	params.WithWithSignature(&details.Signatures)
+	params.WithWithImmutableStatus(&details.ImmutableStatus)
+	params.WithWithAccessory(&details.Accessories)
+
+	if accept := details.acceptVulnerabilities(); accept != nil {
+		params.WithXAcceptVulnerabilities(accept)
+	}
+
+	resp, err := c.V2Client.Artifact.GetArtifact(params, c.AuthInfo)
+	if err != nil {
+		return nil, handleSwaggerArtifactErrors(err)
+	}
+
+	return resp.Payload, nil
+}
+
+// ListArtifactsWithOptions lists the artifacts of the repository matching the filters of 'listOpts',
+// including the details selected by it. A nil 'listOpts' is equivalent to its zero value.
+// Queries passed using 'opts' are combined with the filters.
+func (c *RESTClient) ListArtifactsWithOptions(ctx context.Context, projectName, repositoryName string, listOpts *ListArtifactsOptions, opts ...config.CallOption) ([]*model.Artifact, error) {
+	if listOpts == nil {
+		listOpts = &ListArtifactsOptions{}
+	}
+
+	// A malformed pattern does not match any tag, so it is rejected instead of listing no artifacts.
+	if _, err := path.Match(listOpts.TagPattern, ""); err != nil {
+		return nil, err
+	}
+
+	o := c.Options.Apply(opts...)
+
+	q, err := listOpts.query(o.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	withTag := listOpts.Tags || listOpts.TagPattern != ""
+
+	params := artifact.NewListArtifactsParams()
+	params.WithContext(ctx)
+	params.WithTimeout(o.Timeout)
+	params.PageSize = &o.PageSize
+	params.Q = &q
+	params.Sort = &o.Sort
+	params.WithProjectName(projectName)
+	params.WithRepositoryName(imageref.EscapeRepositoryName(repositoryName))
+	params.WithWithTag(&withTag)
+	params.WithWithLabel(&listOpts.Labels)
+	params.WithWithScanOverview(&listOpts.ScanOverview)
+	params.WithWithSignature(&listOpts.Signatures)
+	params.WithWithImmutableStatus(&listOpts.ImmutableStatus)
+	params.WithWithAccessory(&listOpts.Accessories)
+
+	if accept := listOpts.acceptVulnerabilities(); accept != nil {
+		params.WithXAcceptVulnerabilities(accept)
+	}
+
+	artifacts, err := c.listArtifacts(params, o.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := artifacts[:0]
+
+	for _, a := range artifacts {
+		if !listOpts.matches(a) {
+			continue
+		}
+
+		if !listOpts.Tags {
+			a.Tags = nil
+		}
+
+		filtered = append(filtered, a)
+	}
+
+	return filtered, nil
+}
+
+// ListArtifactsByReferenceWithOptions is like ListArtifactsWithOptions, listing the repository referenced by 'ref'.
+func (c *RESTClient) ListArtifactsByReferenceWithOptions(ctx context.Context, ref *imageref.Reference, listOpts *ListArtifactsOptions, opts ...config.CallOption) ([]*model.Artifact, error) {
+	if err := ref.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c.ListArtifactsWithOptions(ctx, ref.Project, ref.Repository, listOpts, opts...)
+}
@@ -0,0 +1,160 @@
+//go:build !integration
+
+package artifact
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/artifact"
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/util"
+)
+
+func TestListArtifactsOptions_Query(t *testing.T) {
+	pushed := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for want, opts := range map[string]ListArtifactsOptions{
+		"":                                 {},
+		"type=IMAGE":                       {Types: []ArtifactType{ArtifactTypeImage}},
+		`type={"IMAGE" "CHART"}`:           {Types: []ArtifactType{ArtifactTypeImage, ArtifactTypeChart}},
+		"tags=v1.2.3":                      {TagPattern: "v1.2.3"},
+		"tags=~v1.":                        {TagPattern: "v1.*"},
+		"push_time=[2023-05-01 12:00:00~]": {PushedAfter: pushed},
+		"type=SBOM,pull_time=[~2023-05-01 12:00:00]": {
+			Types:        []ArtifactType{ArtifactTypeSBOM},
+			TagPattern:   "*-rc",
+			PulledBefore: pushed,
+		},
+	} {
+		q, err := opts.query("")
+		require.NoError(t, err)
+		require.Equal(t, want, q)
+	}
+
+	q, err := (&ListArtifactsOptions{Types: []ArtifactType{ArtifactTypeCNAB}}).query("digest=sha256:abc")
+	require.NoError(t, err)
+	require.Equal(t, "type=CNAB,digest=sha256:abc", q)
+
+	_, err = (&ListArtifactsOptions{TagPattern: "a,b*"}).query("")
+	require.ErrorIs(t, err, &clienterrors.ErrInvalidQuery{})
+}
+
+func TestRESTClient_ListArtifactsWithOptions(t *testing.T) {
+	apiClient, mockClient := APIandMockClientsForTests()
+
+	accept := MimeTypeGenericVulnerabilityReport
+
+	listParams := artifact.NewListArtifactsParams()
+	listParams.WithProjectName(projectName)
+	listParams.WithRepositoryName(repositoryName)
+	listParams.WithContext(ctx)
+	listParams.WithPage(&apiClient.Options.Page)
+	listParams.WithPageSize(&apiClient.Options.PageSize)
+	listParams.WithSort(&apiClient.Options.Sort)
+	listParams.WithQ(util.StringPtr("type=IMAGE,tags=~v1."))
+	listParams.WithTimeout(apiClient.Options.Timeout)
+	listParams.WithWithTag(util.BoolPtr(true))
+	listParams.WithWithLabel(util.BoolPtr(false))
+	listParams.WithWithScanOverview(util.BoolPtr(true))
+	listParams.WithWithSignature(util.BoolPtr(false))
+	listParams.WithWithImmutableStatus(util.BoolPtr(false))
+	listParams.WithWithAccessory(util.BoolPtr(false))
+	listParams.WithXAcceptVulnerabilities(&accept)
+
+	mockClient.Artifact.On("ListArtifacts", listParams, mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&artifact.ListArtifactsOK{Payload: []*model.Artifact{
+			{Digest: "sha256:a", Tags: []*model.Tag{{Name: "v1.0"}, {Name: "latest"}}},
+			{Digest: "sha256:b", Tags: []*model.Tag{{Name: "v1-rc"}}},
+			{Digest: "sha256:c", Tags: []*model.Tag{{Name: "v1.1"}}},
+		}, XTotalCount: 3}, nil)
+
+	resp, err := apiClient.ListArtifactsWithOptions(ctx, projectName, repositoryName, &ListArtifactsOptions{
+		ArtifactDetails: ArtifactDetails{
+			ScanOverview:          true,
+			AcceptVulnerabilities: []string{MimeTypeGenericVulnerabilityReport},
+		},
+		Types:      []ArtifactType{ArtifactTypeImage},
+		TagPattern: "v1.*",
+	})
+	require.NoError(t, err)
+	require.Len(t, resp, 2)
+	require.Equal(t, "sha256:a", resp[0].Digest)
+	require.Equal(t, "sha256:c", resp[1].Digest)
+	require.Nil(t, resp[0].Tags, "tags were not requested")
+
+	mockClient.Artifact.AssertExpectations(t)
+}
+
+func TestRESTClient_ListArtifactsWithOptions_BadTagPattern(t *testing.T) {
+	apiClient, mockClient := APIandMockClientsForTests()
+
+	_, err := apiClient.ListArtifactsWithOptions(ctx, projectName, repositoryName, &ListArtifactsOptions{
+		TagPattern: "v1.[0",
+	})
+	require.ErrorIs(t, err, path.ErrBadPattern)
+
+	mockClient.Artifact.AssertNotCalled(t, "ListArtifacts", mock.Anything, mock.Anything)
+}
+
+func TestRESTClient_ListArtifactsWithOptions_Defaults(t *testing.T) {
+	apiClient, mockClient := APIandMockClientsForTests()
+
+	listParams := artifact.NewListArtifactsParams()
+	listParams.WithProjectName(projectName)
+	listParams.WithRepositoryName(repositoryName)
+	listParams.WithContext(ctx)
+	listParams.WithPage(&apiClient.Options.Page)
+	listParams.WithPageSize(&apiClient.Options.PageSize)
+	listParams.WithSort(util.StringPtr("-push_time"))
+	listParams.WithQ(util.StringPtr("digest=sha256:a"))
+	listParams.WithTimeout(apiClient.Options.Timeout)
+	listParams.WithWithTag(util.BoolPtr(false))
+	listParams.WithWithLabel(util.BoolPtr(false))
+	listParams.WithWithScanOverview(util.BoolPtr(false))
+	listParams.WithWithSignature(util.BoolPtr(false))
+	listParams.WithWithImmutableStatus(util.BoolPtr(false))
+	listParams.WithWithAccessory(util.BoolPtr(false))
+
+	mockClient.Artifact.On("ListArtifacts", listParams, mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&artifact.ListArtifactsOK{Payload: []*model.Artifact{{Digest: "sha256:a"}}, XTotalCount: 1}, nil)
+
+	resp, err := apiClient.ListArtifactsWithOptions(ctx, projectName, repositoryName, nil,
+		config.WithQuery("digest=sha256:a"), config.WithSort("-push_time"))
+	require.NoError(t, err)
+	require.Len(t, resp, 1)
+
+	mockClient.Artifact.AssertExpectations(t)
+}
+
+func TestRESTClient_GetArtifactWithDetails(t *testing.T) {
+	apiClient, mockClient := APIandMockClientsForTests()
+
+	getParams := artifact.NewGetArtifactParams()
+	getParams.WithTimeout(apiClient.Options.Timeout)
+	getParams.WithProjectName(projectName)
+	getParams.WithRepositoryName(repositoryName)
+	getParams.WithReference(reference)
+	getParams.WithContext(ctx)
+	getParams.WithWithTag(util.BoolPtr(true))
+	getParams.WithWithLabel(util.BoolPtr(true))
+	getParams.WithWithScanOverview(util.BoolPtr(true))
+	getParams.WithWithSignature(util.BoolPtr(true))
+	getParams.WithWithImmutableStatus(util.BoolPtr(true))
+	getParams.WithWithAccessory(util.BoolPtr(true))
+
+	mockClient.Artifact.On("GetArtifact", getParams, mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&artifact.GetArtifactOK{Payload: &model.Artifact{}}, nil)
+
+	resp, err := apiClient.GetArtifactWithDetails(ctx, projectName, repositoryName, reference, FullArtifactDetails())
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	mockClient.Artifact.AssertExpectations(t)
+}
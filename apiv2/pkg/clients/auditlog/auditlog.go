@@ -2,6 +2,8 @@ package auditlog
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/go-openapi/runtime"
 
@@ -25,30 +27,37 @@ type RESTClient struct {
 
 func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
 	return &RESTClient{
-		Options:  opts,
+		Options:  opts.Copy(),
 		V2Client: v2Client,
 		AuthInfo: authInfo,
 	}
 }
 
 type Client interface {
-	ListAuditLogs(ctx context.Context) ([]*model.AuditLog, error)
+	ListAuditLogs(ctx context.Context, opts ...config.CallOption) ([]*model.AuditLog, error)
+	QueryAuditLogs(ctx context.Context, q *Query, opts ...config.CallOption) ([]*model.AuditLog, error)
+	StreamAuditLogs(ctx context.Context, q *Query, fn func(*model.AuditLog) error, opts ...config.CallOption) error
+	ExportAuditLogs(ctx context.Context, w io.Writer, f Format, q *Query, opts ...config.CallOption) (int, error)
+	Follow(ctx context.Context, q *Query, interval time.Duration, opts ...FollowOption) <-chan AuditEvent
 }
 
 // ListAuditLogs lists the audit logs of all projects the current user is a member of.
-func (c *RESTClient) ListAuditLogs(ctx context.Context) ([]*model.AuditLog, error) {
+// See QueryAuditLogs and StreamAuditLogs for filtering the entries without building a query string.
+func (c *RESTClient) ListAuditLogs(ctx context.Context, opts ...config.CallOption) ([]*model.AuditLog, error) {
+	o := c.Options.Apply(opts...)
+
 	var auditLogs []*model.AuditLog
-	page := c.Options.Page
+	page := o.Page
 
 	params := auditlog.ListAuditLogsParams{
 		Page:     &page,
-		PageSize: &c.Options.PageSize,
-		Q:        &c.Options.Query,
-		Sort:     &c.Options.Sort,
+		PageSize: &o.PageSize,
+		Q:        &o.Query,
+		Sort:     &o.Sort,
 		Context:  ctx,
 	}
 
-	params.WithTimeout(c.Options.Timeout)
+	params.WithTimeout(o.Timeout)
 
 	for {
 		resp, err := c.V2Client.Auditlog.ListAuditLogs(&params, c.AuthInfo)
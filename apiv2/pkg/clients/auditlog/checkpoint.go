@@ -0,0 +1,115 @@
+package auditlog
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Checkpoint records the audit log entries emitted by Follow, so that following can be resumed after a restart.
+type Checkpoint struct {
+	// OpTime is the operation time of the last emitted entry, truncated to seconds as in Harbor's queries.
+	OpTime time.Time `json:"op_time"`
+	// IDs are the IDs of the emitted entries with the operation time OpTime.
+	// As time ranges include their start, these entries are requested again and must not be emitted twice.
+	IDs []int64 `json:"ids,omitempty"`
+}
+
+func (cp *Checkpoint) copy() *Checkpoint {
+	c := *cp
+	c.IDs = append([]int64(nil), cp.IDs...)
+
+	return &c
+}
+
+// CheckpointStore persists the Checkpoint of Follow.
+type CheckpointStore interface {
+	// Load returns the stored checkpoint, or nil if none was stored yet.
+	Load() (*Checkpoint, error)
+
+	// Save stores 'cp'.
+	Save(cp *Checkpoint) error
+}
+
+// MemoryCheckpointStore keeps the checkpoint in memory. It is the default CheckpointStore,
+// which only de-duplicates entries within the same process.
+type MemoryCheckpointStore struct {
+	mu sync.Mutex
+	cp *Checkpoint
+}
+
+// Load implements CheckpointStore.
+func (s *MemoryCheckpointStore) Load() (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cp == nil {
+		return nil, nil
+	}
+
+	return s.cp.copy(), nil
+}
+
+// Save implements CheckpointStore.
+func (s *MemoryCheckpointStore) Save(cp *Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cp = cp.copy()
+
+	return nil
+}
+
+// FileCheckpointStore stores the checkpoint as JSON in a file.
+type FileCheckpointStore struct {
+	Path string
+}
+
+// Load implements CheckpointStore.
+func (s *FileCheckpointStore) Load() (*Checkpoint, error) {
+	b, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	cp := &Checkpoint{}
+	if err := json.Unmarshal(b, cp); err != nil {
+		return nil, err
+	}
+
+	return cp, nil
+}
+
+// Save implements CheckpointStore. The file is replaced atomically, so that it is never left partially written.
+func (s *FileCheckpointStore) Save(cp *Checkpoint) error {
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), s.Path)
+}
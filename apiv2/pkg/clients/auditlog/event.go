@@ -0,0 +1,66 @@
+package auditlog
+
+import (
+	"time"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+// EventType is the kind of activity an AuditEvent describes.
+type EventType string
+
+const (
+	// EventPush describes an artifact being pushed, which Harbor records as the creation of an artifact.
+	EventPush EventType = "push"
+	// EventPull describes an artifact being pulled.
+	EventPull EventType = "pull"
+	// EventDelete describes a resource being deleted.
+	EventDelete EventType = "delete"
+	// EventCreate describes a resource other than an artifact being created.
+	EventCreate EventType = "create"
+)
+
+// AuditEvent is an audit log entry, typed by the activity it describes.
+type AuditEvent struct {
+	// Type is derived from the operation and resource type of the entry.
+	// It is the operation itself for operations unknown to this package.
+	Type         EventType
+	ID           int64
+	Time         time.Time
+	Username     string
+	ResourceType ResourceType
+	Resource     string
+
+	// Log is the audit log entry the event is derived from.
+	Log *model.AuditLog
+}
+
+// NewAuditEvent returns the AuditEvent describing the audit log entry 'l'.
+func NewAuditEvent(l *model.AuditLog) AuditEvent {
+	return AuditEvent{
+		Type:         eventType(Operation(l.Operation), ResourceType(l.ResourceType)),
+		ID:           l.ID,
+		Time:         opTime(l),
+		Username:     l.Username,
+		ResourceType: ResourceType(l.ResourceType),
+		Resource:     l.Resource,
+		Log:          l,
+	}
+}
+
+func eventType(op Operation, resourceType ResourceType) EventType {
+	switch op {
+	case OperationCreate:
+		if resourceType == ResourceTypeArtifact {
+			return EventPush
+		}
+
+		return EventCreate
+	case OperationPull:
+		return EventPull
+	case OperationDelete:
+		return EventDelete
+	default:
+		return EventType(op)
+	}
+}
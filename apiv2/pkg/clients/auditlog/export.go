@@ -0,0 +1,219 @@
+package auditlog
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+// Format is the output format of exported audit log entries.
+type Format string
+
+const (
+	// FormatJSONLines writes one JSON object per line.
+	FormatJSONLines Format = "jsonl"
+	// FormatCSV writes comma separated values, preceded by a header line.
+	FormatCSV Format = "csv"
+	// FormatCEF writes one event per line in the ArcSight Common Event Format, as understood by most SIEMs.
+	FormatCEF Format = "cef"
+)
+
+// Formats are the supported output formats.
+var Formats = []Format{FormatJSONLines, FormatCSV, FormatCEF}
+
+// ParseFormat returns the Format named 's', e.g. "csv".
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+
+	return "", &errors.ErrAuditLogUnsupportedFormat{Format: s}
+}
+
+// Encoder writes audit log entries to an io.Writer.
+type Encoder interface {
+	// Encode writes the entry 'l'.
+	Encode(l *model.AuditLog) error
+	// Flush writes any buffered data. It must be called after the last entry was encoded.
+	Flush() error
+}
+
+// NewEncoder returns an Encoder writing entries in format 'f' to 'w'.
+func NewEncoder(w io.Writer, f Format) (Encoder, error) {
+	switch f {
+	case FormatJSONLines:
+		return &jsonLinesEncoder{enc: json.NewEncoder(w)}, nil
+	case FormatCSV:
+		return &csvEncoder{w: csv.NewWriter(w)}, nil
+	case FormatCEF:
+		return &cefEncoder{w: w}, nil
+	default:
+		return nil, &errors.ErrAuditLogUnsupportedFormat{Format: string(f)}
+	}
+}
+
+// ExportAuditLogs streams the audit log entries matching 'q' to 'w' in format 'f',
+// returning the number of exported entries. A nil 'q' matches all entries.
+func (c *RESTClient) ExportAuditLogs(ctx context.Context, w io.Writer, f Format, q *Query, opts ...config.CallOption) (int, error) {
+	enc, err := NewEncoder(w, f)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+
+	err = c.StreamAuditLogs(ctx, q, func(l *model.AuditLog) error {
+		if err := enc.Encode(l); err != nil {
+			return err
+		}
+
+		n++
+
+		return nil
+	}, opts...)
+	if err != nil {
+		return n, err
+	}
+
+	return n, enc.Flush()
+}
+
+// opTime returns the operation time of 'l' in UTC.
+func opTime(l *model.AuditLog) time.Time {
+	return time.Time(l.OpTime).UTC()
+}
+
+type jsonLinesEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *jsonLinesEncoder) Encode(l *model.AuditLog) error {
+	return e.enc.Encode(l)
+}
+
+func (e *jsonLinesEncoder) Flush() error {
+	return nil
+}
+
+// csvHeader are the columns written by the CSV encoder.
+var csvHeader = []string{"id", "op_time", "username", "operation", "resource_type", "resource"}
+
+type csvEncoder struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func (e *csvEncoder) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+
+	e.headerWritten = true
+
+	return e.w.Write(csvHeader)
+}
+
+func (e *csvEncoder) Encode(l *model.AuditLog) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	return e.w.Write([]string{
+		strconv.FormatInt(l.ID, 10),
+		opTime(l).Format(time.RFC3339),
+		l.Username,
+		l.Operation,
+		l.ResourceType,
+		l.Resource,
+	})
+}
+
+// Flush writes the header if no entries were written, so that empty exports are valid CSV files, too.
+func (e *csvEncoder) Flush() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	e.w.Flush()
+
+	return e.w.Error()
+}
+
+const (
+	cefVendor  = "goharbor"
+	cefProduct = "Harbor"
+)
+
+type cefEncoder struct {
+	w io.Writer
+}
+
+// Encode writes 'l' as CEF event, using the operation as signature ID and the following extensions:
+// rt (operation time), suser (username), act (operation), externalId (entry ID),
+// cs1 (resource type) and cs2 (resource).
+func (e *cefEncoder) Encode(l *model.AuditLog) error {
+	header := []string{
+		"CEF:0",
+		cefHeader(cefVendor),
+		cefHeader(cefProduct),
+		"",
+		cefHeader(l.Operation),
+		cefHeader(strings.TrimSpace(l.Operation + " " + l.ResourceType)),
+		strconv.Itoa(cefSeverity(l.Operation)),
+	}
+
+	extensions := []string{
+		"rt=" + strconv.FormatInt(opTime(l).UnixMilli(), 10),
+		"suser=" + cefExtension(l.Username),
+		"act=" + cefExtension(l.Operation),
+		"externalId=" + strconv.FormatInt(l.ID, 10),
+		"cs1Label=resourceType",
+		"cs1=" + cefExtension(l.ResourceType),
+		"cs2Label=resource",
+		"cs2=" + cefExtension(l.Resource),
+	}
+
+	_, err := fmt.Fprintf(e.w, "%s|%s\n", strings.Join(header, "|"), strings.Join(extensions, " "))
+
+	return err
+}
+
+func (e *cefEncoder) Flush() error {
+	return nil
+}
+
+// cefSeverity maps an operation to a CEF severity between 0 and 10.
+func cefSeverity(operation string) int {
+	switch Operation(operation) {
+	case OperationDelete:
+		return 6
+	case OperationPull:
+		return 1
+	default:
+		return 3
+	}
+}
+
+var (
+	cefHeaderEscaper    = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ")
+	cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`)
+)
+
+func cefHeader(s string) string {
+	return cefHeaderEscaper.Replace(s)
+}
+
+func cefExtension(s string) string {
+	return cefExtensionEscaper.Replace(s)
+}
@@ -0,0 +1,103 @@
+//go:build !integration
+
+package auditlog
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/auditlog"
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+var entries = []*model.AuditLog{
+	{
+		ID:           1,
+		OpTime:       strfmt.DateTime(time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)),
+		Operation:    "create",
+		Resource:     "library/app:v1",
+		ResourceType: "artifact",
+		Username:     "admin",
+	},
+	{
+		ID:           2,
+		OpTime:       strfmt.DateTime(time.Date(2023, 3, 1, 13, 0, 0, 0, time.UTC)),
+		Operation:    "delete",
+		Resource:     "library/a=b|c",
+		ResourceType: "repository",
+		Username:     `ci\bot`,
+	},
+}
+
+func encode(t *testing.T, f Format, logs ...*model.AuditLog) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	enc, err := NewEncoder(&buf, f)
+	require.NoError(t, err)
+
+	for _, l := range logs {
+		require.NoError(t, enc.Encode(l))
+	}
+
+	require.NoError(t, enc.Flush())
+
+	return buf.String()
+}
+
+func TestEncoder_JSONLines(t *testing.T) {
+	require.Equal(t, `{"id":1,"op_time":"2023-03-01T12:00:00.000Z","operation":"create","resource":"library/app:v1","resource_type":"artifact","username":"admin"}
+{"id":2,"op_time":"2023-03-01T13:00:00.000Z","operation":"delete","resource":"library/a=b|c","resource_type":"repository","username":"ci\\bot"}
+`, encode(t, FormatJSONLines, entries...))
+}
+
+func TestEncoder_CSV(t *testing.T) {
+	require.Equal(t, `id,op_time,username,operation,resource_type,resource
+1,2023-03-01T12:00:00Z,admin,create,artifact,library/app:v1
+2,2023-03-01T13:00:00Z,ci\bot,delete,repository,library/a=b|c
+`, encode(t, FormatCSV, entries...))
+
+	require.Equal(t, "id,op_time,username,operation,resource_type,resource\n", encode(t, FormatCSV))
+}
+
+func TestEncoder_CEF(t *testing.T) {
+	require.Equal(t, `CEF:0|goharbor|Harbor||create|create artifact|3|rt=1677672000000 suser=admin act=create externalId=1 cs1Label=resourceType cs1=artifact cs2Label=resource cs2=library/app:v1
+CEF:0|goharbor|Harbor||delete|delete repository|6|rt=1677675600000 suser=ci\\bot act=delete externalId=2 cs1Label=resourceType cs1=repository cs2Label=resource cs2=library/a\=b|c
+`, encode(t, FormatCEF, entries...))
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("CSV")
+	require.NoError(t, err)
+	require.Equal(t, FormatCSV, f)
+
+	_, err = ParseFormat("xml")
+	require.ErrorAs(t, err, new(*clienterrors.ErrAuditLogUnsupportedFormat))
+
+	_, err = NewEncoder(&bytes.Buffer{}, "xml")
+	require.Error(t, err)
+}
+
+func TestRESTClient_ExportAuditLogs(t *testing.T) {
+	apiClient, mockClient := APIandMockClientsForTests()
+
+	mockClient.Auditlog.On("ListAuditLogs", listParams(apiClient, 1, "username=admin", "op_time,id"), mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&auditlog.ListAuditLogsOK{Payload: entries[:1], XTotalCount: 1}, nil).Once()
+
+	var buf bytes.Buffer
+
+	n, err := apiClient.ExportAuditLogs(ctx, &buf, FormatJSONLines, &Query{Username: "admin"}, config.WithPageSize(2))
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Contains(t, buf.String(), `"username":"admin"`)
+
+	mockClient.Auditlog.AssertExpectations(t)
+}
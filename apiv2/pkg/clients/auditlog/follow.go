@@ -0,0 +1,157 @@
+package auditlog
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+// DefaultFollowInterval is the interval Follow polls at if none is given.
+const DefaultFollowInterval = 10 * time.Second
+
+// FollowOption configures Follow.
+type FollowOption func(*follower)
+
+// WithCheckpointStore persists the progress of Follow in 's', so that it resumes after the last emitted entry
+// when started again. A MemoryCheckpointStore is used by default.
+func WithCheckpointStore(s CheckpointStore) FollowOption {
+	return func(f *follower) {
+		f.store = s
+	}
+}
+
+// WithErrorHandler calls 'fn' with the errors encountered while following, e.g. failed requests.
+// Failed polls are retried at the next interval. Errors are discarded by default.
+func WithErrorHandler(fn func(error)) FollowOption {
+	return func(f *follower) {
+		f.onError = fn
+	}
+}
+
+type follower struct {
+	client   *RESTClient
+	query    Query
+	interval time.Duration
+	store    CheckpointStore
+	onError  func(error)
+}
+
+// Follow polls the audit log every 'interval' and emits the entries matching 'q' in order of their operation time,
+// each entry exactly once. A nil 'q' matches all entries. Its time range and order are ignored:
+// following starts at the checkpoint loaded from the CheckpointStore, or q.From, or the current time otherwise.
+//
+// The checkpoint is saved after each poll, so that following resumes after the last emitted entry when started
+// again. The returned channel is closed when 'ctx' is done, or if the checkpoint cannot be loaded.
+func (c *RESTClient) Follow(ctx context.Context, q *Query, interval time.Duration, opts ...FollowOption) <-chan AuditEvent {
+	f := &follower{
+		client:   c,
+		interval: interval,
+		store:    &MemoryCheckpointStore{},
+		onError:  func(error) {},
+	}
+
+	if q != nil {
+		f.query = *q
+	}
+
+	if f.interval <= 0 {
+		f.interval = DefaultFollowInterval
+	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	events := make(chan AuditEvent)
+
+	go f.run(ctx, events)
+
+	return events
+}
+
+func (f *follower) run(ctx context.Context, events chan<- AuditEvent) {
+	defer close(events)
+
+	cp, err := f.store.Load()
+	if err != nil {
+		f.onError(err)
+		return
+	}
+
+	if cp == nil {
+		start := f.query.From
+		if start.IsZero() {
+			start = time.Now()
+		}
+
+		cp = &Checkpoint{OpTime: start.UTC().Truncate(time.Second)}
+
+		// Save the start, so that entries added until the first entry is emitted are not skipped after a restart.
+		if err := f.store.Save(cp); err != nil {
+			f.onError(err)
+		}
+	}
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := f.poll(ctx, events, cp); err != nil && ctx.Err() == nil {
+			f.onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll emits the entries added since 'cp', updating and saving it.
+func (f *follower) poll(ctx context.Context, events chan<- AuditEvent, cp *Checkpoint) error {
+	q := f.query
+	q.From = cp.OpTime
+	q.To = time.Time{}
+	q.Descending = false
+
+	var emitted bool
+
+	err := f.client.StreamAuditLogs(ctx, &q, func(l *model.AuditLog) error {
+		t := opTime(l).Truncate(time.Second)
+
+		switch {
+		case t.Before(cp.OpTime):
+			return nil
+		case t.Equal(cp.OpTime):
+			if slices.Contains(cp.IDs, l.ID) {
+				return nil
+			}
+
+			cp.IDs = append(cp.IDs, l.ID)
+		default:
+			cp.OpTime = t
+			cp.IDs = []int64{l.ID}
+		}
+
+		select {
+		case events <- NewAuditEvent(l):
+			emitted = true
+			return nil
+		case <-ctx.Done():
+			// The entry was not emitted, it must be requested again.
+			cp.IDs = cp.IDs[:len(cp.IDs)-1]
+			return ctx.Err()
+		}
+	})
+
+	if emitted {
+		if saveErr := f.store.Save(cp); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,137 @@
+//go:build !integration
+
+package auditlog
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/auditlog"
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+func entry(id int64, t time.Time, operation, resourceType string) *model.AuditLog {
+	return &model.AuditLog{
+		ID:           id,
+		OpTime:       strfmt.DateTime(t),
+		Operation:    operation,
+		ResourceType: resourceType,
+		Resource:     "library/app",
+		Username:     "admin",
+	}
+}
+
+func withQuery(q string) interface{} {
+	return mock.MatchedBy(func(p *auditlog.ListAuditLogsParams) bool {
+		return p.Q != nil && *p.Q == q && p.Sort != nil && *p.Sort == "op_time,id"
+	})
+}
+
+func TestRESTClient_Follow(t *testing.T) {
+	apiClient, mockClient := APIandMockClientsForTests()
+
+	t0 := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Minute)
+
+	mockClient.Auditlog.On("ListAuditLogs", withQuery("operation={\"create\" \"pull\"},op_time=[2023-03-01 12:00:00~]"), mock.Anything).
+		Return(&auditlog.ListAuditLogsOK{Payload: []*model.AuditLog{
+			entry(1, t0, "create", "artifact"),
+			entry(2, t0, "pull", "artifact"),
+			entry(3, t1, "create", "project"),
+		}, XTotalCount: 3}, nil).Once()
+	mockClient.Auditlog.On("ListAuditLogs", withQuery("operation={\"create\" \"pull\"},op_time=[2023-03-01 12:01:00~]"), mock.Anything).
+		Return(nil, errors.New("unavailable")).Once()
+	mockClient.Auditlog.On("ListAuditLogs", withQuery("operation={\"create\" \"pull\"},op_time=[2023-03-01 12:01:00~]"), mock.Anything).
+		Return(&auditlog.ListAuditLogsOK{Payload: []*model.AuditLog{
+			entry(3, t1, "create", "project"),
+			entry(4, t1, "create", "artifact"),
+		}, XTotalCount: 2}, nil).Once()
+	mockClient.Auditlog.On("ListAuditLogs", mock.Anything, mock.Anything).
+		Return(&auditlog.ListAuditLogsOK{}, nil)
+
+	store := &MemoryCheckpointStore{}
+	require.NoError(t, store.Save(&Checkpoint{OpTime: t0, IDs: []int64{1}}))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, 10)
+
+	events := apiClient.Follow(ctx, &Query{
+		Operations: []Operation{OperationCreate, OperationPull},
+		From:       t0.Add(-time.Hour),
+		Descending: true,
+	}, 10*time.Millisecond, WithCheckpointStore(store), WithErrorHandler(func(err error) { errs <- err }))
+
+	var got []AuditEvent
+	for len(got) < 3 {
+		got = append(got, <-events)
+	}
+
+	cancel()
+
+	for range events {
+		// Drain until closed.
+	}
+
+	require.Equal(t, []int64{2, 3, 4}, []int64{got[0].ID, got[1].ID, got[2].ID})
+	require.Equal(t, []EventType{EventPull, EventCreate, EventPush}, []EventType{got[0].Type, got[1].Type, got[2].Type})
+	require.Equal(t, t1, got[2].Time)
+
+	require.ErrorContains(t, <-errs, "unavailable")
+
+	cp, err := store.Load()
+	require.NoError(t, err)
+	require.Equal(t, &Checkpoint{OpTime: t1, IDs: []int64{3, 4}}, cp)
+}
+
+func TestRESTClient_Follow_LoadError(t *testing.T) {
+	apiClient, _ := APIandMockClientsForTests()
+
+	var got error
+
+	events := apiClient.Follow(ctx, nil, time.Millisecond,
+		WithCheckpointStore(&FileCheckpointStore{Path: t.TempDir()}),
+		WithErrorHandler(func(err error) { got = err }))
+
+	_, ok := <-events
+	require.False(t, ok)
+	require.Error(t, got)
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	store := &FileCheckpointStore{Path: filepath.Join(t.TempDir(), "checkpoint.json")}
+
+	cp, err := store.Load()
+	require.NoError(t, err)
+	require.Nil(t, cp)
+
+	want := &Checkpoint{OpTime: time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC), IDs: []int64{7, 8}}
+	require.NoError(t, store.Save(want))
+
+	cp, err = store.Load()
+	require.NoError(t, err)
+	require.Equal(t, want, cp)
+}
+
+func TestNewAuditEvent(t *testing.T) {
+	for want, l := range map[EventType]*model.AuditLog{
+		EventPush:   entry(1, time.Now(), "create", "artifact"),
+		EventCreate: entry(1, time.Now(), "create", "repository"),
+		EventPull:   entry(1, time.Now(), "pull", "artifact"),
+		EventDelete: entry(1, time.Now(), "delete", "tag"),
+		"update":    entry(1, time.Now(), "update", "project"),
+	} {
+		e := NewAuditEvent(l)
+		require.Equal(t, want, e.Type, strings.Join([]string{l.Operation, l.ResourceType}, " "))
+		require.Same(t, l, e.Log)
+	}
+}
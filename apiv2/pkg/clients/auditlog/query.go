@@ -0,0 +1,178 @@
+package auditlog
+
+import (
+	"context"
+	"time"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/auditlog"
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/query"
+)
+
+// Operation is the operation recorded by an audit log entry.
+type Operation string
+
+const (
+	OperationCreate Operation = "create"
+	OperationDelete Operation = "delete"
+	OperationPull   Operation = "pull"
+)
+
+func (in Operation) String() string {
+	return string(in)
+}
+
+// ResourceType is the type of the resource an audit log entry refers to.
+type ResourceType string
+
+const (
+	ResourceTypeArtifact   ResourceType = "artifact"
+	ResourceTypeProject    ResourceType = "project"
+	ResourceTypeRepository ResourceType = "repository"
+	ResourceTypeTag        ResourceType = "tag"
+)
+
+func (in ResourceType) String() string {
+	return string(in)
+}
+
+// Query filters the audit log entries returned by QueryAuditLogs, StreamAuditLogs and ExportAuditLogs.
+// Fields left empty do not filter the entries, the zero value matches all entries.
+type Query struct {
+	// Operations limits the entries to any of the given operations.
+	Operations []Operation
+	// ResourceTypes limits the entries to any of the given resource types.
+	ResourceTypes []ResourceType
+	// Resource limits the entries to resources containing the given string, e.g. "library/app".
+	Resource string
+	// Username limits the entries to operations of the given user.
+	Username string
+	// From and To limit the entries to operations in the given time range, including both.
+	// Either may be the zero time for an open range.
+	From, To time.Time
+	// Descending returns the newest entries first. By default, the oldest entries are returned first,
+	// so that entries added while the results are paged through do not shift the following pages.
+	Descending bool
+}
+
+// options returns the CallOptions applying the query to a list operation.
+func (q *Query) options() ([]config.CallOption, error) {
+	b := query.AuditLogs()
+
+	union(b, query.AuditLogOperation, q.Operations)
+	union(b, query.AuditLogResourceType, q.ResourceTypes)
+
+	if q.Resource != "" {
+		b.Fuzzy(query.AuditLogResource, q.Resource)
+	}
+
+	if q.Username != "" {
+		b.Exact(query.AuditLogUsername, q.Username)
+	}
+
+	if !q.From.IsZero() || !q.To.IsZero() {
+		b.TimeRange(query.AuditLogOpTime, q.From, q.To)
+	}
+
+	// Entries with the same operation time are ordered by ID, so that the order is stable across pages.
+	if q.Descending {
+		b.Desc(query.AuditLogOpTime).Desc(query.AuditLogID)
+	} else {
+		b.Asc(query.AuditLogOpTime).Asc(query.AuditLogID)
+	}
+
+	s, err := b.Query()
+	if err != nil {
+		return nil, err
+	}
+
+	// The query replaces the client's default query, even if it matches all entries.
+	return []config.CallOption{config.WithQuery(s), config.WithSort(b.Sort())}, nil
+}
+
+// union adds an exact term for a single value and a union for multiple values.
+func union[T any](b *query.Builder[query.AuditLogField], f query.AuditLogField, values []T) {
+	switch len(values) {
+	case 0:
+	case 1:
+		b.Exact(f, values[0])
+	default:
+		v := make([]any, len(values))
+		for i := range values {
+			v[i] = values[i]
+		}
+
+		b.Union(f, v...)
+	}
+}
+
+// QueryAuditLogs returns the audit log entries matching 'q'. A nil 'q' matches all entries.
+// Use StreamAuditLogs to process large numbers of entries without holding them in memory.
+func (c *RESTClient) QueryAuditLogs(ctx context.Context, q *Query, opts ...config.CallOption) ([]*model.AuditLog, error) {
+	var auditLogs []*model.AuditLog
+
+	err := c.StreamAuditLogs(ctx, q, func(l *model.AuditLog) error {
+		auditLogs = append(auditLogs, l)
+		return nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return auditLogs, nil
+}
+
+// StreamAuditLogs calls 'fn' for each audit log entry matching 'q', requesting one page at a time.
+// A nil 'q' matches all entries. Streaming stops at the first error returned by 'fn', which is returned.
+// CallOptions overriding the query or sort order take precedence over 'q'.
+func (c *RESTClient) StreamAuditLogs(ctx context.Context, q *Query, fn func(*model.AuditLog) error, opts ...config.CallOption) error {
+	if q == nil {
+		q = &Query{}
+	}
+
+	queryOpts, err := q.options()
+	if err != nil {
+		return err
+	}
+
+	o := c.Options.Apply(append(queryOpts, opts...)...)
+
+	var seen int64
+	page := o.Page
+
+	params := auditlog.ListAuditLogsParams{
+		Page:     &page,
+		PageSize: &o.PageSize,
+		Q:        &o.Query,
+		Sort:     &o.Sort,
+		Context:  ctx,
+	}
+
+	params.WithTimeout(o.Timeout)
+
+	for {
+		resp, err := c.V2Client.Auditlog.ListAuditLogs(&params, c.AuthInfo)
+		if err != nil {
+			return handleSwaggerAuditLogErrors(err)
+		}
+
+		if len(resp.Payload) == 0 {
+			return nil
+		}
+
+		for _, l := range resp.Payload {
+			if err := fn(l); err != nil {
+				return err
+			}
+		}
+
+		seen += int64(len(resp.Payload))
+
+		if seen >= resp.XTotalCount {
+			return nil
+		}
+
+		page++
+	}
+}
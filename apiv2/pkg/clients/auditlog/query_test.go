@@ -0,0 +1,107 @@
+//go:build !integration
+
+package auditlog
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/auditlog"
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/util"
+)
+
+func TestQuery_Options(t *testing.T) {
+	from := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		query     Query
+		wantQuery string
+		wantSort  string
+	}{
+		{Query{}, "", "op_time,id"},
+		{Query{Descending: true}, "", "-op_time,-id"},
+		{
+			Query{Operations: []Operation{OperationDelete}, Username: "admin"},
+			"operation=delete,username=admin", "op_time,id",
+		},
+		{
+			Query{
+				Operations:    []Operation{OperationCreate, OperationDelete},
+				ResourceTypes: []ResourceType{ResourceTypeArtifact},
+				Resource:      "library/app",
+				From:          from,
+			},
+			`operation={"create" "delete"},resource_type=artifact,resource=~library/app,op_time=[2023-03-01 00:00:00~]`, "op_time,id",
+		},
+	} {
+		opts, err := tc.query.options()
+		require.NoError(t, err)
+
+		o := config.Defaults().WithQuery("username=default").Apply(opts...)
+		require.Equal(t, tc.wantQuery, o.Query)
+		require.Equal(t, tc.wantSort, o.Sort)
+	}
+
+	_, err := (&Query{Resource: "a,b"}).options()
+	require.Error(t, err)
+}
+
+func listParams(apiClient *RESTClient, page int64, q, sort string) *auditlog.ListAuditLogsParams {
+	params := &auditlog.ListAuditLogsParams{
+		Page:     &page,
+		PageSize: util.Int64Ptr(2),
+		Q:        &q,
+		Sort:     &sort,
+		Context:  ctx,
+	}
+
+	params.WithTimeout(apiClient.Options.Timeout)
+
+	return params
+}
+
+func TestRESTClient_StreamAuditLogs(t *testing.T) {
+	apiClient, mockClient := APIandMockClientsForTests()
+
+	mockClient.Auditlog.On("ListAuditLogs", listParams(apiClient, 1, "operation=pull", "op_time,id"), mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&auditlog.ListAuditLogsOK{Payload: []*model.AuditLog{{ID: 1}, {ID: 2}}, XTotalCount: 3}, nil).Once()
+	mockClient.Auditlog.On("ListAuditLogs", listParams(apiClient, 2, "operation=pull", "op_time,id"), mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&auditlog.ListAuditLogsOK{Payload: []*model.AuditLog{{ID: 3}}, XTotalCount: 3}, nil).Once()
+
+	var ids []int64
+
+	err := apiClient.StreamAuditLogs(ctx, &Query{Operations: []Operation{OperationPull}}, func(l *model.AuditLog) error {
+		ids = append(ids, l.ID)
+		return nil
+	}, config.WithPageSize(2))
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2, 3}, ids)
+
+	mockClient.Auditlog.AssertExpectations(t)
+}
+
+func TestRESTClient_StreamAuditLogs_Stop(t *testing.T) {
+	apiClient, mockClient := APIandMockClientsForTests()
+
+	mockClient.Auditlog.On("ListAuditLogs", listParams(apiClient, 1, "", "-op_time,-id"), mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&auditlog.ListAuditLogsOK{Payload: []*model.AuditLog{{ID: 2}, {ID: 1}}, XTotalCount: 10}, nil).Once()
+
+	stop := errors.New("stop")
+
+	var ids []int64
+
+	err := apiClient.StreamAuditLogs(ctx, &Query{Descending: true}, func(l *model.AuditLog) error {
+		ids = append(ids, l.ID)
+		return stop
+	}, config.WithPageSize(2))
+	require.ErrorIs(t, err, stop)
+	require.Equal(t, []int64{2}, ids)
+
+	mockClient.Auditlog.AssertExpectations(t)
+}
@@ -7,8 +7,10 @@ import (
 
 	v2client "github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client"
 	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/configure"
+	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/user"
 	"github.com/mittwald/goharbor-client/v5/apiv2/model"
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
 )
 
 // RESTClient is a subclient for handling project related actions.
@@ -25,7 +27,7 @@ type RESTClient struct {
 
 func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
 	return &RESTClient{
-		Options:  opts,
+		Options:  opts.Copy(),
 		V2Client: v2Client,
 		AuthInfo: authInfo,
 	}
@@ -34,6 +36,43 @@ func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime
 type Client interface {
 	GetConfigs(ctx context.Context) (*model.ConfigurationsResponse, error)
 	UpdateConfigs(ctx context.Context, newConfiguration *model.Configurations) error
+	PatchConfigs(ctx context.Context, desired *model.Configurations, opts *PatchOptions) ([]Change, error)
+	ValidateAuthConfigs(ctx context.Context, proposed *model.Configurations) (*AuthDiagnostics, error)
+}
+
+// PatchOptions controls how PatchConfigs applies a configuration.
+// The zero value applies all changes that are not dangerous.
+type PatchOptions struct {
+	// DryRun only computes the changes without applying them.
+	DryRun bool
+
+	// AllowDangerous applies changes that may lock out users or block the registry,
+	// e.g. enabling the read-only mode or disabling certificate verification.
+	AllowDangerous bool
+}
+
+// dangerousChanges maps the keys of configuration items to functions returning the reason
+// why changing the item to the desired value is dangerous, or an empty string if it is not.
+var dangerousChanges = map[string]func(desired interface{}) string{
+	"read_only": func(desired interface{}) string {
+		if desired == true {
+			return "the registry rejects all pushes and deletions"
+		}
+
+		return ""
+	},
+	"ldap_verify_cert":           insecureChange,
+	"oidc_verify_cert":           insecureChange,
+	"uaa_verify_cert":            insecureChange,
+	"http_authproxy_verify_cert": insecureChange,
+}
+
+func insecureChange(desired interface{}) string {
+	if desired == false {
+		return "the certificate of the authentication provider is no longer verified"
+	}
+
+	return ""
 }
 
 // GetConfigs returns a system configurations object.
@@ -62,3 +101,89 @@ func (c *RESTClient) UpdateConfigs(ctx context.Context, cfg *model.Configuration
 	_, err := c.V2Client.Configure.UpdateConfigurations(params, c.AuthInfo)
 	return handleSwaggerConfigurationsErrors(err)
 }
+
+// PatchConfigs updates only the items of the system configuration whose 'desired' value differs from the
+// current one, see Diff, and returns the changes. Non-editable items are ignored.
+// Changing the authentication mode fails with an ErrConfigureAuthModeUsersExist if users other than
+// the admin exist. Dangerous changes fail with an ErrConfigureDangerousChange unless allowed by 'opts'.
+func (c *RESTClient) PatchConfigs(ctx context.Context, desired *model.Configurations, opts *PatchOptions) ([]Change, error) {
+	if opts == nil {
+		opts = &PatchOptions{}
+	}
+
+	current, err := c.GetConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := Diff(current, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkChanges(ctx, changes, opts); err != nil {
+		return nil, err
+	}
+
+	if len(changes) == 0 || opts.DryRun {
+		return changes, nil
+	}
+
+	patch, err := Patch(changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.UpdateConfigs(ctx, patch); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// checkChanges returns an error if one of 'changes' must not be applied.
+func (c *RESTClient) checkChanges(ctx context.Context, changes []Change, opts *PatchOptions) error {
+	for _, change := range changes {
+		if change.Key == "auth_mode" {
+			users, err := c.countUsers(ctx)
+			if err != nil {
+				return err
+			}
+
+			if users > 1 {
+				return &errors.ErrConfigureAuthModeUsersExist{Users: users}
+			}
+		}
+
+		if opts.AllowDangerous {
+			continue
+		}
+
+		if check, ok := dangerousChanges[change.Key]; ok {
+			if reason := check(change.Desired); reason != "" {
+				return &errors.ErrConfigureDangerousChange{Key: change.Key, Reason: reason}
+			}
+		}
+	}
+
+	return nil
+}
+
+// countUsers returns the number of users, including the admin.
+func (c *RESTClient) countUsers(ctx context.Context) (int64, error) {
+	page, pageSize := int64(1), int64(1)
+
+	params := &user.ListUsersParams{
+		Page:     &page,
+		PageSize: &pageSize,
+		Context:  ctx,
+	}
+	params.WithTimeout(c.Options.Timeout)
+
+	resp, err := c.V2Client.User.ListUsers(params, c.AuthInfo)
+	if err != nil {
+		return 0, handleSwaggerConfigurationsErrors(err)
+	}
+
+	return resp.XTotalCount, nil
+}
@@ -5,13 +5,22 @@ import (
 
 	"github.com/go-openapi/runtime"
 
+	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/ldap"
+	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/oidc"
+
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
 )
 
-// handleSwaggerConfigurationsErrors takes a swagger generated error as input,
+// handleSwaggerConfigurationsErrors maps a swagger generated error to a typed error, which is wrapped
+// in an errors.HarborError carrying the details of the response the error originates from.
+func handleSwaggerConfigurationsErrors(in error) error {
+	return errors.WrapSwaggerError(in, mapSwaggerConfigurationsErrors(in))
+}
+
+// mapSwaggerConfigurationsErrors takes a swagger generated error as input,
 // which usually does not contain any form of error message,
 // and outputs a new error with a proper message.
-func handleSwaggerConfigurationsErrors(in error) error {
+func mapSwaggerConfigurationsErrors(in error) error {
 	t, ok := in.(*runtime.APIError)
 	if ok {
 		switch t.Code {
@@ -23,5 +32,16 @@ func handleSwaggerConfigurationsErrors(in error) error {
 			return &errors.ErrConfigureInternalServerError{}
 		}
 	}
-	return nil
+
+	switch in.(type) {
+	case *ldap.PingLdapUnauthorized, *oidc.PingOIDCUnauthorized:
+		return &errors.ErrConfigureUnauthorized{}
+	case *ldap.PingLdapForbidden, *oidc.PingOIDCForbidden:
+		return &errors.ErrConfigureNoPermission{}
+	case *ldap.PingLdapInternalServerError:
+		return &errors.ErrConfigureInternalServerError{}
+	}
+
+	// Errors which are not mapped are returned as is, so that callers never mistake a failure for success.
+	return in
 }
@@ -4,12 +4,17 @@ package configure
 
 import (
 	"context"
+	goerrors "errors"
+	"net/http"
 	"testing"
 
 	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/configure"
+	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/user"
 	"github.com/mittwald/goharbor-client/v5/apiv2/mocks"
 	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
 	clienttesting "github.com/mittwald/goharbor-client/v5/apiv2/pkg/testing"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/util"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -27,6 +32,9 @@ var (
 func APIandMockClientsForTests() (*RESTClient, *clienttesting.MockClients) {
 	desiredMockClients := &clienttesting.MockClients{
 		Configure: mocks.MockConfigureClientService{},
+		User:      mocks.MockUserClientService{},
+		Ldap:      mocks.MockLdapClientService{},
+		OIDC:      mocks.MockOidcClientService{},
 	}
 
 	v2Client := clienttesting.BuildV2ClientWithMocks(desiredMockClients)
@@ -52,6 +60,36 @@ func TestRESTClient_GetConfigurations(t *testing.T) {
 	mockClient.Configure.AssertExpectations(t)
 }
 
+func TestRESTClient_GetConfigurations_UnmappedErrors(t *testing.T) {
+	apiClient, mockClient := APIandMockClientsForTests()
+
+	params := &configure.GetConfigurationsParams{
+		Context: ctx,
+	}
+	params.WithTimeout(apiClient.Options.Timeout)
+
+	mockClient.Configure.On("GetConfigurations", params, mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(nil, &configure.GetConfigurationsUnauthorized{}).Once()
+
+	cfg, err := apiClient.GetConfigs(ctx)
+	require.Nil(t, cfg)
+	require.ErrorIs(t, err, &errors.ErrUnauthorized{})
+
+	he, ok := errors.AsHarborError(err)
+	require.True(t, ok)
+	require.Equal(t, http.StatusUnauthorized, he.StatusCode)
+
+	refused := goerrors.New("connection refused")
+
+	mockClient.Configure.On("GetConfigurations", params, mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(nil, refused).Once()
+
+	_, err = apiClient.GetConfigs(ctx)
+	require.ErrorIs(t, err, refused)
+
+	mockClient.Configure.AssertExpectations(t)
+}
+
 func TestRESTClient_UpdateConfigs(t *testing.T) {
 	apiClient, mockClient := APIandMockClientsForTests()
 
@@ -67,3 +105,73 @@ func TestRESTClient_UpdateConfigs(t *testing.T) {
 	require.NoError(t, err)
 	mockClient.Configure.AssertExpectations(t)
 }
+
+// mockGetConfigs makes the configure client return currentConfig.
+func mockGetConfigs(apiClient *RESTClient, m *clienttesting.MockClients) {
+	params := &configure.GetConfigurationsParams{
+		Context: ctx,
+	}
+	params.WithTimeout(apiClient.Options.Timeout)
+
+	m.Configure.On("GetConfigurations", params, mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&configure.GetConfigurationsOK{Payload: currentConfig}, nil)
+}
+
+func TestRESTClient_PatchConfigs(t *testing.T) {
+	apiClient, mockClient := APIandMockClientsForTests()
+
+	mockGetConfigs(apiClient, mockClient)
+
+	updateParams := &configure.UpdateConfigurationsParams{
+		Configurations: &model.Configurations{SessionTimeout: util.Int64Ptr(120)},
+		Context:        ctx,
+	}
+	updateParams.WithTimeout(apiClient.Options.Timeout)
+
+	mockClient.Configure.On("UpdateConfigurations", updateParams, mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&configure.UpdateConfigurationsOK{}, nil).Once()
+
+	changes, err := apiClient.PatchConfigs(ctx, &model.Configurations{
+		AuthMode:       util.StringPtr("db_auth"),
+		SessionTimeout: util.Int64Ptr(120),
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, "session_timeout", changes[0].Key)
+
+	changes, err = apiClient.PatchConfigs(ctx, &model.Configurations{SessionTimeout: util.Int64Ptr(120)}, &PatchOptions{DryRun: true})
+	require.NoError(t, err)
+	require.Len(t, changes, 1, "changes are only computed on dry runs")
+
+	mockClient.Configure.AssertExpectations(t)
+}
+
+func TestRESTClient_PatchConfigs_GuardRails(t *testing.T) {
+	apiClient, mockClient := APIandMockClientsForTests()
+
+	mockGetConfigs(apiClient, mockClient)
+
+	page, pageSize := int64(1), int64(1)
+	listParams := &user.ListUsersParams{
+		Page:     &page,
+		PageSize: &pageSize,
+		Context:  ctx,
+	}
+	listParams.WithTimeout(apiClient.Options.Timeout)
+
+	mockClient.User.On("ListUsers", listParams, mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&user.ListUsersOK{XTotalCount: 3}, nil)
+
+	_, err := apiClient.PatchConfigs(ctx, &model.Configurations{AuthMode: util.StringPtr("oidc_auth")}, &PatchOptions{AllowDangerous: true})
+	require.ErrorIs(t, err, &errors.ErrConfigureAuthModeUsersExist{})
+
+	_, err = apiClient.PatchConfigs(ctx, &model.Configurations{ReadOnly: util.BoolPtr(true)}, nil)
+	require.ErrorIs(t, err, &errors.ErrConfigureDangerousChange{})
+
+	changes, err := apiClient.PatchConfigs(ctx, &model.Configurations{ReadOnly: util.BoolPtr(true)}, &PatchOptions{DryRun: true, AllowDangerous: true})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+
+	mockClient.Configure.AssertNotCalled(t, "UpdateConfigurations", mock.Anything, mock.Anything)
+	mockClient.User.AssertExpectations(t)
+}
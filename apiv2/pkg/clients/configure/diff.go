@@ -0,0 +1,160 @@
+package configure
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+// Change describes a configuration item whose desired value differs from the current one.
+type Change struct {
+	// Key is the JSON name of the item, e.g. "auth_mode".
+	Key string `json:"key"`
+
+	// Current is the current value of the item, which is nil for write-only items
+	// such as "ldap_search_password" that are never returned by Harbor.
+	Current interface{} `json:"current"`
+
+	// Desired is the value the item is changed to.
+	Desired interface{} `json:"desired"`
+}
+
+// item is a configuration item of a configurations response, e.g. a StringConfigItem.
+type item struct {
+	Editable bool            `json:"editable"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// responseItems returns the items of 'resp' keyed by their JSON name.
+// Entries that are not of the {"editable", "value"} form, e.g. the scan all policy, are skipped.
+func responseItems(resp *model.ConfigurationsResponse) (map[string]item, error) {
+	raw, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, err
+	}
+
+	items := make(map[string]item, len(entries))
+
+	for key, entry := range entries {
+		var i item
+		if err := json.Unmarshal(entry, &i); err != nil || i.Value == nil {
+			continue
+		}
+
+		items[key] = i
+	}
+
+	return items, nil
+}
+
+// configurations decodes the JSON encoded 'values' into an update request.
+func configurations(values map[string]json.RawMessage) (*model.Configurations, error) {
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &model.Configurations{}
+
+	return cfg, json.Unmarshal(raw, cfg)
+}
+
+// ToConfigurations converts the editable items of a configurations response into an update request.
+// Non-editable items and settings without counterpart in the request, like the scan all policy, are dropped.
+func ToConfigurations(resp *model.ConfigurationsResponse) (*model.Configurations, error) {
+	items, err := responseItems(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]json.RawMessage, len(items))
+
+	for key, i := range items {
+		if i.Editable {
+			values[key] = i.Value
+		}
+	}
+
+	return configurations(values)
+}
+
+// Diff returns the changes needed to turn the 'current' configuration into the 'desired' one, sorted by key.
+// Only the items set in 'desired' are compared; items that are not editable on the Harbor instance are ignored.
+// Write-only items, which are missing in 'current', are always considered changed.
+func Diff(current *model.ConfigurationsResponse, desired *model.Configurations) ([]Change, error) {
+	items, err := responseItems(current)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+
+	for key, value := range values {
+		c := Change{Key: key}
+
+		if i, ok := items[key]; ok {
+			if !i.Editable || bytes.Equal(i.Value, value) {
+				continue
+			}
+
+			if c.Current, err = decode(i.Value); err != nil {
+				return nil, err
+			}
+		}
+
+		if c.Desired, err = decode(value); err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, c)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes, nil
+}
+
+// Patch returns an update request setting only the desired values of 'changes'.
+func Patch(changes []Change) (*model.Configurations, error) {
+	values := make(map[string]json.RawMessage, len(changes))
+
+	for _, c := range changes {
+		raw, err := json.Marshal(c.Desired)
+		if err != nil {
+			return nil, err
+		}
+
+		values[c.Key] = raw
+	}
+
+	return configurations(values)
+}
+
+// decode decodes a JSON value, keeping numbers as json.Number.
+func decode(raw json.RawMessage) (interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+
+	var v interface{}
+
+	return v, d.Decode(&v)
+}
@@ -0,0 +1,64 @@
+//go:build !integration
+
+package configure
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/util"
+)
+
+var currentConfig = &model.ConfigurationsResponse{
+	AuthMode:       &model.StringConfigItem{Value: "db_auth", Editable: true},
+	ReadOnly:       &model.BoolConfigItem{Value: false, Editable: true},
+	SessionTimeout: &model.IntegerConfigItem{Value: 60, Editable: true},
+	OIDCName:       &model.StringConfigItem{Value: "", Editable: true},
+	LdapURL:        &model.StringConfigItem{Value: "ldaps://ldap.example.com", Editable: false},
+	ScanAllPolicy:  &model.ConfigurationsResponseScanAllPolicy{Type: "daily"},
+}
+
+func TestToConfigurations(t *testing.T) {
+	cfg, err := ToConfigurations(currentConfig)
+	require.NoError(t, err)
+
+	require.Equal(t, &model.Configurations{
+		AuthMode:       util.StringPtr("db_auth"),
+		ReadOnly:       util.BoolPtr(false),
+		SessionTimeout: util.Int64Ptr(60),
+		OIDCName:       util.StringPtr(""),
+	}, cfg, "non-editable items and the scan all policy are dropped")
+}
+
+func TestDiff(t *testing.T) {
+	changes, err := Diff(currentConfig, &model.Configurations{
+		AuthMode:           util.StringPtr("db_auth"),
+		SessionTimeout:     util.Int64Ptr(120),
+		OIDCName:           util.StringPtr("sso"),
+		LdapURL:            util.StringPtr("ldaps://other.example.com"),
+		LdapSearchPassword: util.StringPtr("secret"),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []Change{
+		{Key: "ldap_search_password", Current: nil, Desired: "secret"},
+		{Key: "oidc_name", Current: "", Desired: "sso"},
+		{Key: "session_timeout", Current: json.Number("60"), Desired: json.Number("120")},
+	}, changes, "unchanged and non-editable items are ignored, write-only items are always changed")
+
+	patch, err := Patch(changes)
+	require.NoError(t, err)
+
+	require.Equal(t, &model.Configurations{
+		LdapSearchPassword: util.StringPtr("secret"),
+		OIDCName:           util.StringPtr("sso"),
+		SessionTimeout:     util.Int64Ptr(120),
+	}, patch)
+
+	changes, err = Diff(currentConfig, &model.Configurations{})
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
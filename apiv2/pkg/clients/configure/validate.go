@@ -0,0 +1,372 @@
+package configure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/ldap"
+	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/oidc"
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/util"
+)
+
+const (
+	// AuthModeLDAP is the auth_mode authenticating users against an LDAP server.
+	AuthModeLDAP = "ldap_auth"
+
+	// AuthModeOIDC is the auth_mode authenticating users via an OIDC provider.
+	AuthModeOIDC = "oidc_auth"
+)
+
+// AuthDiagnostics describes the outcome of ValidateAuthConfigs.
+type AuthDiagnostics struct {
+	// AuthMode is the validated authentication mode, e.g. "ldap_auth".
+	AuthMode string `json:"auth_mode"`
+
+	// LDAP describes the LDAP settings, if AuthMode is AuthModeLDAP.
+	LDAP *LDAPDiagnostics `json:"ldap,omitempty"`
+
+	// OIDC describes the OIDC settings, if AuthMode is AuthModeOIDC.
+	OIDC *OIDCDiagnostics `json:"oidc,omitempty"`
+}
+
+// OK reports whether the validated settings are expected to work.
+// Authentication modes without connectivity checks, like "db_auth", are always OK.
+func (d *AuthDiagnostics) OK() bool {
+	if d.LDAP != nil && !d.LDAP.OK() {
+		return false
+	}
+
+	return d.OIDC == nil || d.OIDC.OK()
+}
+
+// LDAPDiagnostics describes the LDAP settings validated by ValidateAuthConfigs.
+type LDAPDiagnostics struct {
+	// URL is the URL of the LDAP server.
+	URL string `json:"url"`
+
+	// Problems lists mistakes found in the settings without contacting the server, e.g. a malformed base DN.
+	Problems []string `json:"problems,omitempty"`
+
+	// Reachable reports whether Harbor connected to the LDAP server. It is nil if unknown, which is the case
+	// if the ping failed, as Harbor only reports a message and does not tell failed connections and binds apart.
+	Reachable *bool `json:"reachable,omitempty"`
+
+	// Bound reports whether Harbor bound to the LDAP server as the search DN.
+	// It is false if no search DN is set, in which case users are searched anonymously.
+	Bound bool `json:"bound"`
+
+	// Message is the message of Harbor if the ping failed.
+	Message string `json:"message,omitempty"`
+}
+
+// OK reports whether the LDAP server is reachable, accepts the search DN, if any, and no problems were found.
+func (d *LDAPDiagnostics) OK() bool {
+	return len(d.Problems) == 0 && d.Reachable != nil && *d.Reachable && d.Message == ""
+}
+
+// OIDCDiagnostics describes the OIDC settings validated by ValidateAuthConfigs.
+type OIDCDiagnostics struct {
+	// Endpoint is the URL of the OIDC provider.
+	Endpoint string `json:"endpoint"`
+
+	// Problems lists mistakes found in the settings without contacting the provider, e.g. a missing client ID.
+	Problems []string `json:"problems,omitempty"`
+
+	// Reachable reports whether Harbor discovered the provider at the endpoint.
+	Reachable bool `json:"reachable"`
+
+	// Message is the message of Harbor if the ping failed.
+	Message string `json:"message,omitempty"`
+}
+
+// OK reports whether the OIDC provider is reachable and no problems were found.
+func (d *OIDCDiagnostics) OK() bool {
+	return len(d.Problems) == 0 && d.Reachable
+}
+
+// ValidateAuthConfigs checks whether the authentication settings of the 'proposed' configuration work,
+// without persisting anything. The proposed items are applied on top of the current configuration,
+// so 'proposed' may contain only the items to be changed, like for PatchConfigs.
+//
+// For the "ldap_auth" mode, Harbor connects to the LDAP server and binds as the search DN; the search
+// password of the current configuration is used unless 'proposed' sets one. For the "oidc_auth" mode,
+// Harbor discovers the OIDC provider at the endpoint. Harbor does not search with unsaved settings,
+// so the base DN, filter and scope are only checked for mistakes by the client.
+// Other authentication modes are not checked.
+//
+// A failed check is reported by the returned AuthDiagnostics; an error is only returned if the checks
+// could not be run, e.g. because the user is not an admin.
+func (c *RESTClient) ValidateAuthConfigs(ctx context.Context, proposed *model.Configurations) (*AuthDiagnostics, error) {
+	current, err := c.GetConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := ToConfigurations(current)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := merge(cfg, proposed); err != nil {
+		return nil, err
+	}
+
+	d := &AuthDiagnostics{AuthMode: stringValue(cfg.AuthMode)}
+
+	switch d.AuthMode {
+	case AuthModeLDAP:
+		d.LDAP, err = c.validateLDAP(ctx, cfg)
+	case AuthModeOIDC:
+		d.OIDC, err = c.validateOIDC(ctx, cfg)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func (c *RESTClient) validateLDAP(ctx context.Context, cfg *model.Configurations) (*LDAPDiagnostics, error) {
+	conf := &model.LdapConf{
+		LdapBaseDn:            stringValue(cfg.LdapBaseDn),
+		LdapConnectionTimeout: int64Value(cfg.LdapTimeout),
+		LdapFilter:            stringValue(cfg.LdapFilter),
+		LdapScope:             int64Value(cfg.LdapScope),
+		LdapSearchDn:          stringValue(cfg.LdapSearchDn),
+		LdapSearchPassword:    stringValue(cfg.LdapSearchPassword),
+		LdapUID:               stringValue(cfg.LdapUID),
+		LdapURL:               stringValue(cfg.LdapURL),
+		LdapVerifyCert:        cfg.LdapVerifyCert == nil || *cfg.LdapVerifyCert,
+	}
+
+	d := &LDAPDiagnostics{
+		URL:      conf.LdapURL,
+		Problems: ldapProblems(conf),
+	}
+
+	// Harbor falls back to the stored LDAP settings if no URL is given, which would not check the proposed ones.
+	if conf.LdapURL == "" {
+		return d, nil
+	}
+
+	params := &ldap.PingLdapParams{
+		Ldapconf: conf,
+		Context:  ctx,
+	}
+	params.WithTimeout(c.Options.Timeout)
+
+	resp, err := c.V2Client.Ldap.PingLdap(params, c.AuthInfo)
+	if err != nil {
+		message, ok := pingFailure(err)
+		if !ok {
+			return nil, handleSwaggerConfigurationsErrors(err)
+		}
+
+		d.Message = message
+
+		return d, nil
+	}
+
+	switch {
+	case resp.Payload == nil:
+		d.Reachable = util.BoolPtr(true)
+	case resp.Payload.Success:
+		d.Reachable, d.Bound = util.BoolPtr(true), conf.LdapSearchDn != ""
+	default:
+		d.Message = resp.Payload.Message
+	}
+
+	return d, nil
+}
+
+func (c *RESTClient) validateOIDC(ctx context.Context, cfg *model.Configurations) (*OIDCDiagnostics, error) {
+	d := &OIDCDiagnostics{
+		Endpoint: stringValue(cfg.OIDCEndpoint),
+		Problems: oidcProblems(cfg),
+	}
+
+	if d.Endpoint == "" {
+		return d, nil
+	}
+
+	params := &oidc.PingOIDCParams{
+		Endpoint: oidc.PingOIDCBody{
+			URL:        d.Endpoint,
+			VerifyCert: cfg.OIDCVerifyCert == nil || *cfg.OIDCVerifyCert,
+		},
+		Context: ctx,
+	}
+	params.WithTimeout(c.Options.Timeout)
+
+	if _, err := c.V2Client.OIDC.PingOIDC(params, c.AuthInfo); err != nil {
+		message, ok := pingFailure(err)
+		if !ok {
+			return nil, handleSwaggerConfigurationsErrors(err)
+		}
+
+		d.Message = message
+
+		return d, nil
+	}
+
+	d.Reachable = true
+
+	return d, nil
+}
+
+// pingFailure returns the message of 'err' if it reports a failed ping rather than a failed request.
+// Harbor answers pings it could not complete with a bad request.
+func pingFailure(err error) (string, bool) {
+	he, ok := errors.AsHarborError(errors.WrapSwaggerError(err, err))
+	if !ok || !he.Is(&errors.ErrBadRequest{}) {
+		return "", false
+	}
+
+	if details := he.Details(); details != "" {
+		return details, true
+	}
+
+	return he.Error(), true
+}
+
+// ldapProblems returns the mistakes in the LDAP settings 'conf'.
+func ldapProblems(conf *model.LdapConf) []string {
+	var problems []string
+
+	if conf.LdapURL == "" {
+		problems = append(problems, "ldap_url is not set")
+	} else if u, err := url.Parse(conf.LdapURL); err == nil && strings.Contains(conf.LdapURL, "://") &&
+		u.Scheme != "ldap" && u.Scheme != "ldaps" {
+		problems = append(problems, fmt.Sprintf("ldap_url has the unsupported scheme %q, expected ldap or ldaps", u.Scheme))
+	}
+
+	if conf.LdapBaseDn == "" {
+		problems = append(problems, "ldap_base_dn is not set")
+	} else if !validDN(conf.LdapBaseDn) {
+		problems = append(problems, fmt.Sprintf("ldap_base_dn %q is not a valid DN", conf.LdapBaseDn))
+	}
+
+	if conf.LdapSearchDn != "" && !validDN(conf.LdapSearchDn) {
+		problems = append(problems, fmt.Sprintf("ldap_search_dn %q is not a valid DN", conf.LdapSearchDn))
+	}
+
+	if conf.LdapUID == "" {
+		problems = append(problems, "ldap_uid is not set")
+	}
+
+	if conf.LdapScope < 0 || conf.LdapScope > 2 {
+		problems = append(problems, fmt.Sprintf("ldap_scope %d is invalid, expected 0 (base), 1 (one level) or 2 (subtree)", conf.LdapScope))
+	}
+
+	if conf.LdapFilter != "" && !validFilter(conf.LdapFilter) {
+		problems = append(problems, fmt.Sprintf("ldap_filter %q is not a parenthesized LDAP filter", conf.LdapFilter))
+	}
+
+	return problems
+}
+
+// validDN reports whether 'dn' consists of attribute=value pairs, e.g. "ou=people,dc=example,dc=com".
+// Escaped separators are not supported and may be reported as invalid.
+func validDN(dn string) bool {
+	for _, rdn := range strings.Split(dn, ",") {
+		attr, value, ok := strings.Cut(rdn, "=")
+		if !ok || strings.TrimSpace(attr) == "" || strings.TrimSpace(value) == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validFilter reports whether 'filter' is enclosed in balanced parentheses, e.g. "(objectClass=person)".
+func validFilter(filter string) bool {
+	filter = strings.TrimSpace(filter)
+	if !strings.HasPrefix(filter, "(") || !strings.HasSuffix(filter, ")") {
+		return false
+	}
+
+	depth := 0
+
+	for i, r := range filter {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+
+		if depth == 0 && i < len(filter)-1 {
+			return false
+		}
+	}
+
+	return depth == 0
+}
+
+// oidcProblems returns the mistakes in the OIDC settings of 'cfg'.
+func oidcProblems(cfg *model.Configurations) []string {
+	var problems []string
+
+	if endpoint := stringValue(cfg.OIDCEndpoint); endpoint == "" {
+		problems = append(problems, "oidc_endpoint is not set")
+	} else if u, err := url.Parse(endpoint); err != nil || u.Scheme != "https" || u.Host == "" {
+		problems = append(problems, fmt.Sprintf("oidc_endpoint %q is not an https URL", endpoint))
+	}
+
+	if stringValue(cfg.OIDCName) == "" {
+		problems = append(problems, "oidc_name is not set")
+	}
+
+	if stringValue(cfg.OIDCClientID) == "" {
+		problems = append(problems, "oidc_client_id is not set")
+	}
+
+	scopes := strings.Split(stringValue(cfg.OIDCScope), ",")
+
+	hasOpenID := false
+	for _, s := range scopes {
+		hasOpenID = hasOpenID || strings.TrimSpace(s) == "openid"
+	}
+
+	if !hasOpenID {
+		problems = append(problems, `oidc_scope does not contain "openid"`)
+	}
+
+	return problems
+}
+
+// merge sets the items set in 'overlay' on 'cfg'.
+func merge(cfg, overlay *model.Configurations) error {
+	if overlay == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(overlay)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, cfg)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+func int64Value(i *int64) int64 {
+	if i == nil {
+		return 0
+	}
+
+	return *i
+}
@@ -0,0 +1,120 @@
+//go:build !integration
+
+package configure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/ldap"
+	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/oidc"
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/util"
+)
+
+var ldapConfig = &model.Configurations{
+	AuthMode:     util.StringPtr(AuthModeLDAP),
+	LdapURL:      util.StringPtr("ldaps://ldap.example.com"),
+	LdapBaseDn:   util.StringPtr("ou=people,dc=example,dc=com"),
+	LdapSearchDn: util.StringPtr("cn=harbor,dc=example,dc=com"),
+	LdapUID:      util.StringPtr("uid"),
+	LdapScope:    util.Int64Ptr(2),
+	LdapFilter:   util.StringPtr("(&(objectClass=person)(memberOf=cn=harbor,dc=example,dc=com))"),
+}
+
+func TestRESTClient_ValidateAuthConfigs_LDAP(t *testing.T) {
+	apiClient, mockClient := APIandMockClientsForTests()
+	mockGetConfigs(apiClient, mockClient)
+
+	mockClient.Ldap.On("PingLdap", mock.MatchedBy(func(p *ldap.PingLdapParams) bool {
+		return p.Ldapconf.LdapURL == "ldaps://ldap.example.com" && p.Ldapconf.LdapVerifyCert
+	}), mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&ldap.PingLdapOK{Payload: &model.LdapPingResult{Success: true}}, nil).Once()
+
+	d, err := apiClient.ValidateAuthConfigs(ctx, ldapConfig)
+	require.NoError(t, err)
+	require.True(t, d.OK())
+	require.Equal(t, &LDAPDiagnostics{URL: "ldaps://ldap.example.com", Reachable: util.BoolPtr(true), Bound: true}, d.LDAP)
+	require.Nil(t, d.OIDC)
+
+	mockClient.Ldap.On("PingLdap", mock.Anything, mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&ldap.PingLdapOK{Payload: &model.LdapPingResult{Message: "invalid credential"}}, nil).Once()
+
+	d, err = apiClient.ValidateAuthConfigs(ctx, ldapConfig)
+	require.NoError(t, err)
+	require.False(t, d.OK())
+	require.Nil(t, d.LDAP.Reachable, "a failed ping does not tell whether the server was reached")
+	require.False(t, d.LDAP.Bound)
+	require.Equal(t, "invalid credential", d.LDAP.Message)
+
+	mockClient.Ldap.On("PingLdap", mock.Anything, mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(nil, &ldap.PingLdapForbidden{}).Once()
+
+	_, err = apiClient.ValidateAuthConfigs(ctx, ldapConfig)
+	require.ErrorIs(t, err, &errors.ErrConfigureNoPermission{})
+
+	mockClient.Ldap.AssertExpectations(t)
+}
+
+func TestRESTClient_ValidateAuthConfigs_OIDC(t *testing.T) {
+	apiClient, mockClient := APIandMockClientsForTests()
+	mockGetConfigs(apiClient, mockClient)
+
+	proposed := &model.Configurations{
+		AuthMode:     util.StringPtr(AuthModeOIDC),
+		OIDCEndpoint: util.StringPtr("https://sso.example.com"),
+		OIDCClientID: util.StringPtr("harbor"),
+		OIDCScope:    util.StringPtr("openid,email"),
+	}
+
+	mockClient.OIDC.On("PingOIDC", mock.MatchedBy(func(p *oidc.PingOIDCParams) bool {
+		return p.Endpoint.URL == "https://sso.example.com"
+	}), mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(nil, &oidc.PingOIDCBadRequest{Payload: &model.Errors{Errors: []*model.Error{
+			{Code: "BAD_REQUEST", Message: "failed to verify connection"},
+		}}}).Once()
+
+	d, err := apiClient.ValidateAuthConfigs(ctx, proposed)
+	require.NoError(t, err)
+	require.False(t, d.OK())
+	require.Equal(t, []string{"oidc_name is not set"}, d.OIDC.Problems, "oidc_name of the current configuration is empty")
+	require.False(t, d.OIDC.Reachable)
+	require.Equal(t, "BAD_REQUEST: failed to verify connection", d.OIDC.Message)
+
+	mockClient.OIDC.AssertExpectations(t)
+}
+
+func TestRESTClient_ValidateAuthConfigs_Unchecked(t *testing.T) {
+	apiClient, mockClient := APIandMockClientsForTests()
+	mockGetConfigs(apiClient, mockClient)
+
+	d, err := apiClient.ValidateAuthConfigs(ctx, &model.Configurations{SessionTimeout: util.Int64Ptr(30)})
+	require.NoError(t, err)
+	require.True(t, d.OK())
+	require.Equal(t, "db_auth", d.AuthMode)
+}
+
+func TestLdapProblems(t *testing.T) {
+	require.Empty(t, ldapProblems(&model.LdapConf{
+		LdapURL:    "ldap.example.com:389",
+		LdapBaseDn: "dc=example,dc=com",
+		LdapUID:    "uid",
+		LdapFilter: "(objectClass=person)",
+	}))
+
+	require.Equal(t, []string{
+		`ldap_url has the unsupported scheme "https", expected ldap or ldaps`,
+		`ldap_base_dn "example.com" is not a valid DN`,
+		"ldap_uid is not set",
+		"ldap_scope 3 is invalid, expected 0 (base), 1 (one level) or 2 (subtree)",
+		`ldap_filter "(a=b)(c=d)" is not a parenthesized LDAP filter`,
+	}, ldapProblems(&model.LdapConf{
+		LdapURL:    "https://ldap.example.com",
+		LdapBaseDn: "example.com",
+		LdapScope:  3,
+		LdapFilter: "(a=b)(c=d)",
+	}))
+}
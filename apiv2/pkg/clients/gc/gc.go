@@ -27,7 +27,7 @@ type RESTClient struct {
 
 func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
 	return &RESTClient{
-		Options:  opts,
+		Options:  opts.Copy(),
 		V2Client: v2Client,
 		AuthInfo: authInfo,
 	}
@@ -37,7 +37,7 @@ type Client interface {
 	NewGarbageCollection(ctx context.Context, gcSchedule *model.Schedule) error
 	UpdateGarbageCollection(ctx context.Context,
 		newGCSchedule *model.Schedule) error
-	GetGarbageCollectionExecutions(ctx context.Context) ([]*model.GCHistory, error)
+	GetGarbageCollectionExecutions(ctx context.Context, opts ...config.CallOption) ([]*model.GCHistory, error)
 	GetGarbageCollectionExecution(ctx context.Context, id int64) (*model.GCHistory, error)
 	GetGarbageCollectionSchedule(ctx context.Context) (*model.GCHistory, error)
 	ResetGarbageCollection(ctx context.Context) error
@@ -90,15 +90,42 @@ func (c *RESTClient) UpdateGarbageCollection(ctx context.Context,
 }
 
 // GetGarbageCollectionExecutions Returns the garbage collection executions.
-func (c *RESTClient) GetGarbageCollectionExecutions(ctx context.Context) ([]*model.GCHistory, error) {
-	resp, err := c.V2Client.GC.GetGCHistory(&gc.GetGCHistoryParams{
-		Context: ctx,
-	}, c.AuthInfo)
-	if err != nil {
-		return nil, handleSwaggerSystemErrors(err)
+func (c *RESTClient) GetGarbageCollectionExecutions(ctx context.Context, opts ...config.CallOption) ([]*model.GCHistory, error) {
+	o := c.Options.Apply(opts...)
+
+	var executions []*model.GCHistory
+	page := o.Page
+
+	params := &gc.GetGCHistoryParams{
+		Page:     &page,
+		PageSize: &o.PageSize,
+		Q:        &o.Query,
+		Sort:     &o.Sort,
+		Context:  ctx,
 	}
 
-	return resp.Payload, nil
+	params.WithTimeout(o.Timeout)
+
+	for {
+		resp, err := c.V2Client.GC.GetGCHistory(params, c.AuthInfo)
+		if err != nil {
+			return nil, handleSwaggerSystemErrors(err)
+		}
+
+		if len(resp.Payload) == 0 {
+			break
+		}
+
+		executions = append(executions, resp.Payload...)
+
+		if int64(len(executions)) >= resp.XTotalCount {
+			break
+		}
+
+		page++
+	}
+
+	return executions, nil
 }
 
 // GetGarbageCollectionExecution Returns a garbage collection execution identified by its id.
@@ -15,6 +15,7 @@ import (
 	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/gc"
 	modelv2 "github.com/mittwald/goharbor-client/v5/apiv2/model"
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/query"
 	clienttesting "github.com/mittwald/goharbor-client/v5/apiv2/pkg/testing"
 
 	"github.com/stretchr/testify/mock"
@@ -254,3 +255,41 @@ func TestRESTClient_ResetGarbageCollection(t *testing.T) {
 
 	mockClient.GC.AssertExpectations(t)
 }
+
+func TestRESTClient_GetGarbageCollectionExecutions(t *testing.T) {
+	apiClient, mockClient := APIandMockClientsForTests()
+
+	opts, err := query.New[query.Key]().Exact("job_status", "Error").Desc("creation_time").Options()
+	require.NoError(t, err)
+
+	o := apiClient.Options.Apply(opts...)
+
+	firstPage := int64(1)
+	secondPage := int64(2)
+
+	listParams := func(page *int64) *gc.GetGCHistoryParams {
+		p := &gc.GetGCHistoryParams{
+			Page:     page,
+			PageSize: &o.PageSize,
+			Q:        &o.Query,
+			Sort:     &o.Sort,
+			Context:  ctx,
+		}
+		p.WithTimeout(o.Timeout)
+
+		return p
+	}
+
+	mockClient.GC.On("GetGCHistory", listParams(&firstPage), mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&gc.GetGCHistoryOK{Payload: []*modelv2.GCHistory{{ID: 1}}, XTotalCount: 2}, nil).Once()
+	mockClient.GC.On("GetGCHistory", listParams(&secondPage), mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&gc.GetGCHistoryOK{Payload: []*modelv2.GCHistory{{ID: 2}}, XTotalCount: 2}, nil).Once()
+
+	executions, err := apiClient.GetGarbageCollectionExecutions(ctx, opts...)
+	require.NoError(t, err)
+	require.Len(t, executions, 2)
+	require.Equal(t, "job_status=Error", o.Query)
+	require.Equal(t, "-creation_time", o.Sort)
+
+	mockClient.GC.AssertExpectations(t)
+}
@@ -0,0 +1,165 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// DefaultCriticalComponents are the components the Handler requires to be healthy by default.
+// Optional components like ComponentTrivy are reported but do not fail the probe.
+var DefaultCriticalComponents = []string{
+	ComponentCore,
+	ComponentDatabase,
+	ComponentJobService,
+	ComponentRedis,
+	ComponentRegistry,
+}
+
+// defaultHandlerTimeout limits the health check of a probe request.
+const defaultHandlerTimeout = 5 * time.Second
+
+// HandlerOption configures the Handler returned by NewHandler.
+type HandlerOption func(*handler)
+
+// WithCriticalComponents sets the components that must be healthy for the probe to succeed,
+// replacing DefaultCriticalComponents.
+func WithCriticalComponents(components ...string) HandlerOption {
+	return func(h *handler) {
+		h.critical = components
+	}
+}
+
+// WithHandlerTimeout limits the duration of the health check of each probe request, 5 seconds by default.
+func WithHandlerTimeout(timeout time.Duration) HandlerOption {
+	return func(h *handler) {
+		h.timeout = timeout
+	}
+}
+
+// ProbeResult is the JSON body written by the Handler.
+type ProbeResult struct {
+	// Status is StatusHealthy if all critical components are healthy, StatusUnhealthy otherwise.
+	Status string `json:"status"`
+
+	// Components are the reported components and the critical ones Harbor did not report, sorted by name.
+	Components []ProbeComponent `json:"components,omitempty"`
+
+	// Error describes why the health of Harbor could not be determined.
+	Error string `json:"error,omitempty"`
+}
+
+// ProbeComponent describes the health of a single component in a ProbeResult.
+type ProbeComponent struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Critical bool   `json:"critical"`
+}
+
+type handler struct {
+	client   Client
+	critical []string
+	timeout  time.Duration
+}
+
+// NewHandler returns an http.Handler reporting the aggregated health of the Harbor instance behind 'c',
+// suitable as Kubernetes liveness or readiness probe. It responds with 200 OK if all critical components
+// are healthy and with 503 Service Unavailable otherwise, including when Harbor cannot be reached.
+// The body is a ProbeResult; it is omitted for HEAD requests.
+func NewHandler(c Client, opts ...HandlerOption) http.Handler {
+	h := &handler{
+		client:   c,
+		critical: DefaultCriticalComponents,
+		timeout:  defaultHandlerTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	res := h.probe(r.Context())
+
+	code := http.StatusOK
+	if res.Status != StatusHealthy {
+		code = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// probe checks the health of Harbor and aggregates it by the critical components.
+func (h *handler) probe(ctx context.Context) *ProbeResult {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	status, err := h.client.GetHealth(ctx)
+	if err != nil {
+		return &ProbeResult{Status: StatusUnhealthy, Error: err.Error()}
+	}
+
+	critical := make(map[string]bool, len(h.critical))
+	for _, name := range h.critical {
+		critical[name] = true
+	}
+
+	res := &ProbeResult{Status: StatusHealthy}
+
+	for _, c := range status.Components {
+		if c == nil {
+			continue
+		}
+
+		res.Components = append(res.Components, ProbeComponent{
+			Name:     c.Name,
+			Status:   c.Status,
+			Error:    c.Error,
+			Critical: critical[c.Name],
+		})
+	}
+
+	for _, name := range h.critical {
+		if Component(status, name) == nil {
+			res.Components = append(res.Components, ProbeComponent{
+				Name:     name,
+				Status:   StatusUnhealthy,
+				Error:    "not reported by Harbor",
+				Critical: true,
+			})
+		}
+	}
+
+	sort.Slice(res.Components, func(i, j int) bool {
+		return res.Components[i].Name < res.Components[j].Name
+	})
+
+	for _, c := range res.Components {
+		if c.Critical && c.Status != StatusHealthy {
+			res.Status = StatusUnhealthy
+		}
+	}
+
+	return res
+}
@@ -2,6 +2,8 @@ package health
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/go-openapi/runtime"
 
@@ -9,6 +11,7 @@ import (
 	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/health"
 	"github.com/mittwald/goharbor-client/v5/apiv2/model"
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
 )
 
 // RESTClient is a subclient for handling system related actions.
@@ -25,7 +28,7 @@ type RESTClient struct {
 
 func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
 	return &RESTClient{
-		Options:  opts,
+		Options:  opts.Copy(),
 		V2Client: v2Client,
 		AuthInfo: authInfo,
 	}
@@ -33,8 +36,12 @@ func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime
 
 type Client interface {
 	GetHealth(ctx context.Context) (*model.OverallHealthStatus, error)
+	WaitUntilHealthy(ctx context.Context, components ...string) error
 }
 
+// pollInterval is the interval in which WaitUntilHealthy checks the health of Harbor.
+var pollInterval = 2 * time.Second
+
 func (c *RESTClient) GetHealth(ctx context.Context) (*model.OverallHealthStatus, error) {
 	params := &health.GetHealthParams{
 		Context: ctx,
@@ -49,3 +56,50 @@ func (c *RESTClient) GetHealth(ctx context.Context) (*model.OverallHealthStatus,
 
 	return resp.Payload, nil
 }
+
+// WaitUntilHealthy blocks until the given 'components' of Harbor, e.g. ComponentCore, are healthy.
+// Without 'components', it waits for all reported components. Failing health checks, e.g. while Harbor
+// is starting, are retried until 'ctx' is done, in which case the context's error is returned
+// wrapping the outcome of the last check, usually an ErrUnhealthy.
+func (c *RESTClient) WaitUntilHealthy(ctx context.Context, components ...string) error {
+	t := time.NewTicker(pollInterval)
+	defer t.Stop()
+
+	var lastErr error
+
+	for {
+		err := c.checkHealthy(ctx, components)
+		if err == nil {
+			return nil
+		}
+
+		// A check aborted by the context says nothing about the health of Harbor.
+		if ctx.Err() == nil {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr == nil {
+				return ctx.Err()
+			}
+
+			return fmt.Errorf("%w: %w", ctx.Err(), lastErr)
+		case <-t.C:
+		}
+	}
+}
+
+// checkHealthy returns an ErrUnhealthy if one of 'components' is not healthy.
+func (c *RESTClient) checkHealthy(ctx context.Context, components []string) error {
+	status, err := c.GetHealth(ctx)
+	if err != nil {
+		return err
+	}
+
+	if unhealthy := Unhealthy(status, components...); len(unhealthy) > 0 {
+		return &errors.ErrUnhealthy{Components: unhealthy}
+	}
+
+	return nil
+}
@@ -5,10 +5,16 @@ import (
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
 )
 
-// handleSwaggerHealthErrors takes a swagger generated error as input,
+// handleSwaggerHealthErrors maps a swagger generated error to a typed error, which is wrapped
+// in an errors.HarborError carrying the details of the response the error originates from.
+func handleSwaggerHealthErrors(in error) error {
+	return errors.WrapSwaggerError(in, mapSwaggerHealthErrors(in))
+}
+
+// mapSwaggerHealthErrors takes a swagger generated error as input,
 // which usually does not contain any form of error message,
 // and outputs a new error with a proper message.
-func handleSwaggerHealthErrors(in error) error {
+func mapSwaggerHealthErrors(in error) error {
 	switch in.(type) {
 	case *health.GetHealthInternalServerError:
 		return &errors.ErrInternalErrors{}
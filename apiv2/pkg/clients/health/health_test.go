@@ -0,0 +1,115 @@
+//go:build !integration
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/testing/fakeharbor"
+)
+
+func newTestClient(t *testing.T) (*RESTClient, *fakeharbor.Server) {
+	t.Helper()
+
+	srv := fakeharbor.NewServer()
+	t.Cleanup(srv.Close)
+
+	return NewClient(srv.V2Client(), config.Defaults(), srv.AuthInfo()), srv
+}
+
+func TestRESTClient_WaitUntilHealthy(t *testing.T) {
+	defer func(interval time.Duration) { pollInterval = interval }(pollInterval)
+	pollInterval = 10 * time.Millisecond
+
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	require.NoError(t, c.WaitUntilHealthy(ctx))
+
+	srv.SetComponentHealth(ComponentRedis, "connection refused")
+	require.NoError(t, c.WaitUntilHealthy(ctx, ComponentCore, ComponentDatabase), "redis is not awaited")
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		srv.SetComponentHealth(ComponentRedis, "")
+	}()
+
+	require.NoError(t, c.WaitUntilHealthy(ctx, ComponentRedis))
+
+	srv.SetComponentHealth(ComponentTrivy, "timeout")
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	err := c.WaitUntilHealthy(timeoutCtx, ComponentTrivy, "notary")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var unhealthy *errors.ErrUnhealthy
+	require.ErrorAs(t, err, &unhealthy)
+	require.Equal(t, []string{"notary", ComponentTrivy}, unhealthy.Components, "unreported components are unhealthy")
+}
+
+func TestHandler(t *testing.T) {
+	c, srv := newTestClient(t)
+
+	probe := func(h http.Handler, method string) (int, *ProbeResult) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, "/healthz", nil))
+
+		var res ProbeResult
+		if rec.Header().Get("Content-Type") == "application/json" && rec.Body.Len() > 0 {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		}
+
+		return rec.Code, &res
+	}
+
+	h := NewHandler(c)
+
+	code, res := probe(h, http.MethodGet)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, StatusHealthy, res.Status)
+	require.Len(t, res.Components, 8)
+
+	srv.SetComponentHealth(ComponentTrivy, "timeout")
+
+	code, _ = probe(h, http.MethodGet)
+	require.Equal(t, http.StatusOK, code, "trivy is not critical by default")
+
+	srv.SetComponentHealth(ComponentRedis, "connection refused")
+
+	code, res = probe(h, http.MethodGet)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, StatusUnhealthy, res.Status)
+	require.Contains(t, res.Components, ProbeComponent{
+		Name: ComponentRedis, Status: StatusUnhealthy, Error: "connection refused", Critical: true,
+	})
+
+	code, res = probe(NewHandler(c, WithCriticalComponents(ComponentCore)), http.MethodHead)
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, res.Status, "HEAD responses have no body")
+
+	code, res = probe(NewHandler(c, WithCriticalComponents(ComponentCore, "notary")), http.MethodGet)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Contains(t, res.Components, ProbeComponent{
+		Name: "notary", Status: StatusUnhealthy, Error: "not reported by Harbor", Critical: true,
+	})
+
+	code, _ = probe(h, http.MethodPost)
+	require.Equal(t, http.StatusMethodNotAllowed, code)
+
+	srv.Close()
+
+	code, res = probe(h, http.MethodGet)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.NotEmpty(t, res.Error)
+}
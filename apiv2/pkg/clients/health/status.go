@@ -0,0 +1,64 @@
+package health
+
+import (
+	"sort"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+// Names of the components reported by Harbor's health endpoint.
+const (
+	ComponentCore        = "core"
+	ComponentDatabase    = "database"
+	ComponentJobService  = "jobservice"
+	ComponentPortal      = "portal"
+	ComponentRedis       = "redis"
+	ComponentRegistry    = "registry"
+	ComponentRegistryCtl = "registryctl"
+	ComponentTrivy       = "trivy"
+)
+
+// Health statuses of Harbor and its components.
+const (
+	StatusHealthy   = "healthy"
+	StatusUnhealthy = "unhealthy"
+)
+
+// Component returns the health status of the component 'name' in 'status', or nil if it is not reported.
+func Component(status *model.OverallHealthStatus, name string) *model.ComponentHealthStatus {
+	if status == nil {
+		return nil
+	}
+
+	for _, c := range status.Components {
+		if c != nil && c.Name == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// Unhealthy returns the sorted names of the given 'components' that are not healthy in 'status',
+// including those not reported at all. Without 'components', all reported components are checked.
+func Unhealthy(status *model.OverallHealthStatus, components ...string) []string {
+	if len(components) == 0 && status != nil {
+		for _, c := range status.Components {
+			if c != nil {
+				components = append(components, c.Name)
+			}
+		}
+	}
+
+	var unhealthy []string
+
+	for _, name := range components {
+		if c := Component(status, name); c == nil || c.Status != StatusHealthy {
+			unhealthy = append(unhealthy, name)
+		}
+	}
+
+	sort.Strings(unhealthy)
+
+	return unhealthy
+}
@@ -24,7 +24,7 @@ type RESTClient struct {
 
 func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
 	return &RESTClient{
-		Options:  opts,
+		Options:  opts.Copy(),
 		V2Client: v2Client,
 		AuthInfo: authInfo,
 	}
@@ -34,7 +34,7 @@ type Client interface {
 	CreateImmuRule(ctx context.Context, projectNameOrID string, immutableRule *model.ImmutableRule) error
 	UpdateImmuRule(ctx context.Context, projectNameOrID string, immutableRule *model.ImmutableRule, immutableRuleID int64) error
 	DeleteImmuRule(ctx context.Context, projectNameOrID string, immutableRuleID int64) error
-	ListImmuRules(ctx context.Context, projectNameOrID string) ([]*model.ImmutableRule, error)
+	ListImmuRules(ctx context.Context, projectNameOrID string, opts ...config.CallOption) ([]*model.ImmutableRule, error)
 }
 
 func (c *RESTClient) CreateImmuRule(ctx context.Context, projectNameOrID string, immutableRule *model.ImmutableRule) error {
@@ -91,20 +91,22 @@ func (c *RESTClient) DeleteImmuRule(ctx context.Context, projectNameOrID string,
 	return nil
 }
 
-func (c *RESTClient) ListImmuRules(ctx context.Context, projectNameOrID string) ([]*model.ImmutableRule, error) {
+func (c *RESTClient) ListImmuRules(ctx context.Context, projectNameOrID string, opts ...config.CallOption) ([]*model.ImmutableRule, error) {
+	o := c.Options.Apply(opts...)
+
 	var immutableRules []*model.ImmutableRule
-	page := c.Options.Page
+	page := o.Page
 
 	params := &immutableapi.ListImmuRulesParams{
 		Page:            &page,
-		PageSize:        &c.Options.PageSize,
+		PageSize:        &o.PageSize,
 		ProjectNameOrID: projectNameOrID,
-		Q:               &c.Options.Query,
-		Sort:            &c.Options.Sort,
+		Q:               &o.Query,
+		Sort:            &o.Sort,
 		Context:         ctx,
 	}
 
-	params.WithTimeout(c.Options.Timeout)
+	params.WithTimeout(o.Timeout)
 
 	for {
 		resp, err := c.V2Client.Immutable.ListImmuRules(params, c.AuthInfo)
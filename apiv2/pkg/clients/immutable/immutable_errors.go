@@ -8,7 +8,14 @@ import (
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
 )
 
+// handleSwaggerImmutableRuleErrors maps a swagger generated error to a typed error, which is wrapped
+// in an errors.HarborError carrying the details of the response the error originates from.
 func handleSwaggerImmutableRuleErrors(in error) error {
+	return errors.WrapSwaggerError(in, mapSwaggerImmutableRuleErrors(in))
+}
+
+// mapSwaggerImmutableRuleErrors maps a swagger generated error to a typed error.
+func mapSwaggerImmutableRuleErrors(in error) error {
 	t, ok := in.(*runtime.APIError)
 	if ok {
 		switch t.Code {
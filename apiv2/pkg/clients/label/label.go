@@ -26,7 +26,7 @@ type RESTClient struct {
 
 func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
 	return &RESTClient{
-		Options:  opts,
+		Options:  opts.Copy(),
 		V2Client: v2Client,
 		AuthInfo: authInfo,
 	}
@@ -35,7 +35,7 @@ func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime
 type Client interface {
 	CreateLabel(ctx context.Context, l *model.Label) error
 	GetLabelByID(ctx context.Context, id int64) (*model.Label, error)
-	ListLabels(ctx context.Context, name string, projectID *int64, scope Scope) ([]*model.Label, error)
+	ListLabels(ctx context.Context, name string, projectID *int64, scope Scope, opts ...config.CallOption) ([]*model.Label, error)
 	DeleteLabel(ctx context.Context, id int64) error
 	UpdateLabel(ctx context.Context, id int64, l *model.Label) error
 }
@@ -83,9 +83,11 @@ func (c *RESTClient) GetLabelByID(ctx context.Context, id int64) (*model.Label,
 	return resp.Payload, nil
 }
 
-func (c *RESTClient) ListLabels(ctx context.Context, name string, projectID *int64) ([]*model.Label, error) {
+func (c *RESTClient) ListLabels(ctx context.Context, name string, projectID *int64, opts ...config.CallOption) ([]*model.Label, error) {
+	o := c.Options.Apply(opts...)
+
 	var labels []*model.Label
-	page := c.Options.Page
+	page := o.Page
 
 	var scope Scope
 	if projectID == nil {
@@ -97,15 +99,15 @@ func (c *RESTClient) ListLabels(ctx context.Context, name string, projectID *int
 	params := &label.ListLabelsParams{
 		Name:      &name,
 		Page:      &page,
-		PageSize:  &c.Options.PageSize,
+		PageSize:  &o.PageSize,
 		ProjectID: projectID,
-		Q:         &c.Options.Query,
+		Q:         &o.Query,
 		Scope:     util.StringPtr(scope.String()),
-		Sort:      &c.Options.Sort,
+		Sort:      &o.Sort,
 		Context:   ctx,
 	}
 
-	params.WithTimeout(c.Options.Timeout)
+	params.WithTimeout(o.Timeout)
 
 	for {
 		resp, err := c.V2Client.Label.ListLabels(params, c.AuthInfo)
@@ -7,10 +7,16 @@ import (
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
 )
 
-// handleSwaggerLabelErrors takes a swagger generated error as input,
+// handleSwaggerLabelErrors maps a swagger generated error to a typed error, which is wrapped
+// in an errors.HarborError carrying the details of the response the error originates from.
+func handleSwaggerLabelErrors(in error) error {
+	return errors.WrapSwaggerError(in, mapSwaggerLabelErrors(in))
+}
+
+// mapSwaggerLabelErrors takes a swagger generated error as input,
 // which usually does not contain any form of error message,
 // and outputs a new error with a proper message.
-func handleSwaggerLabelErrors(in error) error {
+func mapSwaggerLabelErrors(in error) error {
 	t, ok := in.(*runtime.APIError)
 	if ok {
 		switch t.Code {
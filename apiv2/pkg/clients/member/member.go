@@ -2,6 +2,7 @@ package member
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/go-openapi/runtime"
 
@@ -23,6 +24,50 @@ func (t EntityType) String() string {
 	return string(t)
 }
 
+// Role defines the role of a project member, identified by its role ID.
+type Role int64
+
+const (
+	RoleProjectAdmin Role = 1
+	RoleDeveloper    Role = 2
+	RoleGuest        Role = 3
+	RoleMaintainer   Role = 4
+	RoleLimitedGuest Role = 5
+)
+
+var roleNames = map[Role]string{
+	RoleProjectAdmin: "projectAdmin",
+	RoleDeveloper:    "developer",
+	RoleGuest:        "guest",
+	RoleMaintainer:   "maintainer",
+	RoleLimitedGuest: "limitedGuest",
+}
+
+// String returns the role name as used by Harbor, e.g. "projectAdmin".
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("role(%d)", int64(r))
+}
+
+// ID returns the role ID, as used in model.ProjectMember.RoleID.
+func (r Role) ID() int64 {
+	return int64(r)
+}
+
+// ParseRole returns the Role matching the provided role name, e.g. "developer".
+func ParseRole(name string) (Role, error) {
+	for r, n := range roleNames {
+		if n == name {
+			return r, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown project member role %q", name)
+}
+
 // RESTClient is a subclient for handling system related actions.
 type RESTClient struct {
 	// Options contains optional configuration when making API calls.
@@ -37,7 +82,7 @@ type RESTClient struct {
 
 func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
 	return &RESTClient{
-		Options:  opts,
+		Options:  opts.Copy(),
 		V2Client: v2Client,
 		AuthInfo: authInfo,
 	}
@@ -45,7 +90,7 @@ func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime
 
 type Client interface {
 	AddProjectMember(ctx context.Context, projectNameOrID string, m *model.ProjectMember) error
-	ListProjectMembers(ctx context.Context, projectNameOrID, memberQuery string) ([]*model.ProjectMemberEntity, error)
+	ListProjectMembers(ctx context.Context, projectNameOrID, memberQuery string, opts ...config.CallOption) ([]*model.ProjectMemberEntity, error)
 	UpdateProjectMember(ctx context.Context, projectNameOrID string, m *model.ProjectMember) error
 	DeleteProjectMember(ctx context.Context, projectNameOrID string, m *model.ProjectMember) error
 }
@@ -70,19 +115,21 @@ func (c *RESTClient) AddProjectMember(ctx context.Context, projectNameOrID strin
 }
 
 // ListProjectMembers returns a list of project members.
-func (c *RESTClient) ListProjectMembers(ctx context.Context, projectNameOrID, memberQuery string) ([]*model.ProjectMemberEntity, error) {
+func (c *RESTClient) ListProjectMembers(ctx context.Context, projectNameOrID, memberQuery string, opts ...config.CallOption) ([]*model.ProjectMemberEntity, error) {
+	o := c.Options.Apply(opts...)
+
 	var members []*model.ProjectMemberEntity
-	page := c.Options.Page
+	page := o.Page
 
 	params := &member.ListProjectMembersParams{
 		Page:            &page,
-		PageSize:        &c.Options.PageSize,
+		PageSize:        &o.PageSize,
 		Entityname:      &memberQuery,
 		ProjectNameOrID: projectNameOrID,
 		Context:         ctx,
 	}
 
-	params.WithTimeout(c.Options.Timeout)
+	params.WithTimeout(o.Timeout)
 
 	for {
 		resp, err := c.V2Client.Member.ListProjectMembers(params, c.AuthInfo)
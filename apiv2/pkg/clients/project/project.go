@@ -28,7 +28,7 @@ type RESTClient struct {
 
 func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
 	return &RESTClient{
-		Options:  opts,
+		Options:  opts.Copy(),
 		V2Client: v2Client,
 		AuthInfo: authInfo,
 	}
@@ -38,7 +38,7 @@ type Client interface {
 	NewProject(ctx context.Context, projectRequest *model.ProjectReq) error
 	DeleteProject(ctx context.Context, nameOrID string) error
 	GetProject(ctx context.Context, nameOrID string) (*model.Project, error)
-	ListProjects(ctx context.Context, nameFilter string) ([]*model.Project, error)
+	ListProjects(ctx context.Context, nameFilter string, opts ...config.CallOption) ([]*model.Project, error)
 	UpdateProject(ctx context.Context, p *model.Project, storageLimit *int64) error
 	ProjectExists(ctx context.Context, nameOrID string) (bool, error)
 }
@@ -121,20 +121,22 @@ func (c *RESTClient) GetProject(ctx context.Context, nameOrID string) (*model.Pr
 // ListProjects returns a list of projects based on a name filter.
 // Returns all projects if name is an empty string.
 // Returns an error if no projects were found.
-func (c *RESTClient) ListProjects(ctx context.Context, nameFilter string) ([]*model.Project, error) {
+func (c *RESTClient) ListProjects(ctx context.Context, nameFilter string, opts ...config.CallOption) ([]*model.Project, error) {
+	o := c.Options.Apply(opts...)
+
 	var projects []*model.Project
-	page := c.Options.Page
+	page := o.Page
 
 	params := &projectapi.ListProjectsParams{
 		Name:     &nameFilter,
 		Page:     &page,
-		PageSize: &c.Options.PageSize,
-		Q:        &c.Options.Query,
-		Sort:     &c.Options.Sort,
+		PageSize: &o.PageSize,
+		Q:        &o.Query,
+		Sort:     &o.Sort,
 		Context:  ctx,
 	}
 
-	params.WithTimeout(c.Options.Timeout)
+	params.WithTimeout(o.Timeout)
 
 	for {
 		resp, err := c.V2Client.Project.ListProjects(params, c.AuthInfo)
@@ -8,10 +8,16 @@ import (
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
 )
 
-// handleSwaggerProjectMetaErrors takes a swagger generated error as input,
+// handleSwaggerProjectMetaErrors maps a swagger generated error to a typed error, which is wrapped
+// in an errors.HarborError carrying the details of the response the error originates from.
+func handleSwaggerProjectMetaErrors(in error) error {
+	return errors.WrapSwaggerError(in, mapSwaggerProjectMetaErrors(in))
+}
+
+// mapSwaggerProjectMetaErrors takes a swagger generated error as input,
 // which usually does not contain any form of error message,
 // and outputs a new error with a proper message.
-func handleSwaggerProjectMetaErrors(in error) error {
+func mapSwaggerProjectMetaErrors(in error) error {
 	t, ok := in.(*runtime.APIError)
 	if ok {
 		switch t.Code {
@@ -26,7 +26,7 @@ type RESTClient struct {
 
 func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
 	return &RESTClient{
-		Options:  opts,
+		Options:  opts.Copy(),
 		V2Client: v2Client,
 		AuthInfo: authInfo,
 	}
@@ -35,7 +35,7 @@ func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime
 type Client interface {
 	CreatePurgeSchedule(ctx context.Context, schedule *model.Schedule) error
 	RunPurge(ctx context.Context, dryRun bool) error
-	ListPurgeHistory(ctx context.Context) ([]*model.ExecHistory, error)
+	ListPurgeHistory(ctx context.Context, opts ...config.CallOption) ([]*model.ExecHistory, error)
 	GetPurgeJob(ctx context.Context, id int64) (*model.ExecHistory, error)
 	GetPurgeJobLog(ctx context.Context, id int64) (string, error)
 	GetPurgeSchedule(ctx context.Context) (*model.ExecHistory, error)
@@ -101,17 +101,19 @@ func (c *RESTClient) RunPurge(ctx context.Context, dryRun bool) error {
 // ListPurgeHistory lists all purge history entries.
 // While the APIs purge service exposes a method called
 // 'GetPurgeHistory', it technically returns a list of purge schedules.
-func (c *RESTClient) ListPurgeHistory(ctx context.Context) ([]*model.ExecHistory, error) {
+func (c *RESTClient) ListPurgeHistory(ctx context.Context, opts ...config.CallOption) ([]*model.ExecHistory, error) {
+	o := c.Options.Apply(opts...)
+
 	var history []*model.ExecHistory
-	page := c.Options.Page
+	page := o.Page
 
 	params := purge.NewGetPurgeHistoryParams()
 	params.WithPage(&page)
 	params.WithContext(ctx)
-	params.WithTimeout(c.Options.Timeout)
-	params.WithPageSize(&c.Options.PageSize)
-	params.WithQ(&c.Options.Query)
-	params.WithSort(&c.Options.Sort)
+	params.WithTimeout(o.Timeout)
+	params.WithPageSize(&o.PageSize)
+	params.WithQ(&o.Query)
+	params.WithSort(&o.Sort)
 
 	for {
 		resp, err := c.V2Client.Purge.GetPurgeHistory(params, c.AuthInfo)
@@ -30,32 +30,34 @@ type RESTClient struct {
 
 func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
 	return &RESTClient{
-		Options:  opts,
+		Options:  opts.Copy(),
 		V2Client: v2Client,
 		AuthInfo: authInfo,
 	}
 }
 
 type Client interface {
-	ListQuotas(ctx context.Context, referenceType, referenceID *string) ([]*model.Quota, error)
+	ListQuotas(ctx context.Context, referenceType, referenceID *string, opts ...config.CallOption) ([]*model.Quota, error)
 	GetQuotaByProjectID(ctx context.Context, projectID int64) (*model.Quota, error)
 	UpdateStorageQuotaByProjectID(ctx context.Context, projectID int64, storageLimit int64) error
 }
 
-func (c *RESTClient) ListQuotas(ctx context.Context, referenceType, referenceID *string) ([]*model.Quota, error) {
+func (c *RESTClient) ListQuotas(ctx context.Context, referenceType, referenceID *string, opts ...config.CallOption) ([]*model.Quota, error) {
+	o := c.Options.Apply(opts...)
+
 	var quotas []*model.Quota
-	page := c.Options.Page
+	page := o.Page
 
 	params := &quota.ListQuotasParams{
 		Page:        &page,
-		PageSize:    &c.Options.PageSize,
+		PageSize:    &o.PageSize,
 		Reference:   referenceType,
 		ReferenceID: referenceID,
-		Sort:        &c.Options.Sort,
+		Sort:        &o.Sort,
 		Context:     ctx,
 	}
 
-	params.WithTimeout(c.Options.Timeout)
+	params.WithTimeout(o.Timeout)
 
 	for {
 		resp, err := c.V2Client.Quota.ListQuotas(params, c.AuthInfo)
@@ -8,10 +8,16 @@ import (
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
 )
 
-// handleSwaggerQuotaErrors takes a swagger generated error as input,
+// handleSwaggerQuotaErrors maps a swagger generated error to a typed error, which is wrapped
+// in an errors.HarborError carrying the details of the response the error originates from.
+func handleSwaggerQuotaErrors(in error) error {
+	return errors.WrapSwaggerError(in, mapSwaggerQuotaErrors(in))
+}
+
+// mapSwaggerQuotaErrors takes a swagger generated error as input,
 // which usually does not contain any form of error message,
 // and outputs a new error with a proper message.
-func handleSwaggerQuotaErrors(in error) error {
+func mapSwaggerQuotaErrors(in error) error {
 	t, ok := in.(*runtime.APIError)
 	if ok {
 		switch t.Code {
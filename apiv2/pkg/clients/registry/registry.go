@@ -26,7 +26,7 @@ type RESTClient struct {
 
 func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
 	return &RESTClient{
-		Options:  opts,
+		Options:  opts.Copy(),
 		V2Client: v2Client,
 		AuthInfo: authInfo,
 	}
@@ -35,8 +35,8 @@ func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime
 type Client interface {
 	NewRegistry(ctx context.Context, reg *model.Registry) error
 	GetRegistryByID(ctx context.Context, id int64) (*model.Registry, error)
-	GetRegistryByName(ctx context.Context, name string) (*model.Registry, error)
-	ListRegistries(ctx context.Context) ([]*model.Registry, error)
+	GetRegistryByName(ctx context.Context, name string, opts ...config.CallOption) (*model.Registry, error)
+	ListRegistries(ctx context.Context, opts ...config.CallOption) ([]*model.Registry, error)
 	DeleteRegistryByID(ctx context.Context, id int64) error
 	UpdateRegistry(ctx context.Context, u *model.RegistryUpdate, id int64) error
 }
@@ -78,10 +78,10 @@ func (c *RESTClient) GetRegistryByID(ctx context.Context, id int64) (*model.Regi
 	return resp.Payload, nil
 }
 
-func (c *RESTClient) GetRegistryByName(ctx context.Context, name string) (*model.Registry, error) {
-	c.Options.Query = "name=" + name
+func (c *RESTClient) GetRegistryByName(ctx context.Context, name string, opts ...config.CallOption) (*model.Registry, error) {
+	opts = append(opts[:len(opts):len(opts)], config.WithQuery("name="+name))
 
-	registries, err := c.ListRegistries(ctx)
+	registries, err := c.ListRegistries(ctx, opts...)
 	if err != nil {
 		return nil, handleSwaggerRegistryErrors(err)
 	}
@@ -95,19 +95,21 @@ func (c *RESTClient) GetRegistryByName(ctx context.Context, name string) (*model
 	return registries[0], nil
 }
 
-func (c *RESTClient) ListRegistries(ctx context.Context) ([]*model.Registry, error) {
+func (c *RESTClient) ListRegistries(ctx context.Context, opts ...config.CallOption) ([]*model.Registry, error) {
+	o := c.Options.Apply(opts...)
+
 	var registries []*model.Registry
-	page := c.Options.Page
+	page := o.Page
 
 	params := &registry.ListRegistriesParams{
 		Page:     &page,
-		PageSize: &c.Options.PageSize,
-		Q:        &c.Options.Query,
-		Sort:     &c.Options.Sort,
+		PageSize: &o.PageSize,
+		Q:        &o.Query,
+		Sort:     &o.Sort,
 		Context:  ctx,
 	}
 
-	params.WithTimeout(c.Options.Timeout)
+	params.WithTimeout(o.Timeout)
 
 	for {
 		resp, err := c.V2Client.Registry.ListRegistries(params, c.AuthInfo)
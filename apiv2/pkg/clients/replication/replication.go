@@ -26,7 +26,7 @@ type RESTClient struct {
 
 func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
 	return &RESTClient{
-		Options:  opts,
+		Options:  opts.Copy(),
 		V2Client: v2Client,
 		AuthInfo: authInfo,
 	}
@@ -37,13 +37,13 @@ type Client interface {
 		replicateDeletion, override, enablePolicy bool,
 		filters []*model.ReplicationFilter, trigger *model.ReplicationTrigger,
 		destNamespace, description, name string) error
-	GetReplicationPolicyByName(ctx context.Context, name string) (*model.ReplicationPolicy, error)
-	ListReplicationPolicies(ctx context.Context) ([]*model.ReplicationPolicy, error)
+	GetReplicationPolicyByName(ctx context.Context, name string, opts ...config.CallOption) (*model.ReplicationPolicy, error)
+	ListReplicationPolicies(ctx context.Context, opts ...config.CallOption) ([]*model.ReplicationPolicy, error)
 	GetReplicationPolicyByID(ctx context.Context, id int64) (*model.ReplicationPolicy, error)
 	DeleteReplicationPolicyByID(ctx context.Context, id int64) error
 	UpdateReplicationPolicy(ctx context.Context, r *model.ReplicationPolicy, id int64) error
 	TriggerReplicationExecution(ctx context.Context, r *model.StartReplicationExecution) error
-	ListReplicationExecutions(ctx context.Context, policyID *int64, status, trigger *string) ([]*model.ReplicationExecution, error)
+	ListReplicationExecutions(ctx context.Context, policyID *int64, status, trigger *string, opts ...config.CallOption) ([]*model.ReplicationExecution, error)
 	GetReplicationExecutionByID(ctx context.Context, id int64) (*model.ReplicationExecution, error)
 }
 
@@ -82,14 +82,14 @@ func (c *RESTClient) NewReplicationPolicy(ctx context.Context, destRegistry, src
 }
 
 // GetReplicationPolicyByName returns a replication identified by name.
-func (c *RESTClient) GetReplicationPolicyByName(ctx context.Context, name string) (*model.ReplicationPolicy, error) {
+func (c *RESTClient) GetReplicationPolicyByName(ctx context.Context, name string, opts ...config.CallOption) (*model.ReplicationPolicy, error) {
 	if name == "" {
 		return nil, &ErrReplicationNotProvided{}
 	}
 
-	c.Options.Query = "name=" + name
+	opts = append(opts[:len(opts):len(opts)], config.WithQuery("name="+name))
 
-	policies, err := c.ListReplicationPolicies(ctx)
+	policies, err := c.ListReplicationPolicies(ctx, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -104,19 +104,21 @@ func (c *RESTClient) GetReplicationPolicyByName(ctx context.Context, name string
 	return policies[0], nil
 }
 
-func (c *RESTClient) ListReplicationPolicies(ctx context.Context) ([]*model.ReplicationPolicy, error) {
+func (c *RESTClient) ListReplicationPolicies(ctx context.Context, opts ...config.CallOption) ([]*model.ReplicationPolicy, error) {
+	o := c.Options.Apply(opts...)
+
 	var replicationPolicies []*model.ReplicationPolicy
-	page := c.Options.Page
+	page := o.Page
 
 	params := &replicationapi.ListReplicationPoliciesParams{
 		Page:     &page,
-		PageSize: &c.Options.PageSize,
-		Q:        &c.Options.Query,
-		Sort:     &c.Options.Sort,
+		PageSize: &o.PageSize,
+		Q:        &o.Query,
+		Sort:     &o.Sort,
 		Context:  ctx,
 	}
 
-	params.WithTimeout(c.Options.Timeout)
+	params.WithTimeout(o.Timeout)
 
 	for {
 		resp, err := c.V2Client.Replication.ListReplicationPolicies(params, c.AuthInfo)
@@ -216,21 +218,23 @@ func (c *RESTClient) TriggerReplicationExecution(ctx context.Context, r *model.S
 
 // ListReplicationExecutions lists replication executions specified by execution ID, status or trigger.
 // Specifying the property "policy_id" will return executions of the specified policy.
-func (c *RESTClient) ListReplicationExecutions(ctx context.Context, policyID *int64, status, trigger *string) ([]*model.ReplicationExecution, error) {
+func (c *RESTClient) ListReplicationExecutions(ctx context.Context, policyID *int64, status, trigger *string, opts ...config.CallOption) ([]*model.ReplicationExecution, error) {
+	o := c.Options.Apply(opts...)
+
 	var replicationExecutions []*model.ReplicationExecution
-	page := c.Options.Page
+	page := o.Page
 
 	params := &replicationapi.ListReplicationExecutionsParams{
 		Page:     &page,
-		PageSize: &c.Options.PageSize,
+		PageSize: &o.PageSize,
 		PolicyID: policyID,
-		Sort:     &c.Options.Sort,
+		Sort:     &o.Sort,
 		Status:   status,
 		Trigger:  trigger,
 		Context:  ctx,
 	}
 
-	params.WithTimeout(c.Options.Timeout)
+	params.WithTimeout(o.Timeout)
 
 	for {
 		resp, err := c.V2Client.Replication.ListReplicationExecutions(params, c.AuthInfo)
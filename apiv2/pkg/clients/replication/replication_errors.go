@@ -55,7 +55,9 @@ const (
 )
 
 // ErrReplicationIllegalIDFormat describes an illegal request format.
-type ErrReplicationIllegalIDFormat struct{}
+type ErrReplicationIllegalIDFormat struct {
+	errors.Response[ErrReplicationIllegalIDFormat]
+}
 
 // Error returns the error message.
 func (e *ErrReplicationIllegalIDFormat) Error() string {
@@ -63,7 +65,9 @@ func (e *ErrReplicationIllegalIDFormat) Error() string {
 }
 
 // ErrReplicationUnauthorized describes an unauthorized request.
-type ErrReplicationUnauthorized struct{}
+type ErrReplicationUnauthorized struct {
+	errors.Response[ErrReplicationUnauthorized]
+}
 
 // Error returns the error message.
 func (e *ErrReplicationUnauthorized) Error() string {
@@ -71,7 +75,9 @@ func (e *ErrReplicationUnauthorized) Error() string {
 }
 
 // ErrReplicationInternalErrors describes server-side internal errors.
-type ErrReplicationInternalErrors struct{}
+type ErrReplicationInternalErrors struct {
+	errors.Response[ErrReplicationInternalErrors]
+}
 
 // Error returns the error message.
 func (e *ErrReplicationInternalErrors) Error() string {
@@ -79,7 +85,9 @@ func (e *ErrReplicationInternalErrors) Error() string {
 }
 
 // ErrReplicationNoPermission describes a request error without permission.
-type ErrReplicationNoPermission struct{}
+type ErrReplicationNoPermission struct {
+	errors.Response[ErrReplicationNoPermission]
+}
 
 // Error returns the error message.
 func (e *ErrReplicationNoPermission) Error() string {
@@ -88,7 +96,9 @@ func (e *ErrReplicationNoPermission) Error() string {
 
 // ErrReplicationIDNotExists describes an error
 // when no proper replication ID is found.
-type ErrReplicationIDNotExists struct{}
+type ErrReplicationIDNotExists struct {
+	errors.Response[ErrReplicationIDNotExists]
+}
 
 // Error returns the error message.
 func (e *ErrReplicationIDNotExists) Error() string {
@@ -96,7 +106,9 @@ func (e *ErrReplicationIDNotExists) Error() string {
 }
 
 // ErrReplicationNameAlreadyExists describes a duplicate replication name error.
-type ErrReplicationNameAlreadyExists struct{}
+type ErrReplicationNameAlreadyExists struct {
+	errors.Response[ErrReplicationNameAlreadyExists]
+}
 
 // Error returns the error message.
 func (e *ErrReplicationNameAlreadyExists) Error() string {
@@ -105,7 +117,9 @@ func (e *ErrReplicationNameAlreadyExists) Error() string {
 
 // ErrReplicationMismatch describes a failed lookup
 // of a replication with name/id pair.
-type ErrReplicationMismatch struct{}
+type ErrReplicationMismatch struct {
+	errors.Response[ErrReplicationMismatch]
+}
 
 // Error returns the error message.
 func (e *ErrReplicationMismatch) Error() string {
@@ -114,28 +128,36 @@ func (e *ErrReplicationMismatch) Error() string {
 
 // ErrReplicationNotFound describes an error
 // when a specific replication is not found.
-type ErrReplicationNotFound struct{}
+type ErrReplicationNotFound struct {
+	errors.Response[ErrReplicationNotFound]
+}
 
 // Error returns the error message.
 func (e *ErrReplicationNotFound) Error() string {
 	return ErrReplicationNotFoundMsg
 }
 
-type ErrReplicationNotProvided struct{}
+type ErrReplicationNotProvided struct {
+	errors.Response[ErrReplicationNotProvided]
+}
 
 // Error returns the error message.
 func (e *ErrReplicationNotProvided) Error() string {
 	return ErrReplicationNotProvidedMsg
 }
 
-type ErrReplicationExecutionNotProvided struct{}
+type ErrReplicationExecutionNotProvided struct {
+	errors.Response[ErrReplicationExecutionNotProvided]
+}
 
 // Error returns the error message.
 func (e *ErrReplicationExecutionNotProvided) Error() string {
 	return ErrReplicationExecutionNotProvidedMsg
 }
 
-type ErrReplicationExecutionReplicationIDMismatch struct{}
+type ErrReplicationExecutionReplicationIDMismatch struct {
+	errors.Response[ErrReplicationExecutionReplicationIDMismatch]
+}
 
 // Error returns the error message.
 func (e *ErrReplicationExecutionReplicationIDMismatch) Error() string {
@@ -143,17 +165,25 @@ func (e *ErrReplicationExecutionReplicationIDMismatch) Error() string {
 }
 
 // ErrReplicationDisabled describes an error that the underlying replication is disabled.
-type ErrReplicationDisabled struct{}
+type ErrReplicationDisabled struct {
+	errors.Response[ErrReplicationDisabled]
+}
 
 // Error returns the error message.
 func (e *ErrReplicationDisabled) Error() string {
 	return ErrReplicationDisabledMsg
 }
 
-// handleSwaggerReplicationErrors takes a swagger generated error as input,
+// handleSwaggerReplicationErrors maps a swagger generated error to a typed error, which is wrapped
+// in an errors.HarborError carrying the details of the response the error originates from.
+func handleSwaggerReplicationErrors(in error) error {
+	return errors.WrapSwaggerError(in, mapSwaggerReplicationErrors(in))
+}
+
+// mapSwaggerReplicationErrors takes a swagger generated error as input,
 // which usually does not contain any form of error message,
 // and outputs a new error with a proper message.
-func handleSwaggerReplicationErrors(in error) error {
+func mapSwaggerReplicationErrors(in error) error {
 	t, ok := in.(*runtime.APIError)
 	if ok {
 		switch t.Code {
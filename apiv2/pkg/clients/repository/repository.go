@@ -9,6 +9,7 @@ import (
 	"github.com/mittwald/goharbor-client/v5/apiv2/model"
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/reference"
 )
 
 // RESTClient is a subclient for handling repository related actions.
@@ -25,7 +26,7 @@ type RESTClient struct {
 
 func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
 	return &RESTClient{
-		Options:  opts,
+		Options:  opts.Copy(),
 		V2Client: v2Client,
 		AuthInfo: authInfo,
 	}
@@ -34,15 +35,17 @@ func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime
 type Client interface {
 	GetRepository(ctx context.Context, projectName, repositoryName string) (*model.Repository, error)
 	UpdateRepository(ctx context.Context, projectName, repositoryName string, update *model.Repository) error
-	ListAllRepositories(ctx context.Context) ([]*model.Repository, error)
-	ListRepositories(ctx context.Context, projectName string) ([]*model.Repository, error)
+	ListAllRepositories(ctx context.Context, opts ...config.CallOption) ([]*model.Repository, error)
+	ListRepositories(ctx context.Context, projectName string, opts ...config.CallOption) ([]*model.Repository, error)
 	DeleteRepository(ctx context.Context, projectName, repositoryName string) error
+	GetRepositoryByReference(ctx context.Context, ref *reference.Reference) (*model.Repository, error)
+	DeleteRepositoryByReference(ctx context.Context, ref *reference.Reference) error
 }
 
 func (c *RESTClient) GetRepository(ctx context.Context, projectName, repositoryName string) (*model.Repository, error) {
 	params := &repository.GetRepositoryParams{
 		ProjectName:    projectName,
-		RepositoryName: repositoryName,
+		RepositoryName: reference.EscapeRepositoryName(repositoryName),
 		Context:        ctx,
 	}
 
@@ -64,7 +67,7 @@ func (c *RESTClient) UpdateRepository(ctx context.Context, projectName, reposito
 	params := &repository.UpdateRepositoryParams{
 		ProjectName:    projectName,
 		Repository:     update,
-		RepositoryName: repositoryName,
+		RepositoryName: reference.EscapeRepositoryName(repositoryName),
 		Context:        ctx,
 	}
 
@@ -78,19 +81,21 @@ func (c *RESTClient) UpdateRepository(ctx context.Context, projectName, reposito
 	return nil
 }
 
-func (c *RESTClient) ListAllRepositories(ctx context.Context) ([]*model.Repository, error) {
+func (c *RESTClient) ListAllRepositories(ctx context.Context, opts ...config.CallOption) ([]*model.Repository, error) {
+	o := c.Options.Apply(opts...)
+
 	var repositories []*model.Repository
-	page := c.Options.Page
+	page := o.Page
 
 	params := &repository.ListAllRepositoriesParams{
 		Page:     &page,
-		PageSize: &c.Options.PageSize,
-		Q:        &c.Options.Query,
-		Sort:     &c.Options.Sort,
+		PageSize: &o.PageSize,
+		Q:        &o.Query,
+		Sort:     &o.Sort,
 		Context:  ctx,
 	}
 
-	params.WithTimeout(c.Options.Timeout)
+	params.WithTimeout(o.Timeout)
 
 	for {
 		resp, err := c.V2Client.Repository.ListAllRepositories(params, c.AuthInfo)
@@ -116,20 +121,22 @@ func (c *RESTClient) ListAllRepositories(ctx context.Context) ([]*model.Reposito
 	return repositories, nil
 }
 
-func (c *RESTClient) ListRepositories(ctx context.Context, projectName string) ([]*model.Repository, error) {
+func (c *RESTClient) ListRepositories(ctx context.Context, projectName string, opts ...config.CallOption) ([]*model.Repository, error) {
+	o := c.Options.Apply(opts...)
+
 	var repositories []*model.Repository
-	page := c.Options.Page
+	page := o.Page
 
 	params := &repository.ListRepositoriesParams{
 		Page:        &page,
-		PageSize:    &c.Options.PageSize,
+		PageSize:    &o.PageSize,
 		ProjectName: projectName,
-		Q:           &c.Options.Query,
-		Sort:        &c.Options.Sort,
+		Q:           &o.Query,
+		Sort:        &o.Sort,
 		Context:     ctx,
 	}
 
-	params.WithTimeout(c.Options.Timeout)
+	params.WithTimeout(o.Timeout)
 
 	for {
 		resp, err := c.V2Client.Repository.ListRepositories(params, c.AuthInfo)
@@ -154,7 +161,7 @@ func (c *RESTClient) ListRepositories(ctx context.Context, projectName string) (
 func (c *RESTClient) DeleteRepository(ctx context.Context, projectName, repositoryName string) error {
 	params := &repository.DeleteRepositoryParams{
 		ProjectName:    projectName,
-		RepositoryName: repositoryName,
+		RepositoryName: reference.EscapeRepositoryName(repositoryName),
 		Context:        ctx,
 	}
 
@@ -167,3 +174,21 @@ func (c *RESTClient) DeleteRepository(ctx context.Context, projectName, reposito
 
 	return nil
 }
+
+// GetRepositoryByReference returns the repository referenced by 'ref'. Its tag and digest are ignored.
+func (c *RESTClient) GetRepositoryByReference(ctx context.Context, ref *reference.Reference) (*model.Repository, error) {
+	if err := ref.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c.GetRepository(ctx, ref.Project, ref.Repository)
+}
+
+// DeleteRepositoryByReference deletes the repository referenced by 'ref'. Its tag and digest are ignored.
+func (c *RESTClient) DeleteRepositoryByReference(ctx context.Context, ref *reference.Reference) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+
+	return c.DeleteRepository(ctx, ref.Project, ref.Repository)
+}
@@ -7,10 +7,16 @@ import (
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
 )
 
-// handleSwaggerRepositoryErrors takes a swagger generated error as input,
+// handleSwaggerRepositoryErrors maps a swagger generated error to a typed error, which is wrapped
+// in an errors.HarborError carrying the details of the response the error originates from.
+func handleSwaggerRepositoryErrors(in error) error {
+	return errors.WrapSwaggerError(in, mapSwaggerRepositoryErrors(in))
+}
+
+// mapSwaggerRepositoryErrors takes a swagger generated error as input,
 // which usually does not contain any form of error message,
 // and outputs a new error with a proper message.
-func handleSwaggerRepositoryErrors(in error) error {
+func mapSwaggerRepositoryErrors(in error) error {
 	t, ok := in.(*runtime.APIError)
 	if ok {
 		switch t.Code {
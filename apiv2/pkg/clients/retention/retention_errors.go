@@ -27,7 +27,9 @@ const (
 )
 
 // ErrRetentionUnauthorized describes an unauthorized request.
-type ErrRetentionUnauthorized struct{}
+type ErrRetentionUnauthorized struct {
+	errors.Response[ErrRetentionUnauthorized]
+}
 
 // Error returns the error message.
 func (e *ErrRetentionUnauthorized) Error() string {
@@ -35,7 +37,9 @@ func (e *ErrRetentionUnauthorized) Error() string {
 }
 
 // ErrRetentionNotProvided describes a missing retention instance
-type ErrRetentionNotProvided struct{}
+type ErrRetentionNotProvided struct {
+	errors.Response[ErrRetentionNotProvided]
+}
 
 // Error returns the error message.
 func (e *ErrRetentionNotProvided) Error() string {
@@ -43,7 +47,9 @@ func (e *ErrRetentionNotProvided) Error() string {
 }
 
 // ErrRetentionNoPermission describes a request error without permission.
-type ErrRetentionNoPermission struct{}
+type ErrRetentionNoPermission struct {
+	errors.Response[ErrRetentionNoPermission]
+}
 
 // Error returns the error message.
 func (e *ErrRetentionNoPermission) Error() string {
@@ -51,7 +57,9 @@ func (e *ErrRetentionNoPermission) Error() string {
 }
 
 // ErrRetentionDoesNotExist describes the  absence of a retention policy.
-type ErrRetentionDoesNotExist struct{}
+type ErrRetentionDoesNotExist struct {
+	errors.Response[ErrRetentionDoesNotExist]
+}
 
 // Error returns the error message.
 func (e *ErrRetentionDoesNotExist) Error() string {
@@ -59,17 +67,25 @@ func (e *ErrRetentionDoesNotExist) Error() string {
 }
 
 // ErrRetentionInternalErrors describes server-side internal errors.
-type ErrRetentionInternalErrors struct{}
+type ErrRetentionInternalErrors struct {
+	errors.Response[ErrRetentionInternalErrors]
+}
 
 // Error returns the error message.
 func (e *ErrRetentionInternalErrors) Error() string {
 	return ErrRetentionInternalErrorsMsg
 }
 
-// handleProjectErrors takes a swagger generated error as input,
+// handleSwaggerRetentionErrors maps a swagger generated error to a typed error, which is wrapped
+// in an errors.HarborError carrying the details of the response the error originates from.
+func handleSwaggerRetentionErrors(in error) error {
+	return errors.WrapSwaggerError(in, mapSwaggerRetentionErrors(in))
+}
+
+// mapSwaggerRetentionErrors takes a swagger generated error as input,
 // which usually does not contain any form of error message,
 // and outputs a new error with a proper message.
-func handleSwaggerRetentionErrors(in error) error {
+func mapSwaggerRetentionErrors(in error) error {
 	t, ok := in.(*runtime.APIError)
 	if ok {
 		switch t.Code {
@@ -49,14 +49,14 @@ type RESTClient struct {
 
 func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
 	return &RESTClient{
-		Options:  opts,
+		Options:  opts.Copy(),
 		V2Client: v2Client,
 		AuthInfo: authInfo,
 	}
 }
 
 type Client interface {
-	ListRobotAccounts(ctx context.Context) ([]*model.Robot, error)
+	ListRobotAccounts(ctx context.Context, opts ...config.CallOption) ([]*model.Robot, error)
 	GetRobotAccountByName(ctx context.Context, name string) (*model.Robot, error)
 	GetRobotAccountByID(ctx context.Context, id int64) (*model.Robot, error)
 	NewRobotAccount(ctx context.Context, r *model.RobotCreate) (*model.RobotCreated, error)
@@ -86,18 +86,20 @@ func (in AccessAction) String() string {
 }
 
 // ListRobotAccounts ListProjectRobots returns a list of all robot accounts.
-func (c *RESTClient) ListRobotAccounts(ctx context.Context) ([]*model.Robot, error) {
+func (c *RESTClient) ListRobotAccounts(ctx context.Context, opts ...config.CallOption) ([]*model.Robot, error) {
+	o := c.Options.Apply(opts...)
+
 	var robotAccounts []*model.Robot
-	page := c.Options.Page
+	page := o.Page
 
 	params := &robot.ListRobotParams{
 		Page:     &page,
-		PageSize: &c.Options.PageSize,
-		Q:        &c.Options.Query,
-		Sort:     &c.Options.Sort,
+		PageSize: &o.PageSize,
+		Q:        &o.Query,
+		Sort:     &o.Sort,
 		Context:  ctx,
 	}
-	params.WithTimeout(c.Options.Timeout)
+	params.WithTimeout(o.Timeout)
 
 	for {
 		resp, err := c.V2Client.Robot.ListRobot(params, c.AuthInfo)
@@ -9,10 +9,16 @@ import (
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
 )
 
-// handleSwaggerRobotErrors takes a swagger generated error as input,
+// handleSwaggerRobotErrors maps a swagger generated error to a typed error, which is wrapped
+// in an errors.HarborError carrying the details of the response the error originates from.
+func handleSwaggerRobotErrors(in error) error {
+	return errors.WrapSwaggerError(in, mapSwaggerRobotErrors(in))
+}
+
+// mapSwaggerRobotErrors takes a swagger generated error as input,
 // which usually does not contain any form of error message,
 // and outputs a new error with a proper message.
-func handleSwaggerRobotErrors(in error) error {
+func mapSwaggerRobotErrors(in error) error {
 	t, ok := in.(*runtime.APIError)
 	if ok {
 		switch t.Code {
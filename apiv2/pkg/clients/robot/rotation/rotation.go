@@ -0,0 +1,304 @@
+package rotation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/robot"
+)
+
+// Strategy defines how a robot account that is about to expire is handled.
+type Strategy string
+
+// There is no strategy only refreshing the secret: Harbor does not move the expiry date of a robot account
+// when refreshing its secret, so the robot account would still expire and be rotated again on every run.
+const (
+	// StrategyExtend extends the robot account's duration,
+	// keeping its current secret valid.
+	StrategyExtend Strategy = "extend"
+	// StrategyExtendAndRefresh extends the robot account's duration, generates a new secret afterwards
+	// and hands it to the configured SecretSink.
+	StrategyExtendAndRefresh Strategy = "extend-and-refresh"
+)
+
+// Action describes what has been (or, in dry-run mode, would have been) done to a robot account.
+type Action string
+
+const (
+	ActionNone     Action = "none"
+	ActionExtended Action = "extended"
+	// ActionExtendedAndRefreshed is the action taken when using StrategyExtendAndRefresh.
+	ActionExtendedAndRefreshed Action = "extended-and-refreshed"
+)
+
+// neverExpires is the value of model.Robot.ExpiresAt / model.Robot.Duration
+// for robot accounts without an expiration date.
+const neverExpires int64 = -1
+
+// SecretSink receives the new secret of a rotated robot account,
+// e.g. to write it to a file or a docker config.
+type SecretSink interface {
+	Write(ctx context.Context, r *model.Robot, secret string) error
+}
+
+// SecretSinkFunc allows using ordinary functions as a SecretSink.
+type SecretSinkFunc func(ctx context.Context, r *model.Robot, secret string) error
+
+// Write calls f(ctx, r, secret).
+func (f SecretSinkFunc) Write(ctx context.Context, r *model.Robot, secret string) error {
+	return f(ctx, r, secret)
+}
+
+// Rotator finds robot accounts expiring within a configurable threshold
+// and extends them, optionally refreshing their secrets.
+type Rotator struct {
+	// Client is used to list, update and refresh robot accounts.
+	Client robot.Client
+	// Sink receives newly generated secrets.
+	// It may be nil when using StrategyExtend.
+	Sink SecretSink
+	// Threshold defines the time frame in which expiring robot accounts are rotated.
+	Threshold time.Duration
+	// Strategy defines the action to take on expiring robot accounts, defaults to StrategyExtendAndRefresh.
+	Strategy Strategy
+	// ExtendBy is the time a robot account is valid for after being extended (counting from now).
+	// Harbor stores robot durations in days, so this value is rounded up to full days.
+	// If zero, robot accounts are extended by their own duration, e.g. by another 30 days
+	// for a robot account created with a duration of 30 days.
+	ExtendBy time.Duration
+	// DryRun only reports the robot accounts that would be rotated, without changing anything.
+	DryRun bool
+	// Filter optionally restricts the robot accounts considered for rotation.
+	Filter func(r *model.Robot) bool
+
+	now func() time.Time
+}
+
+// NewRotator returns a Rotator using StrategyExtendAndRefresh for robot accounts expiring within 'threshold'.
+func NewRotator(client robot.Client, sink SecretSink, threshold time.Duration) *Rotator {
+	return &Rotator{
+		Client:    client,
+		Sink:      sink,
+		Threshold: threshold,
+		Strategy:  StrategyExtendAndRefresh,
+		now:       time.Now,
+	}
+}
+
+// Result describes the outcome of rotating a single robot account.
+type Result struct {
+	// ID of the robot account.
+	ID int64
+	// Name of the robot account, including its 'robot$' prefix.
+	Name string
+	// ExpiresAt is the expiry date of the robot account before rotation.
+	ExpiresAt time.Time
+	// NewExpiresAt is the (expected) expiry date of the robot account after rotation.
+	NewExpiresAt time.Time
+	// Action is the action taken (or planned, in dry-run mode).
+	Action Action
+	// Err contains an error that occurred while rotating the robot account.
+	Err error
+	// Secret is the new secret of the robot account if it could not be written to the Sink, so it can be
+	// recovered; the previous secret is no longer valid then. It is empty otherwise.
+	Secret string
+}
+
+// Report summarizes a rotation run.
+type Report struct {
+	// DryRun is true if no changes have been made.
+	DryRun bool
+	// Checked is the number of robot accounts considered for rotation.
+	Checked int
+	// Results contains one entry per expiring robot account.
+	Results []Result
+}
+
+// Failed returns the results containing an error.
+func (r *Report) Failed() []Result {
+	var failed []Result
+
+	for _, res := range r.Results {
+		if res.Err != nil {
+			failed = append(failed, res)
+		}
+	}
+
+	return failed
+}
+
+// Err returns an error summarizing all failed rotations, or nil if none failed.
+func (r *Report) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("rotating %d of %d robot accounts failed, first error (%s): %w",
+		len(failed), len(r.Results), failed[0].Name, failed[0].Err)
+}
+
+// Expiring returns all robot accounts that expire within the Rotator's threshold.
+// Robot accounts that never expire or are disabled are skipped.
+func (rt *Rotator) Expiring(ctx context.Context) ([]*model.Robot, error) {
+	robots, err := rt.Client.ListRobotAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := rt.clock().Add(rt.Threshold)
+
+	var expiring []*model.Robot
+
+	for _, r := range robots {
+		if rt.eligible(r) && time.Unix(r.ExpiresAt, 0).Before(deadline) {
+			expiring = append(expiring, r)
+		}
+	}
+
+	return expiring, nil
+}
+
+// Rotate rotates all expiring robot accounts according to the configured strategy.
+// Errors affecting single robot accounts are recorded in the report and do not abort the run.
+func (rt *Rotator) Rotate(ctx context.Context) (*Report, error) {
+	robots, err := rt.Client.ListRobotAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{DryRun: rt.DryRun}
+
+	deadline := rt.clock().Add(rt.Threshold)
+
+	for _, r := range robots {
+		if !rt.eligible(r) {
+			continue
+		}
+
+		report.Checked++
+
+		if !time.Unix(r.ExpiresAt, 0).Before(deadline) {
+			continue
+		}
+
+		report.Results = append(report.Results, rt.rotate(ctx, r))
+	}
+
+	return report, nil
+}
+
+// eligible reports whether 'r' is considered for rotation at all.
+func (rt *Rotator) eligible(r *model.Robot) bool {
+	if r.Disable || r.ExpiresAt == neverExpires || r.ExpiresAt == 0 {
+		return false
+	}
+
+	return rt.Filter == nil || rt.Filter(r)
+}
+
+func (rt *Rotator) rotate(ctx context.Context, r *model.Robot) Result {
+	res := Result{
+		ID:           r.ID,
+		Name:         r.Name,
+		ExpiresAt:    time.Unix(r.ExpiresAt, 0),
+		NewExpiresAt: time.Unix(r.ExpiresAt, 0),
+		Action:       ActionNone,
+	}
+
+	strategy := rt.Strategy
+	if strategy == "" {
+		strategy = StrategyExtendAndRefresh
+	}
+
+	if strategy != StrategyExtend && strategy != StrategyExtendAndRefresh {
+		res.Err = fmt.Errorf("unknown rotation strategy %q", strategy)
+		return res
+	}
+
+	refresh := strategy == StrategyExtendAndRefresh
+
+	if refresh && rt.Sink == nil {
+		res.Err = fmt.Errorf("no secret sink configured for strategy %q", strategy)
+		return res
+	}
+
+	duration, expiresAt, err := rt.extendedDuration(r)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	res.NewExpiresAt = expiresAt
+	res.Action = ActionExtended
+
+	if !rt.DryRun {
+		updated := *r
+		updated.Duration = duration
+
+		if err := rt.Client.UpdateRobotAccount(ctx, &updated); err != nil {
+			res.Err = fmt.Errorf("extending robot account: %w", err)
+			return res
+		}
+	}
+
+	if refresh {
+		res.Action = ActionExtendedAndRefreshed
+
+		if rt.DryRun {
+			return res
+		}
+
+		// Providing an empty secret makes Harbor generate a new one.
+		sec, err := rt.Client.RefreshRobotAccountSecretByID(ctx, r.ID, "")
+		if err != nil {
+			res.Err = fmt.Errorf("refreshing robot account secret: %w", err)
+			return res
+		}
+
+		if err := rt.Sink.Write(ctx, r, sec.Secret); err != nil {
+			res.Err = fmt.Errorf("writing robot account secret to sink, the new secret is kept in the result: %w", err)
+			res.Secret = sec.Secret
+
+			return res
+		}
+	}
+
+	return res
+}
+
+// extendedDuration calculates the robot duration (in days, relative to the robot's creation time)
+// needed for the robot account to stay valid for at least ExtendBy, counting from now.
+func (rt *Rotator) extendedDuration(r *model.Robot) (int64, time.Time, error) {
+	extendBy := rt.ExtendBy
+
+	switch {
+	case extendBy < 0:
+		return 0, time.Time{}, fmt.Errorf("ExtendBy must not be negative")
+	case extendBy == 0 && r.Duration <= 0:
+		return 0, time.Time{}, fmt.Errorf("robot account %q has no duration to extend it by, set ExtendBy", r.Name)
+	case extendBy == 0:
+		extendBy = time.Duration(r.Duration) * 24 * time.Hour
+	}
+
+	created := time.Time(r.CreationTime)
+	if created.IsZero() {
+		return 0, time.Time{}, fmt.Errorf("robot account %q has no creation time", r.Name)
+	}
+
+	target := rt.clock().Add(extendBy)
+	days := int64(math.Ceil(target.Sub(created).Hours() / 24))
+
+	return days, created.AddDate(0, 0, int(days)), nil
+}
+
+func (rt *Rotator) clock() time.Time {
+	if rt.now == nil {
+		return time.Now()
+	}
+
+	return rt.now()
+}
@@ -0,0 +1,221 @@
+//go:build !integration
+
+package rotation
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	robotapi "github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/robot"
+	"github.com/mittwald/goharbor-client/v5/apiv2/mocks"
+	modelv2 "github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/robot"
+	clienttesting "github.com/mittwald/goharbor-client/v5/apiv2/pkg/testing"
+)
+
+var (
+	ctx = context.Background()
+	now = time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	expiringRobot = &modelv2.Robot{
+		ID:           1,
+		Name:         "robot$expiring",
+		Duration:     30,
+		CreationTime: strfmt.DateTime(now.AddDate(0, 0, -29)),
+		ExpiresAt:    now.Add(24 * time.Hour).Unix(),
+	}
+	validRobot = &modelv2.Robot{
+		ID:        2,
+		Name:      "robot$valid",
+		ExpiresAt: now.AddDate(0, 0, 60).Unix(),
+	}
+	eternalRobot = &modelv2.Robot{
+		ID:        3,
+		Name:      "robot$eternal",
+		ExpiresAt: -1,
+	}
+)
+
+func rotatorForTests(sink SecretSink) (*Rotator, *clienttesting.MockClients) {
+	desiredMockClients := &clienttesting.MockClients{
+		Robot: mocks.MockRobotClientService{},
+	}
+
+	v2Client := clienttesting.BuildV2ClientWithMocks(desiredMockClients)
+
+	rt := NewRotator(robot.NewClient(v2Client, clienttesting.DefaultOpts, clienttesting.AuthInfo), sink, 7*24*time.Hour)
+	rt.now = func() time.Time { return now }
+
+	desiredMockClients.Robot.On("ListRobot", mock.AnythingOfType("*robot.ListRobotParams"),
+		mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&robotapi.ListRobotOK{
+			Payload:     []*modelv2.Robot{expiringRobot, validRobot, eternalRobot},
+			XTotalCount: 3,
+		}, nil)
+
+	return rt, desiredMockClients
+}
+
+func TestRotator_Expiring(t *testing.T) {
+	rt, mockClient := rotatorForTests(nil)
+
+	robots, err := rt.Expiring(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []*modelv2.Robot{expiringRobot}, robots)
+
+	mockClient.Robot.AssertExpectations(t)
+}
+
+func TestRotator_RotateDryRun(t *testing.T) {
+	rt, mockClient := rotatorForTests(SecretSinkFunc(func(context.Context, *modelv2.Robot, string) error {
+		t.Fatal("sink must not be called in dry-run mode")
+		return nil
+	}))
+	rt.DryRun = true
+
+	report, err := rt.Rotate(ctx)
+	require.NoError(t, err)
+	require.True(t, report.DryRun)
+	require.Equal(t, 2, report.Checked)
+	require.Len(t, report.Results, 1)
+	require.Equal(t, ActionExtendedAndRefreshed, report.Results[0].Action)
+	require.NoError(t, report.Err())
+
+	mockClient.Robot.AssertExpectations(t)
+	mockClient.Robot.AssertNotCalled(t, "UpdateRobot", mock.Anything, mock.Anything)
+	mockClient.Robot.AssertNotCalled(t, "RefreshSec", mock.Anything, mock.Anything)
+}
+
+// mockRefresh makes the robot client extend the expiring robot by its own duration of 30 days
+// and refresh its secret to 'secret'.
+func mockRefresh(mockClient *clienttesting.MockClients, secret string) {
+	mockClient.Robot.On("UpdateRobot", mock.MatchedBy(func(p *robotapi.UpdateRobotParams) bool {
+		// Created 29 days ago, valid for another 30 days.
+		return p.RobotID == expiringRobot.ID && p.Robot.Duration == 59
+	}), mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&robotapi.UpdateRobotOK{}, nil)
+
+	refreshParams := &robotapi.RefreshSecParams{
+		RobotSec: &modelv2.RobotSec{Secret: ""},
+		RobotID:  expiringRobot.ID,
+		Context:  ctx,
+	}
+	refreshParams.WithTimeout(clienttesting.DefaultOpts.Timeout)
+
+	mockClient.Robot.On("RefreshSec", refreshParams, mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&robotapi.RefreshSecOK{Payload: &modelv2.RobotSec{Secret: secret}}, nil)
+}
+
+func TestRotator_RotateExtendAndRefresh(t *testing.T) {
+	var written string
+
+	rt, mockClient := rotatorForTests(SecretSinkFunc(func(_ context.Context, r *modelv2.Robot, secret string) error {
+		require.Equal(t, expiringRobot.Name, r.Name)
+		written = secret
+		return nil
+	}))
+
+	mockRefresh(mockClient, "n3wS3cret")
+
+	report, err := rt.Rotate(ctx)
+	require.NoError(t, err)
+	require.NoError(t, report.Err())
+	require.Len(t, report.Results, 1)
+	require.Equal(t, ActionExtendedAndRefreshed, report.Results[0].Action)
+	require.True(t, now.AddDate(0, 0, 30).Equal(report.Results[0].NewExpiresAt))
+	require.Equal(t, "n3wS3cret", written)
+	require.Empty(t, report.Results[0].Secret, "written secrets are not kept in the result")
+
+	mockClient.Robot.AssertExpectations(t)
+}
+
+func TestRotator_RotateSinkFailure(t *testing.T) {
+	rt, mockClient := rotatorForTests(SecretSinkFunc(func(context.Context, *modelv2.Robot, string) error {
+		return os.ErrPermission
+	}))
+
+	mockRefresh(mockClient, "n3wS3cret")
+
+	report, err := rt.Rotate(ctx)
+	require.NoError(t, err)
+	require.ErrorIs(t, report.Err(), os.ErrPermission)
+	require.Equal(t, "n3wS3cret", report.Results[0].Secret, "the secret can be recovered from the result")
+}
+
+func TestRotator_RotateExtend(t *testing.T) {
+	rt, mockClient := rotatorForTests(nil)
+	rt.Strategy = StrategyExtend
+	rt.ExtendBy = 30 * 24 * time.Hour
+
+	mockClient.Robot.On("UpdateRobot", mock.MatchedBy(func(p *robotapi.UpdateRobotParams) bool {
+		// Created 29 days ago, valid for another 30 days.
+		return p.RobotID == expiringRobot.ID && p.Robot.Duration == 59
+	}), mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&robotapi.UpdateRobotOK{}, nil)
+
+	report, err := rt.Rotate(ctx)
+	require.NoError(t, err)
+	require.NoError(t, report.Err())
+	require.Len(t, report.Results, 1)
+	require.Equal(t, ActionExtended, report.Results[0].Action)
+	require.True(t, now.AddDate(0, 0, 30).Equal(report.Results[0].NewExpiresAt))
+
+	mockClient.Robot.AssertExpectations(t)
+}
+
+func TestRotator_RotateRefreshWithoutSink(t *testing.T) {
+	rt, _ := rotatorForTests(nil)
+
+	report, err := rt.Rotate(ctx)
+	require.NoError(t, err)
+	require.Error(t, report.Err())
+	require.Len(t, report.Failed(), 1)
+}
+
+func TestDockerConfigJSONSink_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"auths":{"other.registry":{"auth":"Zm9vOmJhcg==","identitytoken":"t0ken","email":"a@example.com"}},"credsStore":"desktop"}`), 0o600))
+
+	sink := NewDockerConfigJSONSink(path, "harbor.mydomain.com")
+	require.NoError(t, sink.Write(ctx, expiringRobot, "s3cret"))
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var cfg struct {
+		Auths      map[string]dockerAuth `json:"auths"`
+		CredsStore string                `json:"credsStore"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &cfg))
+
+	require.Equal(t, "desktop", cfg.CredsStore)
+	require.Equal(t, "Zm9vOmJhcg==", cfg.Auths["other.registry"].Auth)
+
+	var other struct {
+		Auths map[string]map[string]string `json:"auths"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &other))
+	require.Equal(t, map[string]string{"auth": "Zm9vOmJhcg==", "identitytoken": "t0ken", "email": "a@example.com"},
+		other.Auths["other.registry"], "fields unknown to the sink are kept")
+	require.Equal(t, expiringRobot.Name, cfg.Auths["harbor.mydomain.com"].Username)
+	require.Equal(t, "s3cret", cfg.Auths["harbor.mydomain.com"].Password)
+}
+
+func TestFileSink_Write(t *testing.T) {
+	dir := t.TempDir()
+
+	sink := NewFileSink(dir)
+	require.NoError(t, sink.Write(ctx, expiringRobot, "s3cret"))
+
+	secret, err := os.ReadFile(filepath.Join(dir, "robot_expiring"))
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", string(secret))
+}
@@ -0,0 +1,144 @@
+package rotation
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+// FileSink writes each secret to a separate file inside Dir.
+type FileSink struct {
+	// Dir is the directory the secret files are written to.
+	Dir string
+	// FileName returns the file name for the given robot account.
+	// Defaults to the robot name with '$' and '+' replaced by '_'.
+	FileName func(r *model.Robot) string
+}
+
+// NewFileSink returns a FileSink writing secrets to 'dir'.
+func NewFileSink(dir string) *FileSink {
+	return &FileSink{Dir: dir}
+}
+
+// Write atomically writes 'secret' to the file belonging to robot account 'r'.
+func (s *FileSink) Write(_ context.Context, r *model.Robot, secret string) error {
+	name := defaultFileName(r)
+	if s.FileName != nil {
+		name = s.FileName(r)
+	}
+
+	return writeFileAtomic(filepath.Join(s.Dir, name), []byte(secret))
+}
+
+func defaultFileName(r *model.Robot) string {
+	return strings.NewReplacer("$", "_", "+", "_", "/", "_").Replace(r.Name)
+}
+
+// DockerConfigJSONSink writes secrets as registry credentials
+// into a docker config file (e.g. '~/.docker/config.json' or a '.dockerconfigjson' secret payload).
+// Existing entries of other registries are preserved.
+type DockerConfigJSONSink struct {
+	// Path of the docker config file.
+	Path string
+	// Registry is the registry host the credentials are stored for, e.g. 'harbor.mydomain.com'.
+	Registry string
+
+	mu sync.Mutex
+}
+
+// NewDockerConfigJSONSink returns a DockerConfigJSONSink writing to 'path' for 'registry'.
+func NewDockerConfigJSONSink(path, registry string) *DockerConfigJSONSink {
+	return &DockerConfigJSONSink{Path: path, Registry: registry}
+}
+
+type dockerAuth struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// Write stores the robot account name and 'secret' as the credentials for the configured registry.
+func (s *DockerConfigJSONSink) Write(_ context.Context, r *model.Robot, secret string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cfg := map[string]json.RawMessage{}
+
+	existing, err := os.ReadFile(s.Path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(existing, &cfg); err != nil {
+			return fmt.Errorf("parsing docker config %s: %w", s.Path, err)
+		}
+	case !os.IsNotExist(err):
+		return err
+	}
+
+	// Entries of other registries are kept as is, including fields like "identitytoken" or "email".
+	auths := map[string]json.RawMessage{}
+
+	if raw, ok := cfg["auths"]; ok {
+		if err := json.Unmarshal(raw, &auths); err != nil {
+			return fmt.Errorf("parsing auths of docker config %s: %w", s.Path, err)
+		}
+	}
+
+	auth, err := json.Marshal(dockerAuth{
+		Username: r.Name,
+		Password: secret,
+		Auth:     base64.StdEncoding.EncodeToString([]byte(r.Name + ":" + secret)),
+	})
+	if err != nil {
+		return err
+	}
+
+	auths[s.Registry] = auth
+
+	rawAuths, err := json.Marshal(auths)
+	if err != nil {
+		return err
+	}
+
+	cfg["auths"] = rawAuths
+
+	out, err := json.MarshalIndent(cfg, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(s.Path, out)
+}
+
+// writeFileAtomic writes 'data' to a temporary file next to 'path' and renames it afterwards,
+// so consumers never observe partially written credentials.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
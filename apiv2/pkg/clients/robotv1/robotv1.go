@@ -24,34 +24,36 @@ type RESTClient struct {
 
 func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
 	return &RESTClient{
-		Options:  opts,
+		Options:  opts.Copy(),
 		V2Client: v2Client,
 		AuthInfo: authInfo,
 	}
 }
 
 type Client interface {
-	ListProjectRobotsV1(ctx context.Context, projectNameOrID string) ([]*modelv2.Robot, error)
+	ListProjectRobotsV1(ctx context.Context, projectNameOrID string, opts ...config.CallOption) ([]*modelv2.Robot, error)
 	AddProjectRobotV1(ctx context.Context, projectNameOrID string, r *modelv2.RobotCreateV1) error
 	UpdateProjectRobotV1(ctx context.Context, projectNameOrID string, robotID int64, r *modelv2.Robot) error
 	DeleteProjectRobotV1(ctx context.Context, projectNameOrID string, robotID int64) error
 }
 
 // ListProjectRobotsV1 returns a list of all robot accounts in project p.
-func (c *RESTClient) ListProjectRobotsV1(ctx context.Context, projectNameOrID string) ([]*modelv2.Robot, error) {
+func (c *RESTClient) ListProjectRobotsV1(ctx context.Context, projectNameOrID string, opts ...config.CallOption) ([]*modelv2.Robot, error) {
+	o := c.Options.Apply(opts...)
+
 	var robotAccounts []*modelv2.Robot
-	page := c.Options.Page
+	page := o.Page
 
 	params := &robotv1.ListRobotV1Params{
 		Page:            &page,
-		PageSize:        &c.Options.PageSize,
+		PageSize:        &o.PageSize,
 		ProjectNameOrID: projectNameOrID,
-		Q:               &c.Options.Query,
-		Sort:            &c.Options.Sort,
+		Q:               &o.Query,
+		Sort:            &o.Sort,
 		Context:         ctx,
 	}
 
-	params.WithTimeout(c.Options.Timeout)
+	params.WithTimeout(o.Timeout)
 
 	for {
 		resp, err := c.V2Client.Robotv1.ListRobotV1(params, c.AuthInfo)
@@ -5,10 +5,16 @@ import (
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
 )
 
-// handleSwaggerSystemInfoErrors takes a swagger generated error as input,
+// handleSwaggerSystemInfoErrors maps a swagger generated error to a typed error, which is wrapped
+// in an errors.HarborError carrying the details of the response the error originates from.
+func handleSwaggerSystemInfoErrors(in error) error {
+	return errors.WrapSwaggerError(in, mapSwaggerSystemInfoErrors(in))
+}
+
+// mapSwaggerSystemInfoErrors takes a swagger generated error as input,
 // which usually does not contain any form of error message,
 // and outputs a new error with a proper message.
-func handleSwaggerSystemInfoErrors(in error) error {
+func mapSwaggerSystemInfoErrors(in error) error {
 	switch in.(type) {
 	case *systeminfo.GetSystemInfoInternalServerError:
 		return &errors.ErrInternalErrors{}
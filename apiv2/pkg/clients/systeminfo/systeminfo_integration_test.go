@@ -19,5 +19,5 @@ func TestAPIGetSystemInfo(t *testing.T) {
 	require.NoError(t, err)
 
 	require.Equal(t, false, *resp.ReadOnly)
-	require.Equal(t, "core.harbor.domain", *resp.RegistryURL)
+	require.Equal(t, clienttesting.RegistryURL, *resp.RegistryURL)
 }
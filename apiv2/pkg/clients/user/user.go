@@ -29,7 +29,7 @@ type RESTClient struct {
 
 func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
 	return &RESTClient{
-		Options:  opts,
+		Options:  opts.Copy(),
 		V2Client: v2Client,
 		AuthInfo: authInfo,
 	}
@@ -37,16 +37,17 @@ func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime
 
 type Client interface {
 	NewUser(ctx context.Context, username, email, realname, password, comments string) error
-	GetUserByName(ctx context.Context, username string) (*model.UserResp, error)
+	GetUserByName(ctx context.Context, username string, opts ...config.CallOption) (*model.UserResp, error)
 	GetUserByID(ctx context.Context, id int64) (*model.UserResp, error)
-	ListUsers(ctx context.Context) ([]*model.UserResp, error)
-	SearchUsers(ctx context.Context, name string) ([]*model.UserSearchRespItem, error)
+	ListUsers(ctx context.Context, opts ...config.CallOption) ([]*model.UserResp, error)
+	SearchUsers(ctx context.Context, name string, opts ...config.CallOption) ([]*model.UserSearchRespItem, error)
 	GetCurrentUserInfo(ctx context.Context) (*model.UserResp, error)
 	GetCurrentUserPermisisons(ctx context.Context, relative bool, scope string) ([]*model.Permission, error)
 	SetUserSysAdmin(ctx context.Context, id int64, admin bool) error
 	DeleteUser(ctx context.Context, id int64) error
 	UpdateUserProfile(ctx context.Context, id int64, profile *model.UserProfile) error
 	UpdateUserPassword(ctx context.Context, userID int64, passwordRequest *model.PasswordReq) error
+	SetCLISecret(ctx context.Context, userID int64, secret string) error
 	UserExists(ctx context.Context, idOrName intstr.IntOrString) (bool, error)
 }
 
@@ -74,14 +75,14 @@ func (c *RESTClient) NewUser(ctx context.Context, username, email, realname, pas
 }
 
 // GetUserByName returns an existing user identified by name.
-func (c *RESTClient) GetUserByName(ctx context.Context, username string) (*model.UserResp, error) {
+func (c *RESTClient) GetUserByName(ctx context.Context, username string, opts ...config.CallOption) (*model.UserResp, error) {
 	if username == "" {
 		return nil, errors.New("no username provided")
 	}
 
-	c.Options.PageSize = 100
+	opts = append([]config.CallOption{config.WithPageSize(100)}, opts...)
 
-	resp, err := c.ListUsers(ctx)
+	resp, err := c.ListUsers(ctx, opts...)
 	if err != nil {
 		return nil, handleSwaggerUserErrors(err)
 	}
@@ -101,8 +102,6 @@ func (c *RESTClient) GetUserByID(ctx context.Context, id int64) (*model.UserResp
 		return nil, &clienterrors.ErrUserInvalidID{}
 	}
 
-	c.Options.PageSize = 100
-
 	params := &user.GetUserParams{
 		UserID:  id,
 		Context: ctx,
@@ -130,19 +129,21 @@ func (c *RESTClient) GetUserByID(ctx context.Context, id int64) (*model.UserResp
 
 // ListUsers lists and returns all registered Harbor users.
 // The maximum number of users listed is bound to the RESTClient's configured PageSize.
-func (c *RESTClient) ListUsers(ctx context.Context) ([]*model.UserResp, error) {
+func (c *RESTClient) ListUsers(ctx context.Context, opts ...config.CallOption) ([]*model.UserResp, error) {
+	o := c.Options.Apply(opts...)
+
 	var users []*model.UserResp
-	page := c.Options.Page
+	page := o.Page
 
 	params := user.ListUsersParams{
 		Page:     &page,
-		PageSize: &c.Options.PageSize,
-		Q:        &c.Options.Query,
-		Sort:     &c.Options.Sort,
+		PageSize: &o.PageSize,
+		Q:        &o.Query,
+		Sort:     &o.Sort,
 		Context:  ctx,
 	}
 
-	params.WithTimeout(c.Options.Timeout)
+	params.WithTimeout(o.Timeout)
 
 	for {
 		resp, err := c.V2Client.User.ListUsers(&params, c.AuthInfo)
@@ -169,14 +170,16 @@ func (c *RESTClient) ListUsers(ctx context.Context) ([]*model.UserResp, error) {
 }
 
 // SearchUsers searches all existing users by the provided username 'name' and returns matching users.
-func (c *RESTClient) SearchUsers(ctx context.Context, name string) ([]*model.UserSearchRespItem, error) {
+func (c *RESTClient) SearchUsers(ctx context.Context, name string, opts ...config.CallOption) ([]*model.UserSearchRespItem, error) {
+	o := c.Options.Apply(opts...)
+
 	params := &user.SearchUsersParams{
-		PageSize: &c.Options.PageSize,
+		PageSize: &o.PageSize,
 		Username: name,
 		Context:  ctx,
 	}
 
-	params.WithTimeout(c.Options.Timeout)
+	params.WithTimeout(o.Timeout)
 
 	resp, err := c.V2Client.User.SearchUsers(params, c.AuthInfo)
 	if err != nil {
@@ -305,6 +308,26 @@ func (c *RESTClient) UpdateUserPassword(ctx context.Context, userID int64, passw
 	return handleSwaggerUserErrors(err)
 }
 
+// SetCLISecret sets the CLI secret of the OIDC user identified by 'userID'.
+// The CLI secret authenticates the user in place of a password, e.g. using auth.OIDCCLISecret.
+func (c *RESTClient) SetCLISecret(ctx context.Context, userID int64, secret string) error {
+	if secret == "" {
+		return errors.New("no secret provided")
+	}
+
+	params := &user.SetCliSecretParams{
+		Secret:  &model.OIDCCliSecretReq{Secret: secret},
+		UserID:  userID,
+		Context: ctx,
+	}
+
+	params.WithTimeout(c.Options.Timeout)
+
+	_, err := c.V2Client.User.SetCliSecret(params, c.AuthInfo)
+
+	return handleSwaggerUserErrors(err)
+}
+
 // UserExists checks the user with the provided 'idOrName' for existence.
 func (c *RESTClient) UserExists(ctx context.Context, idOrName intstr.IntOrString) (bool, error) {
 	switch idOrName.Type {
@@ -314,7 +337,7 @@ func (c *RESTClient) UserExists(ctx context.Context, idOrName intstr.IntOrString
 		if idOrName.Type == intstr.Int {
 			_, err := c.GetUserByID(ctx, int64(idOrName.IntVal))
 			if err != nil {
-				if _, ok := err.(*clienterrors.ErrUserNotFound); ok {
+				if errors.As(err, new(*clienterrors.ErrUserNotFound)) {
 					return false, nil
 				}
 				return false, err
@@ -323,7 +346,7 @@ func (c *RESTClient) UserExists(ctx context.Context, idOrName intstr.IntOrString
 	case intstr.String:
 		_, err := c.GetUserByName(ctx, idOrName.StrVal)
 		if err != nil {
-			if _, ok := err.(*clienterrors.ErrUserNotFound); ok {
+			if errors.As(err, new(*clienterrors.ErrUserNotFound)) {
 				return false, nil
 			}
 			return false, err
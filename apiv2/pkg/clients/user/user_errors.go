@@ -9,10 +9,16 @@ import (
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
 )
 
-// handleUserErrors takes a swagger generated error as input,
+// handleSwaggerUserErrors maps a swagger generated error to a typed error, which is wrapped
+// in an errors.HarborError carrying the details of the response the error originates from.
+func handleSwaggerUserErrors(in error) error {
+	return errors.WrapSwaggerError(in, mapSwaggerUserErrors(in))
+}
+
+// mapSwaggerUserErrors takes a swagger generated error as input,
 // which usually does not contain any form of error message,
 // and outputs a new error with a proper message.
-func handleSwaggerUserErrors(in error) error {
+func mapSwaggerUserErrors(in error) error {
 	t, ok := in.(*runtime.APIError)
 	if ok {
 		switch t.Code {
@@ -28,7 +34,15 @@ func handleSwaggerUserErrors(in error) error {
 		return in
 	case *user.CreateUserBadRequest:
 		return &errors.ErrUserBadRequest{}
+	case *user.CreateUserConflict:
+		return &errors.ErrUserAlreadyExists{}
 	case *user.UpdateUserPasswordBadRequest:
 		return &errors.ErrUserPasswordInvalid{}
+	case *user.SetCliSecretBadRequest:
+		return &errors.ErrUserBadRequest{}
+	case *user.SetCliSecretNotFound:
+		return &errors.ErrUserNotFound{}
+	case *user.SetCliSecretPreconditionFailed:
+		return &errors.ErrUserCLISecretUnsupported{}
 	}
 }
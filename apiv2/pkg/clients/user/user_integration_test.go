@@ -7,8 +7,8 @@ import (
 	"strconv"
 	"testing"
 
-	"github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/user"
 	modelv2 "github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
 	clienttesting "github.com/mittwald/goharbor-client/v5/apiv2/pkg/testing"
 
 	"github.com/stretchr/testify/require"
@@ -60,7 +60,7 @@ func TestAPIUserAlreadyExists(t *testing.T) {
 	err = c.NewUser(ctx, username, email, realname, password, comments)
 
 	require.Error(t, err)
-	require.IsType(t, &user.CreateUserConflict{}, err)
+	require.ErrorIs(t, err, &errors.ErrUserAlreadyExists{})
 }
 
 func TestAPIUserGet(t *testing.T) {
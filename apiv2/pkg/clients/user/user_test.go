@@ -65,6 +65,31 @@ func TestTRESTClient_NewUser(t *testing.T) {
 	mockClient.User.AssertExpectations(t)
 }
 
+func TestRESTClient_NewUser_AlreadyExists(t *testing.T) {
+	apiClient, mockClient := APIandMockClientsForTests()
+
+	createParams := &user.CreateUserParams{
+		UserReq: &modelv2.UserCreationReq{
+			Email:    exampleEmail,
+			Password: examplePassword,
+			Username: exampleUsername,
+		},
+		Context: ctx,
+	}
+
+	createParams.WithTimeout(apiClient.Options.Timeout)
+
+	mockClient.User.On("CreateUser", createParams,
+		mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(nil, &user.CreateUserConflict{})
+
+	err := apiClient.NewUser(ctx, exampleUsername, exampleEmail, "", examplePassword, "")
+
+	require.ErrorIs(t, err, &errors.ErrUserAlreadyExists{})
+
+	mockClient.User.AssertExpectations(t)
+}
+
 func TestRESTClient_GetUserByID(t *testing.T) {
 	apiClient, mockClient := APIandMockClientsForTests()
 
@@ -141,9 +166,12 @@ func TestRESTClient_GetUserByID_3(t *testing.T) {
 func TestRESTClient_GetUserByName(t *testing.T) {
 	apiClient, mockClient := APIandMockClientsForTests()
 
+	// Users are looked up using the maximum page size, without modifying the client's options.
+	pageSize := int64(100)
+
 	listParams := &user.ListUsersParams{
 		Page:     &apiClient.Options.Page,
-		PageSize: &apiClient.Options.PageSize,
+		PageSize: &pageSize,
 		Q:        &apiClient.Options.Query,
 		Sort:     &apiClient.Options.Sort,
 		Context:  ctx,
@@ -161,6 +189,7 @@ func TestRESTClient_GetUserByName(t *testing.T) {
 
 	_, err := apiClient.GetUserByName(ctx, exampleUsername)
 	require.NoError(t, err)
+	require.Equal(t, clienttesting.DefaultOpts.PageSize, apiClient.Options.PageSize)
 
 	mockClient.User.AssertExpectations(t)
 }
@@ -303,6 +332,37 @@ func TestRESTClient_DeleteUser(t *testing.T) {
 	mockClient.User.AssertExpectations(t)
 }
 
+func TestRESTClient_SetCLISecret(t *testing.T) {
+	apiClient, mockClient := APIandMockClientsForTests()
+
+	params := &user.SetCliSecretParams{
+		Secret:  &modelv2.OIDCCliSecretReq{Secret: "Secret1234"},
+		UserID:  exampleUserID,
+		Context: ctx,
+	}
+
+	params.WithTimeout(apiClient.Options.Timeout)
+
+	mockClient.User.On("SetCliSecret", params,
+		mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(&user.SetCliSecretOK{}, nil).Once()
+
+	err := apiClient.SetCLISecret(ctx, exampleUserID, "Secret1234")
+	require.NoError(t, err)
+
+	mockClient.User.On("SetCliSecret", params,
+		mock.AnythingOfType("runtime.ClientAuthInfoWriterFunc")).
+		Return(nil, &user.SetCliSecretPreconditionFailed{}).Once()
+
+	err = apiClient.SetCLISecret(ctx, exampleUserID, "Secret1234")
+	require.ErrorIs(t, err, &errors.ErrUserCLISecretUnsupported{})
+
+	err = apiClient.SetCLISecret(ctx, exampleUserID, "")
+	require.Error(t, err)
+
+	mockClient.User.AssertExpectations(t)
+}
+
 func TestRESTClient_UserExists(t *testing.T) {
 	apiClient, mockClient := APIandMockClientsForTests()
 
@@ -313,9 +373,11 @@ func TestRESTClient_UserExists(t *testing.T) {
 
 	getParams.WithTimeout(apiClient.Options.Timeout)
 
+	pageSize := int64(100)
+
 	listParams := &user.ListUsersParams{
 		Page:     &apiClient.Options.Page,
-		PageSize: &apiClient.Options.PageSize,
+		PageSize: &pageSize,
 		Q:        &apiClient.Options.Query,
 		Sort:     &apiClient.Options.Sort,
 		Context:  ctx,
@@ -27,34 +27,36 @@ type RESTClient struct {
 
 func NewClient(v2Client *v2client.Harbor, opts *config.Options, authInfo runtime.ClientAuthInfoWriter) *RESTClient {
 	return &RESTClient{
-		Options:  opts,
+		Options:  opts.Copy(),
 		V2Client: v2Client,
 		AuthInfo: authInfo,
 	}
 }
 
 type Client interface {
-	ListProjectWebhookPolicies(ctx context.Context, projectID int) ([]*model.WebhookPolicy, error)
+	ListProjectWebhookPolicies(ctx context.Context, projectID int, opts ...config.CallOption) ([]*model.WebhookPolicy, error)
 	AddProjectWebhookPolicy(ctx context.Context, projectID int, policy *model.WebhookPolicy) error
 	UpdateProjectWebhookPolicy(ctx context.Context, projectID int, policy *model.WebhookPolicy) error
 	DeleteProjectWebhookPolicy(ctx context.Context, projectID int, policyID int64) error
 }
 
 // ListProjectWebhookPolicies returns a list of all webhook policies in project p.
-func (c *RESTClient) ListProjectWebhookPolicies(ctx context.Context, projectID int) ([]*model.WebhookPolicy, error) {
+func (c *RESTClient) ListProjectWebhookPolicies(ctx context.Context, projectID int, opts ...config.CallOption) ([]*model.WebhookPolicy, error) {
+	o := c.Options.Apply(opts...)
+
 	var webhookPolicies []*model.WebhookPolicy
-	page := c.Options.Page
+	page := o.Page
 
 	params := &webhook.ListWebhookPoliciesOfProjectParams{
 		Page:            &page,
-		PageSize:        &c.Options.PageSize,
+		PageSize:        &o.PageSize,
 		ProjectNameOrID: strconv.Itoa(projectID),
-		Q:               &c.Options.Query,
-		Sort:            &c.Options.Sort,
+		Q:               &o.Query,
+		Sort:            &o.Sort,
 		Context:         ctx,
 	}
 
-	params.WithTimeout(c.Options.Timeout)
+	params.WithTimeout(o.Timeout)
 
 	for {
 		resp, err := c.V2Client.Webhook.ListWebhookPoliciesOfProject(params, c.AuthInfo)
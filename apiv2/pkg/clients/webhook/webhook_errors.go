@@ -4,12 +4,20 @@ import (
 	"net/http"
 
 	"github.com/go-openapi/runtime"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
 )
 
-// handleSwaggerWebhookErrors takes a swagger generated error as input,
+// handleSwaggerWebhookErrors maps a swagger generated error to a typed error, which is wrapped
+// in an errors.HarborError carrying the details of the response the error originates from.
+func handleSwaggerWebhookErrors(in error) error {
+	return errors.WrapSwaggerError(in, mapSwaggerWebhookErrors(in))
+}
+
+// mapSwaggerWebhookErrors takes a swagger generated error as input,
 // which usually does not contain any form of error message,
 // and outputs a new error with a proper message.
-func handleSwaggerWebhookErrors(in error) error {
+func mapSwaggerWebhookErrors(in error) error {
 	t, ok := in.(*runtime.APIError)
 	if ok {
 		switch t.Code {
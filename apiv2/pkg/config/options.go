@@ -2,9 +2,16 @@ package config
 
 import (
 	"time"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/retry"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/telemetry"
 )
 
 // Options defines optional parameters for configuring an API client.
+//
+// The Options passed to a client are copied on construction and treated as immutable defaults afterwards.
+// Operations listing resources accept CallOptions overriding these defaults for a single call,
+// making clients safe for concurrent use with differing queries, sort orders or page sizes.
 type Options struct {
 	// PageSize used for the client operations, a maximum of 100 is enforced by the Goharbor API.
 	PageSize int64
@@ -12,12 +19,21 @@ type Options struct {
 	Page int64
 	// The timeout for client operations.
 	Timeout time.Duration
-	// Sort string used on 'list' client operations.
+	// Sort string used on 'list' client operations, see the query package for building it.
 	Sort string
-	// Query string used for client operations.
+	// Query string used for client operations, see the query package for building it.
 	Query string
+	// Retry is the policy for retrying failed requests of clients constructed from a host URL.
+	// Requests are not retried if nil. Clients constructed from a swagger client reject it, see apiv2.NewRESTClientFromSwagger.
+	Retry *retry.Policy
+	// CircuitBreaker optionally rejects requests of clients constructed from a host URL
+	// while the Harbor API is unavailable. Clients constructed from a swagger client reject it as well.
+	CircuitBreaker *retry.CircuitBreaker
+	// Telemetry optionally records traces and metrics of client operations. Nothing is recorded if nil.
+	Telemetry *telemetry.Telemetry
 }
 
+// Defaults returns the default Options.
 func Defaults() *Options {
 	return &Options{
 		PageSize: 10,
@@ -52,3 +68,81 @@ func (o *Options) WithQuery(query string) *Options {
 	o.Query = query
 	return o
 }
+
+func (o *Options) WithRetry(policy *retry.Policy) *Options {
+	o.Retry = policy
+	return o
+}
+
+func (o *Options) WithCircuitBreaker(breaker *retry.CircuitBreaker) *Options {
+	o.CircuitBreaker = breaker
+	return o
+}
+
+func (o *Options) WithTelemetry(t *telemetry.Telemetry) *Options {
+	o.Telemetry = t
+	return o
+}
+
+// Copy returns a copy of 'o'. Defaults are returned if 'o' is nil.
+func (o *Options) Copy() *Options {
+	if o == nil {
+		return Defaults()
+	}
+
+	c := *o
+
+	return &c
+}
+
+// Apply returns a copy of 'o' with 'opts' applied, leaving 'o' unchanged.
+func (o *Options) Apply(opts ...CallOption) *Options {
+	c := o.Copy()
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+
+	return c
+}
+
+// CallOption overrides the Options of a single client operation.
+type CallOption func(*Options)
+
+// WithPage overrides the page the listing of a single client operation starts at.
+func WithPage(page int64) CallOption {
+	return func(o *Options) {
+		o.Page = page
+	}
+}
+
+// WithPageSize overrides the page size of a single client operation.
+func WithPageSize(pageSize int64) CallOption {
+	return func(o *Options) {
+		o.PageSize = pageSize
+	}
+}
+
+// WithQuery overrides the query string of a single client operation, e.g. 'name=~foo'.
+// The query package builds query strings and the corresponding CallOptions.
+func WithQuery(query string) CallOption {
+	return func(o *Options) {
+		o.Query = query
+	}
+}
+
+// WithSort overrides the sort string of a single client operation, e.g. '-creation_time'.
+func WithSort(sort string) CallOption {
+	return func(o *Options) {
+		o.Sort = sort
+	}
+}
+
+// WithTimeout overrides the timeout of a single client operation.
+func WithTimeout(timeout time.Duration) CallOption {
+	return func(o *Options) {
+		o.Timeout = timeout
+	}
+}
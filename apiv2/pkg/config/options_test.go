@@ -0,0 +1,26 @@
+//go:build !integration
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_Apply(t *testing.T) {
+	defaults := Defaults().WithQuery("name=foo")
+
+	o := defaults.Apply(WithPage(2), WithPageSize(100), WithQuery("name=bar"), WithSort("-name"), WithTimeout(time.Second), nil)
+
+	require.Equal(t, &Options{Page: 2, PageSize: 100, Query: "name=bar", Sort: "-name", Timeout: time.Second}, o)
+	require.Equal(t, Defaults().WithQuery("name=foo"), defaults, "Apply must not modify the receiver")
+}
+
+func TestOptions_ApplyNil(t *testing.T) {
+	var o *Options
+
+	require.Equal(t, Defaults(), o.Apply())
+	require.Equal(t, Defaults().WithPageSize(1), o.Apply(WithPageSize(1)))
+}
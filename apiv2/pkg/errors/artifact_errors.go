@@ -0,0 +1,16 @@
+package errors
+
+const (
+	// ErrArtifactAccessoryNotProvidedMsg is the error message for ErrArtifactAccessoryNotProvided error.
+	ErrArtifactAccessoryNotProvidedMsg = "no accessory provided"
+)
+
+// ErrArtifactAccessoryNotProvided describes an error when no accessory (or an accessory without digest) is provided.
+type ErrArtifactAccessoryNotProvided struct {
+	Response[ErrArtifactAccessoryNotProvided]
+}
+
+// Error returns the error message.
+func (e *ErrArtifactAccessoryNotProvided) Error() string {
+	return ErrArtifactAccessoryNotProvidedMsg
+}
@@ -1,5 +1,7 @@
 package errors
 
+import "fmt"
+
 const (
 	// ErrAuditLogBadRequestMsg is the error message for ErrAuditLogBadRequest error.
 	ErrAuditLogBadRequestMsg = "unsatisfied with constraints of the auditlog request"
@@ -12,7 +14,9 @@ const (
 )
 
 // ErrAuditLogBadRequest describes an error when a request to the auditlog API is malformed.
-type ErrAuditLogBadRequest struct{}
+type ErrAuditLogBadRequest struct {
+	Response[ErrAuditLogBadRequest]
+}
 
 // Error returns the error message.
 func (e *ErrAuditLogBadRequest) Error() string {
@@ -20,7 +24,9 @@ func (e *ErrAuditLogBadRequest) Error() string {
 }
 
 // ErrAuditLogUnauthorized describes an unauthorized request.
-type ErrAuditLogUnauthorized struct{}
+type ErrAuditLogUnauthorized struct {
+	Response[ErrAuditLogUnauthorized]
+}
 
 // Error returns the error message.
 func (e *ErrAuditLogUnauthorized) Error() string {
@@ -28,9 +34,22 @@ func (e *ErrAuditLogUnauthorized) Error() string {
 }
 
 // ErrAuditLogInternalServerError describes server-side internal errors.
-type ErrAuditLogInternalServerError struct{}
+type ErrAuditLogInternalServerError struct {
+	Response[ErrAuditLogInternalServerError]
+}
 
 // Error returns the error message.
 func (e *ErrAuditLogInternalServerError) Error() string {
 	return ErrAuditLogInternalServerErrorMsg
 }
+
+// ErrAuditLogUnsupportedFormat describes an error when audit log entries are exported in an unknown format.
+type ErrAuditLogUnsupportedFormat struct {
+	// Format is the unsupported format.
+	Format string
+}
+
+// Error returns the error message.
+func (e *ErrAuditLogUnsupportedFormat) Error() string {
+	return fmt.Sprintf("unsupported audit log format %q", e.Format)
+}
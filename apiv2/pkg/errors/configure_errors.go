@@ -1,5 +1,7 @@
 package errors
 
+import "fmt"
+
 const (
 	// ErrConfigureUnauthorizedMsg is the error message for ErrConfigureUnauthorized error.
 	ErrConfigureUnauthorizedMsg = "unauthorized"
@@ -13,13 +15,19 @@ const (
 
 type (
 	// ErrConfigureInternalServerError describes server-side internal errors.
-	ErrConfigureInternalServerError struct{}
+	ErrConfigureInternalServerError struct {
+		Response[ErrConfigureInternalServerError]
+	}
 
 	// ErrConfigureNoPermission describes a request error without permission.
-	ErrConfigureNoPermission struct{}
+	ErrConfigureNoPermission struct {
+		Response[ErrConfigureNoPermission]
+	}
 
 	// ErrConfigureUnauthorized describes an unauthorized request.
-	ErrConfigureUnauthorized struct{}
+	ErrConfigureUnauthorized struct {
+		Response[ErrConfigureUnauthorized]
+	}
 )
 
 // Error returns the error message.
@@ -36,3 +44,42 @@ func (e *ErrConfigureNoPermission) Error() string {
 func (e *ErrConfigureInternalServerError) Error() string {
 	return ErrConfigureInternalServerErrorMsg
 }
+
+// ErrConfigureAuthModeUsersExist describes an error when the authentication mode is changed
+// while users other than the admin exist, which Harbor refuses.
+type ErrConfigureAuthModeUsersExist struct {
+	// Users is the number of users, including the admin.
+	Users int64
+}
+
+// Error returns the error message.
+func (e *ErrConfigureAuthModeUsersExist) Error() string {
+	return fmt.Sprintf("auth_mode cannot be changed while users other than admin exist (%d users)", e.Users)
+}
+
+// Is reports whether 'target' is an ErrConfigureAuthModeUsersExist, regardless of the number of users.
+func (e *ErrConfigureAuthModeUsersExist) Is(target error) bool {
+	_, ok := target.(*ErrConfigureAuthModeUsersExist)
+	return ok
+}
+
+// ErrConfigureDangerousChange describes an error when a configuration change that may lock out users
+// or block the registry is applied without being allowed explicitly.
+type ErrConfigureDangerousChange struct {
+	// Key is the JSON name of the changed item, e.g. "read_only".
+	Key string
+
+	// Reason describes the consequences of the change.
+	Reason string
+}
+
+// Error returns the error message.
+func (e *ErrConfigureDangerousChange) Error() string {
+	return fmt.Sprintf("refusing dangerous change of %q: %s", e.Key, e.Reason)
+}
+
+// Is reports whether 'target' is an ErrConfigureDangerousChange, regardless of the changed item.
+func (e *ErrConfigureDangerousChange) Is(target error) bool {
+	_, ok := target.(*ErrConfigureDangerousChange)
+	return ok
+}
@@ -2,22 +2,24 @@ package errors
 
 type (
 	// ErrUnauthorized describes an unauthorized request.
-	ErrUnauthorized struct{}
+	ErrUnauthorized struct{ Response[ErrUnauthorized] }
 	// ErrBadRequest describes a malformed / invalid request.
-	ErrBadRequest struct{}
+	ErrBadRequest struct{ Response[ErrBadRequest] }
 	// ErrForbidden describes a forbidden request.
-	ErrForbidden struct{}
+	ErrForbidden struct{ Response[ErrForbidden] }
 	// ErrNotFound describes an error when no corresponding resources could be found.
-	ErrNotFound struct{}
+	ErrNotFound struct{ Response[ErrNotFound] }
 	// ErrConflict describes an error when a resource already exists.
-	ErrConflict struct{}
+	ErrConflict struct{ Response[ErrConflict] }
 	// ErrMultipleResults describes an error when multiple
 	// resources were found by an API call that should return only one.
-	ErrMultipleResults struct{}
+	ErrMultipleResults struct{ Response[ErrMultipleResults] }
 	// ErrUnsupportedMediaType describes an error when the request contains an unsupported media type.
-	ErrUnsupportedMediaType struct{}
+	ErrUnsupportedMediaType struct {
+		Response[ErrUnsupportedMediaType]
+	}
 	// ErrInternalErrors describes a generic error that led to an internal server error.
-	ErrInternalErrors struct{}
+	ErrInternalErrors struct{ Response[ErrInternalErrors] }
 )
 
 const (
@@ -33,7 +33,9 @@ const (
 )
 
 // ErrSystemInvalidSchedule describes an invalid schedule type request.
-type ErrSystemInvalidSchedule struct{}
+type ErrSystemInvalidSchedule struct {
+	Response[ErrSystemInvalidSchedule]
+}
 
 // Error returns the error message.
 func (e *ErrSystemInvalidSchedule) Error() string {
@@ -41,7 +43,9 @@ func (e *ErrSystemInvalidSchedule) Error() string {
 }
 
 // ErrSystemGcInProgress describes that a gc progress is already running.
-type ErrSystemGcInProgress struct{}
+type ErrSystemGcInProgress struct {
+	Response[ErrSystemGcInProgress]
+}
 
 // Error returns the error message.
 func (e *ErrSystemGcInProgress) Error() string {
@@ -49,7 +53,9 @@ func (e *ErrSystemGcInProgress) Error() string {
 }
 
 // ErrSystemUnauthorized describes an unauthorized request.
-type ErrSystemUnauthorized struct{}
+type ErrSystemUnauthorized struct {
+	Response[ErrSystemUnauthorized]
+}
 
 // Error returns the error message.
 func (e *ErrSystemUnauthorized) Error() string {
@@ -57,7 +63,9 @@ func (e *ErrSystemUnauthorized) Error() string {
 }
 
 // ErrSystemInternalErrors describes server-side internal errors.
-type ErrSystemInternalErrors struct{}
+type ErrSystemInternalErrors struct {
+	Response[ErrSystemInternalErrors]
+}
 
 // Error returns the error message.
 func (e *ErrSystemInternalErrors) Error() string {
@@ -65,7 +73,9 @@ func (e *ErrSystemInternalErrors) Error() string {
 }
 
 // ErrSystemNoPermission describes a request error without permission.
-type ErrSystemNoPermission struct{}
+type ErrSystemNoPermission struct {
+	Response[ErrSystemNoPermission]
+}
 
 // Error returns the error message.
 func (e *ErrSystemNoPermission) Error() string {
@@ -73,7 +83,7 @@ func (e *ErrSystemNoPermission) Error() string {
 }
 
 // ErrSystemGcUndefined describes a server-side response returning an empty GC schedule.
-type ErrSystemGcUndefined struct{}
+type ErrSystemGcUndefined struct{ Response[ErrSystemGcUndefined] }
 
 // Error returns the error message.
 func (e *ErrSystemGcUndefined) Error() string {
@@ -81,7 +91,9 @@ func (e *ErrSystemGcUndefined) Error() string {
 }
 
 // ErrSystemGcScheduleIdentical describes equality between two GC schedules.
-type ErrSystemGcScheduleIdentical struct{}
+type ErrSystemGcScheduleIdentical struct {
+	Response[ErrSystemGcScheduleIdentical]
+}
 
 // Error returns the error message.
 func (e *ErrSystemGcScheduleIdentical) Error() string {
@@ -89,7 +101,9 @@ func (e *ErrSystemGcScheduleIdentical) Error() string {
 }
 
 // ErrSystemGcScheduleNotProvided describes the absence of a required schedule.
-type ErrSystemGcScheduleNotProvided struct{}
+type ErrSystemGcScheduleNotProvided struct {
+	Response[ErrSystemGcScheduleNotProvided]
+}
 
 // Error returns the error message.
 func (e *ErrSystemGcScheduleNotProvided) Error() string {
@@ -97,7 +111,9 @@ func (e *ErrSystemGcScheduleNotProvided) Error() string {
 }
 
 // ErrSystemGcScheduleUndefined describes an error when the fetched gc schedule is undefined.
-type ErrSystemGcScheduleUndefined struct{}
+type ErrSystemGcScheduleUndefined struct {
+	Response[ErrSystemGcScheduleUndefined]
+}
 
 // Error returns the error message.
 func (e *ErrSystemGcScheduleUndefined) Error() string {
@@ -105,7 +121,9 @@ func (e *ErrSystemGcScheduleUndefined) Error() string {
 }
 
 // ErrSystemGcScheduleParametersUndefined describes an error when a GC schedule's parameters are undefined
-type ErrSystemGcScheduleParametersUndefined struct{}
+type ErrSystemGcScheduleParametersUndefined struct {
+	Response[ErrSystemGcScheduleParametersUndefined]
+}
 
 // Error returns the error message.
 func (e *ErrSystemGcScheduleParametersUndefined) Error() string {
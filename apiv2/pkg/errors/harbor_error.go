@@ -0,0 +1,312 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	goerrors "errors"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+// HarborError describes an error response of the Harbor API.
+//
+// Typed errors returned by the sub-clients, such as ErrProjectNotFound, keep their type for backward
+// compatibility. If they originate from an HTTP response, the HarborError describing it can be retrieved
+// using errors.As or AsHarborError. Errors the sub-clients do not map to a typed error are returned as
+// a HarborError wrapping the swagger error.
+//
+// A HarborError matches the generic errors ErrBadRequest, ErrUnauthorized, ErrForbidden, ErrNotFound,
+// ErrConflict, ErrUnsupportedMediaType and ErrInternalErrors by its status code, e.g.
+// errors.Is(err, &ErrNotFound{}) is true for every error caused by a 404 response.
+type HarborError struct {
+	// Operation is the HTTP method and path template of the failed request, e.g. 'GET /projects/{project_name_or_id}'.
+	Operation string
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Errors are the errors contained in the response payload.
+	Errors []*model.Error
+	// RequestID is the value of the 'X-Request-Id' response header.
+	RequestID string
+	// Err is the error returned by the swagger client.
+	Err error
+}
+
+// Error returns the message of the swagger error, including the Harbor error details if available.
+func (e *HarborError) Error() string {
+	if details := e.Details(); details != "" {
+		return e.Err.Error() + ": " + details
+	}
+
+	return e.Err.Error()
+}
+
+// Unwrap returns the swagger error.
+func (e *HarborError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether 'target' is a generic error matching the status code of the response.
+func (e *HarborError) Is(target error) bool {
+	switch target.(type) {
+	case *ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case *ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case *ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case *ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case *ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case *ErrUnsupportedMediaType:
+		return e.StatusCode == http.StatusUnsupportedMediaType
+	case *ErrInternalErrors:
+		return e.StatusCode == http.StatusInternalServerError
+	default:
+		return false
+	}
+}
+
+// Details returns the Harbor error codes and messages of the response,
+// e.g. 'NOT_FOUND: project foo not found'.
+func (e *HarborError) Details() string {
+	details := make([]string, 0, len(e.Errors))
+
+	for _, he := range e.Errors {
+		if he == nil {
+			continue
+		}
+
+		switch {
+		case he.Code != "" && he.Message != "":
+			details = append(details, he.Code+": "+he.Message)
+		case he.Code != "":
+			details = append(details, he.Code)
+		case he.Message != "":
+			details = append(details, he.Message)
+		}
+	}
+
+	return strings.Join(details, "; ")
+}
+
+// AsHarborError returns the HarborError contained in the chain of 'err', if any.
+func AsHarborError(err error) (*HarborError, bool) {
+	var he *HarborError
+	if goerrors.As(err, &he) {
+		return he, true
+	}
+
+	return nil, false
+}
+
+// swaggerErrorRegex matches the messages of generated swagger errors,
+// e.g. '[GET /projects/{project_name_or_id}][401] getProjectUnauthorized'.
+var swaggerErrorRegex = regexp.MustCompile(`^\[([A-Z]+ [^\]]+)\]\[(\d{3})\]`)
+
+// operationResponse is implemented by client responses carrying the operation that produced them.
+type operationResponse interface {
+	Operation() string
+}
+
+// Response is embedded into typed errors, linking them to the HarborError describing
+// the response they originate from. It makes typed errors match errors.Is by their type,
+// e.g. errors.Is(err, &ErrProjectNotFound{}), and exposes the HarborError via errors.As.
+type Response[T any] struct {
+	harborError *HarborError
+}
+
+// Is reports whether 'target' is of the same type as the error embedding the Response.
+func (r *Response[T]) Is(target error) bool {
+	_, ok := any(target).(*T)
+	return ok
+}
+
+// Unwrap returns the HarborError describing the response the error originates from, if any.
+func (r *Response[T]) Unwrap() error {
+	if r.harborError == nil {
+		return nil
+	}
+
+	return r.harborError
+}
+
+func (r *Response[T]) setHarborError(he *HarborError) {
+	r.harborError = he
+}
+
+// WrapSwaggerError links 'mapped', the typed error a sub-client mapped the swagger error 'in' to,
+// to a HarborError carrying the details of the response 'in' originates from.
+// If 'in' was not mapped to a typed error, the HarborError itself is returned.
+// 'mapped' is returned as is if 'in' does not originate from an HTTP response.
+func WrapSwaggerError(in, mapped error) error {
+	if in == nil || mapped == nil {
+		return mapped
+	}
+
+	he := newHarborError(in)
+	if he == nil {
+		return mapped
+	}
+
+	if mapped == in {
+		return he
+	}
+
+	if typed, ok := mapped.(interface{ setHarborError(*HarborError) }); ok {
+		typed.setHarborError(he)
+	}
+
+	return mapped
+}
+
+// newHarborError returns a HarborError describing the response the swagger error 'in' originates from,
+// or nil if it does not originate from an HTTP response.
+func newHarborError(in error) *HarborError {
+	if he, ok := in.(*HarborError); ok {
+		return he
+	}
+
+	he := &HarborError{Err: in}
+
+	var apiErr *runtime.APIError
+
+	if goerrors.As(in, &apiErr) {
+		he.StatusCode = apiErr.Code
+
+		if resp, ok := apiErr.Response.(runtime.ClientResponse); ok {
+			he.RequestID = resp.GetHeader("X-Request-Id")
+			he.Errors = readPayload(resp)
+		}
+
+		if resp, ok := apiErr.Response.(operationResponse); ok {
+			he.Operation = resp.Operation()
+		}
+
+		return he
+	}
+
+	m := swaggerErrorRegex.FindStringSubmatch(in.Error())
+	if m == nil {
+		return nil
+	}
+
+	he.Operation = m[1]
+	he.StatusCode, _ = strconv.Atoi(m[2])
+
+	if p, ok := in.(interface{ GetPayload() *model.Errors }); ok && p.GetPayload() != nil {
+		he.Errors = p.GetPayload().Errors
+	}
+
+	// Generated swagger responses expose the request ID as a field, not via a method.
+	if v := reflect.Indirect(reflect.ValueOf(in)); v.Kind() == reflect.Struct {
+		if f := v.FieldByName("XRequestID"); f.IsValid() && f.Kind() == reflect.String {
+			he.RequestID = f.String()
+		}
+	}
+
+	return he
+}
+
+// readPayload decodes the Harbor error payload of 'resp', if its body can still be read.
+func readPayload(resp runtime.ClientResponse) []*model.Error {
+	body := resp.Body()
+	if body == nil {
+		return nil
+	}
+
+	b, err := io.ReadAll(body)
+	if err != nil || len(bytes.TrimSpace(b)) == 0 {
+		return nil
+	}
+
+	payload := &model.Errors{}
+	if err := json.Unmarshal(b, payload); err != nil {
+		return nil
+	}
+
+	return payload.Errors
+}
+
+// maxPayloadSize limits the size of error response bodies buffered by WrapTransport.
+const maxPayloadSize = 1 << 20
+
+// WrapTransport returns a swagger client transport buffering the bodies of error responses
+// that are not declared by the swagger specification, so that WrapSwaggerError can decode
+// their Harbor error payload after the response has been closed.
+func WrapTransport(next runtime.ClientTransport) runtime.ClientTransport {
+	return &transport{next: next}
+}
+
+type transport struct {
+	next runtime.ClientTransport
+}
+
+// Submit implements runtime.ClientTransport.
+func (t *transport) Submit(op *runtime.ClientOperation) (interface{}, error) {
+	wrapped := *op
+	wrapped.Reader = &bufferingReader{
+		next:      op.Reader,
+		operation: op.Method + " " + op.PathPattern,
+	}
+
+	return t.next.Submit(&wrapped)
+}
+
+type bufferingReader struct {
+	next      runtime.ClientResponseReader
+	operation string
+}
+
+// ReadResponse implements runtime.ClientResponseReader.
+func (r *bufferingReader) ReadResponse(resp runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	if resp.Code() < http.StatusBadRequest {
+		return r.next.ReadResponse(resp, consumer)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body(), maxPayloadSize))
+	if err != nil {
+		return nil, err
+	}
+
+	return r.next.ReadResponse(&bufferedResponse{resp: resp, body: b, operation: r.operation}, consumer)
+}
+
+// bufferedResponse is a runtime.ClientResponse whose body can be read multiple times.
+type bufferedResponse struct {
+	resp      runtime.ClientResponse
+	body      []byte
+	operation string
+}
+
+func (r *bufferedResponse) Code() int {
+	return r.resp.Code()
+}
+
+func (r *bufferedResponse) Message() string {
+	return r.resp.Message()
+}
+
+func (r *bufferedResponse) GetHeader(name string) string {
+	return r.resp.GetHeader(name)
+}
+
+func (r *bufferedResponse) GetHeaders(name string) []string {
+	return r.resp.GetHeaders(name)
+}
+
+func (r *bufferedResponse) Body() io.ReadCloser {
+	return io.NopCloser(bytes.NewReader(r.body))
+}
+
+func (r *bufferedResponse) Operation() string {
+	return r.operation
+}
@@ -0,0 +1,93 @@
+//go:build !integration
+
+package errors_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2"
+	projectapi "github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client/project"
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/testing/fakeharbor"
+)
+
+func TestWrapSwaggerError_TypedError(t *testing.T) {
+	in := &projectapi.CreateProjectConflict{
+		XRequestID: "abc",
+		Payload: &model.Errors{Errors: []*model.Error{{
+			Code:    "CONFLICT",
+			Message: "project foo already exists",
+		}}},
+	}
+
+	err := clienterrors.WrapSwaggerError(in, &clienterrors.ErrProjectNameAlreadyExists{})
+
+	// Typed errors keep their type and message.
+	require.IsType(t, &clienterrors.ErrProjectNameAlreadyExists{}, err)
+	require.EqualError(t, err, clienterrors.ErrProjectNameAlreadyExistsMsg)
+	require.ErrorIs(t, err, &clienterrors.ErrProjectNameAlreadyExists{})
+	require.NotErrorIs(t, err, &clienterrors.ErrProjectNotFound{})
+
+	require.ErrorIs(t, err, &clienterrors.ErrConflict{})
+	require.NotErrorIs(t, err, &clienterrors.ErrNotFound{})
+
+	he, ok := clienterrors.AsHarborError(err)
+	require.True(t, ok)
+	require.Equal(t, "POST /projects", he.Operation)
+	require.Equal(t, http.StatusConflict, he.StatusCode)
+	require.Equal(t, "abc", he.RequestID)
+	require.Equal(t, "CONFLICT: project foo already exists", he.Details())
+	require.Same(t, in, he.Err)
+}
+
+func TestWrapSwaggerError_Unmapped(t *testing.T) {
+	in := runtime.NewAPIError("unexpected", nil, http.StatusTooManyRequests)
+
+	err := clienterrors.WrapSwaggerError(in, in)
+
+	var he *clienterrors.HarborError
+	require.ErrorAs(t, err, &he)
+	require.Equal(t, http.StatusTooManyRequests, he.StatusCode)
+
+	var apiErr *runtime.APIError
+	require.ErrorAs(t, err, &apiErr)
+}
+
+func TestWrapSwaggerError_NonHTTPErrors(t *testing.T) {
+	require.NoError(t, clienterrors.WrapSwaggerError(nil, nil))
+	require.NoError(t, clienterrors.WrapSwaggerError(runtime.NewAPIError("created", nil, http.StatusCreated), nil))
+
+	err := clienterrors.WrapSwaggerError(context.DeadlineExceeded, context.DeadlineExceeded)
+	require.Equal(t, context.DeadlineExceeded, err)
+
+	_, ok := clienterrors.AsHarborError(&clienterrors.ErrProjectNotFound{})
+	require.False(t, ok)
+}
+
+func TestHarborError_FromServer(t *testing.T) {
+	srv := fakeharbor.NewServer()
+	defer srv.Close()
+
+	c, err := apiv2.NewRESTClientForHost(srv.Host(), fakeharbor.AdminUser, fakeharbor.AdminPassword, config.Defaults())
+	require.NoError(t, err)
+
+	_, err = c.GetProject(context.Background(), "unknown")
+	require.IsType(t, &clienterrors.ErrProjectNotFound{}, err)
+	require.True(t, errors.Is(err, &clienterrors.ErrNotFound{}))
+
+	he, ok := clienterrors.AsHarborError(err)
+	require.True(t, ok)
+	require.Equal(t, "GET /projects/{project_name_or_id}", he.Operation)
+	require.Equal(t, http.StatusNotFound, he.StatusCode)
+	require.NotEmpty(t, he.RequestID)
+	require.Len(t, he.Errors, 1)
+	require.Equal(t, "NOT_FOUND", he.Errors[0].Code)
+}
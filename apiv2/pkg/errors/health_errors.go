@@ -0,0 +1,24 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrUnhealthy describes an error when Harbor components are not healthy.
+type ErrUnhealthy struct {
+	// Components are the names of the unhealthy components, e.g. "redis".
+	// Components that Harbor does not report at all are considered unhealthy.
+	Components []string
+}
+
+// Error returns the error message.
+func (e *ErrUnhealthy) Error() string {
+	return fmt.Sprintf("unhealthy components: %s", strings.Join(e.Components, ", "))
+}
+
+// Is reports whether 'target' is an ErrUnhealthy, regardless of the components.
+func (e *ErrUnhealthy) Is(target error) bool {
+	_, ok := target.(*ErrUnhealthy)
+	return ok
+}
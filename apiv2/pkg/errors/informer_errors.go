@@ -0,0 +1,20 @@
+package errors
+
+import "fmt"
+
+// ErrInformerIndexNotFound describes an error when an informer is queried by an index it does not have.
+type ErrInformerIndexNotFound struct {
+	// Name is the name of the missing index.
+	Name string
+}
+
+// Error returns the error message.
+func (e *ErrInformerIndexNotFound) Error() string {
+	return fmt.Sprintf("informer index %q not found", e.Name)
+}
+
+// Is reports whether 'target' is an ErrInformerIndexNotFound, regardless of the index name.
+func (e *ErrInformerIndexNotFound) Is(target error) bool {
+	_, ok := target.(*ErrInformerIndexNotFound)
+	return ok
+}
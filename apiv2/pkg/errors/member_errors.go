@@ -1,9 +1,11 @@
 package errors
 
 type (
-	ErrNoMemberFound struct{}
+	ErrNoMemberFound struct{ Response[ErrNoMemberFound] }
 
-	ErrMemberAlreadyExists struct{}
+	ErrMemberAlreadyExists struct {
+		Response[ErrMemberAlreadyExists]
+	}
 )
 
 const (
@@ -40,43 +40,69 @@ const (
 
 type (
 	// ErrProjectNameNotProvided describes a missing project name.
-	ErrProjectNameNotProvided struct{}
+	ErrProjectNameNotProvided struct {
+		Response[ErrProjectNameNotProvided]
+	}
 	// ErrProjectIllegalIDFormat describes an illegal request format.
-	ErrProjectIllegalIDFormat struct{}
+	ErrProjectIllegalIDFormat struct {
+		Response[ErrProjectIllegalIDFormat]
+	}
 	// ErrProjectInternalErrors describes server-side internal errors.
-	ErrProjectInternalErrors struct{}
+	ErrProjectInternalErrors struct {
+		Response[ErrProjectInternalErrors]
+	}
 	// ErrProjectNoPermission describes a request error without permission.
-	ErrProjectNoPermission struct{}
+	ErrProjectNoPermission struct {
+		Response[ErrProjectNoPermission]
+	}
 	// ErrProjectIDNotExists describes an error
 	// when no proper project ID is found.
-	ErrProjectIDNotExists struct{}
+	ErrProjectIDNotExists struct {
+		Response[ErrProjectIDNotExists]
+	}
 	// ErrProjectNameAlreadyExists describes a duplicate project name error.
-	ErrProjectNameAlreadyExists struct{}
+	ErrProjectNameAlreadyExists struct {
+		Response[ErrProjectNameAlreadyExists]
+	}
 
 	// ErrProjectMismatch describes a failed lookup
 	// of a project with name/id pair.
-	ErrProjectMismatch struct{}
+	ErrProjectMismatch struct{ Response[ErrProjectMismatch] }
 	// ErrProjectNotFound describes an error
 	// when a specific project is not found.
-	ErrProjectNotFound struct{}
+	ErrProjectNotFound struct{ Response[ErrProjectNotFound] }
 
-	ErrProjectNotProvided struct{}
+	ErrProjectNotProvided struct {
+		Response[ErrProjectNotProvided]
+	}
 	// ErrProjectNoMemberProvided describes an error when a project's member is not provided.
-	ErrProjectNoMemberProvided struct{}
+	ErrProjectNoMemberProvided struct {
+		Response[ErrProjectNoMemberProvided]
+	}
 	// ErrProjectMemberMismatch describes an error
 	// when user does not exist in context of
 	// project member operations.
-	ErrProjectMemberMismatch struct{}
+	ErrProjectMemberMismatch struct {
+		Response[ErrProjectMemberMismatch]
+	}
 
 	// ErrProjectMemberIllegalFormat describes a communication error when performing project member operations.
-	ErrProjectMemberIllegalFormat struct{}
+	ErrProjectMemberIllegalFormat struct {
+		Response[ErrProjectMemberIllegalFormat]
+	}
 	// ErrProjectInvalidRequest describes a communication
 	// error when performing project member operations.
-	ErrProjectInvalidRequest struct{}
+	ErrProjectInvalidRequest struct {
+		Response[ErrProjectInvalidRequest]
+	}
 	// ErrProjectUnknownResource describes an error after requesting an unknown resource.
-	ErrProjectUnknownResource struct{}
+	ErrProjectUnknownResource struct {
+		Response[ErrProjectUnknownResource]
+	}
 	// ErrProjectNoWebhookPolicyProvided describes an error when no webhook policy is provided.
-	ErrProjectNoWebhookPolicyProvided struct{}
+	ErrProjectNoWebhookPolicyProvided struct {
+		Response[ErrProjectNoWebhookPolicyProvided]
+	}
 )
 
 // Error returns the error message.
@@ -16,25 +16,43 @@ const (
 
 type (
 	ErrProjectMetadataUndefined    struct{}
-	ErrProjectMetadataKeyUndefined struct{}
+	ErrProjectMetadataKeyUndefined struct {
+		Response[ErrProjectMetadataKeyUndefined]
+	}
 	// ErrProjectMetadataValueEnableContentTrustUndefined describes an error regarding a metadata value being undefined or nil.
-	ErrProjectMetadataValueEnableContentTrustUndefined struct{}
+	ErrProjectMetadataValueEnableContentTrustUndefined struct {
+		Response[ErrProjectMetadataValueEnableContentTrustUndefined]
+	}
 	// ErrProjectMetadataValueAutoScanUndefined describes an error regarding a metadata value being undefined or nil.
-	ErrProjectMetadataValueAutoScanUndefined struct{}
+	ErrProjectMetadataValueAutoScanUndefined struct {
+		Response[ErrProjectMetadataValueAutoScanUndefined]
+	}
 	// ErrProjectMetadataValueSeverityUndefined describes an error regarding a metadata value being undefined or nil.
-	ErrProjectMetadataValueSeverityUndefined struct{}
+	ErrProjectMetadataValueSeverityUndefined struct {
+		Response[ErrProjectMetadataValueSeverityUndefined]
+	}
 	// ErrProjectMetadataValueReuseSysCveAllowlistUndefined describes an error regarding a metadata value being undefined or nil.
-	ErrProjectMetadataValueReuseSysCveAllowlistUndefined struct{}
+	ErrProjectMetadataValueReuseSysCveAllowlistUndefined struct {
+		Response[ErrProjectMetadataValueReuseSysCveAllowlistUndefined]
+	}
 	// ErrProjectMetadataValuePublicUndefined describes an error regarding a metadata value being undefined or nil.
-	ErrProjectMetadataValuePublicUndefined struct{}
+	ErrProjectMetadataValuePublicUndefined struct {
+		Response[ErrProjectMetadataValuePublicUndefined]
+	}
 	// ErrProjectMetadataValuePreventVulUndefined describes an error regarding a metadata value being undefined or nil.
 	ErrProjectMetadataValuePreventVulUndefined  struct{}
-	ErrProjectMetadataValueRetentionIDUndefined struct{}
+	ErrProjectMetadataValueRetentionIDUndefined struct {
+		Response[ErrProjectMetadataValueRetentionIDUndefined]
+	}
 	// ErrProjectMetadataAlreadyExists describes an error, which happens
 	// when a metadata key of a project is tried to be created a second time.
-	ErrProjectMetadataAlreadyExists struct{}
+	ErrProjectMetadataAlreadyExists struct {
+		Response[ErrProjectMetadataAlreadyExists]
+	}
 
-	ErrProjectMetadataInvalidRequest struct{}
+	ErrProjectMetadataInvalidRequest struct {
+		Response[ErrProjectMetadataInvalidRequest]
+	}
 )
 
 // Error returns the error message.
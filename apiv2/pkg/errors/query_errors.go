@@ -0,0 +1,22 @@
+package errors
+
+import "fmt"
+
+// ErrInvalidQuery describes an error when a query cannot be expressed in Harbor's query syntax.
+type ErrInvalidQuery struct {
+	// Field is the field of the offending query term.
+	Field string
+	// Reason describes why the term is invalid.
+	Reason string
+}
+
+// Error returns the error message.
+func (e *ErrInvalidQuery) Error() string {
+	return fmt.Sprintf("invalid query for field %q: %s", e.Field, e.Reason)
+}
+
+// Is reports whether 'target' is an ErrInvalidQuery, regardless of its fields.
+func (e *ErrInvalidQuery) Is(target error) bool {
+	_, ok := target.(*ErrInvalidQuery)
+	return ok
+}
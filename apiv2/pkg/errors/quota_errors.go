@@ -20,7 +20,9 @@ const (
 )
 
 // ErrQuotaIllegalIDFormat describes an error due to an illegal request format.
-type ErrQuotaIllegalIDFormat struct{}
+type ErrQuotaIllegalIDFormat struct {
+	Response[ErrQuotaIllegalIDFormat]
+}
 
 // Error returns the error message.
 func (e *ErrQuotaIllegalIDFormat) Error() string {
@@ -28,7 +30,7 @@ func (e *ErrQuotaIllegalIDFormat) Error() string {
 }
 
 // ErrQuotaUnauthorized describes an unauthorized request.
-type ErrQuotaUnauthorized struct{}
+type ErrQuotaUnauthorized struct{ Response[ErrQuotaUnauthorized] }
 
 // Error returns the error message.
 func (e *ErrQuotaUnauthorized) Error() string {
@@ -36,7 +38,7 @@ func (e *ErrQuotaUnauthorized) Error() string {
 }
 
 // ErrQuotaNoPermission describes an error in the request due to the lack of permissions.
-type ErrQuotaNoPermission struct{}
+type ErrQuotaNoPermission struct{ Response[ErrQuotaNoPermission] }
 
 // Error returns the error message.
 func (e *ErrQuotaNoPermission) Error() string {
@@ -44,7 +46,9 @@ func (e *ErrQuotaNoPermission) Error() string {
 }
 
 // ErrQuotaUnknownResource describes an error when the specified quota could not be found.
-type ErrQuotaUnknownResource struct{}
+type ErrQuotaUnknownResource struct {
+	Response[ErrQuotaUnknownResource]
+}
 
 // Error returns the error message.
 func (e *ErrQuotaUnknownResource) Error() string {
@@ -52,7 +56,9 @@ func (e *ErrQuotaUnknownResource) Error() string {
 }
 
 // ErrQuotaInternalServerErrors describes miscellaneous internal server errors.
-type ErrQuotaInternalServerErrors struct{}
+type ErrQuotaInternalServerErrors struct {
+	Response[ErrQuotaInternalServerErrors]
+}
 
 // Error returns the error message.
 func (e *ErrQuotaInternalServerErrors) Error() string {
@@ -60,7 +66,7 @@ func (e *ErrQuotaInternalServerErrors) Error() string {
 }
 
 // ErrQuotaRefNotFound describes an error when the quota reference could not be found.
-type ErrQuotaRefNotFound struct{}
+type ErrQuotaRefNotFound struct{ Response[ErrQuotaRefNotFound] }
 
 func (e *ErrQuotaRefNotFound) Error() string {
 	return ErrQuotaRefNotFoundMsg
@@ -0,0 +1,22 @@
+package errors
+
+import "fmt"
+
+// ErrInvalidReference describes an error when a repository or artifact reference cannot be parsed or is invalid.
+type ErrInvalidReference struct {
+	// Reference is the invalid reference, e.g. 'library/App:v1'.
+	Reference string
+	// Reason describes why the reference is invalid.
+	Reason string
+}
+
+// Error returns the error message.
+func (e *ErrInvalidReference) Error() string {
+	return fmt.Sprintf("invalid reference %q: %s", e.Reference, e.Reason)
+}
+
+// Is reports whether 'target' is an ErrInvalidReference, regardless of its fields.
+func (e *ErrInvalidReference) Is(target error) bool {
+	_, ok := target.(*ErrInvalidReference)
+	return ok
+}
@@ -14,27 +14,41 @@ const (
 
 type (
 	// ErrRegistryIllegalIDFormat describes an illegal request format.
-	ErrRegistryIllegalIDFormat struct{}
+	ErrRegistryIllegalIDFormat struct {
+		Response[ErrRegistryIllegalIDFormat]
+	}
 	// ErrRegistryUnauthorized describes an unauthorized request.
-	ErrRegistryUnauthorized struct{}
+	ErrRegistryUnauthorized struct {
+		Response[ErrRegistryUnauthorized]
+	}
 	// ErrRegistryInternalErrors describes server-side internal errors.
-	ErrRegistryInternalErrors struct{}
+	ErrRegistryInternalErrors struct {
+		Response[ErrRegistryInternalErrors]
+	}
 	// ErrRegistryNoPermission describes a request error without permission.
-	ErrRegistryNoPermission struct{}
+	ErrRegistryNoPermission struct {
+		Response[ErrRegistryNoPermission]
+	}
 	// ErrRegistryIDNotExists describes an error
 	// when no proper registry ID is found.
-	ErrRegistryIDNotExists struct{}
+	ErrRegistryIDNotExists struct {
+		Response[ErrRegistryIDNotExists]
+	}
 	// ErrRegistryNameAlreadyExists describes a duplicate registry name error.
-	ErrRegistryNameAlreadyExists struct{}
+	ErrRegistryNameAlreadyExists struct {
+		Response[ErrRegistryNameAlreadyExists]
+	}
 	// ErrRegistryMismatch describes a failed lookup
 	// of a registry with name/id pair.
-	ErrRegistryMismatch struct{}
+	ErrRegistryMismatch struct{ Response[ErrRegistryMismatch] }
 	// ErrRegistryNotFound describes an error
 	// when a specific registry is not found.
-	ErrRegistryNotFound struct{}
+	ErrRegistryNotFound struct{ Response[ErrRegistryNotFound] }
 	// ErrRegistryNotProvided describes an error
 	// when no registry was provided.
-	ErrRegistryNotProvided struct{}
+	ErrRegistryNotProvided struct {
+		Response[ErrRegistryNotProvided]
+	}
 )
 
 // Error returns the error message.
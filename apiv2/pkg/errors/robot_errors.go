@@ -15,16 +15,26 @@ const (
 
 type (
 	// ErrRobotAccountInvalid describes an invalid robot account error.
-	ErrRobotAccountInvalid struct{}
+	ErrRobotAccountInvalid struct {
+		Response[ErrRobotAccountInvalid]
+	}
 	// ErrRobotAccountUnauthorized describes an unauthorized request to the 'robots' API.
-	ErrRobotAccountUnauthorized struct{}
+	ErrRobotAccountUnauthorized struct {
+		Response[ErrRobotAccountUnauthorized]
+	}
 	// ErrRobotAccountNoPermission describes a request error without permission.
-	ErrRobotAccountNoPermission struct{}
+	ErrRobotAccountNoPermission struct {
+		Response[ErrRobotAccountNoPermission]
+	}
 	// ErrRobotAccountUnknownResource describes an error when
 	// the specified robot account could not be found.
-	ErrRobotAccountUnknownResource struct{}
+	ErrRobotAccountUnknownResource struct {
+		Response[ErrRobotAccountUnknownResource]
+	}
 	// ErrRobotAccountInternalErrors describes server-sided internal errors.
-	ErrRobotAccountInternalErrors struct{}
+	ErrRobotAccountInternalErrors struct {
+		Response[ErrRobotAccountInternalErrors]
+	}
 )
 
 // Error returns the error message.
@@ -0,0 +1,27 @@
+package errors
+
+const (
+	// ErrCircuitOpenMsg is the error message for ErrCircuitOpen error.
+	ErrCircuitOpenMsg = "circuit breaker is open: the Harbor API is considered unavailable"
+
+	// ErrRetryUnsupportedMsg is the error message for ErrRetryUnsupported error.
+	ErrRetryUnsupportedMsg = "retry policies and circuit breakers can only be applied to clients constructed by New or NewRESTClientForHost"
+)
+
+// ErrCircuitOpen describes an error when a request was rejected without being sent,
+// because the circuit breaker observed too many consecutive failures.
+type ErrCircuitOpen struct{ Response[ErrCircuitOpen] }
+
+// Error returns the error message.
+func (e *ErrCircuitOpen) Error() string {
+	return ErrCircuitOpenMsg
+}
+
+// ErrRetryUnsupported describes an error when a client constructed from an existing swagger client
+// was configured with a retry policy or circuit breaker, which cannot be applied to its transport.
+type ErrRetryUnsupported struct{ Response[ErrRetryUnsupported] }
+
+// Error returns the error message.
+func (e *ErrRetryUnsupported) Error() string {
+	return ErrRetryUnsupportedMsg
+}
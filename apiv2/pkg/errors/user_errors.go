@@ -21,23 +21,33 @@ const (
 
 	// ErrUserPasswordInvalidMsg  is the error message for ErrUserPasswordInvalid error.
 	ErrUserPasswordInvalidMsg = "invalid user password"
+
+	// ErrUserCLISecretUnsupportedMsg is the error message for ErrUserCLISecretUnsupported error.
+	ErrUserCLISecretUnsupportedMsg = "the CLI secret can only be set for users onboarded via OIDC authentication"
 )
 
 type (
 	// ErrUserNotFound describes an error when a specific user was not found on server side.
-	ErrUserNotFound struct{}
+	ErrUserNotFound struct{ Response[ErrUserNotFound] }
 	// ErrUserBadRequest describes a formal error when creating or updating a user (such as bad password).
-	ErrUserBadRequest struct{}
+	ErrUserBadRequest struct{ Response[ErrUserBadRequest] }
 	// ErrUserMismatch describes an error when the id and name of a user do not match on server side.
-	ErrUserMismatch struct{}
+	ErrUserMismatch struct{ Response[ErrUserMismatch] }
 	// ErrUserAlreadyExists describes an error indicating that this user already exists.
-	ErrUserAlreadyExists struct{}
+	ErrUserAlreadyExists struct{ Response[ErrUserAlreadyExists] }
 	// ErrUserInvalidID describes an error indicating an invalid user id.
-	ErrUserInvalidID struct{}
+	ErrUserInvalidID struct{ Response[ErrUserInvalidID] }
 	// ErrUserIDNotExists describes an error indicating a nonexistent user id.
-	ErrUserIDNotExists struct{}
+	ErrUserIDNotExists struct{ Response[ErrUserIDNotExists] }
 	// ErrUserPasswordInvalid describes an error indicating an invalid password.
-	ErrUserPasswordInvalid struct{}
+	ErrUserPasswordInvalid struct {
+		Response[ErrUserPasswordInvalid]
+	}
+	// ErrUserCLISecretUnsupported describes an error indicating that Harbor does not use
+	// OIDC authentication or the user was not onboarded via OIDC.
+	ErrUserCLISecretUnsupported struct {
+		Response[ErrUserCLISecretUnsupported]
+	}
 )
 
 func (e *ErrUserNotFound) Error() string {
@@ -67,3 +77,7 @@ func (e *ErrUserIDNotExists) Error() string {
 func (e *ErrUserPasswordInvalid) Error() string {
 	return ErrUserPasswordInvalidMsg
 }
+
+func (e *ErrUserCLISecretUnsupported) Error() string {
+	return ErrUserCLISecretUnsupportedMsg
+}
@@ -0,0 +1,26 @@
+package errors
+
+import "fmt"
+
+// ErrUnsupportedServerVersion describes an error when an operation was rejected without being sent,
+// because the Harbor version of the server does not provide the feature it depends on.
+type ErrUnsupportedServerVersion struct {
+	// Feature is the name of the feature the operation depends on, e.g. 'robot_v1'.
+	Feature string
+	// ServerVersion is the Harbor version reported by the server, e.g. 'v2.1.4'.
+	ServerVersion string
+	// Supported describes the Harbor versions providing the feature, e.g. '>= v2.2.0'.
+	Supported string
+}
+
+// Error returns the error message.
+func (e *ErrUnsupportedServerVersion) Error() string {
+	return fmt.Sprintf("unsupported server version: %s requires Harbor %s, server runs %s",
+		e.Feature, e.Supported, e.ServerVersion)
+}
+
+// Is reports whether 'target' is an ErrUnsupportedServerVersion, regardless of its fields.
+func (e *ErrUnsupportedServerVersion) Is(target error) bool {
+	_, ok := target.(*ErrUnsupportedServerVersion)
+	return ok
+}
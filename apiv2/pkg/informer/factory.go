@@ -0,0 +1,140 @@
+package informer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+// Factory shares the informers of projects, repositories and artifacts, so that each kind is listed only once,
+// regardless of the number of components using it. It is safe for concurrent use.
+type Factory struct {
+	lister Lister
+	resync time.Duration
+	opts   []Option
+
+	mu           sync.Mutex
+	ctx          context.Context
+	projects     *Informer[*model.Project]
+	repositories *Informer[*model.Repository]
+	artifacts    *Informer[*Artifact]
+	// started holds the informers run by Start.
+	started map[any]bool
+}
+
+// NewFactory returns a Factory creating informers listing resources using 'l' every 'resync' interval.
+func NewFactory(l Lister, resync time.Duration, opts ...Option) *Factory {
+	return &Factory{
+		lister:  l,
+		resync:  resync,
+		opts:    opts,
+		started: map[any]bool{},
+	}
+}
+
+// Projects returns the shared project informer, creating it on the first call.
+func (f *Factory) Projects() *Informer[*model.Project] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.projects == nil {
+		f.projects = NewProjectInformer(f.lister, f.resync, f.opts...)
+		f.startLocked(f.projects, f.projects.Run)
+	}
+
+	return f.projects
+}
+
+// Repositories returns the shared repository informer, creating it on the first call.
+func (f *Factory) Repositories() *Informer[*model.Repository] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.repositoriesLocked()
+}
+
+func (f *Factory) repositoriesLocked() *Informer[*model.Repository] {
+	if f.repositories == nil {
+		f.repositories = NewRepositoryInformer(f.lister, f.resync, f.opts...)
+		f.startLocked(f.repositories, f.repositories.Run)
+	}
+
+	return f.repositories
+}
+
+// Artifacts returns the shared artifact informer, creating it and the repository informer it depends on
+// on the first call.
+func (f *Factory) Artifacts() *Informer[*Artifact] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.artifacts == nil {
+		f.artifacts = NewArtifactInformer(f.lister, f.repositoriesLocked(), f.resync, f.opts...)
+		f.startLocked(f.artifacts, f.artifacts.Run)
+	}
+
+	return f.artifacts
+}
+
+// Start runs all informers created so far until 'ctx' is done. Informers created afterwards are run immediately.
+// Calling Start again has no effect on informers already running.
+func (f *Factory) Start(ctx context.Context) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.ctx = ctx
+
+	if f.projects != nil {
+		f.startLocked(f.projects, f.projects.Run)
+	}
+
+	if f.repositories != nil {
+		f.startLocked(f.repositories, f.repositories.Run)
+	}
+
+	if f.artifacts != nil {
+		f.startLocked(f.artifacts, f.artifacts.Run)
+	}
+}
+
+// startLocked runs 'informer' in a new goroutine if Start was called and it is not running yet.
+// The caller must hold f.mu.
+func (f *Factory) startLocked(informer any, run func(context.Context)) {
+	if f.ctx == nil || f.started[informer] {
+		return
+	}
+
+	f.started[informer] = true
+
+	go run(f.ctx)
+}
+
+// WaitForCacheSync blocks until all informers created so far have synced,
+// returning false if 'ctx' is done before.
+func (f *Factory) WaitForCacheSync(ctx context.Context) bool {
+	f.mu.Lock()
+	synced := []func(context.Context) bool{}
+
+	if f.projects != nil {
+		synced = append(synced, f.projects.WaitForCacheSync)
+	}
+
+	if f.repositories != nil {
+		synced = append(synced, f.repositories.WaitForCacheSync)
+	}
+
+	if f.artifacts != nil {
+		synced = append(synced, f.artifacts.WaitForCacheSync)
+	}
+	f.mu.Unlock()
+
+	for _, wait := range synced {
+		if !wait(ctx) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,106 @@
+//go:build !integration
+
+package informer_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2"
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/label"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/informer"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/testing/fakeharbor"
+)
+
+func TestFactory(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := fakeharbor.NewServer()
+	t.Cleanup(srv.Close)
+
+	c, err := apiv2.NewRESTClientForHost(srv.Host(), fakeharbor.AdminUser, fakeharbor.AdminPassword, config.Defaults())
+	require.NoError(t, err)
+
+	require.NoError(t, c.NewProject(ctx, &model.ProjectReq{ProjectName: "team-a"}))
+	require.NoError(t, c.NewProject(ctx, &model.ProjectReq{ProjectName: "team-b"}))
+
+	teamA, err := c.GetProject(ctx, "team-a")
+	require.NoError(t, err)
+
+	_, err = srv.PushArtifact("team-a", "app", "v1")
+	require.NoError(t, err)
+	_, err = srv.PushArtifact("team-a", "tools/cli", "latest")
+	require.NoError(t, err)
+	_, err = srv.PushArtifact("team-b", "app", "v2")
+	require.NoError(t, err)
+
+	f := informer.NewFactory(c, 20*time.Millisecond)
+	require.Same(t, f.Repositories(), f.Repositories())
+
+	artifacts := f.Artifacts()
+
+	var (
+		mu    sync.Mutex
+		added []string
+	)
+
+	artifacts.AddEventHandler(informer.EventHandlerFuncs[*informer.Artifact]{
+		AddFunc: func(a *informer.Artifact) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			added = append(added, a.RepositoryName)
+		},
+	})
+
+	f.Start(ctx)
+	require.True(t, f.WaitForCacheSync(ctx))
+
+	projects := f.Projects()
+	require.True(t, projects.WaitForCacheSync(ctx))
+
+	p, ok := projects.Get("team-b")
+	require.True(t, ok)
+	require.Equal(t, "team-b", p.Name)
+
+	repos, err := f.Repositories().ByIndex(informer.IndexProjectID, strconv.FormatInt(int64(teamA.ProjectID), 10))
+	require.NoError(t, err)
+	require.Len(t, repos, 2)
+	require.Equal(t, "team-a/app", repos[0].Name)
+	require.Equal(t, "team-a/tools/cli", repos[1].Name)
+
+	require.Len(t, artifacts.List(), 3)
+	mu.Lock()
+	require.ElementsMatch(t, []string{"team-a/app", "team-a/tools/cli", "team-b/app"}, added)
+	mu.Unlock()
+
+	tagged, err := artifacts.ByIndex(informer.IndexTag, "latest")
+	require.NoError(t, err)
+	require.Len(t, tagged, 1)
+	require.Equal(t, "team-a/tools/cli", tagged[0].RepositoryName)
+
+	require.NoError(t, c.CreateLabel(ctx, &model.Label{Name: "approved", Scope: "g"}))
+	labels, err := c.ListLabels(ctx, "approved", nil, label.ScopeGlobal)
+	require.NoError(t, err)
+	require.NoError(t, c.AddArtifactLabel(ctx, "team-b", "app", "v2", labels[0]))
+
+	require.Eventually(t, func() bool {
+		approved, err := artifacts.ByIndex(informer.IndexLabel, "approved")
+		return err == nil && len(approved) == 1 && approved[0].RepositoryName == "team-b/app"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, c.DeleteRepository(ctx, "team-a", "app"))
+
+	require.Eventually(t, func() bool {
+		_, ok := f.Repositories().Get("team-a/app")
+		return !ok && len(artifacts.List()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+}
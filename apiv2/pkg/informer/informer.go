@@ -0,0 +1,363 @@
+// Package informer caches Harbor resources in memory, keeping the cache up to date by listing the resources
+// periodically, similar to the informers of Kubernetes' client-go.
+//
+// An Informer detects added, updated and deleted resources by comparing the results of consecutive listings,
+// notifies registered EventHandlers and serves lookups by key and by index without calling the Harbor API.
+// A Factory shares the informers of projects, repositories and artifacts between the components of a program:
+//
+//	f := informer.NewFactory(client, 5*time.Minute)
+//	repositories := f.Repositories()
+//	repositories.AddEventHandler(informer.EventHandlerFuncs[*model.Repository]{
+//		AddFunc: func(r *model.Repository) { log.Printf("new repository %s", r.Name) },
+//	})
+//
+//	f.Start(ctx)
+//	if !f.WaitForCacheSync(ctx) {
+//		return ctx.Err()
+//	}
+//
+//	repos, err := repositories.ByIndex(informer.IndexProjectID, "1")
+package informer
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+// DefaultResync is the resync interval used if none is given.
+const DefaultResync = 5 * time.Minute
+
+// ListFunc lists all resources of an Informer.
+type ListFunc[T any] func(ctx context.Context) ([]T, error)
+
+// KeyFunc returns the key uniquely identifying a resource, e.g. its name.
+type KeyFunc[T any] func(T) string
+
+// VersionFunc returns the version of a resource, e.g. its update time.
+// A resource is considered updated if its version differs from the cached one.
+type VersionFunc[T any] func(T) string
+
+// IndexFunc returns the values a resource is indexed by, e.g. the ID of its project.
+type IndexFunc[T any] func(T) []string
+
+// EventHandler is notified about the changes detected by an Informer.
+// The handlers are called sequentially and should return quickly.
+type EventHandler[T any] interface {
+	OnAdd(obj T)
+	OnUpdate(oldObj, newObj T)
+	OnDelete(obj T)
+}
+
+// EventHandlerFuncs implements EventHandler using functions, which may be nil.
+type EventHandlerFuncs[T any] struct {
+	AddFunc    func(obj T)
+	UpdateFunc func(oldObj, newObj T)
+	DeleteFunc func(obj T)
+}
+
+// OnAdd implements EventHandler.
+func (f EventHandlerFuncs[T]) OnAdd(obj T) {
+	if f.AddFunc != nil {
+		f.AddFunc(obj)
+	}
+}
+
+// OnUpdate implements EventHandler.
+func (f EventHandlerFuncs[T]) OnUpdate(oldObj, newObj T) {
+	if f.UpdateFunc != nil {
+		f.UpdateFunc(oldObj, newObj)
+	}
+}
+
+// OnDelete implements EventHandler.
+func (f EventHandlerFuncs[T]) OnDelete(obj T) {
+	if f.DeleteFunc != nil {
+		f.DeleteFunc(obj)
+	}
+}
+
+// Option configures an Informer.
+type Option func(*options)
+
+type options struct {
+	onError func(error)
+}
+
+// WithErrorHandler calls 'fn' with the errors of failed listings while an Informer runs.
+// The cache keeps its contents and the listing is retried at the next resync. Errors are discarded by default.
+func WithErrorHandler(fn func(error)) Option {
+	return func(o *options) {
+		o.onError = fn
+	}
+}
+
+// Informer caches the resources of type T. It is safe for concurrent use.
+// The cached resources are shared and must not be modified.
+type Informer[T any] struct {
+	list    ListFunc[T]
+	key     KeyFunc[T]
+	version VersionFunc[T]
+	resync  time.Duration
+	options options
+
+	mu       sync.RWMutex
+	items    map[string]T
+	indexers map[string]IndexFunc[T]
+	// indices maps the index name to the indexed values and the keys of the resources having them.
+	indices  map[string]map[string]map[string]struct{}
+	handlers []EventHandler[T]
+	synced   chan struct{}
+
+	// dispatch serializes the notification of handlers, so that they observe the changes in order.
+	dispatch sync.Mutex
+}
+
+// New returns an Informer listing the resources using 'list' every 'resync' interval, or DefaultResync if not positive.
+func New[T any](list ListFunc[T], key KeyFunc[T], version VersionFunc[T], resync time.Duration, opts ...Option) *Informer[T] {
+	if resync <= 0 {
+		resync = DefaultResync
+	}
+
+	i := &Informer[T]{
+		list:     list,
+		key:      key,
+		version:  version,
+		resync:   resync,
+		items:    map[string]T{},
+		indexers: map[string]IndexFunc[T]{},
+		indices:  map[string]map[string]map[string]struct{}{},
+		synced:   make(chan struct{}),
+		options:  options{onError: func(error) {}},
+	}
+
+	for _, opt := range opts {
+		opt(&i.options)
+	}
+
+	return i
+}
+
+// AddIndexer adds the index 'name', indexing the resources by the values returned by 'fn'.
+// Cached resources are indexed immediately. An existing index of the same name is replaced.
+func (i *Informer[T]) AddIndexer(name string, fn IndexFunc[T]) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.indexers[name] = fn
+	i.indices[name] = map[string]map[string]struct{}{}
+
+	for k, obj := range i.items {
+		i.index(name, k, obj)
+	}
+}
+
+// AddEventHandler registers 'h'. It is notified about the resources already cached as additions.
+func (i *Informer[T]) AddEventHandler(h EventHandler[T]) {
+	i.dispatch.Lock()
+	defer i.dispatch.Unlock()
+
+	i.mu.Lock()
+	i.handlers = append(i.handlers, h)
+	existing := i.sorted(i.items)
+	i.mu.Unlock()
+
+	for _, obj := range existing {
+		h.OnAdd(obj)
+	}
+}
+
+// Get returns the cached resource identified by 'key'.
+func (i *Informer[T]) Get(key string) (T, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	obj, ok := i.items[key]
+
+	return obj, ok
+}
+
+// List returns all cached resources, ordered by their keys.
+func (i *Informer[T]) List() []T {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return i.sorted(i.items)
+}
+
+// ByIndex returns the cached resources indexed by 'value' in the index 'name', ordered by their keys.
+func (i *Informer[T]) ByIndex(name, value string) ([]T, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	index, ok := i.indices[name]
+	if !ok {
+		return nil, &errors.ErrInformerIndexNotFound{Name: name}
+	}
+
+	objs := make(map[string]T, len(index[value]))
+	for k := range index[value] {
+		objs[k] = i.items[k]
+	}
+
+	return i.sorted(objs), nil
+}
+
+// HasSynced reports whether the resources were listed successfully at least once.
+func (i *Informer[T]) HasSynced() bool {
+	select {
+	case <-i.synced:
+		return true
+	default:
+		return false
+	}
+}
+
+// WaitForCacheSync blocks until the resources were listed successfully at least once,
+// returning false if 'ctx' is done before.
+func (i *Informer[T]) WaitForCacheSync(ctx context.Context) bool {
+	select {
+	case <-i.synced:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Run lists the resources immediately and then every resync interval, until 'ctx' is done.
+func (i *Informer[T]) Run(ctx context.Context) {
+	ticker := time.NewTicker(i.resync)
+	defer ticker.Stop()
+
+	for {
+		if err := i.Resync(ctx); err != nil && ctx.Err() == nil {
+			i.options.onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Resync lists the resources, replaces the cached resources and notifies the handlers about the changes.
+// It is called by Run, but may be called directly to refresh the cache, e.g. after modifying a resource.
+func (i *Informer[T]) Resync(ctx context.Context) error {
+	objs, err := i.list(ctx)
+	if err != nil {
+		return err
+	}
+
+	i.dispatch.Lock()
+	defer i.dispatch.Unlock()
+
+	i.mu.Lock()
+
+	next := make(map[string]T, len(objs))
+	for _, obj := range objs {
+		next[i.key(obj)] = obj
+	}
+
+	var added, deleted []string
+
+	updated := map[string]T{}
+
+	for k, obj := range next {
+		old, ok := i.items[k]
+
+		switch {
+		case !ok:
+			added = append(added, k)
+		case i.version(old) != i.version(obj):
+			updated[k] = old
+		}
+	}
+
+	old := i.items
+
+	for k := range old {
+		if _, ok := next[k]; !ok {
+			deleted = append(deleted, k)
+		}
+	}
+
+	i.items = next
+
+	for name := range i.indexers {
+		i.indices[name] = map[string]map[string]struct{}{}
+
+		for k, obj := range next {
+			i.index(name, k, obj)
+		}
+	}
+
+	handlers := append([]EventHandler[T](nil), i.handlers...)
+
+	i.mu.Unlock()
+
+	sort.Strings(added)
+	sort.Strings(deleted)
+
+	for _, h := range handlers {
+		for _, k := range added {
+			h.OnAdd(next[k])
+		}
+
+		for _, k := range sortedKeys(updated) {
+			h.OnUpdate(updated[k], next[k])
+		}
+
+		for _, k := range deleted {
+			h.OnDelete(old[k])
+		}
+	}
+
+	// The cache is synced once the handlers were notified about the initial resources.
+	select {
+	case <-i.synced:
+	default:
+		close(i.synced)
+	}
+
+	return nil
+}
+
+// index adds the resource 'obj' identified by 'key' to the index 'name'. The caller must hold i.mu.
+func (i *Informer[T]) index(name, key string, obj T) {
+	index := i.indices[name]
+
+	for _, v := range i.indexers[name](obj) {
+		if index[v] == nil {
+			index[v] = map[string]struct{}{}
+		}
+
+		index[v][key] = struct{}{}
+	}
+}
+
+// sorted returns the values of 'objs' ordered by their keys.
+func (i *Informer[T]) sorted(objs map[string]T) []T {
+	res := make([]T, 0, len(objs))
+
+	for _, k := range sortedKeys(objs) {
+		res = append(res, objs[k])
+	}
+
+	return res
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
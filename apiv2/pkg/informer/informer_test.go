@@ -0,0 +1,186 @@
+//go:build !integration
+
+package informer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+var ctx = context.Background()
+
+type item struct {
+	name    string
+	version string
+	group   string
+}
+
+// fakeList returns the items set by set, or an error if set to one.
+type fakeList struct {
+	mu    sync.Mutex
+	items []item
+	err   error
+}
+
+func (l *fakeList) set(items ...item) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.items, l.err = items, nil
+}
+
+func (l *fakeList) fail(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.err = err
+}
+
+func (l *fakeList) list(context.Context) ([]item, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]item(nil), l.items...), l.err
+}
+
+// recorder records the events it is notified about.
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) OnAdd(obj item) {
+	r.events = append(r.events, "add "+obj.name)
+}
+
+func (r *recorder) OnUpdate(oldObj, newObj item) {
+	r.events = append(r.events, "update "+oldObj.name+" "+newObj.version)
+}
+
+func (r *recorder) OnDelete(obj item) {
+	r.events = append(r.events, "delete "+obj.name)
+}
+
+func (r *recorder) take() []string {
+	events := r.events
+	r.events = nil
+
+	return events
+}
+
+func newTestInformer(l *fakeList) *Informer[item] {
+	i := New(l.list,
+		func(it item) string { return it.name },
+		func(it item) string { return it.version },
+		time.Hour)
+
+	i.AddIndexer("group", func(it item) []string { return []string{it.group} })
+
+	return i
+}
+
+func TestInformer_Resync(t *testing.T) {
+	l := &fakeList{}
+	i := newTestInformer(l)
+	r := &recorder{}
+
+	i.AddEventHandler(r)
+	require.False(t, i.HasSynced())
+
+	l.set(item{"b", "1", "x"}, item{"a", "1", "x"}, item{"c", "1", "y"})
+	require.NoError(t, i.Resync(ctx))
+	require.True(t, i.HasSynced())
+	require.Equal(t, []string{"add a", "add b", "add c"}, r.take())
+
+	l.set(item{"a", "2", "y"}, item{"b", "1", "x"}, item{"d", "1", "x"})
+	require.NoError(t, i.Resync(ctx))
+	require.Equal(t, []string{"add d", "update a 2", "delete c"}, r.take())
+
+	require.NoError(t, i.Resync(ctx))
+	require.Empty(t, r.take(), "unchanged resources must not be reported")
+
+	a, ok := i.Get("a")
+	require.True(t, ok)
+	require.Equal(t, "2", a.version)
+
+	_, ok = i.Get("c")
+	require.False(t, ok)
+
+	require.Equal(t, []item{{"a", "2", "y"}, {"b", "1", "x"}, {"d", "1", "x"}}, i.List())
+
+	x, err := i.ByIndex("group", "x")
+	require.NoError(t, err)
+	require.Equal(t, []item{{"b", "1", "x"}, {"d", "1", "x"}}, x)
+
+	y, err := i.ByIndex("group", "y")
+	require.NoError(t, err)
+	require.Equal(t, []item{{"a", "2", "y"}}, y)
+
+	_, err = i.ByIndex("unknown", "x")
+	require.ErrorIs(t, err, &clienterrors.ErrInformerIndexNotFound{})
+
+	late := &recorder{}
+	i.AddEventHandler(late)
+	require.Equal(t, []string{"add a", "add b", "add d"}, late.take(), "cached resources are replayed")
+}
+
+func TestInformer_ResyncError(t *testing.T) {
+	l := &fakeList{}
+	i := newTestInformer(l)
+
+	l.set(item{"a", "1", "x"})
+	require.NoError(t, i.Resync(ctx))
+
+	l.fail(errors.New("unavailable"))
+	require.Error(t, i.Resync(ctx))
+	require.Len(t, i.List(), 1, "the cache is kept if listing fails")
+}
+
+func TestInformer_Run(t *testing.T) {
+	l := &fakeList{}
+	l.set(item{"a", "1", "x"})
+
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+
+	i := New(l.list,
+		func(it item) string { return it.name },
+		func(it item) string { return it.version },
+		10*time.Millisecond,
+		WithErrorHandler(func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			errs = append(errs, err)
+		}))
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go i.Run(runCtx)
+
+	require.True(t, i.WaitForCacheSync(runCtx))
+
+	l.set(item{"a", "1", "x"}, item{"b", "1", "x"})
+	require.Eventually(t, func() bool { return len(i.List()) == 2 }, time.Second, 5*time.Millisecond)
+
+	l.fail(errors.New("unavailable"))
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(errs) > 0
+	}, time.Second, 5*time.Millisecond)
+
+	cancelled, cancelNow := context.WithCancel(ctx)
+	cancelNow()
+	require.False(t, New(l.list, nil, nil, 0).WaitForCacheSync(cancelled))
+}
@@ -0,0 +1,168 @@
+package informer
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+)
+
+// Names of the indices added by NewProjectInformer, NewRepositoryInformer and NewArtifactInformer.
+const (
+	// IndexProjectID indexes projects, repositories and artifacts by the ID of their project.
+	IndexProjectID = "project_id"
+	// IndexRepository indexes artifacts by the full name of their repository, e.g. "library/app".
+	IndexRepository = "repository"
+	// IndexLabel indexes artifacts by the names of their labels.
+	IndexLabel = "label"
+	// IndexTag indexes artifacts by the names of their tags.
+	IndexTag = "tag"
+)
+
+// Lister lists the resources cached by the informers of this package. It is implemented by apiv2.RESTClient.
+type Lister interface {
+	ListProjects(ctx context.Context, nameFilter string, opts ...config.CallOption) ([]*model.Project, error)
+	ListAllRepositories(ctx context.Context, opts ...config.CallOption) ([]*model.Repository, error)
+	ListArtifacts(ctx context.Context, projectName, repositoryName string, opts ...config.CallOption) ([]*model.Artifact, error)
+}
+
+// listPageSize is the page size used for listing, the maximum allowed by Harbor to reduce the number of requests.
+const listPageSize = 100
+
+// Artifact is an artifact cached by an artifact informer, along with the name of its repository.
+type Artifact struct {
+	*model.Artifact
+
+	// RepositoryName is the full name of the repository, including the project, e.g. "library/app".
+	RepositoryName string
+}
+
+// Key returns the key of the artifact in the informer, e.g. "library/app@sha256:...".
+func (a *Artifact) Key() string {
+	return a.RepositoryName + "@" + a.Digest
+}
+
+// NewProjectInformer returns an Informer caching all projects, keyed by their name.
+// Projects are considered updated if their update time changes.
+func NewProjectInformer(l Lister, resync time.Duration, opts ...Option) *Informer[*model.Project] {
+	i := New(
+		func(ctx context.Context) ([]*model.Project, error) {
+			return l.ListProjects(ctx, "", config.WithPage(1), config.WithPageSize(listPageSize))
+		},
+		func(p *model.Project) string { return p.Name },
+		func(p *model.Project) string { return p.UpdateTime.String() },
+		resync, opts...)
+
+	i.AddIndexer(IndexProjectID, func(p *model.Project) []string {
+		return []string{strconv.FormatInt(int64(p.ProjectID), 10)}
+	})
+
+	return i
+}
+
+// NewRepositoryInformer returns an Informer caching all repositories, keyed by their full name.
+// Repositories are considered updated if their update time or artifact count changes.
+func NewRepositoryInformer(l Lister, resync time.Duration, opts ...Option) *Informer[*model.Repository] {
+	i := New(
+		func(ctx context.Context) ([]*model.Repository, error) {
+			return l.ListAllRepositories(ctx, config.WithPage(1), config.WithPageSize(listPageSize))
+		},
+		func(r *model.Repository) string { return r.Name },
+		func(r *model.Repository) string {
+			return r.UpdateTime.String() + "/" + strconv.FormatInt(r.ArtifactCount, 10)
+		},
+		resync, opts...)
+
+	i.AddIndexer(IndexProjectID, func(r *model.Repository) []string {
+		return []string{strconv.FormatInt(r.ProjectID, 10)}
+	})
+
+	return i
+}
+
+// NewArtifactInformer returns an Informer caching the artifacts of all repositories cached by 'repositories',
+// keyed by Artifact.Key. Artifacts are considered updated if their tags or labels change.
+// Each resync lists the artifacts of every repository, so the resync interval should be chosen accordingly.
+// The repository informer must be run, too.
+func NewArtifactInformer(l Lister, repositories *Informer[*model.Repository], resync time.Duration, opts ...Option) *Informer[*Artifact] {
+	i := New(
+		func(ctx context.Context) ([]*Artifact, error) {
+			if !repositories.WaitForCacheSync(ctx) {
+				return nil, ctx.Err()
+			}
+
+			var artifacts []*Artifact
+
+			for _, r := range repositories.List() {
+				projectName, repositoryName, _ := strings.Cut(r.Name, "/")
+
+				res, err := l.ListArtifacts(ctx, projectName, repositoryName, config.WithPage(1), config.WithPageSize(listPageSize))
+				if err != nil {
+					return nil, err
+				}
+
+				for _, a := range res {
+					artifacts = append(artifacts, &Artifact{Artifact: a, RepositoryName: r.Name})
+				}
+			}
+
+			return artifacts, nil
+		},
+		(*Artifact).Key,
+		artifactVersion,
+		resync, opts...)
+
+	i.AddIndexer(IndexProjectID, func(a *Artifact) []string {
+		return []string{strconv.FormatInt(a.ProjectID, 10)}
+	})
+	i.AddIndexer(IndexRepository, func(a *Artifact) []string {
+		return []string{a.RepositoryName}
+	})
+	i.AddIndexer(IndexLabel, func(a *Artifact) []string {
+		var names []string
+
+		for _, l := range a.Labels {
+			if l != nil {
+				names = append(names, l.Name)
+			}
+		}
+
+		return names
+	})
+	i.AddIndexer(IndexTag, func(a *Artifact) []string {
+		return tagNames(a)
+	})
+
+	return i
+}
+
+// artifactVersion returns the sorted tag names and label IDs of 'a'.
+func artifactVersion(a *Artifact) string {
+	parts := tagNames(a)
+
+	for _, l := range a.Labels {
+		if l != nil {
+			parts = append(parts, "label:"+strconv.FormatInt(l.ID, 10))
+		}
+	}
+
+	sort.Strings(parts)
+
+	return strings.Join(parts, ",")
+}
+
+func tagNames(a *Artifact) []string {
+	var names []string
+
+	for _, t := range a.Tags {
+		if t != nil {
+			names = append(names, t.Name)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,102 @@
+package query
+
+// Key is a field of resources without typed fields, e.g. users or registries.
+type Key string
+
+// ArtifactField is a field of artifacts, used when listing artifacts, tags and accessories.
+type ArtifactField string
+
+const (
+	ArtifactType      ArtifactField = "type"
+	ArtifactMediaType ArtifactField = "media_type"
+	ArtifactDigest    ArtifactField = "digest"
+	// ArtifactTags matches the tags of an artifact, see TagsAny and TagsNone.
+	ArtifactTags ArtifactField = "tags"
+	// ArtifactLabels matches the IDs of the labels added to an artifact.
+	ArtifactLabels   ArtifactField = "labels"
+	ArtifactPushTime ArtifactField = "push_time"
+	ArtifactPullTime ArtifactField = "pull_time"
+)
+
+const (
+	// TagsAny matches artifacts having at least one tag when used with ArtifactTags.
+	TagsAny = "*"
+	// TagsNone matches untagged artifacts when used with ArtifactTags.
+	TagsNone = "nil"
+)
+
+// Artifacts returns a Builder for querying artifacts.
+func Artifacts() *Builder[ArtifactField] {
+	return New[ArtifactField]()
+}
+
+// AuditLogField is a field of audit log entries.
+type AuditLogField string
+
+const (
+	AuditLogID           AuditLogField = "id"
+	AuditLogUsername     AuditLogField = "username"
+	AuditLogOperation    AuditLogField = "operation"
+	AuditLogResource     AuditLogField = "resource"
+	AuditLogResourceType AuditLogField = "resource_type"
+	AuditLogOpTime       AuditLogField = "op_time"
+)
+
+// AuditLogs returns a Builder for querying audit log entries.
+func AuditLogs() *Builder[AuditLogField] {
+	return New[AuditLogField]()
+}
+
+// ProjectField is a field of projects.
+type ProjectField string
+
+const (
+	ProjectID           ProjectField = "project_id"
+	ProjectName         ProjectField = "name"
+	ProjectPublic       ProjectField = "public"
+	ProjectOwner        ProjectField = "owner"
+	ProjectRegistryID   ProjectField = "registry_id"
+	ProjectCreationTime ProjectField = "creation_time"
+	ProjectUpdateTime   ProjectField = "update_time"
+)
+
+// Projects returns a Builder for querying projects.
+func Projects() *Builder[ProjectField] {
+	return New[ProjectField]()
+}
+
+// RobotField is a field of robot accounts.
+type RobotField string
+
+const (
+	RobotName RobotField = "name"
+	// RobotLevel is either "system" or "project". Harbor expects this key capitalized.
+	RobotLevel RobotField = "Level"
+	// RobotProjectID is the ID of the project of project level robot accounts. Harbor expects this key capitalized.
+	RobotProjectID    RobotField = "ProjectID"
+	RobotDisabled     RobotField = "disabled"
+	RobotCreationTime RobotField = "creation_time"
+	RobotUpdateTime   RobotField = "update_time"
+)
+
+// Robots returns a Builder for querying robot accounts.
+func Robots() *Builder[RobotField] {
+	return New[RobotField]()
+}
+
+// RepositoryField is a field of repositories.
+type RepositoryField string
+
+const (
+	RepositoryName          RepositoryField = "name"
+	RepositoryProjectID     RepositoryField = "project_id"
+	RepositoryArtifactCount RepositoryField = "artifact_count"
+	RepositoryPullCount     RepositoryField = "pull_count"
+	RepositoryCreationTime  RepositoryField = "creation_time"
+	RepositoryUpdateTime    RepositoryField = "update_time"
+)
+
+// Repositories returns a Builder for querying repositories.
+func Repositories() *Builder[RepositoryField] {
+	return New[RepositoryField]()
+}
@@ -0,0 +1,283 @@
+// Package query builds the values of the 'q' and 'sort' parameters accepted by Harbor's list operations.
+//
+// Harbor's query syntax combines comma separated terms of the following forms:
+//
+//	exact match:    k=v
+//	fuzzy match:    k=~v
+//	range:          k=[min~max]
+//	union:          k={v1 v2 v3}
+//	intersection:   k=(v1 v2 v3)
+//
+// Sort orders are comma separated fields, prefixed with '-' for a descending order, e.g. "name,-creation_time".
+//
+// A Builder is parameterized by the field type of the listed resource, e.g. ArtifactField,
+// so that only fields supported by the resource can be used:
+//
+//	opts, err := query.Artifacts().
+//		Exact(query.ArtifactTags, query.TagsNone).
+//		Range(query.ArtifactPushTime, nil, time.Now().Add(-30*24*time.Hour)).
+//		Desc(query.ArtifactPushTime).
+//		Options()
+//	if err != nil {
+//		return err
+//	}
+//
+//	artifacts, err := client.ListArtifacts(ctx, "library", "app", opts...)
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+// TimeFormat is the format of time values in queries. Times are formatted in UTC.
+const TimeFormat = "2006-01-02 15:04:05"
+
+// Field is the constraint of the field types of a Builder.
+type Field interface {
+	~string
+}
+
+// Builder builds the query and sort order of a list operation.
+// Errors are recorded and returned by Query and Options, so that calls can be chained.
+// A Builder is not safe for concurrent use.
+type Builder[F Field] struct {
+	terms  []string
+	fields map[F]bool
+	sort   []string
+	err    error
+}
+
+// New returns an empty Builder for the fields of type F.
+func New[F Field]() *Builder[F] {
+	return &Builder[F]{fields: map[F]bool{}}
+}
+
+// Exact adds a term matching resources whose field 'f' equals 'v'.
+// 'v' may be a string, an integer, a bool or a time.Time.
+func (b *Builder[F]) Exact(f F, v any) *Builder[F] {
+	s, err := format(v, false)
+	if err == nil && strings.ContainsAny(s[:1], "~[{(") {
+		err = fmt.Errorf("value %q must not start with %q", s, s[:1])
+	}
+
+	return b.add(f, s, err)
+}
+
+// Fuzzy adds a term matching resources whose field 'f' contains 'v'.
+func (b *Builder[F]) Fuzzy(f F, v string) *Builder[F] {
+	s, err := format(v, false)
+
+	return b.add(f, "~"+s, err)
+}
+
+// Range adds a term matching resources whose field 'f' lies between 'min' and 'max', including both.
+// Either bound may be nil for an open range.
+func (b *Builder[F]) Range(f F, min, max any) *Builder[F] {
+	if min == nil && max == nil {
+		return b.add(f, "", fmt.Errorf("at least one bound of the range must be set"))
+	}
+
+	bounds := make([]string, 2)
+
+	for i, v := range []any{min, max} {
+		if v == nil {
+			continue
+		}
+
+		s, err := format(v, true)
+		if err == nil && strings.Contains(s, "~") {
+			err = fmt.Errorf("range bound %q must not contain '~'", s)
+		}
+
+		if err != nil {
+			return b.add(f, "", err)
+		}
+
+		bounds[i] = s
+	}
+
+	return b.add(f, "["+bounds[0]+"~"+bounds[1]+"]", nil)
+}
+
+// TimeRange adds a term matching resources whose time field 'f' lies between 'from' and 'to'.
+// Either bound may be the zero time for an open range.
+func (b *Builder[F]) TimeRange(f F, from, to time.Time) *Builder[F] {
+	var min, max any
+
+	if !from.IsZero() {
+		min = from
+	}
+
+	if !to.IsZero() {
+		max = to
+	}
+
+	return b.Range(f, min, max)
+}
+
+// Union adds a term matching resources whose field 'f' equals any of 'values'.
+func (b *Builder[F]) Union(f F, values ...any) *Builder[F] {
+	s, err := list(values)
+
+	return b.add(f, "{"+s+"}", err)
+}
+
+// Intersection adds a term matching resources whose field 'f' contains all of 'values', e.g. labels.
+func (b *Builder[F]) Intersection(f F, values ...any) *Builder[F] {
+	s, err := list(values)
+
+	return b.add(f, "("+s+")", err)
+}
+
+// Asc sorts the resources by field 'f' in ascending order.
+// Resources are sorted by the fields in the order they were added.
+func (b *Builder[F]) Asc(f F) *Builder[F] {
+	b.sort = append(b.sort, string(f))
+	return b
+}
+
+// Desc sorts the resources by field 'f' in descending order.
+// Resources are sorted by the fields in the order they were added.
+func (b *Builder[F]) Desc(f F) *Builder[F] {
+	b.sort = append(b.sort, "-"+string(f))
+	return b
+}
+
+// Query returns the value of the 'q' parameter, or the first error encountered while adding terms.
+func (b *Builder[F]) Query() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+
+	return strings.Join(b.terms, ","), nil
+}
+
+// Sort returns the value of the 'sort' parameter.
+func (b *Builder[F]) Sort() string {
+	return strings.Join(b.sort, ",")
+}
+
+// Options returns the CallOptions applying the query and sort order to a list operation,
+// or the first error encountered while adding terms.
+// The client's default query or sort order is kept if no terms or sort fields were added.
+func (b *Builder[F]) Options() ([]config.CallOption, error) {
+	q, err := b.Query()
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []config.CallOption
+
+	if q != "" {
+		opts = append(opts, config.WithQuery(q))
+	}
+
+	if s := b.Sort(); s != "" {
+		opts = append(opts, config.WithSort(s))
+	}
+
+	return opts, nil
+}
+
+func (b *Builder[F]) add(f F, value string, err error) *Builder[F] {
+	if b.err != nil {
+		return b
+	}
+
+	switch {
+	case err != nil:
+	case f == "":
+		err = fmt.Errorf("field must not be empty")
+	case b.fields[f]:
+		err = fmt.Errorf("field is already part of the query")
+	}
+
+	if err != nil {
+		b.err = &errors.ErrInvalidQuery{Field: string(f), Reason: err.Error()}
+		return b
+	}
+
+	if b.fields == nil {
+		b.fields = map[F]bool{}
+	}
+
+	b.fields[f] = true
+	b.terms = append(b.terms, string(f)+"="+value)
+
+	return b
+}
+
+func list(values []any) (string, error) {
+	if len(values) == 0 {
+		return "", fmt.Errorf("at least one value must be provided")
+	}
+
+	items := make([]string, len(values))
+
+	for i, v := range values {
+		s, err := format(v, true)
+		if err != nil {
+			return "", err
+		}
+
+		if strings.Contains(s, " ") {
+			return "", fmt.Errorf("list value %q must not contain spaces", s)
+		}
+
+		items[i] = s
+	}
+
+	return strings.Join(items, " "), nil
+}
+
+// escaper escapes the characters altered by Harbor unescaping the query once more after decoding the request.
+var escaper = strings.NewReplacer("%", "%25", "+", "%2B")
+
+// format formats 'v' as a query value. Strings inside ranges and lists are quoted,
+// as Harbor interprets unquoted values as integers or times if possible.
+func format(v any, quote bool) (string, error) {
+	var s string
+
+	switch v := v.(type) {
+	case string:
+		if v == "" {
+			return "", fmt.Errorf("value must not be empty")
+		}
+
+		if quote {
+			if strings.ContainsAny(v, `"'`) {
+				return "", fmt.Errorf("value %q must not contain quotes", v)
+			}
+
+			v = `"` + v + `"`
+		}
+
+		s = v
+	case int:
+		s = strconv.Itoa(v)
+	case int32:
+		s = strconv.FormatInt(int64(v), 10)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case bool:
+		s = strconv.FormatBool(v)
+	case time.Time:
+		s = v.UTC().Format(TimeFormat)
+	case fmt.Stringer:
+		return format(v.String(), quote)
+	default:
+		return "", fmt.Errorf("unsupported value type %T", v)
+	}
+
+	if strings.Contains(s, ",") {
+		return "", fmt.Errorf("value %q must not contain ','", s)
+	}
+
+	return escaper.Replace(s), nil
+}
@@ -0,0 +1,106 @@
+//go:build !integration
+
+package query
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+var (
+	from = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	to   = time.Date(2023, 2, 1, 0, 0, 0, 0, time.FixedZone("CET", 3600))
+)
+
+func TestBuilder_Query(t *testing.T) {
+	for want, b := range map[string]interface{ Query() (string, error) }{
+		"":                              Projects(),
+		"name=library":                  Projects().Exact(ProjectName, "library"),
+		"public=true,project_id=3":      Projects().Exact(ProjectPublic, true).Exact(ProjectID, int64(3)),
+		"name=~team":                    Repositories().Fuzzy(RepositoryName, "team"),
+		"tags=nil":                      Artifacts().Exact(ArtifactTags, TagsNone),
+		"labels=(1 2 3)":                Artifacts().Intersection(ArtifactLabels, 1, 2, int64(3)),
+		`operation={"create" "delete"}`: AuditLogs().Union(AuditLogOperation, "create", "delete"),
+		"artifact_count=[10~]":          Repositories().Range(RepositoryArtifactCount, 10, nil),
+		`name=["a"~"m"]`:                Repositories().Range(RepositoryName, "a", "m"),
+		"Level=project,ProjectID=1":     Robots().Exact(RobotLevel, "project").Exact(RobotProjectID, 1),
+		"name=~50%25%2B":                Projects().Fuzzy(ProjectName, "50%+"),
+		"op_time=[2023-01-02 03:04:05~2023-01-31 23:00:00]": AuditLogs().TimeRange(AuditLogOpTime, from, to),
+		"push_time=[~2023-01-02 03:04:05]":                  Artifacts().TimeRange(ArtifactPushTime, time.Time{}, from),
+	} {
+		got, err := b.Query()
+		require.NoError(t, err, want)
+		require.Equal(t, want, got)
+	}
+}
+
+func TestBuilder_QueryInvalid(t *testing.T) {
+	for name, b := range map[string]*Builder[Key]{
+		"empty field":       New[Key]().Exact("", "a"),
+		"empty value":       New[Key]().Exact("name", ""),
+		"duplicate field":   New[Key]().Exact("name", "a").Fuzzy("name", "b"),
+		"comma":             New[Key]().Fuzzy("name", "a,b"),
+		"exact pattern":     New[Key]().Exact("name", "~a"),
+		"open range":        New[Key]().Range("size", nil, nil),
+		"range tilde":       New[Key]().Range("name", "a~b", nil),
+		"empty list":        New[Key]().Union("name"),
+		"list space":        New[Key]().Union("name", "a b"),
+		"list time":         New[Key]().Union("op_time", from),
+		"quote":             New[Key]().Intersection("name", `"a`),
+		"unsupported value": New[Key]().Exact("size", 1.5),
+		"first error kept":  New[Key]().Exact("size", 1.5).Exact("name", "a"),
+	} {
+		_, err := b.Query()
+		require.ErrorIs(t, err, &clienterrors.ErrInvalidQuery{}, name)
+
+		_, err = b.Options()
+		require.Error(t, err, name)
+	}
+}
+
+func TestBuilder_Sort(t *testing.T) {
+	require.Equal(t, "", Projects().Sort())
+	require.Equal(t, "name,-creation_time", Projects().Asc(ProjectName).Desc(ProjectCreationTime).Sort())
+}
+
+func TestBuilder_Options(t *testing.T) {
+	defaults := config.Defaults().WithQuery("name=~default").WithSort("name")
+
+	opts, err := Artifacts().Exact(ArtifactType, "IMAGE").Desc(ArtifactPushTime).Options()
+	require.NoError(t, err)
+
+	o := defaults.Apply(opts...)
+	require.Equal(t, "type=IMAGE", o.Query)
+	require.Equal(t, "-push_time", o.Sort)
+
+	opts, err = Artifacts().Options()
+	require.NoError(t, err)
+
+	o = defaults.Apply(opts...)
+	require.Equal(t, "name=~default", o.Query)
+	require.Equal(t, "name", o.Sort)
+}
+
+func ExampleBuilder() {
+	b := AuditLogs().
+		Union(AuditLogOperation, "create", "delete").
+		TimeRange(AuditLogOpTime, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{}).
+		Desc(AuditLogOpTime)
+
+	q, err := b.Query()
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(q)
+	fmt.Println(b.Sort())
+	// Output:
+	// operation={"create" "delete"},op_time=[2023-01-01 00:00:00~]
+	// -op_time
+}
@@ -0,0 +1,208 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Operation describes what a Change does to a resource.
+type Operation string
+
+const (
+	OperationCreate Operation = "create"
+	OperationUpdate Operation = "update"
+	OperationDelete Operation = "delete"
+)
+
+// Kind describes the type of resource affected by a Change.
+type Kind string
+
+const (
+	KindRegistry          Kind = "registry"
+	KindLabel             Kind = "label"
+	KindProjectLabel      Kind = "project-label"
+	KindProject           Kind = "project"
+	KindProjectMetadata   Kind = "project-metadata"
+	KindQuota             Kind = "quota"
+	KindMember            Kind = "member"
+	KindRobot             Kind = "robot"
+	KindImmutableRule     Kind = "immutable-rule"
+	KindRetentionPolicy   Kind = "retention-policy"
+	KindWebhookPolicy     Kind = "webhook-policy"
+	KindReplicationPolicy Kind = "replication-policy"
+)
+
+// creationOrder defines the order in which resources are created and updated.
+// Deletions are applied in reverse order, after all creations and updates.
+var creationOrder = map[Kind]int{
+	KindRegistry:          0,
+	KindLabel:             1,
+	KindProject:           1,
+	KindProjectMetadata:   2,
+	KindQuota:             2,
+	KindProjectLabel:      3,
+	KindMember:            3,
+	KindRobot:             3,
+	KindImmutableRule:     3,
+	KindRetentionPolicy:   3,
+	KindWebhookPolicy:     3,
+	KindReplicationPolicy: 4,
+}
+
+// Diff describes a single field differing between the live and the desired state.
+type Diff struct {
+	Field   string
+	Current string
+	Desired string
+}
+
+func (d Diff) String() string {
+	return fmt.Sprintf("%s: %s -> %s", d.Field, d.Current, d.Desired)
+}
+
+// Change describes a single create, update or delete operation.
+type Change struct {
+	Kind      Kind
+	Operation Operation
+	// Project is the name of the project the resource belongs to, empty for global resources.
+	Project string
+	// Name identifies the resource, e.g. by its name or member name.
+	Name string
+	// Diffs lists the differing fields of updates.
+	Diffs []Diff
+
+	apply func(ctx context.Context) error
+}
+
+// ID returns a human-readable identifier of the affected resource, e.g. "member library/alice".
+func (c *Change) ID() string {
+	if c.Project == "" || c.Kind == KindProject {
+		return fmt.Sprintf("%s %s", c.Kind, c.Name)
+	}
+
+	return fmt.Sprintf("%s %s/%s", c.Kind, c.Project, c.Name)
+}
+
+func (c *Change) String() string {
+	var sb strings.Builder
+
+	switch c.Operation {
+	case OperationCreate:
+		sb.WriteString("+ ")
+	case OperationUpdate:
+		sb.WriteString("~ ")
+	case OperationDelete:
+		sb.WriteString("- ")
+	}
+
+	sb.WriteString(c.ID())
+
+	for _, d := range c.Diffs {
+		sb.WriteString("\n    ")
+		sb.WriteString(d.String())
+	}
+
+	return sb.String()
+}
+
+// Plan contains the changes needed to converge the live state towards a Spec.
+type Plan struct {
+	// Changes are applied in dependency order by Reconciler.Apply.
+	Changes []*Change
+	// Unmanaged contains resources existing on the server but not in the spec.
+	// These are only deleted when pruning is enabled, in which case they are part of Changes as well.
+	Unmanaged []*Change
+}
+
+// Empty returns true if no changes need to be applied.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Drift returns all changes describing deviations of existing resources from the spec,
+// i.e. updates and resources unknown to the spec.
+func (p *Plan) Drift() []*Change {
+	var drift []*Change
+
+	for _, c := range p.Changes {
+		if c.Operation == OperationUpdate {
+			drift = append(drift, c)
+		}
+	}
+
+	return append(drift, p.Unmanaged...)
+}
+
+// String renders the plan in a human-readable form.
+func (p *Plan) String() string {
+	if p.Empty() && len(p.Unmanaged) == 0 {
+		return "no changes"
+	}
+
+	lines := make([]string, 0, len(p.Changes)+len(p.Unmanaged))
+
+	for _, c := range p.Changes {
+		lines = append(lines, c.String())
+	}
+
+	for _, c := range p.Unmanaged {
+		if !p.contains(c) {
+			lines = append(lines, "? "+c.ID()+" (unmanaged)")
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (p *Plan) contains(change *Change) bool {
+	for _, c := range p.Changes {
+		if c == change {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Plan) add(c *Change) {
+	p.Changes = append(p.Changes, c)
+}
+
+// addUnmanaged records a resource missing in the spec, which is deleted if 'prune' is set.
+func (p *Plan) addUnmanaged(c *Change, prune bool) {
+	p.Unmanaged = append(p.Unmanaged, c)
+
+	if prune {
+		p.add(c)
+	}
+}
+
+// sort orders the changes by their dependencies:
+// creations and updates first (parents before children),
+// followed by deletions (children before parents).
+func (p *Plan) sort() {
+	rank := func(c *Change) int {
+		order := creationOrder[c.Kind]
+		if c.Operation == OperationDelete {
+			return 100 - order
+		}
+
+		return order
+	}
+
+	sort.SliceStable(p.Changes, func(i, j int) bool {
+		return rank(p.Changes[i]) < rank(p.Changes[j])
+	})
+}
+
+// diffs collects Diff entries, skipping equal values.
+type diffs []Diff
+
+func (d *diffs) add(field string, current, desired interface{}) {
+	c, w := fmt.Sprint(current), fmt.Sprint(desired)
+	if c != w {
+		*d = append(*d, Diff{Field: field, Current: c, Desired: w})
+	}
+}
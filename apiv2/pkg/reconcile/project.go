@@ -0,0 +1,775 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/label"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/member"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/robot"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/common"
+)
+
+const (
+	immutableRuleAction   = "immutable"
+	immutableRuleTemplate = "immutable_template"
+	retentionScopeProject = "project"
+	storageResourceName   = "storage"
+)
+
+// projectState holds the live state of a project, or nil values for projects that do not exist yet.
+type projectState struct {
+	project  *model.Project
+	metadata map[string]string
+	robots   []*model.Robot
+}
+
+func (r *Reconciler) planProjects(ctx context.Context, spec *Spec, plan *Plan) error {
+	if spec.Projects == nil {
+		return nil
+	}
+
+	live, err := r.Client.ListProjects(ctx, "")
+	if err != nil {
+		return fmt.Errorf("listing projects: %w", err)
+	}
+
+	byName := map[string]*model.Project{}
+	for _, p := range live {
+		byName[p.Name] = p
+	}
+
+	var robots []*model.Robot
+
+	for _, p := range spec.Projects {
+		if p.Robots != nil {
+			if robots, err = r.Client.ListRobotAccounts(ctx); err != nil {
+				return fmt.Errorf("listing robot accounts: %w", err)
+			}
+
+			break
+		}
+	}
+
+	for i := range spec.Projects {
+		desired := spec.Projects[i]
+		state := &projectState{robots: robots}
+
+		if current, ok := byName[desired.Name]; ok {
+			state.project = current
+			delete(byName, desired.Name)
+		}
+
+		if err := r.planProject(ctx, plan, desired, state); err != nil {
+			return fmt.Errorf("project %q: %w", desired.Name, err)
+		}
+	}
+
+	for _, name := range sortedKeys(byName) {
+		name := name
+
+		plan.addUnmanaged(&Change{
+			Kind:      KindProject,
+			Operation: OperationDelete,
+			Name:      name,
+			apply: func(ctx context.Context) error {
+				return r.Client.DeleteProject(ctx, name)
+			},
+		}, r.Prune)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) planProject(ctx context.Context, plan *Plan, desired ProjectSpec, state *projectState) error {
+	projectID := func(ctx context.Context) (int64, error) {
+		p, err := r.Client.GetProject(ctx, desired.Name)
+		if err != nil {
+			return 0, err
+		}
+
+		return int64(p.ProjectID), nil
+	}
+
+	if state.project == nil {
+		plan.add(&Change{
+			Kind:      KindProject,
+			Operation: OperationCreate,
+			Name:      desired.Name,
+			apply: func(ctx context.Context) error {
+				req := &model.ProjectReq{
+					ProjectName:  desired.Name,
+					StorageLimit: desired.StorageLimit,
+				}
+
+				if desired.Metadata != nil {
+					meta, err := projectMetadata(desired.Metadata)
+					if err != nil {
+						return err
+					}
+
+					req.Metadata = meta
+				}
+
+				return r.Client.NewProject(ctx, req)
+			},
+		})
+	} else {
+		if err := r.planProjectSettings(ctx, plan, desired, state); err != nil {
+			return err
+		}
+	}
+
+	steps := []func(context.Context, *Plan, ProjectSpec, *projectState, func(context.Context) (int64, error)) error{
+		r.planMembers,
+		r.planRobots,
+		r.planProjectLabels,
+		r.planImmutableRules,
+		r.planRetentionPolicy,
+		r.planWebhookPolicies,
+	}
+
+	for _, step := range steps {
+		if err := step(ctx, plan, desired, state, projectID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// planProjectSettings plans metadata and quota changes of an existing project.
+func (r *Reconciler) planProjectSettings(ctx context.Context, plan *Plan, desired ProjectSpec, state *projectState) error {
+	if desired.Metadata != nil {
+		current, err := r.Client.ListProjectMetadata(ctx, desired.Name)
+		if err != nil {
+			return fmt.Errorf("listing metadata: %w", err)
+		}
+
+		state.metadata = current
+
+		for _, key := range sortedKeys(desired.Metadata) {
+			key, value := key, desired.Metadata[key]
+			currentValue, exists := current[key]
+
+			if exists && currentValue == value {
+				continue
+			}
+
+			change := &Change{
+				Kind:      KindProjectMetadata,
+				Operation: OperationCreate,
+				Project:   desired.Name,
+				Name:      key,
+				apply: func(ctx context.Context) error {
+					return r.Client.AddProjectMetadata(ctx, desired.Name, common.MetadataKey(key), value)
+				},
+			}
+
+			if exists {
+				change.Operation = OperationUpdate
+				change.Diffs = []Diff{{Field: key, Current: currentValue, Desired: value}}
+				change.apply = func(ctx context.Context) error {
+					return r.Client.UpdateProjectMetadata(ctx, desired.Name, common.MetadataKey(key), value)
+				}
+			}
+
+			plan.add(change)
+		}
+	}
+
+	if desired.StorageLimit != nil {
+		projectID := int64(state.project.ProjectID)
+
+		q, err := r.Client.GetQuotaByProjectID(ctx, projectID)
+		if err != nil {
+			return fmt.Errorf("getting quota: %w", err)
+		}
+
+		if current := q.Hard[storageResourceName]; current != *desired.StorageLimit {
+			limit := *desired.StorageLimit
+
+			plan.add(&Change{
+				Kind:      KindQuota,
+				Operation: OperationUpdate,
+				Project:   desired.Name,
+				Name:      storageResourceName,
+				Diffs:     []Diff{{Field: storageResourceName, Current: strconv.FormatInt(current, 10), Desired: strconv.FormatInt(limit, 10)}},
+				apply: func(ctx context.Context) error {
+					return r.Client.UpdateStorageQuotaByProjectID(ctx, projectID, limit)
+				},
+			})
+		}
+	}
+
+	return nil
+}
+
+func (r *Reconciler) planMembers(ctx context.Context, plan *Plan, desired ProjectSpec, state *projectState,
+	_ func(context.Context) (int64, error),
+) error {
+	if desired.Members == nil {
+		return nil
+	}
+
+	live := map[string]*model.ProjectMemberEntity{}
+
+	if state.project != nil {
+		members, err := r.Client.ListProjectMembers(ctx, desired.Name, "")
+		if err != nil {
+			return fmt.Errorf("listing members: %w", err)
+		}
+
+		for _, m := range members {
+			live[memberKey(m.EntityType, m.EntityName)] = m
+		}
+	}
+
+	for _, m := range desired.Members {
+		role, err := member.ParseRole(m.Role)
+		if err != nil {
+			return err
+		}
+
+		pm := &model.ProjectMember{RoleID: role.ID()}
+		key := memberKey(member.EntityTypeUser.String(), m.User)
+		name := m.User
+
+		if m.Group != "" {
+			pm.MemberGroup = &model.UserGroup{GroupName: m.Group}
+			key = memberKey(member.EntityTypeGroup.String(), m.Group)
+			name = m.Group
+		} else {
+			pm.MemberUser = &model.UserEntity{Username: m.User}
+		}
+
+		current, ok := live[key]
+		delete(live, key)
+
+		if !ok {
+			plan.add(&Change{
+				Kind:      KindMember,
+				Operation: OperationCreate,
+				Project:   desired.Name,
+				Name:      name,
+				apply: func(ctx context.Context) error {
+					return r.Client.AddProjectMember(ctx, desired.Name, pm)
+				},
+			})
+
+			continue
+		}
+
+		if current.RoleID == role.ID() {
+			continue
+		}
+
+		plan.add(&Change{
+			Kind:      KindMember,
+			Operation: OperationUpdate,
+			Project:   desired.Name,
+			Name:      name,
+			Diffs:     []Diff{{Field: "role", Current: member.Role(current.RoleID).String(), Desired: role.String()}},
+			apply: func(ctx context.Context) error {
+				return r.Client.UpdateProjectMember(ctx, desired.Name, pm)
+			},
+		})
+	}
+
+	for _, key := range sortedKeys(live) {
+		m := live[key]
+
+		// The project owner is implicitly added as project admin and cannot be removed.
+		if m.EntityType == member.EntityTypeUser.String() && m.EntityName == state.project.OwnerName {
+			continue
+		}
+
+		pm := &model.ProjectMember{RoleID: m.RoleID}
+		if m.EntityType == member.EntityTypeGroup.String() {
+			pm.MemberGroup = &model.UserGroup{GroupName: m.EntityName}
+		} else {
+			pm.MemberUser = &model.UserEntity{Username: m.EntityName}
+		}
+
+		plan.addUnmanaged(&Change{
+			Kind:      KindMember,
+			Operation: OperationDelete,
+			Project:   desired.Name,
+			Name:      m.EntityName,
+			apply: func(ctx context.Context) error {
+				return r.Client.DeleteProjectMember(ctx, desired.Name, pm)
+			},
+		}, r.Prune)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) planRobots(_ context.Context, plan *Plan, desired ProjectSpec, state *projectState,
+	_ func(context.Context) (int64, error),
+) error {
+	if desired.Robots == nil {
+		return nil
+	}
+
+	live := map[string]*model.Robot{}
+
+	if state.project != nil {
+		for _, rb := range state.robots {
+			if rb.Level != robot.LevelProject.String() || len(rb.Permissions) == 0 || rb.Permissions[0].Namespace != desired.Name {
+				continue
+			}
+
+			// Project robot accounts are named '<prefix><project>+<name>'.
+			live[rb.Name[strings.LastIndex(rb.Name, "+")+1:]] = rb
+		}
+	}
+
+	for i := range desired.Robots {
+		spec := desired.Robots[i]
+
+		duration := spec.Duration
+		if duration == 0 {
+			duration = -1
+		}
+
+		permissions := []*model.RobotPermission{{
+			Kind:      robot.LevelProject.String(),
+			Namespace: desired.Name,
+			Access:    robotAccess(spec.Access),
+		}}
+
+		current, ok := live[spec.Name]
+		delete(live, spec.Name)
+
+		if !ok {
+			plan.add(&Change{
+				Kind:      KindRobot,
+				Operation: OperationCreate,
+				Project:   desired.Name,
+				Name:      spec.Name,
+				apply: func(ctx context.Context) error {
+					created, err := r.Client.NewRobotAccount(ctx, &model.RobotCreate{
+						Name:        spec.Name,
+						Description: spec.Description,
+						Disable:     spec.Disable,
+						Duration:    duration,
+						Level:       robot.LevelProject.String(),
+						Permissions: permissions,
+					})
+					if err != nil {
+						return err
+					}
+
+					if r.RobotSecretSink == nil {
+						return nil
+					}
+
+					return r.RobotSecretSink.Write(ctx, &model.Robot{
+						ID:        created.ID,
+						Name:      created.Name,
+						ExpiresAt: created.ExpiresAt,
+					}, created.Secret)
+				},
+			})
+
+			continue
+		}
+
+		var d diffs
+		d.add("description", current.Description, spec.Description)
+		d.add("disable", current.Disable, spec.Disable)
+		d.add("duration", current.Duration, duration)
+		d.add("access", accessList(current.Permissions), accessList(permissions))
+
+		if len(d) == 0 {
+			continue
+		}
+
+		plan.add(&Change{
+			Kind:      KindRobot,
+			Operation: OperationUpdate,
+			Project:   desired.Name,
+			Name:      spec.Name,
+			Diffs:     d,
+			apply: func(ctx context.Context) error {
+				updated := *current
+				updated.Description = spec.Description
+				updated.Disable = spec.Disable
+				updated.Duration = duration
+				updated.Permissions = permissions
+
+				return r.Client.UpdateRobotAccount(ctx, &updated)
+			},
+		})
+	}
+
+	for _, name := range sortedKeys(live) {
+		id := live[name].ID
+
+		plan.addUnmanaged(&Change{
+			Kind:      KindRobot,
+			Operation: OperationDelete,
+			Project:   desired.Name,
+			Name:      name,
+			apply: func(ctx context.Context) error {
+				return r.Client.DeleteRobotAccountByID(ctx, id)
+			},
+		}, r.Prune)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) planProjectLabels(ctx context.Context, plan *Plan, desired ProjectSpec, state *projectState,
+	projectID func(context.Context) (int64, error),
+) error {
+	if desired.Labels == nil {
+		return nil
+	}
+
+	var live []*model.Label
+
+	if state.project != nil {
+		id := int64(state.project.ProjectID)
+
+		labels, err := r.Client.ListLabels(ctx, "", &id, label.ScopeProject)
+		if err != nil {
+			return fmt.Errorf("listing labels: %w", err)
+		}
+
+		live = labels
+	}
+
+	r.planLabels(plan, desired.Name, desired.Labels, live, projectID)
+
+	return nil
+}
+
+func (r *Reconciler) planImmutableRules(ctx context.Context, plan *Plan, desired ProjectSpec, state *projectState,
+	_ func(context.Context) (int64, error),
+) error {
+	if desired.ImmutableRules == nil {
+		return nil
+	}
+
+	live := map[string]*model.ImmutableRule{}
+
+	if state.project != nil {
+		rules, err := r.Client.ListImmuRules(ctx, desired.Name)
+		if err != nil {
+			return fmt.Errorf("listing immutable rules: %w", err)
+		}
+
+		for _, rule := range rules {
+			live[immutableRuleKey(rule)] = rule
+		}
+	}
+
+	// Immutable rules have no name, so they are identified by their selectors.
+	// Changing a rule results in deleting the old rule (when pruning) and creating a new one.
+	for _, rule := range desired.ImmutableRules {
+		key := immutableRuleKey(rule)
+		if _, ok := live[key]; ok {
+			delete(live, key)
+			continue
+		}
+
+		create := *rule
+		create.ID = 0
+
+		if create.Action == "" {
+			create.Action = immutableRuleAction
+		}
+
+		if create.Template == "" {
+			create.Template = immutableRuleTemplate
+		}
+
+		plan.add(&Change{
+			Kind:      KindImmutableRule,
+			Operation: OperationCreate,
+			Project:   desired.Name,
+			Name:      key,
+			apply: func(ctx context.Context) error {
+				return r.Client.CreateImmuRule(ctx, desired.Name, &create)
+			},
+		})
+	}
+
+	for _, key := range sortedKeys(live) {
+		id := live[key].ID
+
+		plan.addUnmanaged(&Change{
+			Kind:      KindImmutableRule,
+			Operation: OperationDelete,
+			Project:   desired.Name,
+			Name:      key,
+			apply: func(ctx context.Context) error {
+				return r.Client.DeleteImmuRule(ctx, desired.Name, id)
+			},
+		}, r.Prune)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) planRetentionPolicy(ctx context.Context, plan *Plan, desired ProjectSpec, state *projectState,
+	projectID func(context.Context) (int64, error),
+) error {
+	if desired.Retention == nil {
+		return nil
+	}
+
+	var current *model.RetentionPolicy
+
+	if state.project != nil {
+		metadata := state.metadata
+		if metadata == nil {
+			var err error
+			if metadata, err = r.Client.ListProjectMetadata(ctx, desired.Name); err != nil {
+				return fmt.Errorf("listing metadata: %w", err)
+			}
+		}
+
+		if idStr, ok := metadata[common.ProjectMetadataKeyRetentionID.String()]; ok && idStr != "" {
+			id, err := strconv.ParseInt(idStr, 10, 64)
+			if err != nil {
+				return fmt.Errorf("parsing retention id %q: %w", idStr, err)
+			}
+
+			if current, err = r.Client.GetRetentionPolicyByID(ctx, id); err != nil {
+				return fmt.Errorf("getting retention policy: %w", err)
+			}
+		}
+	}
+
+	policy := *desired.Retention
+
+	if current == nil {
+		plan.add(&Change{
+			Kind:      KindRetentionPolicy,
+			Operation: OperationCreate,
+			Project:   desired.Name,
+			Name:      desired.Name,
+			apply: func(ctx context.Context) error {
+				id, err := projectID(ctx)
+				if err != nil {
+					return err
+				}
+
+				policy.ID = 0
+				policy.Scope = &model.RetentionPolicyScope{Level: retentionScopeProject, Ref: id}
+
+				return r.Client.NewRetentionPolicy(ctx, &policy)
+			},
+		})
+
+		return nil
+	}
+
+	var d diffs
+	d.add("algorithm", current.Algorithm, policy.Algorithm)
+	d.add("rules", canonical(normalizeRetentionRules(current.Rules)), canonical(normalizeRetentionRules(policy.Rules)))
+	d.add("trigger", canonical(current.Trigger), canonical(policy.Trigger))
+
+	if len(d) == 0 {
+		return nil
+	}
+
+	plan.add(&Change{
+		Kind:      KindRetentionPolicy,
+		Operation: OperationUpdate,
+		Project:   desired.Name,
+		Name:      desired.Name,
+		Diffs:     d,
+		apply: func(ctx context.Context) error {
+			policy.ID = current.ID
+			policy.Scope = current.Scope
+
+			return r.Client.UpdateRetentionPolicy(ctx, &policy)
+		},
+	})
+
+	return nil
+}
+
+func (r *Reconciler) planWebhookPolicies(ctx context.Context, plan *Plan, desired ProjectSpec, state *projectState,
+	projectID func(context.Context) (int64, error),
+) error {
+	if desired.Webhooks == nil {
+		return nil
+	}
+
+	live := map[string]*model.WebhookPolicy{}
+
+	if state.project != nil {
+		policies, err := r.Client.ListProjectWebhookPolicies(ctx, int(state.project.ProjectID))
+		if err != nil {
+			return fmt.Errorf("listing webhook policies: %w", err)
+		}
+
+		for _, p := range policies {
+			live[p.Name] = p
+		}
+	}
+
+	for _, spec := range desired.Webhooks {
+		policy := *spec
+		current, ok := live[policy.Name]
+		delete(live, policy.Name)
+
+		if !ok {
+			plan.add(&Change{
+				Kind:      KindWebhookPolicy,
+				Operation: OperationCreate,
+				Project:   desired.Name,
+				Name:      policy.Name,
+				apply: func(ctx context.Context) error {
+					id, err := projectID(ctx)
+					if err != nil {
+						return err
+					}
+
+					policy.ID = 0
+					policy.ProjectID = id
+
+					return r.Client.AddProjectWebhookPolicy(ctx, int(id), &policy)
+				},
+			})
+
+			continue
+		}
+
+		var d diffs
+		d.add("description", current.Description, policy.Description)
+		d.add("enabled", current.Enabled, policy.Enabled)
+		d.add("event_types", sortedCopy(current.EventTypes), sortedCopy(policy.EventTypes))
+		d.add("targets", canonical(current.Targets), canonical(policy.Targets))
+
+		if len(d) == 0 {
+			continue
+		}
+
+		plan.add(&Change{
+			Kind:      KindWebhookPolicy,
+			Operation: OperationUpdate,
+			Project:   desired.Name,
+			Name:      policy.Name,
+			Diffs:     d,
+			apply: func(ctx context.Context) error {
+				policy.ID = current.ID
+				policy.ProjectID = current.ProjectID
+
+				return r.Client.UpdateProjectWebhookPolicy(ctx, int(current.ProjectID), &policy)
+			},
+		})
+	}
+
+	for _, name := range sortedKeys(live) {
+		id := live[name].ID
+		pid := int(state.project.ProjectID)
+
+		plan.addUnmanaged(&Change{
+			Kind:      KindWebhookPolicy,
+			Operation: OperationDelete,
+			Project:   desired.Name,
+			Name:      name,
+			apply: func(ctx context.Context) error {
+				return r.Client.DeleteProjectWebhookPolicy(ctx, pid, id)
+			},
+		}, r.Prune)
+	}
+
+	return nil
+}
+
+// projectMetadata converts metadata key/value pairs into the model used when creating projects.
+func projectMetadata(values map[string]string) (*model.ProjectMetadata, error) {
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &model.ProjectMetadata{}
+	if err := json.Unmarshal(raw, meta); err != nil {
+		return nil, fmt.Errorf("invalid project metadata: %w", err)
+	}
+
+	return meta, nil
+}
+
+func memberKey(entityType, name string) string {
+	return entityType + ":" + name
+}
+
+func robotAccess(access []AccessSpec) []*model.Access {
+	out := make([]*model.Access, 0, len(access))
+
+	for _, a := range access {
+		out = append(out, &model.Access{Resource: a.Resource, Action: a.Action})
+	}
+
+	return out
+}
+
+// accessList returns a sorted list of "resource:action" entries of the provided permissions.
+func accessList(permissions []*model.RobotPermission) []string {
+	var out []string
+
+	for _, p := range permissions {
+		for _, a := range p.Access {
+			out = append(out, a.Resource+":"+a.Action)
+		}
+	}
+
+	sort.Strings(out)
+
+	return out
+}
+
+// immutableRuleKey identifies an immutable rule by its state and selectors.
+func immutableRuleKey(rule *model.ImmutableRule) string {
+	return canonical(struct {
+		Disabled       bool                                 `json:"disabled"`
+		ScopeSelectors map[string][]model.ImmutableSelector `json:"scope_selectors"`
+		TagSelectors   []*model.ImmutableSelector           `json:"tag_selectors"`
+	}{rule.Disabled, rule.ScopeSelectors, rule.TagSelectors})
+}
+
+// normalizeRetentionRules strips server-assigned fields from retention rules.
+func normalizeRetentionRules(rules []*model.RetentionRule) []model.RetentionRule {
+	out := make([]model.RetentionRule, 0, len(rules))
+
+	for _, rule := range rules {
+		normalized := *rule
+		normalized.ID = 0
+		normalized.Priority = 0
+		out = append(out, normalized)
+	}
+
+	return out
+}
+
+func sortedCopy(in []string) []string {
+	out := append([]string(nil), in...)
+	sort.Strings(out)
+
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
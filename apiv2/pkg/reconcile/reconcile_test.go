@@ -0,0 +1,319 @@
+//go:build !integration
+
+package reconcile
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/label"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/member"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/common"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+)
+
+var ctx = context.Background()
+
+const exampleSpec = `
+registries:
+  - name: dockerhub
+    type: docker-hub
+    url: https://hub.docker.com
+labels:
+  - name: approved
+    color: "#00FF00"
+projects:
+  - name: team-a
+    metadata:
+      public: "true"
+    storageLimit: 1073741824
+    members:
+      - user: alice
+        role: maintainer
+      - group: developers
+        role: developer
+    robots:
+      - name: ci
+        access:
+          - resource: repository
+            action: pull
+  - name: team-b
+    members: []
+replicationPolicies:
+  - name: mirror-alpine
+    srcRegistry: dockerhub
+    enabled: true
+    filters:
+      - type: name
+        value: library/alpine
+`
+
+// fakeClient implements the parts of the Client interface used in these tests
+// and records all mutating calls.
+type fakeClient struct {
+	Client
+
+	projects   []*model.Project
+	metadata   map[string]map[string]string
+	quotas     map[int64]int64
+	members    map[string][]*model.ProjectMemberEntity
+	registries []*model.Registry
+	labels     []*model.Label
+
+	calls []string
+}
+
+func (f *fakeClient) record(call string) error {
+	f.calls = append(f.calls, call)
+	return nil
+}
+
+func (f *fakeClient) ListRegistries(context.Context, ...config.CallOption) ([]*model.Registry, error) {
+	return f.registries, nil
+}
+
+func (f *fakeClient) NewRegistry(_ context.Context, reg *model.Registry) error {
+	return f.record("NewRegistry " + reg.Name)
+}
+
+func (f *fakeClient) DeleteRegistryByID(context.Context, int64) error {
+	return f.record("DeleteRegistryByID")
+}
+
+func (f *fakeClient) ListLabels(_ context.Context, _ string, _ *int64, scope label.Scope, _ ...config.CallOption) ([]*model.Label, error) {
+	if scope == label.ScopeGlobal {
+		return f.labels, nil
+	}
+
+	return nil, nil
+}
+
+func (f *fakeClient) CreateLabel(_ context.Context, l *model.Label) error {
+	return f.record("CreateLabel " + l.Name)
+}
+
+func (f *fakeClient) ListProjects(context.Context, string, ...config.CallOption) ([]*model.Project, error) {
+	return f.projects, nil
+}
+
+func (f *fakeClient) NewProject(_ context.Context, p *model.ProjectReq) error {
+	return f.record("NewProject " + p.ProjectName)
+}
+
+func (f *fakeClient) DeleteProject(_ context.Context, name string) error {
+	return f.record("DeleteProject " + name)
+}
+
+func (f *fakeClient) ListProjectMetadata(_ context.Context, name string) (map[string]string, error) {
+	return f.metadata[name], nil
+}
+
+func (f *fakeClient) UpdateProjectMetadata(_ context.Context, project string, key common.MetadataKey, value string) error {
+	return f.record("UpdateProjectMetadata " + project + " " + key.String() + "=" + value)
+}
+
+func (f *fakeClient) GetQuotaByProjectID(_ context.Context, id int64) (*model.Quota, error) {
+	return &model.Quota{Hard: model.ResourceList{"storage": f.quotas[id]}}, nil
+}
+
+func (f *fakeClient) UpdateStorageQuotaByProjectID(context.Context, int64, int64) error {
+	return f.record("UpdateStorageQuotaByProjectID")
+}
+
+func (f *fakeClient) ListProjectMembers(_ context.Context, project, _ string, _ ...config.CallOption) ([]*model.ProjectMemberEntity, error) {
+	return f.members[project], nil
+}
+
+func (f *fakeClient) AddProjectMember(_ context.Context, project string, m *model.ProjectMember) error {
+	if m.MemberGroup != nil {
+		return f.record("AddProjectMember " + project + " " + m.MemberGroup.GroupName)
+	}
+
+	return f.record("AddProjectMember " + project + " " + m.MemberUser.Username)
+}
+
+func (f *fakeClient) UpdateProjectMember(_ context.Context, project string, m *model.ProjectMember) error {
+	return f.record("UpdateProjectMember " + project + " " + m.MemberUser.Username + " " + member.Role(m.RoleID).String())
+}
+
+func (f *fakeClient) DeleteProjectMember(_ context.Context, project string, m *model.ProjectMember) error {
+	return f.record("DeleteProjectMember " + project + " " + m.MemberUser.Username)
+}
+
+func (f *fakeClient) ListRobotAccounts(context.Context, ...config.CallOption) ([]*model.Robot, error) {
+	return nil, nil
+}
+
+func (f *fakeClient) NewRobotAccount(_ context.Context, r *model.RobotCreate) (*model.RobotCreated, error) {
+	return &model.RobotCreated{Name: r.Name}, f.record("NewRobotAccount " + r.Name)
+}
+
+func (f *fakeClient) ListReplicationPolicies(context.Context, ...config.CallOption) ([]*model.ReplicationPolicy, error) {
+	return nil, nil
+}
+
+func (f *fakeClient) GetRegistryByName(_ context.Context, name string, _ ...config.CallOption) (*model.Registry, error) {
+	return &model.Registry{ID: 1, Name: name}, nil
+}
+
+func (f *fakeClient) NewReplicationPolicy(_ context.Context, _, src *model.Registry, _, _, _ bool,
+	_ []*model.ReplicationFilter, _ *model.ReplicationTrigger, _, _, name string,
+) error {
+	return f.record("NewReplicationPolicy " + name + " from " + src.Name)
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		projects: []*model.Project{
+			{Name: "team-b", ProjectID: 2, OwnerName: "admin"},
+			{Name: "legacy", ProjectID: 3},
+		},
+		members: map[string][]*model.ProjectMemberEntity{
+			"team-b": {
+				{EntityName: "admin", EntityType: "u", RoleID: member.RoleProjectAdmin.ID()},
+				{EntityName: "bob", EntityType: "u", RoleID: member.RoleDeveloper.ID()},
+			},
+		},
+		labels: []*model.Label{{ID: 1, Name: "approved", Color: "#00FF00"}},
+	}
+}
+
+func TestDecode(t *testing.T) {
+	spec, err := Decode(strings.NewReader(exampleSpec))
+	require.NoError(t, err)
+
+	require.Len(t, spec.Registries, 1)
+	require.Len(t, spec.Projects, 2)
+	require.Equal(t, "true", spec.Projects[0].Metadata["public"])
+	require.Equal(t, int64(1073741824), *spec.Projects[0].StorageLimit)
+	require.NotNil(t, spec.Projects[1].Members)
+	require.Empty(t, spec.Projects[1].Members)
+	require.Equal(t, "library/alpine", spec.ReplicationPolicies[0].Filters[0].Value)
+
+	_, err = Decode(strings.NewReader(`{"projects": [{"name": "a", "unknown": true}]}`))
+	require.Error(t, err)
+
+	_, err = Decode(strings.NewReader(`{"projects": [{"name": "a", "members": [{"user": "alice", "role": "owner"}]}]}`))
+	require.Error(t, err)
+}
+
+func TestReconciler_Plan(t *testing.T) {
+	spec, err := Decode(strings.NewReader(exampleSpec))
+	require.NoError(t, err)
+
+	r := NewReconciler(newFakeClient())
+
+	plan, err := r.Plan(ctx, spec)
+	require.NoError(t, err)
+
+	var ids []string
+	for _, c := range plan.Changes {
+		ids = append(ids, string(c.Operation)+" "+c.ID())
+	}
+
+	require.Equal(t, []string{
+		"create registry dockerhub",
+		"create project team-a",
+		"create member team-a/alice",
+		"create member team-a/developers",
+		"create robot team-a/ci",
+		"create replication-policy mirror-alpine",
+	}, ids)
+
+	// Without pruning, unmanaged resources are reported but not deleted.
+	var unmanaged []string
+	for _, c := range plan.Unmanaged {
+		unmanaged = append(unmanaged, c.ID())
+	}
+
+	require.Equal(t, []string{"member team-b/bob", "project legacy"}, unmanaged)
+	require.Len(t, plan.Drift(), 2)
+}
+
+func TestReconciler_PlanDrift(t *testing.T) {
+	client := newFakeClient()
+	client.metadata = map[string]map[string]string{"team-b": {"public": "false"}}
+	client.quotas = map[int64]int64{2: -1}
+	client.labels = nil
+
+	limit := int64(1024)
+	spec := &Spec{Projects: []ProjectSpec{{
+		Name:         "team-b",
+		Metadata:     map[string]string{"public": "true"},
+		StorageLimit: &limit,
+		Members:      []MemberSpec{{User: "bob", Role: "maintainer"}},
+	}}}
+
+	r := NewReconciler(client)
+
+	plan, err := r.Plan(ctx, spec)
+	require.NoError(t, err)
+
+	drift := plan.Drift()
+	require.Len(t, drift, 4)
+	require.Equal(t, []Diff{{Field: "public", Current: "false", Desired: "true"}}, drift[0].Diffs)
+	require.Equal(t, []Diff{{Field: "storage", Current: "-1", Desired: "1024"}}, drift[1].Diffs)
+	require.Equal(t, []Diff{{Field: "role", Current: "developer", Desired: "maintainer"}}, drift[2].Diffs)
+	require.Equal(t, "project legacy", drift[3].ID())
+
+	require.NoError(t, r.Apply(ctx, plan))
+	require.Equal(t, []string{
+		"UpdateProjectMetadata team-b public=true",
+		"UpdateStorageQuotaByProjectID",
+		"UpdateProjectMember team-b bob maintainer",
+	}, client.calls)
+}
+
+func TestReconciler_ReconcilePrune(t *testing.T) {
+	client := newFakeClient()
+
+	spec, err := Decode(strings.NewReader(exampleSpec))
+	require.NoError(t, err)
+
+	r := NewReconciler(client)
+	r.Prune = true
+
+	plan, err := r.Reconcile(ctx, spec)
+	require.NoError(t, err)
+	require.Contains(t, plan.String(), "- project legacy")
+
+	require.Equal(t, []string{
+		"NewRegistry dockerhub",
+		"NewProject team-a",
+		"AddProjectMember team-a alice",
+		"AddProjectMember team-a developers",
+		"NewRobotAccount ci",
+		"NewReplicationPolicy mirror-alpine from dockerhub",
+		// Deletions are applied last, children before their parents.
+		"DeleteProjectMember team-b bob",
+		"DeleteProject legacy",
+	}, client.calls)
+}
+
+func TestReconciler_PlanUnmanagedSections(t *testing.T) {
+	client := newFakeClient()
+	client.registries = []*model.Registry{
+		{ID: 1, Name: "dockerhub", Type: "docker-hub", URL: "https://hub.docker.com"},
+		{ID: 2, Name: "quay", Type: "quay", URL: "https://quay.io"},
+	}
+
+	r := NewReconciler(client)
+	r.Prune = true
+
+	plan, err := r.Plan(ctx, &Spec{Registries: []RegistrySpec{
+		{Name: "dockerhub", Type: "harbor", URL: "https://hub.docker.com"},
+	}})
+	require.NoError(t, err)
+
+	var ids []string
+	for _, c := range plan.Changes {
+		ids = append(ids, c.ID())
+	}
+
+	require.Equal(t, []string{"registry quay"}, ids,
+		"the registry type cannot be updated, and labels, projects and replication policies are not managed")
+}
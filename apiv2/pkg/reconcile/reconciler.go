@@ -0,0 +1,473 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/immutable"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/label"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/member"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/project"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/projectmeta"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/quota"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/registry"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/replication"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/retention"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/robot"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/robot/rotation"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/webhook"
+)
+
+// Client is the subset of the apiv2.Client used by the Reconciler.
+type Client interface {
+	immutable.Client
+	label.Client
+	member.Client
+	project.Client
+	projectmeta.Client
+	quota.Client
+	registry.Client
+	replication.Client
+	retention.Client
+	robot.Client
+	webhook.Client
+}
+
+// Reconciler computes and applies the changes needed to converge a Harbor instance towards a Spec.
+type Reconciler struct {
+	// Client used to read the live state and apply changes.
+	Client Client
+	// Prune enables the deletion of resources that are not part of the spec.
+	// Without pruning, these resources are only reported as unmanaged.
+	Prune bool
+	// RobotSecretSink optionally receives the secrets of newly created robot accounts.
+	RobotSecretSink rotation.SecretSink
+}
+
+// NewReconciler returns a Reconciler using 'client', with pruning disabled.
+func NewReconciler(client Client) *Reconciler {
+	return &Reconciler{Client: client}
+}
+
+// Plan compares the live state of the Harbor instance with 'spec'
+// and returns the changes required to converge it, without applying them.
+func (r *Reconciler) Plan(ctx context.Context, spec *Spec) (*Plan, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{}
+
+	steps := []func(context.Context, *Spec, *Plan) error{
+		r.planRegistries,
+		r.planGlobalLabels,
+		r.planProjects,
+		r.planReplicationPolicies,
+	}
+
+	for _, step := range steps {
+		if err := step(ctx, spec, plan); err != nil {
+			return nil, err
+		}
+	}
+
+	plan.sort()
+
+	return plan, nil
+}
+
+// Apply applies the changes of 'plan' in dependency order.
+// It stops at the first failing change, returning an error naming it.
+func (r *Reconciler) Apply(ctx context.Context, plan *Plan) error {
+	for _, c := range plan.Changes {
+		if err := c.apply(ctx); err != nil {
+			return fmt.Errorf("%s %s: %w", c.Operation, c.ID(), err)
+		}
+	}
+
+	return nil
+}
+
+// Reconcile plans and applies the changes needed to converge towards 'spec'.
+// The returned plan contains the applied changes.
+func (r *Reconciler) Reconcile(ctx context.Context, spec *Spec) (*Plan, error) {
+	plan, err := r.Plan(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, r.Apply(ctx, plan)
+}
+
+func (r *Reconciler) planRegistries(ctx context.Context, spec *Spec, plan *Plan) error {
+	if spec.Registries == nil {
+		return nil
+	}
+
+	live, err := r.Client.ListRegistries(ctx)
+	if err != nil {
+		return fmt.Errorf("listing registries: %w", err)
+	}
+
+	byName := map[string]*model.Registry{}
+	for _, reg := range live {
+		byName[reg.Name] = reg
+	}
+
+	for i := range spec.Registries {
+		desired := spec.Registries[i]
+		current, ok := byName[desired.Name]
+		delete(byName, desired.Name)
+
+		if !ok {
+			plan.add(&Change{
+				Kind:      KindRegistry,
+				Operation: OperationCreate,
+				Name:      desired.Name,
+				apply: func(ctx context.Context) error {
+					return r.Client.NewRegistry(ctx, &model.Registry{
+						Name:        desired.Name,
+						Type:        desired.Type,
+						URL:         desired.URL,
+						Description: desired.Description,
+						Insecure:    desired.Insecure,
+						Credential:  desired.Credential,
+					})
+				},
+			})
+
+			continue
+		}
+
+		var d diffs
+		d.add("url", current.URL, desired.URL)
+		d.add("description", current.Description, desired.Description)
+		d.add("insecure", current.Insecure, desired.Insecure)
+
+		if desired.Credential != nil {
+			var currentCred model.RegistryCredential
+			if current.Credential != nil {
+				currentCred = *current.Credential
+			}
+
+			d.add("credential.type", currentCred.Type, desired.Credential.Type)
+			d.add("credential.access_key", currentCred.AccessKey, desired.Credential.AccessKey)
+		}
+
+		if len(d) == 0 {
+			continue
+		}
+
+		id := current.ID
+
+		plan.add(&Change{
+			Kind:      KindRegistry,
+			Operation: OperationUpdate,
+			Name:      desired.Name,
+			Diffs:     d,
+			apply: func(ctx context.Context) error {
+				update := &model.RegistryUpdate{
+					Name:        &desired.Name,
+					URL:         &desired.URL,
+					Description: &desired.Description,
+					Insecure:    &desired.Insecure,
+				}
+
+				if desired.Credential != nil {
+					update.CredentialType = &desired.Credential.Type
+					update.AccessKey = &desired.Credential.AccessKey
+					update.AccessSecret = &desired.Credential.AccessSecret
+				}
+
+				return r.Client.UpdateRegistry(ctx, update, id)
+			},
+		})
+	}
+
+	for _, name := range sortedKeys(byName) {
+		id := byName[name].ID
+
+		plan.addUnmanaged(&Change{
+			Kind:      KindRegistry,
+			Operation: OperationDelete,
+			Name:      name,
+			apply: func(ctx context.Context) error {
+				return r.Client.DeleteRegistryByID(ctx, id)
+			},
+		}, r.Prune)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) planGlobalLabels(ctx context.Context, spec *Spec, plan *Plan) error {
+	if spec.Labels == nil {
+		return nil
+	}
+
+	live, err := r.Client.ListLabels(ctx, "", nil, label.ScopeGlobal)
+	if err != nil {
+		return fmt.Errorf("listing global labels: %w", err)
+	}
+
+	r.planLabels(plan, "", spec.Labels, live, nil)
+
+	return nil
+}
+
+// planLabels plans the changes of global labels ('projectName' is empty) or project labels.
+// 'projectID' resolves the ID of the label's project when applying changes.
+func (r *Reconciler) planLabels(plan *Plan, projectName string, desired []LabelSpec, live []*model.Label,
+	projectID func(ctx context.Context) (int64, error),
+) {
+	byName := map[string]*model.Label{}
+	for _, l := range live {
+		byName[l.Name] = l
+	}
+
+	kind, scope := KindLabel, label.ScopeGlobal
+	if projectName != "" {
+		kind, scope = KindProjectLabel, label.ScopeProject
+	}
+
+	newLabel := func(ctx context.Context, spec LabelSpec) (*model.Label, error) {
+		l := &model.Label{
+			Name:        spec.Name,
+			Color:       spec.Color,
+			Description: spec.Description,
+			Scope:       scope.String(),
+		}
+
+		if projectID != nil {
+			id, err := projectID(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			l.ProjectID = id
+		}
+
+		return l, nil
+	}
+
+	for i := range desired {
+		spec := desired[i]
+		current, ok := byName[spec.Name]
+		delete(byName, spec.Name)
+
+		if !ok {
+			plan.add(&Change{
+				Kind:      kind,
+				Operation: OperationCreate,
+				Project:   projectName,
+				Name:      spec.Name,
+				apply: func(ctx context.Context) error {
+					l, err := newLabel(ctx, spec)
+					if err != nil {
+						return err
+					}
+
+					return r.Client.CreateLabel(ctx, l)
+				},
+			})
+
+			continue
+		}
+
+		var d diffs
+		d.add("color", current.Color, spec.Color)
+		d.add("description", current.Description, spec.Description)
+
+		if len(d) == 0 {
+			continue
+		}
+
+		id := current.ID
+
+		plan.add(&Change{
+			Kind:      kind,
+			Operation: OperationUpdate,
+			Project:   projectName,
+			Name:      spec.Name,
+			Diffs:     d,
+			apply: func(ctx context.Context) error {
+				l, err := newLabel(ctx, spec)
+				if err != nil {
+					return err
+				}
+
+				l.ID = id
+
+				return r.Client.UpdateLabel(ctx, id, l)
+			},
+		})
+	}
+
+	for _, name := range sortedKeys(byName) {
+		id := byName[name].ID
+
+		plan.addUnmanaged(&Change{
+			Kind:      kind,
+			Operation: OperationDelete,
+			Project:   projectName,
+			Name:      name,
+			apply: func(ctx context.Context) error {
+				return r.Client.DeleteLabel(ctx, id)
+			},
+		}, r.Prune)
+	}
+}
+
+func (r *Reconciler) planReplicationPolicies(ctx context.Context, spec *Spec, plan *Plan) error {
+	if spec.ReplicationPolicies == nil {
+		return nil
+	}
+
+	live, err := r.Client.ListReplicationPolicies(ctx)
+	if err != nil {
+		return fmt.Errorf("listing replication policies: %w", err)
+	}
+
+	byName := map[string]*model.ReplicationPolicy{}
+	for _, p := range live {
+		byName[p.Name] = p
+	}
+
+	for i := range spec.ReplicationPolicies {
+		desired := spec.ReplicationPolicies[i]
+		current, ok := byName[desired.Name]
+		delete(byName, desired.Name)
+
+		if !ok {
+			plan.add(&Change{
+				Kind:      KindReplicationPolicy,
+				Operation: OperationCreate,
+				Name:      desired.Name,
+				apply: func(ctx context.Context) error {
+					src, dest, err := r.resolveRegistries(ctx, desired)
+					if err != nil {
+						return err
+					}
+
+					return r.Client.NewReplicationPolicy(ctx, dest, src,
+						desired.ReplicateDeletion, desired.Override, desired.Enabled,
+						desired.Filters, desired.Trigger,
+						desired.DestNamespace, desired.Description, desired.Name)
+				},
+			})
+
+			continue
+		}
+
+		var d diffs
+		d.add("description", current.Description, desired.Description)
+		d.add("src_registry", registryName(current.SrcRegistry), desired.SrcRegistry)
+		d.add("dest_registry", registryName(current.DestRegistry), desired.DestRegistry)
+		d.add("dest_namespace", current.DestNamespace, desired.DestNamespace)
+		d.add("enabled", current.Enabled, desired.Enabled)
+		d.add("override", current.Override, desired.Override)
+		d.add("replicate_deletion", current.ReplicateDeletion, desired.ReplicateDeletion)
+		d.add("filters", canonical(current.Filters), canonical(desired.Filters))
+		d.add("trigger", canonical(current.Trigger), canonical(desired.Trigger))
+
+		if len(d) == 0 {
+			continue
+		}
+
+		id := current.ID
+
+		plan.add(&Change{
+			Kind:      KindReplicationPolicy,
+			Operation: OperationUpdate,
+			Name:      desired.Name,
+			Diffs:     d,
+			apply: func(ctx context.Context) error {
+				src, dest, err := r.resolveRegistries(ctx, desired)
+				if err != nil {
+					return err
+				}
+
+				return r.Client.UpdateReplicationPolicy(ctx, &model.ReplicationPolicy{
+					ID:                id,
+					Name:              desired.Name,
+					Description:       desired.Description,
+					SrcRegistry:       src,
+					DestRegistry:      dest,
+					DestNamespace:     desired.DestNamespace,
+					Enabled:           desired.Enabled,
+					Override:          desired.Override,
+					ReplicateDeletion: desired.ReplicateDeletion,
+					Deletion:          desired.ReplicateDeletion,
+					Filters:           desired.Filters,
+					Trigger:           desired.Trigger,
+				}, id)
+			},
+		})
+	}
+
+	for _, name := range sortedKeys(byName) {
+		id := byName[name].ID
+
+		plan.addUnmanaged(&Change{
+			Kind:      KindReplicationPolicy,
+			Operation: OperationDelete,
+			Name:      name,
+			apply: func(ctx context.Context) error {
+				return r.Client.DeleteReplicationPolicyByID(ctx, id)
+			},
+		}, r.Prune)
+	}
+
+	return nil
+}
+
+// resolveRegistries looks up the source and destination registries of a replication policy.
+// The local Harbor instance is represented by a nil registry.
+func (r *Reconciler) resolveRegistries(ctx context.Context, spec ReplicationPolicySpec) (src, dest *model.Registry, err error) {
+	if spec.SrcRegistry != "" {
+		if src, err = r.Client.GetRegistryByName(ctx, spec.SrcRegistry); err != nil {
+			return nil, nil, fmt.Errorf("resolving source registry %q: %w", spec.SrcRegistry, err)
+		}
+	}
+
+	if spec.DestRegistry != "" {
+		if dest, err = r.Client.GetRegistryByName(ctx, spec.DestRegistry); err != nil {
+			return nil, nil, fmt.Errorf("resolving destination registry %q: %w", spec.DestRegistry, err)
+		}
+	}
+
+	return src, dest, nil
+}
+
+// registryName returns the name of a replication policy's registry,
+// or an empty string for the local Harbor instance.
+func registryName(reg *model.Registry) string {
+	if reg == nil || reg.ID == 0 {
+		return ""
+	}
+
+	return reg.Name
+}
+
+// canonical returns a JSON representation of 'v', used to compare nested values.
+// Empty slices and maps are considered equal to nil.
+func canonical(v interface{}) string {
+	if rv := reflect.ValueOf(v); rv.IsValid() {
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Map:
+			if rv.Len() == 0 {
+				return "null"
+			}
+		}
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(b)
+}
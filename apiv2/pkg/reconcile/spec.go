@@ -0,0 +1,267 @@
+package reconcile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/member"
+)
+
+// Spec describes the desired state of a Harbor instance.
+// Resources not listed in the spec are left untouched, unless pruning is enabled.
+// Like the resource lists of ProjectSpec, lists that are nil are not managed and never pruned,
+// while empty lists declare that no such resources should exist.
+type Spec struct {
+	// Registries are the registry endpoints used by replication policies.
+	Registries []RegistrySpec `json:"registries,omitempty"`
+	// Labels are global (system-wide) labels.
+	Labels []LabelSpec `json:"labels,omitempty"`
+	// Projects and their project-scoped resources.
+	Projects []ProjectSpec `json:"projects,omitempty"`
+	// ReplicationPolicies referencing the registries by name.
+	ReplicationPolicies []ReplicationPolicySpec `json:"replicationPolicies,omitempty"`
+}
+
+// RegistrySpec describes a registry endpoint.
+type RegistrySpec struct {
+	Name string `json:"name"`
+	// Type of the registry, e.g. "docker-hub". It is only used when creating the registry,
+	// as Harbor does not allow changing the type of an existing one.
+	Type        string `json:"type"`
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+	Insecure    bool   `json:"insecure,omitempty"`
+	// Credential used to access the registry.
+	// As Harbor never returns secrets, a changed AccessSecret alone is not detected as drift.
+	Credential *model.RegistryCredential `json:"credential,omitempty"`
+}
+
+// LabelSpec describes a global or project label.
+type LabelSpec struct {
+	Name        string `json:"name"`
+	Color       string `json:"color,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ProjectSpec describes a project and its project-scoped resources.
+// Resource lists that are nil are not managed for the project,
+// while empty lists declare that no such resources should exist.
+type ProjectSpec struct {
+	Name string `json:"name"`
+	// Metadata contains project metadata values, keyed by common.MetadataKey, e.g. "public": "true".
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// StorageLimit is the project's storage quota in bytes, -1 means unlimited.
+	StorageLimit *int64 `json:"storageLimit,omitempty"`
+
+	Members        []MemberSpec           `json:"members,omitempty"`
+	Robots         []RobotSpec            `json:"robots,omitempty"`
+	Labels         []LabelSpec            `json:"labels,omitempty"`
+	ImmutableRules []*model.ImmutableRule `json:"immutableRules,omitempty"`
+	// Retention is the project's tag retention policy. Its scope is set automatically.
+	Retention *model.RetentionPolicy `json:"retention,omitempty"`
+	Webhooks  []*model.WebhookPolicy `json:"webhooks,omitempty"`
+}
+
+// MemberSpec describes a project member, which is either a user or a group.
+type MemberSpec struct {
+	User  string `json:"user,omitempty"`
+	Group string `json:"group,omitempty"`
+	// Role is the name of the member's role, e.g. "developer" or "projectAdmin".
+	Role string `json:"role"`
+}
+
+// RobotSpec describes a project-level robot account.
+type RobotSpec struct {
+	// Name of the robot account, without the 'robot$<project>+' prefix.
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Duration in days, -1 means the robot account never expires.
+	Duration int64        `json:"duration,omitempty"`
+	Disable  bool         `json:"disable,omitempty"`
+	Access   []AccessSpec `json:"access"`
+}
+
+// AccessSpec describes a single permission of a robot account, e.g. "repository" / "pull".
+type AccessSpec struct {
+	Resource string `json:"resource"`
+	Action   string `json:"action"`
+}
+
+// ReplicationPolicySpec describes a replication policy.
+// Registries are referenced by name, an empty name refers to the local Harbor instance.
+type ReplicationPolicySpec struct {
+	Name              string                     `json:"name"`
+	Description       string                     `json:"description,omitempty"`
+	SrcRegistry       string                     `json:"srcRegistry,omitempty"`
+	DestRegistry      string                     `json:"destRegistry,omitempty"`
+	DestNamespace     string                     `json:"destNamespace,omitempty"`
+	Enabled           bool                       `json:"enabled"`
+	Override          bool                       `json:"override,omitempty"`
+	ReplicateDeletion bool                       `json:"replicateDeletion,omitempty"`
+	Filters           []*model.ReplicationFilter `json:"filters,omitempty"`
+	Trigger           *model.ReplicationTrigger  `json:"trigger,omitempty"`
+}
+
+// Decode reads a Spec from 'r', which may contain either YAML or JSON.
+// Field names are the JSON names in both cases.
+func Decode(r io.Reader) (*Spec, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// YAML is decoded into generic values first, which are then re-encoded as JSON.
+	// This way the JSON field names of the embedded model types apply to YAML as well.
+	var generic interface{}
+	if err := yaml.Unmarshal(raw, &generic); err != nil {
+		return nil, fmt.Errorf("decoding spec: %w", err)
+	}
+
+	js, err := json.Marshal(generic)
+	if err != nil {
+		return nil, fmt.Errorf("decoding spec: %w", err)
+	}
+
+	spec := &Spec{}
+
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(spec); err != nil {
+		return nil, fmt.Errorf("decoding spec: %w", err)
+	}
+
+	return spec, spec.Validate()
+}
+
+// DecodeFile reads a Spec from the YAML or JSON file at 'path'.
+func DecodeFile(path string) (*Spec, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Decode(f)
+}
+
+// Validate checks the spec for missing names, duplicates and invalid member roles.
+func (s *Spec) Validate() error {
+	registries := map[string]bool{}
+
+	for _, r := range s.Registries {
+		if r.Name == "" {
+			return fmt.Errorf("registry without name")
+		}
+
+		if registries[r.Name] {
+			return fmt.Errorf("duplicate registry %q", r.Name)
+		}
+
+		registries[r.Name] = true
+	}
+
+	if err := validateLabels("global", s.Labels); err != nil {
+		return err
+	}
+
+	projects := map[string]bool{}
+
+	for _, p := range s.Projects {
+		if p.Name == "" {
+			return fmt.Errorf("project without name")
+		}
+
+		if projects[p.Name] {
+			return fmt.Errorf("duplicate project %q", p.Name)
+		}
+
+		projects[p.Name] = true
+
+		if err := validateLabels("project "+p.Name, p.Labels); err != nil {
+			return err
+		}
+
+		for _, m := range p.Members {
+			if (m.User == "") == (m.Group == "") {
+				return fmt.Errorf("project %q: member must have either a user or a group", p.Name)
+			}
+
+			if _, err := member.ParseRole(m.Role); err != nil {
+				return fmt.Errorf("project %q: %w", p.Name, err)
+			}
+		}
+
+		robots := map[string]bool{}
+
+		for _, r := range p.Robots {
+			if r.Name == "" {
+				return fmt.Errorf("project %q: robot without name", p.Name)
+			}
+
+			if robots[r.Name] {
+				return fmt.Errorf("project %q: duplicate robot %q", p.Name, r.Name)
+			}
+
+			robots[r.Name] = true
+		}
+
+		webhooks := map[string]bool{}
+
+		for _, w := range p.Webhooks {
+			if w == nil || w.Name == "" {
+				return fmt.Errorf("project %q: webhook without name", p.Name)
+			}
+
+			if webhooks[w.Name] {
+				return fmt.Errorf("project %q: duplicate webhook %q", p.Name, w.Name)
+			}
+
+			webhooks[w.Name] = true
+		}
+	}
+
+	policies := map[string]bool{}
+
+	for _, rp := range s.ReplicationPolicies {
+		if rp.Name == "" {
+			return fmt.Errorf("replication policy without name")
+		}
+
+		if policies[rp.Name] {
+			return fmt.Errorf("duplicate replication policy %q", rp.Name)
+		}
+
+		policies[rp.Name] = true
+
+		if rp.SrcRegistry == "" && rp.DestRegistry == "" {
+			return fmt.Errorf("replication policy %q: either source or destination registry must be set", rp.Name)
+		}
+	}
+
+	return nil
+}
+
+func validateLabels(scope string, labels []LabelSpec) error {
+	names := map[string]bool{}
+
+	for _, l := range labels {
+		if l.Name == "" {
+			return fmt.Errorf("%s: label without name", scope)
+		}
+
+		if names[l.Name] {
+			return fmt.Errorf("%s: duplicate label %q", scope, l.Name)
+		}
+
+		names[l.Name] = true
+	}
+
+	return nil
+}
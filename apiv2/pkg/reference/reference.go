@@ -0,0 +1,198 @@
+// Package reference parses, validates and formats references to Harbor repositories and artifacts,
+// e.g. "harbor.example.com/library/team/app:v1.2.3" or "library/app@sha256:<digest>".
+//
+// Repository names may be nested, i.e. contain slashes. Harbor requires such names to be URL encoded twice
+// in request paths; EscapeRepositoryName performs the encoding, which the swagger client does not apply a second time.
+package reference
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+const (
+	// DefaultTag is the tag referenced if neither tag nor digest is set.
+	DefaultTag = "latest"
+
+	// maxNameLength is the maximum length of the project and repository name, as enforced by Harbor.
+	maxNameLength = 255
+)
+
+var (
+	// componentPattern matches a single path component of a project or repository name,
+	// as defined by the distribution specification.
+	componentPattern = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*$`)
+
+	tagPattern    = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+	digestPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,}$`)
+)
+
+// Reference identifies a repository, or an artifact in a repository by its tag or digest.
+type Reference struct {
+	// Registry is the host (and port) of the registry, e.g. "harbor.example.com". It is optional.
+	Registry string
+
+	// Project is the name of the Harbor project.
+	Project string
+
+	// Repository is the name of the repository inside the project, which may contain slashes, e.g. "team/app".
+	Repository string
+
+	// Tag and Digest identify an artifact in the repository. Both are optional.
+	Tag    string
+	Digest string
+}
+
+// Parse parses 's' in the form "[registry/]project/repository[:tag][@digest]" and validates the result.
+// The first path component is considered to be the registry if it contains a '.' or ':', or is "localhost".
+func Parse(s string) (*Reference, error) {
+	ref := &Reference{}
+	rest := s
+
+	if i := strings.LastIndex(rest, "@"); i >= 0 {
+		if ref.Digest = rest[i+1:]; ref.Digest == "" {
+			return nil, invalid(s, "digest must not be empty")
+		}
+
+		rest = rest[:i]
+	}
+
+	// A colon after the last slash separates the tag, other colons belong to the registry's port.
+	if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+		if ref.Tag = rest[i+1:]; ref.Tag == "" {
+			return nil, invalid(s, "tag must not be empty")
+		}
+
+		rest = rest[:i]
+	}
+
+	components := strings.Split(rest, "/")
+	if len(components) > 2 && isRegistry(components[0]) {
+		ref.Registry = components[0]
+		components = components[1:]
+	}
+
+	if len(components) < 2 {
+		return nil, invalid(s, "expected project and repository name")
+	}
+
+	ref.Project = components[0]
+	ref.Repository = strings.Join(components[1:], "/")
+
+	if err := ref.validate(s); err != nil {
+		return nil, err
+	}
+
+	return ref, nil
+}
+
+// MustParse is like Parse but panics if 's' is invalid. It simplifies the initialization of variables.
+func MustParse(s string) *Reference {
+	ref, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return ref
+}
+
+func isRegistry(s string) bool {
+	return strings.ContainsAny(s, ".:") || s == "localhost"
+}
+
+// Validate reports whether the reference is valid, returning an ErrInvalidReference if not, or if 'r' is nil.
+func (r *Reference) Validate() error {
+	if r == nil {
+		return invalid("", "no reference provided")
+	}
+
+	return r.validate(r.String())
+}
+
+func (r *Reference) validate(s string) error {
+	switch {
+	case r.Project == "":
+		return invalid(s, "project name must not be empty")
+	case !componentPattern.MatchString(r.Project):
+		return invalid(s, "invalid project name "+r.Project)
+	case r.Repository == "":
+		return invalid(s, "repository name must not be empty")
+	case len(r.Name()) > maxNameLength:
+		return invalid(s, "repository name is too long")
+	case r.Tag != "" && !tagPattern.MatchString(r.Tag):
+		return invalid(s, "invalid tag "+r.Tag)
+	case r.Digest != "" && !digestPattern.MatchString(r.Digest):
+		return invalid(s, "invalid digest "+r.Digest)
+	}
+
+	for _, c := range strings.Split(r.Repository, "/") {
+		if !componentPattern.MatchString(c) {
+			return invalid(s, "invalid repository name "+r.Repository)
+		}
+	}
+
+	return nil
+}
+
+func invalid(s, reason string) error {
+	return &errors.ErrInvalidReference{Reference: s, Reason: reason}
+}
+
+// Name returns the full name of the repository, including the project, e.g. "library/team/app".
+func (r *Reference) Name() string {
+	return r.Project + "/" + r.Repository
+}
+
+// ArtifactReference returns the reference identifying the artifact in the Harbor API:
+// the digest if set, otherwise the tag or DefaultTag.
+func (r *Reference) ArtifactReference() string {
+	switch {
+	case r.Digest != "":
+		return r.Digest
+	case r.Tag != "":
+		return r.Tag
+	default:
+		return DefaultTag
+	}
+}
+
+// EscapedRepository returns the repository name escaped using EscapeRepositoryName.
+func (r *Reference) EscapedRepository() string {
+	return EscapeRepositoryName(r.Repository)
+}
+
+// String formats the reference as "[registry/]project/repository[:tag][@digest]".
+func (r *Reference) String() string {
+	var b strings.Builder
+
+	if r.Registry != "" {
+		b.WriteString(r.Registry + "/")
+	}
+
+	b.WriteString(r.Name())
+
+	if r.Tag != "" {
+		b.WriteString(":" + r.Tag)
+	}
+
+	if r.Digest != "" {
+		b.WriteString("@" + r.Digest)
+	}
+
+	return b.String()
+}
+
+// EscapeRepositoryName URL encodes the repository name 'name' once, so that it is encoded twice
+// after the swagger client encoded the path, e.g. "team/app" is sent as "team%252Fapp".
+// Names which contain a '%' are considered to be escaped already and are returned unchanged,
+// as '%' is not allowed in repository names.
+func EscapeRepositoryName(name string) string {
+	if strings.Contains(name, "%") {
+		return name
+	}
+
+	return url.PathEscape(name)
+}
@@ -0,0 +1,81 @@
+//go:build !integration
+
+package reference
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+const digest = "sha256:4c3ec7f0b3d8e4b6cf2d2e1c0a9b8f7e6d5c4b3a2918f7e6d5c4b3a291807f6e"
+
+func TestParse(t *testing.T) {
+	for in, want := range map[string]Reference{
+		"library/app":                      {Project: "library", Repository: "app"},
+		"library/app:v1.2.3":               {Project: "library", Repository: "app", Tag: "v1.2.3"},
+		"library/team/app/api":             {Project: "library", Repository: "team/app/api"},
+		"library/app@" + digest:            {Project: "library", Repository: "app", Digest: digest},
+		"library/app:v1@" + digest:         {Project: "library", Repository: "app", Tag: "v1", Digest: digest},
+		"harbor.example.com/library/app":   {Registry: "harbor.example.com", Project: "library", Repository: "app"},
+		"localhost/library/app:latest":     {Registry: "localhost", Project: "library", Repository: "app", Tag: "latest"},
+		"harbor:8443/library/team/app:v1":  {Registry: "harbor:8443", Project: "library", Repository: "team/app", Tag: "v1"},
+		"harbor.example.com/library/a/b_c": {Registry: "harbor.example.com", Project: "library", Repository: "a/b_c"},
+		"team.one/app":                     {Project: "team.one", Repository: "app"},
+	} {
+		got, err := Parse(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, *got, in)
+		require.Equal(t, in, got.String())
+	}
+
+	for _, in := range []string{
+		"",
+		"app",
+		"library/",
+		"/app",
+		"Library/app",
+		"library/App",
+		"library/team//app",
+		"library/app:",
+		"library/app@",
+		"library/app:-v1",
+		"library/app@sha256:abc",
+		"library/app@" + digest[7:],
+		"library/" + strings.Repeat("a", 250),
+	} {
+		_, err := Parse(in)
+		require.ErrorIs(t, err, &clienterrors.ErrInvalidReference{}, in)
+	}
+}
+
+func TestMustParse(t *testing.T) {
+	require.Equal(t, "library/app", MustParse("library/app").Name())
+	require.Panics(t, func() { MustParse("app") })
+}
+
+func TestReference_Validate(t *testing.T) {
+	var ref *Reference
+	require.ErrorIs(t, ref.Validate(), &clienterrors.ErrInvalidReference{})
+
+	require.NoError(t, (&Reference{Project: "library", Repository: "team/app"}).Validate())
+	require.Error(t, (&Reference{Repository: "app"}).Validate())
+	require.Error(t, (&Reference{Project: "library"}).Validate())
+	require.Error(t, (&Reference{Project: "library", Repository: "app", Tag: "a b"}).Validate())
+}
+
+func TestReference_ArtifactReference(t *testing.T) {
+	require.Equal(t, DefaultTag, MustParse("library/app").ArtifactReference())
+	require.Equal(t, "v1", MustParse("library/app:v1").ArtifactReference())
+	require.Equal(t, digest, MustParse("library/app:v1@"+digest).ArtifactReference())
+}
+
+func TestEscapeRepositoryName(t *testing.T) {
+	require.Equal(t, "app", EscapeRepositoryName("app"))
+	require.Equal(t, "team%2Fapp%2Fapi", EscapeRepositoryName("team/app/api"))
+	require.Equal(t, "team%2Fapp", EscapeRepositoryName("team%2Fapp"))
+	require.Equal(t, "team%2Fapp", MustParse("library/team/app:v1").EscapedRepository())
+}
@@ -0,0 +1,130 @@
+package retry
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+// State describes the state of a CircuitBreaker.
+type State int
+
+const (
+	// StateClosed lets all requests pass.
+	StateClosed State = iota
+	// StateOpen rejects all requests until the cooldown has passed.
+	StateOpen
+	// StateHalfOpen lets a single probe request pass to determine whether the API has recovered.
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker opens after a number of consecutive failed requests, rejecting further requests
+// with errors.ErrCircuitOpen until its cooldown has passed. A CircuitBreaker is safe for concurrent use
+// and may be shared between multiple clients talking to the same Harbor instance.
+type CircuitBreaker struct {
+	failureThreshold int
+	cooldown         time.Duration
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+
+	now func() time.Time
+}
+
+// NewCircuitBreaker returns a CircuitBreaker that opens after 'failureThreshold'
+// consecutive failures and stays open for 'cooldown'.
+func NewCircuitBreaker(failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		now:              time.Now,
+	}
+}
+
+// State returns the current state of the circuit breaker.
+func (cb *CircuitBreaker) State() State {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == StateOpen && cb.now().Sub(cb.openedAt) >= cb.cooldown {
+		return StateHalfOpen
+	}
+
+	return cb.state
+}
+
+// allow returns errors.ErrCircuitOpen if a request must not be sent.
+func (cb *CircuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == StateOpen {
+		if cb.now().Sub(cb.openedAt) < cb.cooldown {
+			return &errors.ErrCircuitOpen{}
+		}
+
+		cb.state = StateHalfOpen
+	}
+
+	if cb.state == StateHalfOpen {
+		if cb.probing {
+			return &errors.ErrCircuitOpen{}
+		}
+
+		cb.probing = true
+	}
+
+	return nil
+}
+
+// record updates the circuit breaker with the outcome of a request.
+func (cb *CircuitBreaker) record(success bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.probing = false
+
+	if success {
+		cb.state = StateClosed
+		cb.failures = 0
+
+		return
+	}
+
+	cb.failures++
+
+	if cb.state == StateHalfOpen || cb.failures >= cb.failureThreshold {
+		cb.state = StateOpen
+		cb.openedAt = cb.now()
+	}
+}
+
+// release ends a probe request without affecting the circuit breaker's state,
+// e.g. if the request was canceled by the caller.
+func (cb *CircuitBreaker) release() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.probing = false
+}
@@ -0,0 +1,133 @@
+// Package retry provides an http.RoundTripper that retries transient Harbor API failures
+// using exponential backoff with jitter, and an optional circuit breaker that fails fast
+// while the Harbor API is unavailable.
+package retry
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Policy defines when and how often failed requests are retried.
+type Policy struct {
+	// MaxRetries is the maximum number of retries after the initial attempt.
+	MaxRetries int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts. It does not cap delays requested via 'Retry-After'.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the backoff grows by after each retry.
+	Multiplier float64
+	// Jitter is the fraction (0 to 1) of each backoff that is randomized.
+	Jitter float64
+	// RetryNonIdempotent enables retries for non-idempotent methods, such as POST and PATCH.
+	RetryNonIdempotent bool
+	// RetryableStatusCodes are the HTTP status codes of responses that are retried.
+	RetryableStatusCodes []int
+}
+
+// DefaultPolicy returns a policy retrying idempotent requests up to 3 times
+// on 429, 502, 503 and 504 responses as well as on transient network errors.
+func DefaultPolicy() *Policy {
+	return &Policy{
+		MaxRetries:     3,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+func (p *Policy) WithMaxRetries(maxRetries int) *Policy {
+	p.MaxRetries = maxRetries
+	return p
+}
+
+func (p *Policy) WithBackoff(initial, max time.Duration) *Policy {
+	p.InitialBackoff = initial
+	p.MaxBackoff = max
+	return p
+}
+
+func (p *Policy) WithRetryNonIdempotent(retry bool) *Policy {
+	p.RetryNonIdempotent = retry
+	return p
+}
+
+// retryableMethod returns true if requests using 'method' may be retried.
+func (p *Policy) retryableMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return p.RetryNonIdempotent
+	}
+}
+
+// retryableStatus returns true if responses with status code 'code' are retried.
+func (p *Policy) retryableStatus(code int) bool {
+	for _, c := range p.RetryableStatusCodes {
+		if c == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// backoff returns the delay before retry number 'attempt', starting at 0.
+func (p *Policy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	d := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		d = d*(1-jitter) + d*jitter*rand.Float64()
+	}
+
+	return time.Duration(d)
+}
+
+// retryAfter parses the 'Retry-After' header of 'resp', which may either contain
+// a number of seconds or an HTTP date. It returns false if the header is absent or invalid.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+
+	return 0, true
+}
@@ -0,0 +1,172 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// maxDrainBytes limits how much of a discarded response body is read to allow connection reuse.
+const maxDrainBytes = 4 << 10
+
+// Transport is an http.RoundTripper retrying requests according to its Policy
+// and guarding them with an optional CircuitBreaker.
+type Transport struct {
+	// Base is the underlying transport. http.DefaultTransport is used if nil.
+	Base http.RoundTripper
+	// Policy defines the retry behaviour. Requests are not retried if nil.
+	Policy *Policy
+	// Breaker is an optional circuit breaker.
+	Breaker *CircuitBreaker
+}
+
+// NewTransport wraps 'base' with the provided retry policy and circuit breaker, both of which may be nil.
+func NewTransport(base http.RoundTripper, policy *Policy, breaker *CircuitBreaker) *Transport {
+	return &Transport{
+		Base:    base,
+		Policy:  policy,
+		Breaker: breaker,
+	}
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+
+	return http.DefaultTransport
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			var err error
+			if req, err = rewind(req); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := t.roundTrip(req)
+
+		if !t.shouldRetry(req, resp, err, attempt) {
+			return resp, err
+		}
+
+		delay := t.Policy.backoff(attempt)
+		if resp != nil {
+			if d, ok := retryAfter(resp, time.Now()); ok && d > delay {
+				delay = d
+			}
+		}
+
+		// Hand the last response to the caller if the delay exceeds its deadline.
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, err
+		}
+
+		if resp != nil {
+			drain(resp)
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// roundTrip sends a single request, consulting and updating the circuit breaker.
+func (t *Transport) roundTrip(req *http.Request) (*http.Response, error) {
+	if t.Breaker == nil {
+		return t.base().RoundTrip(req)
+	}
+
+	if err := t.Breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base().RoundTrip(req)
+
+	switch {
+	case err != nil && req.Context().Err() != nil:
+		t.Breaker.release()
+	case err != nil:
+		t.Breaker.record(false)
+	default:
+		t.Breaker.record(resp.StatusCode < http.StatusInternalServerError)
+	}
+
+	return resp, err
+}
+
+func (t *Transport) shouldRetry(req *http.Request, resp *http.Response, err error, attempt int) bool {
+	if t.Policy == nil || attempt >= t.Policy.MaxRetries || req.Context().Err() != nil {
+		return false
+	}
+
+	if !t.Policy.retryableMethod(req.Method) {
+		return false
+	}
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		return transientError(err)
+	}
+
+	return t.Policy.retryableStatus(resp.StatusCode)
+}
+
+// transientError returns true for network errors that are likely to succeed when retried.
+func transientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// rewind returns a copy of 'req' with a fresh body, so that it can be sent again.
+func rewind(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+
+	if req.GetBody == nil || req.Body == nil || req.Body == http.NoBody {
+		return r, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	r.Body = body
+
+	return r, nil
+}
+
+// drain discards and closes the body of a response that will not be returned to the caller.
+func drain(resp *http.Response) {
+	_, _ = io.CopyN(io.Discard, resp.Body, maxDrainBytes)
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,267 @@
+//go:build !integration
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+func testPolicy() *Policy {
+	return DefaultPolicy().WithBackoff(time.Millisecond, 5*time.Millisecond)
+}
+
+// flakyServer responds with 'status' to the first 'failures' requests and with 200 afterwards.
+func flakyServer(t *testing.T, failures int32, status int, header http.Header) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var calls int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+
+		body, _ := io.ReadAll(r.Body)
+
+		if n <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+
+			w.WriteHeader(status)
+
+			return
+		}
+
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &calls
+}
+
+func do(t *testing.T, rt http.RoundTripper, method, url, body string) (*http.Response, error) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+
+	return (&http.Client{Transport: rt}).Do(req)
+}
+
+func TestTransport_RetriesIdempotentRequests(t *testing.T) {
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		t.Run(method, func(t *testing.T) {
+			srv, calls := flakyServer(t, 2, http.StatusServiceUnavailable, nil)
+
+			resp, err := do(t, NewTransport(nil, testPolicy(), nil), method, srv.URL, "payload")
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Equal(t, int32(3), atomic.LoadInt32(calls))
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, "payload", string(body))
+		})
+	}
+}
+
+func TestTransport_GivesUpAfterMaxRetries(t *testing.T) {
+	srv, calls := flakyServer(t, 10, http.StatusBadGateway, nil)
+
+	resp, err := do(t, NewTransport(nil, testPolicy().WithMaxRetries(2), nil), http.MethodGet, srv.URL, "")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	require.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+func TestTransport_NonIdempotentRequests(t *testing.T) {
+	srv, calls := flakyServer(t, 1, http.StatusServiceUnavailable, nil)
+
+	resp, err := do(t, NewTransport(nil, testPolicy(), nil), http.MethodPost, srv.URL, "payload")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.Equal(t, int32(1), atomic.LoadInt32(calls))
+
+	srv, calls = flakyServer(t, 1, http.StatusServiceUnavailable, nil)
+
+	resp, err = do(t, NewTransport(nil, testPolicy().WithRetryNonIdempotent(true), nil), http.MethodPost, srv.URL, "payload")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, int32(2), atomic.LoadInt32(calls))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "payload", string(body))
+}
+
+func TestTransport_DoesNotRetryClientErrors(t *testing.T) {
+	srv, calls := flakyServer(t, 1, http.StatusNotFound, nil)
+
+	resp, err := do(t, NewTransport(nil, testPolicy(), nil), http.MethodGet, srv.URL, "")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	require.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestTransport_RetryAfter(t *testing.T) {
+	srv, calls := flakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}})
+
+	start := time.Now()
+
+	resp, err := do(t, NewTransport(nil, testPolicy(), nil), http.MethodGet, srv.URL, "")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, int32(2), atomic.LoadInt32(calls))
+	require.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestTransport_RetryAfterExceedsDeadline(t *testing.T) {
+	srv, calls := flakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"60"}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := NewTransport(nil, testPolicy(), nil).RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	require.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestTransport_RetriesConnectionErrors(t *testing.T) {
+	var calls int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			conn.Close()
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	resp, err := do(t, NewTransport(nil, testPolicy(), nil), http.MethodGet, srv.URL, "")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestTransport_CircuitBreaker(t *testing.T) {
+	srv, calls := flakyServer(t, 3, http.StatusServiceUnavailable, nil)
+
+	now := time.Now()
+
+	breaker := NewCircuitBreaker(3, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	rt := NewTransport(nil, nil, breaker)
+
+	for i := 0; i < 3; i++ {
+		resp, err := do(t, rt, http.MethodGet, srv.URL, "")
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	require.Equal(t, StateOpen, breaker.State())
+
+	_, err := do(t, rt, http.MethodGet, srv.URL, "")
+
+	var circuitOpen *clienterrors.ErrCircuitOpen
+	require.True(t, errors.As(err, &circuitOpen))
+	require.Equal(t, int32(3), atomic.LoadInt32(calls))
+
+	now = now.Add(time.Minute)
+	require.Equal(t, StateHalfOpen, breaker.State())
+
+	resp, err := do(t, rt, http.MethodGet, srv.URL, "")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, StateClosed, breaker.State())
+}
+
+func TestCircuitBreaker_FailedProbeReopens(t *testing.T) {
+	now := time.Now()
+
+	breaker := NewCircuitBreaker(1, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	require.NoError(t, breaker.allow())
+	breaker.record(false)
+	require.Error(t, breaker.allow())
+
+	now = now.Add(time.Minute)
+
+	// Only a single probe is let through while half-open.
+	require.NoError(t, breaker.allow())
+	require.Error(t, breaker.allow())
+
+	breaker.record(false)
+	require.Equal(t, StateOpen, breaker.State())
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for header, expected := range map[string]time.Duration{
+		"5":                             5 * time.Second,
+		"Sun, 01 Jan 2023 00:00:30 GMT": 30 * time.Second,
+		"Sat, 31 Dec 2022 23:59:00 GMT": 0,
+	} {
+		d, ok := retryAfter(&http.Response{Header: http.Header{"Retry-After": {header}}}, now)
+		require.True(t, ok, header)
+		require.Equal(t, expected, d, header)
+	}
+
+	_, ok := retryAfter(&http.Response{Header: http.Header{"Retry-After": {"soon"}}}, now)
+	require.False(t, ok)
+}
+
+func TestPolicy_Backoff(t *testing.T) {
+	p := &Policy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+
+	require.Equal(t, 100*time.Millisecond, p.backoff(0))
+	require.Equal(t, 400*time.Millisecond, p.backoff(2))
+	require.Equal(t, time.Second, p.backoff(10))
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := p.backoff(1)
+		require.GreaterOrEqual(t, d, 100*time.Millisecond)
+		require.LessOrEqual(t, d, 200*time.Millisecond)
+	}
+}
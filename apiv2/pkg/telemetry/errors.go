@@ -0,0 +1,124 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/go-openapi/runtime"
+
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+// Error classes reported by ErrorClass.
+const (
+	ErrorClassBadRequest   = "bad_request"
+	ErrorClassUnauthorized = "unauthorized"
+	ErrorClassForbidden    = "forbidden"
+	ErrorClassNotFound     = "not_found"
+	ErrorClassConflict     = "conflict"
+	ErrorClassRateLimited  = "rate_limited"
+	ErrorClassClient       = "client_error"
+	ErrorClassServer       = "server_error"
+	ErrorClassTimeout      = "timeout"
+	ErrorClassCanceled     = "canceled"
+	ErrorClassCircuitOpen  = "circuit_open"
+	ErrorClassUnsupported  = "unsupported_server_version"
+	ErrorClassOther        = "other"
+)
+
+// swaggerStatusRegex matches the status code contained in the messages of generated swagger errors,
+// e.g. '[GET /projects][401] listProjectsUnauthorized'.
+var swaggerStatusRegex = regexp.MustCompile(`\]\[(\d{3})\]`)
+
+// genericErrorClasses maps the generic errors, which sub-clients also return without a response,
+// e.g. ErrNotFound when a lookup has no result, to their class.
+var genericErrorClasses = []struct {
+	err   error
+	class string
+}{
+	{&clienterrors.ErrBadRequest{}, ErrorClassBadRequest},
+	{&clienterrors.ErrUnauthorized{}, ErrorClassUnauthorized},
+	{&clienterrors.ErrForbidden{}, ErrorClassForbidden},
+	{&clienterrors.ErrNotFound{}, ErrorClassNotFound},
+	{&clienterrors.ErrConflict{}, ErrorClassConflict},
+	{&clienterrors.ErrInternalErrors{}, ErrorClassServer},
+}
+
+// ErrorClass returns a low-cardinality classification of 'err', suitable as a metric label.
+//
+// Errors caused by an HTTP response, including all typed errors wrapping an errors.HarborError,
+// are classified by the status code of the response. Typed errors not caused by a response,
+// e.g. errors.ErrProjectNameNotProvided, are classified as ErrorClassOther.
+func ErrorClass(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case errors.As(err, new(*clienterrors.ErrCircuitOpen)):
+		return ErrorClassCircuitOpen
+	case errors.As(err, new(*clienterrors.ErrUnsupportedServerVersion)):
+		return ErrorClassUnsupported
+	}
+
+	if code, ok := statusCode(err); ok {
+		return statusClass(code)
+	}
+
+	for _, c := range genericErrorClasses {
+		if errors.Is(err, c.err) {
+			return c.class
+		}
+	}
+
+	return ErrorClassOther
+}
+
+// statusCode extracts the HTTP status code from Harbor and swagger errors.
+func statusCode(err error) (int, bool) {
+	if he, ok := clienterrors.AsHarborError(err); ok {
+		return he.StatusCode, true
+	}
+
+	var apiErr *runtime.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code, true
+	}
+
+	m := swaggerStatusRegex.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0, false
+	}
+
+	code, convErr := strconv.Atoi(m[1])
+
+	return code, convErr == nil
+}
+
+func statusClass(code int) string {
+	switch {
+	case code == http.StatusBadRequest:
+		return ErrorClassBadRequest
+	case code == http.StatusUnauthorized:
+		return ErrorClassUnauthorized
+	case code == http.StatusForbidden:
+		return ErrorClassForbidden
+	case code == http.StatusNotFound:
+		return ErrorClassNotFound
+	case code == http.StatusConflict:
+		return ErrorClassConflict
+	case code == http.StatusTooManyRequests:
+		return ErrorClassRateLimited
+	case code >= 500:
+		return ErrorClassServer
+	case code >= 400:
+		return ErrorClassClient
+	default:
+		return ErrorClassOther
+	}
+}
@@ -0,0 +1,243 @@
+// Package telemetry provides opt-in OpenTelemetry tracing and metrics for Harbor API clients.
+//
+// Only the OpenTelemetry API is used, so no telemetry is recorded or exported unless a Telemetry
+// is configured with (or the global providers are set to) an OpenTelemetry SDK.
+//
+// Metrics are recorded as OpenTelemetry instruments rather than registered with a Prometheus registry,
+// so that this module does not depend on a metrics backend. To expose them to Prometheus, configure a
+// MeterProvider using the OpenTelemetry Prometheus exporter (go.opentelemetry.io/otel/exporters/prometheus).
+// With its default settings, the exporter replaces dots by underscores, appends the unit for durations
+// and '_total' for counters, resulting in the following metrics:
+//
+//	harbor_client_operations_total            MetricOperations
+//	harbor_client_operation_duration_seconds  MetricOperationDuration (histogram)
+//	harbor_client_operation_errors_total      MetricOperationErrors
+//	harbor_client_requests_total              MetricRequests
+//	harbor_client_request_duration_seconds    MetricRequestDuration (histogram)
+//
+// The attributes are exported as labels the same way, e.g. 'harbor_operation' and 'harbor_error_class'.
+package telemetry
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/reference"
+)
+
+// InstrumentationName is the name of the tracer and meter used by this package.
+const InstrumentationName = "github.com/mittwald/goharbor-client/v5/apiv2"
+
+// Metric names recorded by a Telemetry.
+const (
+	// MetricOperations counts high-level client operations, such as 'project.ListProjects'.
+	MetricOperations = "harbor.client.operations"
+	// MetricOperationDuration records the duration of high-level client operations in seconds.
+	MetricOperationDuration = "harbor.client.operation.duration"
+	// MetricOperationErrors counts failed high-level client operations by error class.
+	MetricOperationErrors = "harbor.client.operation.errors"
+	// MetricRequests counts HTTP requests sent to the Harbor API.
+	MetricRequests = "harbor.client.requests"
+	// MetricRequestDuration records the duration of HTTP requests sent to the Harbor API in seconds.
+	MetricRequestDuration = "harbor.client.request.duration"
+)
+
+// Attribute keys set on spans and metrics.
+const (
+	OperationKey      = attribute.Key("harbor.operation")
+	OperationIDKey    = attribute.Key("harbor.operation_id")
+	ErrorClassKey     = attribute.Key("harbor.error.class")
+	ProjectKey        = attribute.Key("harbor.project")
+	RepositoryKey     = attribute.Key("harbor.repository")
+	ReferenceKey      = attribute.Key("harbor.reference")
+	HTTPMethodKey     = attribute.Key("http.request.method")
+	HTTPStatusCodeKey = attribute.Key("http.response.status_code")
+	URLTemplateKey    = attribute.Key("url.template")
+)
+
+// Project returns an attribute describing the project an operation refers to.
+func Project(nameOrID string) attribute.KeyValue {
+	return ProjectKey.String(nameOrID)
+}
+
+// ProjectID returns an attribute describing the project an operation refers to by its ID.
+func ProjectID(id int64) attribute.KeyValue {
+	return ProjectKey.String(strconv.FormatInt(id, 10))
+}
+
+// Repository returns an attribute describing the repository an operation refers to.
+func Repository(name string) attribute.KeyValue {
+	return RepositoryKey.String(name)
+}
+
+// Reference returns an attribute describing the artifact reference (tag or digest) an operation refers to.
+func Reference(reference string) attribute.KeyValue {
+	return ReferenceKey.String(reference)
+}
+
+// ArtifactReference returns the attributes describing the project, repository and reference of 'ref',
+// which may be nil.
+func ArtifactReference(ref *reference.Reference) []attribute.KeyValue {
+	if ref == nil {
+		return nil
+	}
+
+	return []attribute.KeyValue{
+		Project(ref.Project),
+		Repository(ref.Repository),
+		Reference(ref.ArtifactReference()),
+	}
+}
+
+// Telemetry records spans and metrics for Harbor client operations.
+// A nil *Telemetry is valid and records nothing.
+type Telemetry struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+
+	operations        metric.Int64Counter
+	operationDuration metric.Float64Histogram
+	operationErrors   metric.Int64Counter
+	requests          metric.Int64Counter
+	requestDuration   metric.Float64Histogram
+}
+
+type settings struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// Option configures a Telemetry.
+type Option func(*settings)
+
+// WithTracerProvider sets the TracerProvider used to create spans.
+// The global TracerProvider is used by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(s *settings) {
+		s.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the MeterProvider used to record metrics.
+// The global MeterProvider is used by default.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(s *settings) {
+		s.meterProvider = provider
+	}
+}
+
+// WithPropagator sets the propagator injecting the trace context into outgoing requests.
+// The W3C trace context propagator is used by default.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(s *settings) {
+		s.propagator = propagator
+	}
+}
+
+// New constructs a Telemetry using the provided options.
+func New(opts ...Option) (*Telemetry, error) {
+	s := &settings{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+		propagator:     propagation.TraceContext{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	meter := s.meterProvider.Meter(InstrumentationName)
+
+	t := &Telemetry{
+		tracer:     s.tracerProvider.Tracer(InstrumentationName),
+		propagator: s.propagator,
+	}
+
+	var err error
+
+	if t.operations, err = meter.Int64Counter(MetricOperations,
+		metric.WithDescription("Number of Harbor client operations."),
+		metric.WithUnit("{operation}")); err != nil {
+		return nil, err
+	}
+
+	if t.operationDuration, err = meter.Float64Histogram(MetricOperationDuration,
+		metric.WithDescription("Duration of Harbor client operations."),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+
+	if t.operationErrors, err = meter.Int64Counter(MetricOperationErrors,
+		metric.WithDescription("Number of failed Harbor client operations."),
+		metric.WithUnit("{error}")); err != nil {
+		return nil, err
+	}
+
+	if t.requests, err = meter.Int64Counter(MetricRequests,
+		metric.WithDescription("Number of HTTP requests sent to the Harbor API."),
+		metric.WithUnit("{request}")); err != nil {
+		return nil, err
+	}
+
+	if t.requestDuration, err = meter.Float64Histogram(MetricRequestDuration,
+		metric.WithDescription("Duration of HTTP requests sent to the Harbor API."),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// EndFunc ends an operation started by Telemetry.Start, recording 'err' if not nil.
+// It returns 'err' unchanged.
+type EndFunc func(err error) error
+
+func noopEnd(err error) error {
+	return err
+}
+
+// Start starts a span for the high-level client operation 'operation', e.g. 'project.ListProjects'.
+// HTTP requests sent using the returned context are recorded as child spans.
+// The returned EndFunc must be called once the operation has finished.
+func (t *Telemetry) Start(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, EndFunc) {
+	if t == nil {
+		return ctx, noopEnd
+	}
+
+	start := time.Now()
+
+	ctx, span := t.tracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(OperationKey.String(operation)),
+		trace.WithAttributes(attrs...))
+
+	return ctx, func(err error) error {
+		defer span.End()
+
+		labels := metric.WithAttributes(OperationKey.String(operation))
+
+		t.operations.Add(ctx, 1, labels)
+		t.operationDuration.Record(ctx, time.Since(start).Seconds(), labels)
+
+		if err != nil {
+			class := ErrorClass(err)
+
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			span.SetAttributes(ErrorClassKey.String(class))
+
+			t.operationErrors.Add(ctx, 1, metric.WithAttributes(OperationKey.String(operation), ErrorClassKey.String(class)))
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,338 @@
+//go:build !integration
+
+package telemetry_test
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2"
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/telemetry"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/testing/fakeharbor"
+)
+
+// recorder implements a minimal recording TracerProvider and MeterProvider.
+type recorder struct {
+	tracenoop.TracerProvider
+	metricnoop.MeterProvider
+
+	mu      sync.Mutex
+	spans   []*span
+	metrics map[string][]attribute.Set
+}
+
+func newRecorder() *recorder {
+	return &recorder{metrics: map[string][]attribute.Set{}}
+}
+
+func (r *recorder) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return &tracer{recorder: r}
+}
+
+func (r *recorder) Meter(string, ...metric.MeterOption) metric.Meter {
+	return &meter{recorder: r}
+}
+
+func (r *recorder) span(name string) *span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range r.spans {
+		if s.name == name {
+			return s
+		}
+	}
+
+	return nil
+}
+
+func (r *recorder) children(parent *span) []*span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var children []*span
+
+	for _, s := range r.spans {
+		if s.parent == parent.sc.SpanID() {
+			children = append(children, s)
+		}
+	}
+
+	return children
+}
+
+func (r *recorder) record(name string, set attribute.Set) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.metrics[name] = append(r.metrics[name], set)
+}
+
+type tracer struct {
+	tracenoop.Tracer
+
+	recorder *recorder
+}
+
+func (t *tracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+	parent := trace.SpanContextFromContext(ctx)
+
+	var traceID trace.TraceID
+	var spanID trace.SpanID
+
+	if parent.IsValid() {
+		traceID = parent.TraceID()
+	} else {
+		_, _ = rand.Read(traceID[:])
+	}
+
+	_, _ = rand.Read(spanID[:])
+
+	s := &span{
+		name:   name,
+		parent: parent.SpanID(),
+		attrs:  map[attribute.Key]attribute.Value{},
+		sc: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: trace.FlagsSampled,
+		}),
+	}
+	s.SetAttributes(cfg.Attributes()...)
+
+	t.recorder.mu.Lock()
+	t.recorder.spans = append(t.recorder.spans, s)
+	t.recorder.mu.Unlock()
+
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+type span struct {
+	tracenoop.Span
+
+	mu     sync.Mutex
+	name   string
+	parent trace.SpanID
+	sc     trace.SpanContext
+	attrs  map[attribute.Key]attribute.Value
+	status codes.Code
+	ended  bool
+}
+
+func (s *span) SpanContext() trace.SpanContext { return s.sc }
+
+func (s *span) IsRecording() bool { return true }
+
+func (s *span) SetAttributes(kv ...attribute.KeyValue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range kv {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *span) SetStatus(code codes.Code, _ string) { s.status = code }
+
+func (s *span) End(...trace.SpanEndOption) { s.ended = true }
+
+type meter struct {
+	metricnoop.Meter
+
+	recorder *recorder
+}
+
+func (m *meter) Int64Counter(name string, _ ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return &int64Counter{name: name, recorder: m.recorder}, nil
+}
+
+func (m *meter) Float64Histogram(name string, _ ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	return &float64Histogram{name: name, recorder: m.recorder}, nil
+}
+
+type int64Counter struct {
+	metricnoop.Int64Counter
+
+	name     string
+	recorder *recorder
+}
+
+func (c *int64Counter) Add(_ context.Context, _ int64, opts ...metric.AddOption) {
+	c.recorder.record(c.name, metric.NewAddConfig(opts).Attributes())
+}
+
+type float64Histogram struct {
+	metricnoop.Float64Histogram
+
+	name     string
+	recorder *recorder
+}
+
+func (h *float64Histogram) Record(_ context.Context, _ float64, opts ...metric.RecordOption) {
+	h.recorder.record(h.name, metric.NewRecordConfig(opts).Attributes())
+}
+
+func newInstrumentedClient(t *testing.T) (*recorder, *apiv2.RESTClient, *[]string) {
+	t.Helper()
+
+	fake := fakeharbor.NewServer()
+	t.Cleanup(fake.Close)
+
+	var (
+		mu           sync.Mutex
+		traceparents []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		mu.Unlock()
+
+		fake.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	rec := newRecorder()
+
+	tel, err := telemetry.New(telemetry.WithTracerProvider(rec), telemetry.WithMeterProvider(rec))
+	require.NoError(t, err)
+
+	c, err := apiv2.NewRESTClientForHost(srv.URL+fakeharbor.BasePath, fakeharbor.AdminUser, fakeharbor.AdminPassword,
+		config.Defaults().WithPageSize(2).WithTelemetry(tel))
+	require.NoError(t, err)
+
+	return rec, c, &traceparents
+}
+
+func TestTelemetry_Spans(t *testing.T) {
+	rec, c, traceparents := newInstrumentedClient(t)
+
+	ctx := context.Background()
+
+	projects, err := c.ListProjects(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	_, err = c.GetArtifact(ctx, "library", "nested/app", "latest")
+	require.Error(t, err)
+
+	list := rec.span("project.ListProjects")
+	require.NotNil(t, list)
+	require.True(t, list.ended)
+	require.Equal(t, codes.Unset, list.status)
+
+	pages := rec.children(list)
+	require.Len(t, pages, 1)
+	require.Equal(t, "GET /projects", pages[0].name)
+	require.Equal(t, int64(http.StatusOK), pages[0].attrs[telemetry.HTTPStatusCodeKey].AsInt64())
+	require.Equal(t, "listProjects", pages[0].attrs[telemetry.OperationIDKey].AsString())
+
+	get := rec.span("artifact.GetArtifact")
+	require.NotNil(t, get)
+	require.Equal(t, codes.Error, get.status)
+	require.Equal(t, "library", get.attrs[telemetry.ProjectKey].AsString())
+	require.Equal(t, "nested/app", get.attrs[telemetry.RepositoryKey].AsString())
+	require.Equal(t, "latest", get.attrs[telemetry.ReferenceKey].AsString())
+	require.Equal(t, telemetry.ErrorClassNotFound, get.attrs[telemetry.ErrorClassKey].AsString())
+
+	// The trace context of each HTTP request span is propagated to Harbor.
+	require.Len(t, *traceparents, 2)
+	for i, s := range append(pages, rec.children(get)...) {
+		require.Equal(t, "00-"+s.sc.TraceID().String()+"-"+s.sc.SpanID().String()+"-01", (*traceparents)[i])
+	}
+}
+
+func TestTelemetry_PaginatedRequests(t *testing.T) {
+	rec, c, _ := newInstrumentedClient(t)
+
+	ctx := context.Background()
+
+	for _, name := range []string{"a", "b", "c", "d"} {
+		require.NoError(t, c.NewProject(ctx, &model.ProjectReq{ProjectName: "project-" + name}))
+	}
+
+	projects, err := c.ListProjects(ctx, "project-")
+	require.NoError(t, err)
+	require.Len(t, projects, 4)
+
+	list := rec.span("project.ListProjects")
+	require.NotNil(t, list)
+	require.Len(t, rec.children(list), 2)
+}
+
+func TestTelemetry_Metrics(t *testing.T) {
+	rec, c, _ := newInstrumentedClient(t)
+
+	ctx := context.Background()
+
+	_, err := c.GetProject(ctx, "library")
+	require.NoError(t, err)
+
+	_, err = c.GetProject(ctx, "unknown")
+	require.Error(t, err)
+
+	require.Len(t, rec.metrics[telemetry.MetricOperations], 2)
+	require.Len(t, rec.metrics[telemetry.MetricOperationDuration], 2)
+	require.Len(t, rec.metrics[telemetry.MetricRequests], 2)
+	require.Len(t, rec.metrics[telemetry.MetricRequestDuration], 2)
+	require.Len(t, rec.metrics[telemetry.MetricOperationErrors], 1)
+
+	errSet := rec.metrics[telemetry.MetricOperationErrors][0]
+
+	op, _ := errSet.Value(telemetry.OperationKey)
+	require.Equal(t, "project.GetProject", op.AsString())
+
+	class, _ := errSet.Value(telemetry.ErrorClassKey)
+	require.Equal(t, telemetry.ErrorClassNotFound, class.AsString())
+}
+
+func TestTelemetry_Disabled(t *testing.T) {
+	var tel *telemetry.Telemetry
+
+	ctx, end := tel.Start(context.Background(), "project.ListProjects")
+	require.False(t, trace.SpanContextFromContext(ctx).IsValid())
+	require.Equal(t, context.Canceled, end(context.Canceled))
+}
+
+func TestErrorClass(t *testing.T) {
+	// response returns the typed error 'typed' linked to a response with the status code 'code'.
+	response := func(code int, typed error) error {
+		return clienterrors.WrapSwaggerError(runtime.NewAPIError("operation", nil, code), typed)
+	}
+
+	for err, class := range map[error]string{
+		context.DeadlineExceeded:                                                                           telemetry.ErrorClassTimeout,
+		&clienterrors.ErrCircuitOpen{}:                                                                     telemetry.ErrorClassCircuitOpen,
+		&clienterrors.ErrUnsupportedServerVersion{}:                                                        telemetry.ErrorClassUnsupported,
+		response(http.StatusNotFound, &clienterrors.ErrProjectNotFound{}):                                  telemetry.ErrorClassNotFound,
+		response(http.StatusConflict, &clienterrors.ErrProjectNameAlreadyExists{}):                         telemetry.ErrorClassConflict,
+		response(http.StatusConflict, &clienterrors.ErrUserAlreadyExists{}):                                telemetry.ErrorClassConflict,
+		fmt.Errorf("deleting: %w", response(http.StatusForbidden, &clienterrors.ErrProjectNoPermission{})): telemetry.ErrorClassForbidden,
+		response(http.StatusBadGateway, runtime.NewAPIError("operation", nil, http.StatusBadGateway)):      telemetry.ErrorClassServer,
+		&clienterrors.ErrUnauthorized{}:                                                                    telemetry.ErrorClassUnauthorized,
+		&clienterrors.ErrNotFound{}:                                                                        telemetry.ErrorClassNotFound,
+		// Typed errors not caused by a response.
+		&clienterrors.ErrMultipleResults{}:        telemetry.ErrorClassOther,
+		&clienterrors.ErrProjectNameNotProvided{}: telemetry.ErrorClassOther,
+	} {
+		require.Equal(t, class, telemetry.ErrorClass(err), err.Error())
+	}
+}
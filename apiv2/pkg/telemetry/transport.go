@@ -0,0 +1,142 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WrapTransport returns a swagger client transport recording a span and metrics for each HTTP request
+// submitted by 'next', and propagating the trace context to the Harbor API.
+// 'next' is returned as is if 't' is nil.
+func (t *Telemetry) WrapTransport(next runtime.ClientTransport) runtime.ClientTransport {
+	if t == nil {
+		return next
+	}
+
+	return &transport{telemetry: t, next: next}
+}
+
+type transport struct {
+	telemetry *Telemetry
+	next      runtime.ClientTransport
+}
+
+// Submit implements runtime.ClientTransport.
+func (tr *transport) Submit(op *runtime.ClientOperation) (interface{}, error) {
+	t := tr.telemetry
+
+	parent := op.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	attrs := []attribute.KeyValue{
+		OperationIDKey.String(op.ID),
+		HTTPMethodKey.String(op.Method),
+		URLTemplateKey.String(op.PathPattern),
+	}
+
+	ctx, span := t.tracer.Start(parent, op.Method+" "+op.PathPattern,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+	defer span.End()
+
+	reader := &statusReader{next: op.Reader}
+
+	instrumented := *op
+	instrumented.Params = &propagatingWriter{ctx: ctx, telemetry: t, next: op.Params}
+	instrumented.Reader = reader
+
+	// A nil context makes the swagger runtime fall back to its default timeout.
+	if op.Context != nil {
+		instrumented.Context = ctx
+	}
+
+	start := time.Now()
+	result, err := tr.next.Submit(&instrumented)
+
+	if reader.status != 0 {
+		attrs = append(attrs, HTTPStatusCodeKey.Int(reader.status))
+		span.SetAttributes(HTTPStatusCodeKey.Int(reader.status))
+	}
+
+	if err != nil {
+		attrs = append(attrs, ErrorClassKey.String(ErrorClass(err)))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	labels := metric.WithAttributes(attrs...)
+
+	t.requests.Add(ctx, 1, labels)
+	t.requestDuration.Record(ctx, time.Since(start).Seconds(), labels)
+
+	return result, err
+}
+
+// propagatingWriter injects the trace context of 'ctx' into the request headers
+// after writing the request parameters.
+type propagatingWriter struct {
+	ctx       context.Context
+	telemetry *Telemetry
+	next      runtime.ClientRequestWriter
+}
+
+// WriteToRequest implements runtime.ClientRequestWriter.
+func (w *propagatingWriter) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+	if w.next != nil {
+		if err := w.next.WriteToRequest(r, reg); err != nil {
+			return err
+		}
+	}
+
+	carrier := &headerCarrier{request: r}
+	w.telemetry.propagator.Inject(w.ctx, carrier)
+
+	return carrier.err
+}
+
+// headerCarrier adapts a runtime.ClientRequest to a propagation.TextMapCarrier.
+type headerCarrier struct {
+	request runtime.ClientRequest
+	err     error
+}
+
+func (c *headerCarrier) Get(key string) string {
+	return c.request.GetHeaderParams().Get(key)
+}
+
+func (c *headerCarrier) Set(key, value string) {
+	if err := c.request.SetHeaderParam(key, value); err != nil && c.err == nil {
+		c.err = err
+	}
+}
+
+func (c *headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.request.GetHeaderParams()))
+	for k := range c.request.GetHeaderParams() {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// statusReader records the HTTP status code of the response read by 'next'.
+type statusReader struct {
+	next   runtime.ClientResponseReader
+	status int
+}
+
+// ReadResponse implements runtime.ClientResponseReader.
+func (r *statusReader) ReadResponse(resp runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	r.status = resp.Code()
+
+	return r.next.ReadResponse(resp, consumer)
+}
@@ -2,6 +2,7 @@ package testing
 
 import (
 	"net/url"
+	"os"
 
 	runtimeclient "github.com/go-openapi/runtime/client"
 	"github.com/go-openapi/strfmt"
@@ -9,21 +10,45 @@ import (
 	v2client "github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client"
 	"github.com/mittwald/goharbor-client/v5/apiv2/mocks"
 	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/testing/fakeharbor"
 )
 
 const (
-	Host     = "http://core.harbor.domain:80/api/v2.0"
-	User     = "admin"
-	Password = "Harbor12345"
+	DefaultHost        = "http://core.harbor.domain:80/api/v2.0"
+	DefaultRegistryURL = "core.harbor.domain"
+	User               = "admin"
+	Password           = "Harbor12345"
+
+	// FakeServerEnv is the environment variable that, when set to a non-empty value,
+	// makes the integration tests run against an in-memory fake Harbor instead of DefaultHost.
+	FakeServerEnv = "HARBOR_FAKE_SERVER"
 )
 
 var (
-	u, _            = url.Parse(Host)
-	V2SwaggerClient = v2client.New(runtimeclient.New(u.Host, u.Path, []string{u.Scheme}), strfmt.Default)
-	AuthInfo        = runtimeclient.BasicAuth(User, Password)
-	DefaultOpts     = config.Defaults()
+	Host, RegistryURL = host()
+	u, _              = url.Parse(Host)
+	V2SwaggerClient   = v2client.New(runtimeclient.New(u.Host, u.Path, []string{u.Scheme}), strfmt.Default)
+	AuthInfo          = runtimeclient.BasicAuth(User, Password)
+	DefaultOpts       = config.Defaults()
 )
 
+// host returns the URL and registry address of the Harbor instance used by the integration tests,
+// starting a fake server if FakeServerEnv is set. Like the 'upload-test-image' make target does for
+// a real instance, the test image "library/image:test" is pushed to the fake server.
+func host() (string, string) {
+	if os.Getenv(FakeServerEnv) == "" {
+		return DefaultHost, DefaultRegistryURL
+	}
+
+	srv := fakeharbor.NewServer()
+
+	if _, err := srv.PushArtifact("library", "image", "test"); err != nil {
+		panic(err)
+	}
+
+	return srv.Host(), srv.RegistryURL()
+}
+
 type MockClients struct {
 	Artifact           mocks.MockArtifactClientService
 	User               mocks.MockUserClientService
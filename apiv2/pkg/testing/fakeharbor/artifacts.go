@@ -0,0 +1,579 @@
+package fakeharbor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+type repository struct {
+	id          int64
+	projectID   int64
+	name        string
+	description string
+	created     strfmt.DateTime
+	updated     strfmt.DateTime
+}
+
+type artifact struct {
+	model.Artifact
+}
+
+func (s *Server) repositoryModel(repo *repository) *model.Repository {
+	var count int64
+
+	for _, a := range s.artifacts {
+		if a.RepositoryID == repo.id {
+			count++
+		}
+	}
+
+	created := repo.created
+
+	return &model.Repository{
+		ArtifactCount: count,
+		CreationTime:  &created,
+		Description:   repo.description,
+		ID:            repo.id,
+		Name:          repo.name,
+		ProjectID:     repo.projectID,
+		UpdateTime:    repo.updated,
+	}
+}
+
+// artifactModel returns a copy of 'a', so that responses are not affected by later changes.
+func (s *Server) artifactModel(a *artifact) *model.Artifact {
+	m := a.Artifact
+	m.Tags = append([]*model.Tag{}, a.Tags...)
+	m.Labels = append([]*model.Label{}, a.Labels...)
+	m.Accessories = append([]*model.Accessory{}, a.Accessories...)
+	m.References = []*model.Reference{}
+
+	return &m
+}
+
+// repository returns the repository 'name' (without the project prefix) of the given project.
+func (s *Server) repository(p *project, name string) *repository {
+	for _, repo := range s.repositories {
+		if repo.projectID == p.id && repo.name == p.name+"/"+name {
+			return repo
+		}
+	}
+
+	return nil
+}
+
+// lookupRepository resolves the "project_name" and "repository_name" path parameters, responding with 404 if unknown.
+func (s *Server) lookupRepository(w http.ResponseWriter, p params) (*project, *repository) {
+	proj := s.lookupProject(w, p)
+	if proj == nil {
+		return nil, nil
+	}
+
+	repo := s.repository(proj, p["repository_name"])
+	if repo == nil {
+		writeError(w, http.StatusNotFound, "repository %s/%s not found", proj.name, p["repository_name"])
+		return nil, nil
+	}
+
+	return proj, repo
+}
+
+// artifact returns the artifact of 'repo' identified by its digest or one of its tags.
+func (s *Server) artifact(repo *repository, reference string) *artifact {
+	for _, a := range s.artifacts {
+		if a.RepositoryID != repo.id {
+			continue
+		}
+
+		if a.Digest == reference {
+			return a
+		}
+
+		for _, t := range a.Tags {
+			if t.Name == reference {
+				return a
+			}
+		}
+	}
+
+	return nil
+}
+
+// lookupArtifact resolves the repository and "reference" path parameters, responding with 404 if unknown.
+func (s *Server) lookupArtifact(w http.ResponseWriter, p params) (*repository, *artifact) {
+	_, repo := s.lookupRepository(w, p)
+	if repo == nil {
+		return nil, nil
+	}
+
+	a := s.artifact(repo, p["reference"])
+	if a == nil {
+		writeError(w, http.StatusNotFound, "artifact %s:%s not found", repo.name, p["reference"])
+		return nil, nil
+	}
+
+	return repo, a
+}
+
+// PushArtifact simulates pushing an image to the repository 'repositoryName' (without the project prefix)
+// of an existing project. The repository is created if needed.
+// Tags are moved from other artifacts of the repository, just like a regular push would.
+// The push is recorded in the audit log as an operation of the admin.
+func (s *Server) PushArtifact(projectName, repositoryName string, tags ...string) (*model.Artifact, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.project(projectName)
+	if p == nil {
+		return nil, fmt.Errorf("project %s not found", projectName)
+	}
+
+	sum := sha256.Sum256([]byte(randomHex(16)))
+
+	a := s.addArtifact(p, repositoryName, "sha256:"+hex.EncodeToString(sum[:]), 1024)
+	s.tagArtifact(a, tags...)
+	s.audit(AdminUser, "create", "artifact", p.name+"/"+repositoryName+"@"+a.Digest)
+
+	return s.artifactModel(a), nil
+}
+
+func (s *Server) addArtifact(p *project, repositoryName, digest string, size int64) *artifact {
+	repo := s.repository(p, repositoryName)
+	if repo == nil {
+		repo = &repository{
+			id:        s.id("repository"),
+			projectID: p.id,
+			name:      p.name + "/" + repositoryName,
+			created:   now(),
+		}
+
+		s.repositories[repo.id] = repo
+	}
+
+	repo.updated = now()
+
+	if a := s.artifact(repo, digest); a != nil {
+		return a
+	}
+
+	a := &artifact{Artifact: model.Artifact{
+		Digest:            digest,
+		ID:                s.id("artifact"),
+		ManifestMediaType: "application/vnd.docker.distribution.manifest.v2+json",
+		MediaType:         "application/vnd.docker.container.image.v1+json",
+		ProjectID:         p.id,
+		PushTime:          now(),
+		RepositoryID:      repo.id,
+		Size:              size,
+		Type:              "IMAGE",
+	}}
+
+	s.artifacts[a.ID] = a
+
+	return a
+}
+
+// tagArtifact adds 'tags' to 'a', removing them from other artifacts of the same repository.
+func (s *Server) tagArtifact(a *artifact, tags ...string) {
+	for _, name := range tags {
+		for _, other := range s.artifacts {
+			if other.RepositoryID == a.RepositoryID {
+				other.Tags = removeTag(other.Tags, name)
+			}
+		}
+
+		a.Tags = append(a.Tags, &model.Tag{
+			ArtifactID:   a.ID,
+			ID:           s.id("tag"),
+			Name:         name,
+			PushTime:     now(),
+			RepositoryID: a.RepositoryID,
+		})
+	}
+}
+
+func removeTag(tags []*model.Tag, name string) []*model.Tag {
+	var kept []*model.Tag
+
+	for _, t := range tags {
+		if t.Name != name {
+			kept = append(kept, t)
+		}
+	}
+
+	return kept
+}
+
+// AttachAccessory simulates pushing an accessory of 'accessoryType' (e.g. "signature.cosign")
+// for the artifact 'subjectReference' (a digest or tag) of an existing repository.
+func (s *Server) AttachAccessory(projectName, repositoryName, subjectReference, accessoryType string) (*model.Accessory, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.project(projectName)
+	if p == nil {
+		return nil, fmt.Errorf("project %s not found", projectName)
+	}
+
+	repo := s.repository(p, repositoryName)
+	if repo == nil {
+		return nil, fmt.Errorf("repository %s/%s not found", projectName, repositoryName)
+	}
+
+	subject := s.artifact(repo, subjectReference)
+	if subject == nil {
+		return nil, fmt.Errorf("artifact %s:%s not found", repo.name, subjectReference)
+	}
+
+	sum := sha256.Sum256([]byte(randomHex(16)))
+	a := s.addArtifact(p, repositoryName, "sha256:"+hex.EncodeToString(sum[:]), 512)
+
+	acc := &model.Accessory{
+		ArtifactID:            a.ID,
+		CreationTime:          now(),
+		Digest:                a.Digest,
+		ID:                    s.id("accessory"),
+		Size:                  a.Size,
+		SubjectArtifactDigest: subject.Digest,
+		SubjectArtifactID:     subject.ID,
+		SubjectArtifactRepo:   repo.name,
+		Type:                  accessoryType,
+	}
+
+	subject.Accessories = append(subject.Accessories, acc)
+
+	return acc, nil
+}
+
+func (s *Server) registerArtifactRoutes() {
+	const (
+		repoPath     = "/projects/{project_name}/repositories/{repository_name}"
+		artifactPath = repoPath + "/artifacts/{reference}"
+	)
+
+	// Routes are matched in order, more specific routes need to be registered first.
+	s.handle(http.MethodGet, artifactPath+"/tags", s.handleListTags)
+	s.handle(http.MethodPost, artifactPath+"/tags", s.handleCreateTag)
+	s.handle(http.MethodDelete, artifactPath+"/tags/{tag_name}", s.handleDeleteTag)
+	s.handle(http.MethodPost, artifactPath+"/labels", s.handleAddArtifactLabel)
+	s.handle(http.MethodDelete, artifactPath+"/labels/{label_id}", s.handleRemoveArtifactLabel)
+	s.handle(http.MethodGet, artifactPath+"/accessories", s.handleListAccessories)
+	s.handle(http.MethodGet, artifactPath, s.handleGetArtifact)
+	s.handle(http.MethodDelete, artifactPath, s.handleDeleteArtifact)
+	s.handle(http.MethodGet, repoPath+"/artifacts", s.handleListArtifacts)
+	s.handle(http.MethodPost, repoPath+"/artifacts", s.handleCopyArtifact)
+
+	s.handle(http.MethodGet, "/repositories", s.handleListRepositories)
+	s.handle(http.MethodGet, "/projects/{project_name}/repositories", s.handleListRepositories)
+	s.handle(http.MethodGet, repoPath, s.handleGetRepository)
+	s.handle(http.MethodPut, repoPath, s.handleUpdateRepository)
+	s.handle(http.MethodDelete, repoPath, s.handleDeleteRepository)
+}
+
+func (s *Server) handleListRepositories(w http.ResponseWriter, r *http.Request, p params) {
+	var proj *project
+
+	if _, ok := p["project_name"]; ok {
+		if proj = s.lookupProject(w, p); proj == nil {
+			return
+		}
+	}
+
+	q := parseQuery(r)
+
+	var repos []*model.Repository
+
+	for _, id := range sortedIDs(s.repositories) {
+		repo := s.repositories[id]
+
+		if proj != nil && repo.projectID != proj.id {
+			continue
+		}
+
+		if !q.matches(map[string]string{"name": repo.name}) {
+			continue
+		}
+
+		repos = append(repos, s.repositoryModel(repo))
+	}
+
+	writePage(w, r, repos)
+}
+
+func (s *Server) handleGetRepository(w http.ResponseWriter, _ *http.Request, p params) {
+	if _, repo := s.lookupRepository(w, p); repo != nil {
+		writeJSON(w, http.StatusOK, s.repositoryModel(repo))
+	}
+}
+
+func (s *Server) handleUpdateRepository(w http.ResponseWriter, r *http.Request, p params) {
+	_, repo := s.lookupRepository(w, p)
+	if repo == nil {
+		return
+	}
+
+	req := &model.Repository{}
+	if !decode(w, r, req) {
+		return
+	}
+
+	repo.description = req.Description
+	repo.updated = now()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleDeleteRepository(w http.ResponseWriter, r *http.Request, p params) {
+	_, repo := s.lookupRepository(w, p)
+	if repo == nil {
+		return
+	}
+
+	for id, a := range s.artifacts {
+		if a.RepositoryID == repo.id {
+			delete(s.artifacts, id)
+		}
+	}
+
+	delete(s.repositories, repo.id)
+	s.auditRequest(r, "delete", "repository", repo.name)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleListArtifacts(w http.ResponseWriter, r *http.Request, p params) {
+	_, repo := s.lookupRepository(w, p)
+	if repo == nil {
+		return
+	}
+
+	q := parseQuery(r)
+	withTag := r.URL.Query().Get("with_tag") != "false"
+	withLabel := r.URL.Query().Get("with_label") == "true"
+
+	var artifacts []*model.Artifact
+
+	for _, id := range sortedIDs(s.artifacts) {
+		a := s.artifacts[id]
+		if a.RepositoryID != repo.id {
+			continue
+		}
+
+		var tags []string
+		for _, t := range a.Tags {
+			tags = append(tags, t.Name)
+		}
+
+		if !q.matches(map[string]string{"digest": a.Digest, "type": a.Type, "tags": strings.Join(tags, ",")}) {
+			continue
+		}
+
+		m := s.artifactModel(a)
+
+		if !withTag {
+			m.Tags = nil
+		}
+
+		if !withLabel {
+			m.Labels = nil
+		}
+
+		artifacts = append(artifacts, m)
+	}
+
+	writePage(w, r, artifacts)
+}
+
+func (s *Server) handleGetArtifact(w http.ResponseWriter, _ *http.Request, p params) {
+	if _, a := s.lookupArtifact(w, p); a != nil {
+		writeJSON(w, http.StatusOK, s.artifactModel(a))
+	}
+}
+
+func (s *Server) handleDeleteArtifact(w http.ResponseWriter, r *http.Request, p params) {
+	repo, a := s.lookupArtifact(w, p)
+	if a == nil {
+		return
+	}
+
+	for _, acc := range a.Accessories {
+		delete(s.artifacts, acc.ArtifactID)
+	}
+
+	delete(s.artifacts, a.ID)
+	s.auditRequest(r, "delete", "artifact", repo.name+"@"+a.Digest)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleCopyArtifact copies the artifact referenced by the "from" query parameter,
+// e.g. "project/repository@sha256:..." or "project/repository:tag".
+func (s *Server) handleCopyArtifact(w http.ResponseWriter, r *http.Request, p params) {
+	proj := s.lookupProject(w, p)
+	if proj == nil {
+		return
+	}
+
+	from := r.URL.Query().Get("from")
+
+	var repoName, reference string
+
+	if i := strings.LastIndex(from, "@"); i >= 0 {
+		repoName, reference = from[:i], from[i+1:]
+	} else if i := strings.LastIndex(from, ":"); i >= 0 {
+		repoName, reference = from[:i], from[i+1:]
+	}
+
+	srcProjectName, srcRepoName, ok := strings.Cut(repoName, "/")
+	if !ok || reference == "" {
+		writeError(w, http.StatusBadRequest, "invalid source %q", from)
+		return
+	}
+
+	srcProject := s.project(srcProjectName)
+	if srcProject == nil {
+		writeError(w, http.StatusNotFound, "project %s not found", srcProjectName)
+		return
+	}
+
+	srcRepo := s.repository(srcProject, srcRepoName)
+	if srcRepo == nil {
+		writeError(w, http.StatusNotFound, "repository %s not found", repoName)
+		return
+	}
+
+	src := s.artifact(srcRepo, reference)
+	if src == nil {
+		writeError(w, http.StatusNotFound, "artifact %s not found", from)
+		return
+	}
+
+	dst := s.addArtifact(proj, p["repository_name"], src.Digest, src.Size)
+
+	var tags []string
+	for _, t := range src.Tags {
+		tags = append(tags, t.Name)
+	}
+
+	s.tagArtifact(dst, tags...)
+
+	writeCreated(w, r, dst.ID)
+}
+
+func (s *Server) handleListTags(w http.ResponseWriter, r *http.Request, p params) {
+	if _, a := s.lookupArtifact(w, p); a != nil {
+		writePage(w, r, append([]*model.Tag{}, a.Tags...))
+	}
+}
+
+func (s *Server) handleCreateTag(w http.ResponseWriter, r *http.Request, p params) {
+	repo, a := s.lookupArtifact(w, p)
+	if a == nil {
+		return
+	}
+
+	tag := &model.Tag{}
+	if !decode(w, r, tag) {
+		return
+	}
+
+	if tag.Name == "" {
+		writeError(w, http.StatusBadRequest, "tag name must not be empty")
+		return
+	}
+
+	if existing := s.artifact(repo, tag.Name); existing != nil && existing.Digest != tag.Name {
+		writeError(w, http.StatusConflict, "tag %s already exists in repository %s", tag.Name, repo.name)
+		return
+	}
+
+	s.tagArtifact(a, tag.Name)
+	s.auditRequest(r, "create", "tag", repo.name+":"+tag.Name)
+
+	writeCreated(w, r, a.Tags[len(a.Tags)-1].ID)
+}
+
+func (s *Server) handleDeleteTag(w http.ResponseWriter, r *http.Request, p params) {
+	repo, a := s.lookupArtifact(w, p)
+	if a == nil {
+		return
+	}
+
+	kept := removeTag(a.Tags, p["tag_name"])
+	if len(kept) == len(a.Tags) {
+		writeError(w, http.StatusNotFound, "tag %s not found", p["tag_name"])
+		return
+	}
+
+	a.Tags = kept
+	s.auditRequest(r, "delete", "tag", repo.name+":"+p["tag_name"])
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleAddArtifactLabel(w http.ResponseWriter, r *http.Request, p params) {
+	_, a := s.lookupArtifact(w, p)
+	if a == nil {
+		return
+	}
+
+	req := &model.Label{}
+	if !decode(w, r, req) {
+		return
+	}
+
+	l, ok := s.labels[req.ID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "label %d not found", req.ID)
+		return
+	}
+
+	if l.Scope == "p" && l.ProjectID != a.ProjectID {
+		writeError(w, http.StatusBadRequest, "label %d belongs to another project", req.ID)
+		return
+	}
+
+	for _, existing := range a.Labels {
+		if existing.ID == l.ID {
+			writeError(w, http.StatusConflict, "label %d is already added to the artifact", req.ID)
+			return
+		}
+	}
+
+	a.Labels = append(a.Labels, l)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleRemoveArtifactLabel(w http.ResponseWriter, _ *http.Request, p params) {
+	_, a := s.lookupArtifact(w, p)
+	if a == nil {
+		return
+	}
+
+	id, _ := p.int64("label_id")
+
+	for i, l := range a.Labels {
+		if l.ID == id {
+			a.Labels = append(a.Labels[:i], a.Labels[i+1:]...)
+			w.WriteHeader(http.StatusOK)
+
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "label %s is not added to the artifact", p["label_id"])
+}
+
+func (s *Server) handleListAccessories(w http.ResponseWriter, r *http.Request, p params) {
+	if _, a := s.lookupArtifact(w, p); a != nil {
+		writePage(w, r, append([]*model.Accessory{}, a.Accessories...))
+	}
+}
@@ -0,0 +1,82 @@
+package fakeharbor
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+// auditTimeFormat is the format of times in audit log queries.
+const auditTimeFormat = "2006-01-02 15:04:05"
+
+// audit records an operation of 'username' in the audit log, e.g. audit("admin", "create", "project", "library").
+func (s *Server) audit(username, operation, resourceType, resource string) {
+	s.auditLogs = append(s.auditLogs, &model.AuditLog{
+		ID:           s.id("auditlog"),
+		OpTime:       now(),
+		Operation:    operation,
+		Resource:     resource,
+		ResourceType: resourceType,
+		Username:     username,
+	})
+}
+
+// auditRequest records an operation of the user authenticated by 'r' in the audit log.
+func (s *Server) auditRequest(r *http.Request, operation, resourceType, resource string) {
+	name, _, _ := r.BasicAuth()
+	s.audit(name, operation, resourceType, resource)
+}
+
+// handleListAuditLogs lists the audit log entries matching the query, sorted by "op_time" and "id"
+// as requested by the 'sort' parameter. The newest entries are returned first by default.
+func (s *Server) handleListAuditLogs(w http.ResponseWriter, r *http.Request, _ params) {
+	q := parseQuery(r)
+
+	var logs []*model.AuditLog
+
+	for _, l := range s.auditLogs {
+		if q.matches(map[string]string{
+			"operation":     l.Operation,
+			"resource":      l.Resource,
+			"resource_type": l.ResourceType,
+			"username":      l.Username,
+			"op_time":       time.Time(l.OpTime).UTC().Format(auditTimeFormat),
+		}) {
+			logs = append(logs, l)
+		}
+	}
+
+	order := r.URL.Query().Get("sort")
+	if order == "" {
+		order = "-op_time"
+	}
+
+	keys := strings.Split(order, ",")
+
+	sort.SliceStable(logs, func(i, j int) bool {
+		for _, key := range keys {
+			a, b := logs[i], logs[j]
+			if strings.HasPrefix(key, "-") {
+				a, b = b, a
+			}
+
+			switch strings.TrimPrefix(key, "-") {
+			case "op_time":
+				if ta, tb := time.Time(a.OpTime), time.Time(b.OpTime); !ta.Equal(tb) {
+					return ta.Before(tb)
+				}
+			case "id":
+				if a.ID != b.ID {
+					return a.ID < b.ID
+				}
+			}
+		}
+
+		return false
+	})
+
+	writePage(w, r, logs)
+}
@@ -0,0 +1,130 @@
+package fakeharbor
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+// defaultConfig returns the system configuration of a fresh Harbor installation, keyed by JSON name.
+// Items not listed, e.g. the LDAP URL, have their zero value. Write-only items, e.g. passwords, are only set
+// when updated, and never returned.
+func defaultConfig() map[string]json.RawMessage {
+	values := map[string]interface{}{
+		"auth_mode":                       "db_auth",
+		"http_authproxy_verify_cert":      true,
+		"ldap_group_membership_attribute": "memberof",
+		"ldap_group_search_scope":         2,
+		"ldap_scope":                      2,
+		"ldap_timeout":                    5,
+		"ldap_uid":                        "uid",
+		"ldap_verify_cert":                true,
+		"notification_enable":             true,
+		"oidc_scope":                      "openid,offline_access",
+		"oidc_verify_cert":                true,
+		"project_creation_restriction":    "everyone",
+		"quota_per_project_enable":        true,
+		"robot_name_prefix":               "robot$",
+		"robot_token_duration":            defaultRobotDuration,
+		"session_timeout":                 60,
+		"storage_per_project":             -1,
+		"token_expiration":                30,
+		"uaa_verify_cert":                 true,
+	}
+
+	zero := map[reflect.Type]interface{}{
+		reflect.TypeOf(&model.BoolConfigItem{}):    false,
+		reflect.TypeOf(&model.IntegerConfigItem{}): 0,
+		reflect.TypeOf(&model.StringConfigItem{}):  "",
+	}
+
+	config := map[string]json.RawMessage{}
+
+	for key, t := range jsonFields(reflect.TypeOf(model.ConfigurationsResponse{})) {
+		v, ok := values[key]
+		if !ok {
+			if v, ok = zero[t]; !ok {
+				continue
+			}
+		}
+
+		config[key], _ = json.Marshal(v)
+	}
+
+	return config
+}
+
+// jsonFields returns the types of the fields of the struct type 't', keyed by their JSON name.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = t.Field(i).Type
+		}
+	}
+
+	return fields
+}
+
+func (s *Server) registerConfigurationRoutes() {
+	s.handle(http.MethodGet, "/configurations", s.handleGetConfigurations)
+	s.handle(http.MethodPut, "/configurations", s.handleUpdateConfigurations)
+}
+
+// handleGetConfigurations returns all editable items but the write-only ones, like passwords and secrets.
+func (s *Server) handleGetConfigurations(w http.ResponseWriter, _ *http.Request, _ params) {
+	resp := map[string]interface{}{}
+
+	for key := range jsonFields(reflect.TypeOf(model.ConfigurationsResponse{})) {
+		if v, ok := s.config[key]; ok {
+			resp[key] = map[string]interface{}{"editable": true, "value": v}
+		}
+	}
+
+	if sched := s.schedules[jobScanAll]; sched != nil {
+		resp["scan_all_policy"] = &model.ConfigurationsResponseScanAllPolicy{Type: strings.ToLower(sched.Schedule.Type)}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleUpdateConfigurations updates the given items. Like Harbor, it refuses to change the authentication mode
+// once users other than the admin exist.
+func (s *Server) handleUpdateConfigurations(w http.ResponseWriter, r *http.Request, _ params) {
+	var values map[string]json.RawMessage
+	if !decode(w, r, &values) {
+		return
+	}
+
+	known := jsonFields(reflect.TypeOf(model.Configurations{}))
+
+	for key, v := range values {
+		if _, ok := known[key]; !ok {
+			writeError(w, http.StatusBadRequest, "unknown configuration item %s", key)
+			return
+		}
+
+		if key == "auth_mode" && !bytes.Equal(v, s.config[key]) && len(s.users) > 1 {
+			writeError(w, http.StatusBadRequest, "the auth mode can not be changed, as there are users other than the admin")
+			return
+		}
+	}
+
+	raw, _ := json.Marshal(values)
+	if err := json.Unmarshal(raw, &model.Configurations{}); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid configuration: %v", err)
+		return
+	}
+
+	for key, v := range values {
+		s.config[key] = v
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,257 @@
+package fakeharbor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+// Kinds of the system jobs, which equal their path segment below "/system".
+const (
+	jobGC      = "gc"
+	jobPurge   = "purgeaudit"
+	jobScanAll = "scanAll"
+)
+
+// jobNames are the job names Harbor reports for each kind of system job.
+var jobNames = map[string]string{
+	jobGC:      "GARBAGE_COLLECTION",
+	jobPurge:   "PURGE_AUDIT_LOG",
+	jobScanAll: "SCAN_ALL",
+}
+
+// job is a single run of a system job.
+type job struct {
+	model.ExecHistory
+
+	log string
+}
+
+// scheduleHistory returns 'sched' in the format of the schedule endpoints of GC and audit log purge.
+func scheduleHistory(sched *model.Schedule) *model.ExecHistory {
+	if sched == nil {
+		return &model.ExecHistory{}
+	}
+
+	params, _ := json.Marshal(sched.Parameters)
+
+	return &model.ExecHistory{
+		CreationTime:  sched.CreationTime,
+		ID:            sched.ID,
+		JobParameters: string(params),
+		Schedule:      sched.Schedule,
+		UpdateTime:    sched.UpdateTime,
+	}
+}
+
+func (s *Server) registerJobRoutes() {
+	for _, kind := range []string{jobGC, jobPurge} {
+		kind := kind
+		base := "/system/" + kind
+
+		s.handle(http.MethodPost, base+"/schedule", s.handleCreateSchedule(kind))
+		s.handle(http.MethodGet, base+"/schedule", s.handleGetSchedule(kind))
+		s.handle(http.MethodPut, base+"/schedule", s.handleUpdateSchedule(kind))
+		s.handle(http.MethodGet, base, s.handleListJobs(kind))
+		s.handle(http.MethodGet, base+"/{id}", s.handleGetJob(kind))
+		s.handle(http.MethodPut, base+"/{id}", s.handleStopJob(kind))
+		s.handle(http.MethodGet, base+"/{id}/log", s.handleGetJobLog(kind))
+	}
+
+	s.handle(http.MethodPost, "/system/scanAll/schedule", s.handleCreateSchedule(jobScanAll))
+	s.handle(http.MethodGet, "/system/scanAll/schedule", s.handleGetSchedule(jobScanAll))
+	s.handle(http.MethodPut, "/system/scanAll/schedule", s.handleUpdateSchedule(jobScanAll))
+	s.handle(http.MethodPost, "/system/scanAll/stop", func(w http.ResponseWriter, _ *http.Request, _ params) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	s.handle(http.MethodGet, "/scans/all/metrics", s.handleGetScanAllMetrics("Manual"))
+	s.handle(http.MethodGet, "/scans/schedule/metrics", s.handleGetScanAllMetrics("Schedule"))
+}
+
+// decodeSchedule reads a schedule request, responding with 400 if it is invalid.
+func decodeSchedule(w http.ResponseWriter, r *http.Request) *model.Schedule {
+	req := &model.Schedule{}
+	if !decode(w, r, req) {
+		return nil
+	}
+
+	if req.Schedule == nil || req.Schedule.Type == "" {
+		writeError(w, http.StatusBadRequest, "the schedule type must be set")
+		return nil
+	}
+
+	if req.Schedule.Type == "Custom" && req.Schedule.Cron == "" {
+		writeError(w, http.StatusBadRequest, "custom schedules require a cron expression")
+		return nil
+	}
+
+	return req
+}
+
+// setSchedule sets or, for the type "None", removes the schedule of the job 'kind'.
+func (s *Server) setSchedule(kind string, req *model.Schedule) {
+	if req.Schedule.Type == "None" {
+		delete(s.schedules, kind)
+		return
+	}
+
+	sched := s.schedules[kind]
+	if sched == nil {
+		sched = &model.Schedule{ID: s.id("schedule"), CreationTime: now()}
+		s.schedules[kind] = sched
+	}
+
+	sched.Parameters = req.Parameters
+	sched.Schedule = req.Schedule
+	sched.UpdateTime = now()
+}
+
+// run records a completed run of the job 'kind' triggered by 'trigger', i.e. "MANUAL" or "SCHEDULE".
+func (s *Server) run(kind, trigger string, req *model.Schedule) *job {
+	params, _ := json.Marshal(req.Parameters)
+
+	j := &job{ExecHistory: model.ExecHistory{
+		CreationTime:  now(),
+		ID:            s.id(kind),
+		JobKind:       trigger,
+		JobName:       jobNames[kind],
+		JobParameters: string(params),
+		JobStatus:     "Success",
+		Schedule:      req.Schedule,
+	}}
+
+	j.UpdateTime = j.CreationTime
+	j.log = fmt.Sprintf("%s job %d finished successfully\n", jobNames[kind], j.ID)
+
+	s.jobs[kind] = append(s.jobs[kind], j)
+
+	return j
+}
+
+// handleCreateSchedule creates the schedule of the job 'kind', or runs the job immediately for the type "Manual".
+// Like Harbor, it responds with 409 if a schedule already exists.
+func (s *Server) handleCreateSchedule(kind string) func(http.ResponseWriter, *http.Request, params) {
+	return func(w http.ResponseWriter, r *http.Request, _ params) {
+		req := decodeSchedule(w, r)
+		if req == nil {
+			return
+		}
+
+		if req.Schedule.Type == "Manual" {
+			j := s.run(kind, "MANUAL", req)
+			writeCreated(w, r, j.ID)
+
+			return
+		}
+
+		if s.schedules[kind] != nil && req.Schedule.Type != "None" {
+			writeError(w, http.StatusConflict, "the %s schedule already exists", jobNames[kind])
+			return
+		}
+
+		s.setSchedule(kind, req)
+
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+// handleGetSchedule returns the schedule of the job 'kind', which is empty if there is none.
+func (s *Server) handleGetSchedule(kind string) func(http.ResponseWriter, *http.Request, params) {
+	return func(w http.ResponseWriter, _ *http.Request, _ params) {
+		sched := s.schedules[kind]
+
+		switch {
+		case kind != jobScanAll:
+			writeJSON(w, http.StatusOK, scheduleHistory(sched))
+		case sched == nil:
+			writeJSON(w, http.StatusOK, &model.Schedule{})
+		default:
+			writeJSON(w, http.StatusOK, sched)
+		}
+	}
+}
+
+func (s *Server) handleUpdateSchedule(kind string) func(http.ResponseWriter, *http.Request, params) {
+	return func(w http.ResponseWriter, r *http.Request, _ params) {
+		req := decodeSchedule(w, r)
+		if req == nil {
+			return
+		}
+
+		if req.Schedule.Type == "Manual" {
+			writeError(w, http.StatusBadRequest, "manual runs can not be scheduled")
+			return
+		}
+
+		s.setSchedule(kind, req)
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// handleListJobs lists the runs of the job 'kind', the latest first.
+func (s *Server) handleListJobs(kind string) func(http.ResponseWriter, *http.Request, params) {
+	return func(w http.ResponseWriter, r *http.Request, _ params) {
+		jobs := make([]*model.ExecHistory, 0, len(s.jobs[kind]))
+
+		for i := len(s.jobs[kind]) - 1; i >= 0; i-- {
+			jobs = append(jobs, &s.jobs[kind][i].ExecHistory)
+		}
+
+		writePage(w, r, jobs)
+	}
+}
+
+// lookupJob resolves the "id" path parameter of the job 'kind', responding with 404 if unknown.
+func (s *Server) lookupJob(w http.ResponseWriter, kind string, p params) *job {
+	id, _ := p.int64("id")
+
+	for _, j := range s.jobs[kind] {
+		if j.ID == id {
+			return j
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "%s job %s not found", jobNames[kind], p["id"])
+
+	return nil
+}
+
+func (s *Server) handleGetJob(kind string) func(http.ResponseWriter, *http.Request, params) {
+	return func(w http.ResponseWriter, _ *http.Request, p params) {
+		if j := s.lookupJob(w, kind, p); j != nil {
+			writeJSON(w, http.StatusOK, &j.ExecHistory)
+		}
+	}
+}
+
+// handleStopJob stops a run of the job 'kind', which has no effect as runs complete immediately.
+func (s *Server) handleStopJob(kind string) func(http.ResponseWriter, *http.Request, params) {
+	return func(w http.ResponseWriter, _ *http.Request, p params) {
+		if j := s.lookupJob(w, kind, p); j != nil {
+			w.WriteHeader(http.StatusOK)
+		}
+	}
+}
+
+func (s *Server) handleGetJobLog(kind string) func(http.ResponseWriter, *http.Request, params) {
+	return func(w http.ResponseWriter, _ *http.Request, p params) {
+		j := s.lookupJob(w, kind, p)
+		if j == nil {
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(j.log))
+	}
+}
+
+// handleGetScanAllMetrics reports the latest scan all run with the given trigger, i.e. "Manual" or "Schedule".
+// As scanning is not simulated, each run completes immediately without scanning any artifact.
+func (s *Server) handleGetScanAllMetrics(trigger string) func(http.ResponseWriter, *http.Request, params) {
+	return func(w http.ResponseWriter, _ *http.Request, _ params) {
+		writeJSON(w, http.StatusOK, &model.Stats{Trigger: trigger, Metrics: map[string]int64{}})
+	}
+}
@@ -0,0 +1,167 @@
+package fakeharbor
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+func (s *Server) registerLabelRoutes() {
+	s.handle(http.MethodPost, "/labels", s.handleCreateLabel)
+	s.handle(http.MethodGet, "/labels", s.handleListLabels)
+	s.handle(http.MethodGet, "/labels/{label_id}", s.handleGetLabel)
+	s.handle(http.MethodPut, "/labels/{label_id}", s.handleUpdateLabel)
+	s.handle(http.MethodDelete, "/labels/{label_id}", s.handleDeleteLabel)
+}
+
+// labelExists returns true if a label named 'name' exists within the scope of 'l', ignoring 'l' itself.
+func (s *Server) labelExists(l *model.Label, name string) bool {
+	for _, existing := range s.labels {
+		if existing.ID != l.ID && existing.Name == name && existing.Scope == l.Scope && existing.ProjectID == l.ProjectID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) handleCreateLabel(w http.ResponseWriter, r *http.Request, _ params) {
+	l := &model.Label{}
+	if !decode(w, r, l) {
+		return
+	}
+
+	if l.Name == "" {
+		writeError(w, http.StatusBadRequest, "label name must not be empty")
+		return
+	}
+
+	switch l.Scope {
+	case "", "g":
+		l.Scope, l.ProjectID = "g", 0
+	case "p":
+		if _, ok := s.projects[l.ProjectID]; !ok {
+			writeError(w, http.StatusBadRequest, "project %d not found", l.ProjectID)
+			return
+		}
+	default:
+		writeError(w, http.StatusBadRequest, "invalid label scope %q", l.Scope)
+		return
+	}
+
+	if s.labelExists(l, l.Name) {
+		writeError(w, http.StatusConflict, "label %s already exists", l.Name)
+		return
+	}
+
+	l.ID = s.id("label")
+	l.CreationTime = now()
+	l.UpdateTime = l.CreationTime
+
+	s.labels[l.ID] = l
+
+	writeCreated(w, r, l.ID)
+}
+
+func (s *Server) handleListLabels(w http.ResponseWriter, r *http.Request, _ params) {
+	scope := r.URL.Query().Get("scope")
+	name := r.URL.Query().Get("name")
+	projectID := r.URL.Query().Get("project_id")
+
+	if scope == "p" && projectID == "" {
+		writeError(w, http.StatusBadRequest, "project_id is required for project scoped labels")
+		return
+	}
+
+	q := parseQuery(r)
+
+	var labels []*model.Label
+
+	for _, id := range sortedIDs(s.labels) {
+		l := s.labels[id]
+
+		if scope != "" && l.Scope != scope {
+			continue
+		}
+
+		if scope == "p" && strconv.FormatInt(l.ProjectID, 10) != projectID {
+			continue
+		}
+
+		if !strings.Contains(l.Name, name) || !q.matches(map[string]string{"name": l.Name}) {
+			continue
+		}
+
+		labels = append(labels, l)
+	}
+
+	writePage(w, r, labels)
+}
+
+// lookupLabel resolves the "label_id" path parameter, responding with 404 if unknown.
+func (s *Server) lookupLabel(w http.ResponseWriter, p params) *model.Label {
+	id, _ := p.int64("label_id")
+
+	l, ok := s.labels[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "label %s not found", p["label_id"])
+		return nil
+	}
+
+	return l
+}
+
+func (s *Server) handleGetLabel(w http.ResponseWriter, _ *http.Request, p params) {
+	if l := s.lookupLabel(w, p); l != nil {
+		writeJSON(w, http.StatusOK, l)
+	}
+}
+
+func (s *Server) handleUpdateLabel(w http.ResponseWriter, r *http.Request, p params) {
+	l := s.lookupLabel(w, p)
+	if l == nil {
+		return
+	}
+
+	req := &model.Label{}
+	if !decode(w, r, req) {
+		return
+	}
+
+	if req.Name != "" && req.Name != l.Name {
+		if s.labelExists(l, req.Name) {
+			writeError(w, http.StatusConflict, "label %s already exists", req.Name)
+			return
+		}
+
+		l.Name = req.Name
+	}
+
+	l.Color = req.Color
+	l.Description = req.Description
+	l.UpdateTime = now()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleDeleteLabel(w http.ResponseWriter, _ *http.Request, p params) {
+	l := s.lookupLabel(w, p)
+	if l == nil {
+		return
+	}
+
+	for _, a := range s.artifacts {
+		for i, al := range a.Labels {
+			if al.ID == l.ID {
+				a.Labels = append(a.Labels[:i], a.Labels[i+1:]...)
+				break
+			}
+		}
+	}
+
+	delete(s.labels, l.ID)
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,433 @@
+package fakeharbor
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+func (s *Server) registerPolicyRoutes() {
+	s.handle(http.MethodPost, "/replication/policies", s.handleCreateReplicationPolicy)
+	s.handle(http.MethodGet, "/replication/policies", s.handleListReplicationPolicies)
+	s.handle(http.MethodGet, "/replication/policies/{id}", s.handleGetReplicationPolicy)
+	s.handle(http.MethodPut, "/replication/policies/{id}", s.handleUpdateReplicationPolicy)
+	s.handle(http.MethodDelete, "/replication/policies/{id}", s.handleDeleteReplicationPolicy)
+
+	s.handle(http.MethodPost, "/retentions", s.handleCreateRetention)
+	s.handle(http.MethodGet, "/retentions/{id}", s.handleGetRetention)
+	s.handle(http.MethodPut, "/retentions/{id}", s.handleUpdateRetention)
+	s.handle(http.MethodDelete, "/retentions/{id}", s.handleDeleteRetention)
+
+	s.handle(http.MethodPost, "/projects/{project_name_or_id}/immutabletagrules", s.handleCreateImmutableRule)
+	s.handle(http.MethodGet, "/projects/{project_name_or_id}/immutabletagrules", s.handleListImmutableRules)
+	s.handle(http.MethodPut, "/projects/{project_name_or_id}/immutabletagrules/{immutable_rule_id}", s.handleUpdateImmutableRule)
+	s.handle(http.MethodDelete, "/projects/{project_name_or_id}/immutabletagrules/{immutable_rule_id}", s.handleDeleteImmutableRule)
+
+	s.handle(http.MethodPost, "/projects/{project_name_or_id}/webhook/policies", s.handleCreateWebhookPolicy)
+	s.handle(http.MethodGet, "/projects/{project_name_or_id}/webhook/policies", s.handleListWebhookPolicies)
+	s.handle(http.MethodGet, "/projects/{project_name_or_id}/webhook/policies/{webhook_policy_id}", s.handleGetWebhookPolicy)
+	s.handle(http.MethodPut, "/projects/{project_name_or_id}/webhook/policies/{webhook_policy_id}", s.handleUpdateWebhookPolicy)
+	s.handle(http.MethodDelete, "/projects/{project_name_or_id}/webhook/policies/{webhook_policy_id}", s.handleDeleteWebhookPolicy)
+}
+
+// resolveRegistry returns the stored registry referenced by 'ref', or the local registry for nil or ID 0.
+// It responds with 400 and returns false if the registry is unknown.
+func (s *Server) resolveRegistry(w http.ResponseWriter, ref *model.Registry) (*model.Registry, bool) {
+	if ref == nil || ref.ID == 0 {
+		return &model.Registry{ID: 0, Name: "Local", Type: "harbor", Status: "healthy"}, true
+	}
+
+	reg, ok := s.registries[ref.ID]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "registry %d not found", ref.ID)
+		return nil, false
+	}
+
+	return registryModel(reg), true
+}
+
+// validateReplicationPolicy resolves the policy's registries and checks its name for conflicts.
+func (s *Server) validateReplicationPolicy(w http.ResponseWriter, rp *model.ReplicationPolicy) bool {
+	if rp.Name == "" {
+		writeError(w, http.StatusBadRequest, "replication policy name must not be empty")
+		return false
+	}
+
+	for _, existing := range s.replications {
+		if existing.ID != rp.ID && existing.Name == rp.Name {
+			writeError(w, http.StatusConflict, "replication policy %s already exists", rp.Name)
+			return false
+		}
+	}
+
+	local := func(reg *model.Registry) bool { return reg == nil || reg.ID == 0 }
+
+	if local(rp.SrcRegistry) == local(rp.DestRegistry) {
+		writeError(w, http.StatusBadRequest, "either the source or the destination registry must be the local registry")
+		return false
+	}
+
+	var ok bool
+
+	if rp.SrcRegistry, ok = s.resolveRegistry(w, rp.SrcRegistry); !ok {
+		return false
+	}
+
+	rp.DestRegistry, ok = s.resolveRegistry(w, rp.DestRegistry)
+
+	return ok
+}
+
+func (s *Server) handleCreateReplicationPolicy(w http.ResponseWriter, r *http.Request, _ params) {
+	rp := &model.ReplicationPolicy{}
+	if !decode(w, r, rp) || !s.validateReplicationPolicy(w, rp) {
+		return
+	}
+
+	rp.ID = s.id("replication")
+	rp.CreationTime = now()
+	rp.UpdateTime = rp.CreationTime
+
+	s.replications[rp.ID] = rp
+
+	writeCreated(w, r, rp.ID)
+}
+
+func (s *Server) handleListReplicationPolicies(w http.ResponseWriter, r *http.Request, _ params) {
+	q := parseQuery(r)
+	name := r.URL.Query().Get("name")
+
+	var policies []*model.ReplicationPolicy
+
+	for _, id := range sortedIDs(s.replications) {
+		rp := s.replications[id]
+
+		if name != "" && rp.Name != name || !q.matches(map[string]string{"name": rp.Name}) {
+			continue
+		}
+
+		policies = append(policies, rp)
+	}
+
+	writePage(w, r, policies)
+}
+
+// lookupReplicationPolicy resolves the "id" path parameter of replication policy routes, responding with 404 if unknown.
+func (s *Server) lookupReplicationPolicy(w http.ResponseWriter, p params) *model.ReplicationPolicy {
+	id, _ := p.int64("id")
+
+	rp, ok := s.replications[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "replication policy %s not found", p["id"])
+		return nil
+	}
+
+	return rp
+}
+
+func (s *Server) handleGetReplicationPolicy(w http.ResponseWriter, _ *http.Request, p params) {
+	if rp := s.lookupReplicationPolicy(w, p); rp != nil {
+		writeJSON(w, http.StatusOK, rp)
+	}
+}
+
+func (s *Server) handleUpdateReplicationPolicy(w http.ResponseWriter, r *http.Request, p params) {
+	current := s.lookupReplicationPolicy(w, p)
+	if current == nil {
+		return
+	}
+
+	rp := &model.ReplicationPolicy{}
+	if !decode(w, r, rp) {
+		return
+	}
+
+	rp.ID = current.ID
+	rp.CreationTime = current.CreationTime
+
+	if !s.validateReplicationPolicy(w, rp) {
+		return
+	}
+
+	rp.UpdateTime = now()
+	s.replications[rp.ID] = rp
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleDeleteReplicationPolicy(w http.ResponseWriter, _ *http.Request, p params) {
+	if rp := s.lookupReplicationPolicy(w, p); rp != nil {
+		delete(s.replications, rp.ID)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (s *Server) handleCreateRetention(w http.ResponseWriter, r *http.Request, _ params) {
+	policy := &model.RetentionPolicy{}
+	if !decode(w, r, policy) {
+		return
+	}
+
+	if policy.Scope == nil || policy.Scope.Level != "project" {
+		writeError(w, http.StatusBadRequest, "retention policies require a project scope")
+		return
+	}
+
+	proj, ok := s.projects[policy.Scope.Ref]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "project %d not found", policy.Scope.Ref)
+		return
+	}
+
+	if id, err := strconv.ParseInt(proj.metadata["retention_id"], 10, 64); err == nil && s.retentions[id] != nil {
+		writeError(w, http.StatusBadRequest, "project %s already has a retention policy", proj.name)
+		return
+	}
+
+	policy.ID = s.id("retention")
+	s.retentions[policy.ID] = policy
+	proj.metadata["retention_id"] = strconv.FormatInt(policy.ID, 10)
+
+	writeCreated(w, r, policy.ID)
+}
+
+// lookupRetention resolves the "id" path parameter of retention routes.
+// Like Harbor, it responds with 500 if the policy is unknown.
+func (s *Server) lookupRetention(w http.ResponseWriter, p params) *model.RetentionPolicy {
+	id, _ := p.int64("id")
+
+	policy, ok := s.retentions[id]
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "retention policy %s not found", p["id"])
+		return nil
+	}
+
+	return policy
+}
+
+func (s *Server) handleGetRetention(w http.ResponseWriter, _ *http.Request, p params) {
+	if policy := s.lookupRetention(w, p); policy != nil {
+		writeJSON(w, http.StatusOK, policy)
+	}
+}
+
+func (s *Server) handleUpdateRetention(w http.ResponseWriter, r *http.Request, p params) {
+	current := s.lookupRetention(w, p)
+	if current == nil {
+		return
+	}
+
+	policy := &model.RetentionPolicy{}
+	if !decode(w, r, policy) {
+		return
+	}
+
+	policy.ID = current.ID
+	policy.Scope = current.Scope
+	s.retentions[policy.ID] = policy
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleDeleteRetention(w http.ResponseWriter, _ *http.Request, p params) {
+	policy := s.lookupRetention(w, p)
+	if policy == nil {
+		return
+	}
+
+	// Like Harbor, keep the project's "retention_id" metadata referring to the deleted policy.
+	delete(s.retentions, policy.ID)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleCreateImmutableRule(w http.ResponseWriter, r *http.Request, p params) {
+	proj := s.lookupProject(w, p)
+	if proj == nil {
+		return
+	}
+
+	rule := &model.ImmutableRule{}
+	if !decode(w, r, rule) {
+		return
+	}
+
+	rule.ID = s.id("immutable")
+	proj.immutableRules[rule.ID] = rule
+
+	writeCreated(w, r, rule.ID)
+}
+
+func (s *Server) handleListImmutableRules(w http.ResponseWriter, r *http.Request, p params) {
+	proj := s.lookupProject(w, p)
+	if proj == nil {
+		return
+	}
+
+	var rules []*model.ImmutableRule
+	for _, id := range sortedIDs(proj.immutableRules) {
+		rules = append(rules, proj.immutableRules[id])
+	}
+
+	writePage(w, r, rules)
+}
+
+// lookupImmutableRule resolves the "immutable_rule_id" path parameter, responding with 404 if unknown.
+func (s *Server) lookupImmutableRule(w http.ResponseWriter, p params) (*project, *model.ImmutableRule) {
+	proj := s.lookupProject(w, p)
+	if proj == nil {
+		return nil, nil
+	}
+
+	id, _ := p.int64("immutable_rule_id")
+
+	rule, ok := proj.immutableRules[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "immutable rule %s not found", p["immutable_rule_id"])
+		return nil, nil
+	}
+
+	return proj, rule
+}
+
+func (s *Server) handleUpdateImmutableRule(w http.ResponseWriter, r *http.Request, p params) {
+	proj, current := s.lookupImmutableRule(w, p)
+	if current == nil {
+		return
+	}
+
+	rule := &model.ImmutableRule{}
+	if !decode(w, r, rule) {
+		return
+	}
+
+	rule.ID = current.ID
+	proj.immutableRules[rule.ID] = rule
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleDeleteImmutableRule(w http.ResponseWriter, _ *http.Request, p params) {
+	if proj, rule := s.lookupImmutableRule(w, p); rule != nil {
+		delete(proj.immutableRules, rule.ID)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// webhookNameTaken returns true if another webhook policy of 'proj' is named 'name'.
+func webhookNameTaken(proj *project, id int64, name string) bool {
+	for _, existing := range proj.webhooks {
+		if existing.ID != id && existing.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) handleCreateWebhookPolicy(w http.ResponseWriter, r *http.Request, p params) {
+	proj := s.lookupProject(w, p)
+	if proj == nil {
+		return
+	}
+
+	policy := &model.WebhookPolicy{}
+	if !decode(w, r, policy) {
+		return
+	}
+
+	if policy.Name == "" || len(policy.Targets) == 0 {
+		writeError(w, http.StatusBadRequest, "webhook policies require a name and at least one target")
+		return
+	}
+
+	if webhookNameTaken(proj, 0, policy.Name) {
+		writeError(w, http.StatusConflict, "webhook policy %s already exists", policy.Name)
+		return
+	}
+
+	creator, _, _ := r.BasicAuth()
+
+	policy.ID = s.id("webhook")
+	policy.ProjectID = proj.id
+	policy.Creator = creator
+	policy.CreationTime = now()
+	policy.UpdateTime = policy.CreationTime
+
+	proj.webhooks[policy.ID] = policy
+
+	writeCreated(w, r, policy.ID)
+}
+
+func (s *Server) handleListWebhookPolicies(w http.ResponseWriter, r *http.Request, p params) {
+	proj := s.lookupProject(w, p)
+	if proj == nil {
+		return
+	}
+
+	var policies []*model.WebhookPolicy
+	for _, id := range sortedIDs(proj.webhooks) {
+		policies = append(policies, proj.webhooks[id])
+	}
+
+	writePage(w, r, policies)
+}
+
+// lookupWebhookPolicy resolves the "webhook_policy_id" path parameter, responding with 404 if unknown.
+func (s *Server) lookupWebhookPolicy(w http.ResponseWriter, p params) (*project, *model.WebhookPolicy) {
+	proj := s.lookupProject(w, p)
+	if proj == nil {
+		return nil, nil
+	}
+
+	id, _ := p.int64("webhook_policy_id")
+
+	policy, ok := proj.webhooks[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "webhook policy %s not found", p["webhook_policy_id"])
+		return nil, nil
+	}
+
+	return proj, policy
+}
+
+func (s *Server) handleGetWebhookPolicy(w http.ResponseWriter, _ *http.Request, p params) {
+	if _, policy := s.lookupWebhookPolicy(w, p); policy != nil {
+		writeJSON(w, http.StatusOK, policy)
+	}
+}
+
+func (s *Server) handleUpdateWebhookPolicy(w http.ResponseWriter, r *http.Request, p params) {
+	proj, current := s.lookupWebhookPolicy(w, p)
+	if current == nil {
+		return
+	}
+
+	policy := &model.WebhookPolicy{}
+	if !decode(w, r, policy) {
+		return
+	}
+
+	if webhookNameTaken(proj, current.ID, policy.Name) {
+		writeError(w, http.StatusConflict, "webhook policy %s already exists", policy.Name)
+		return
+	}
+
+	policy.ID = current.ID
+	policy.ProjectID = proj.id
+	policy.Creator = current.Creator
+	policy.CreationTime = current.CreationTime
+	policy.UpdateTime = now()
+
+	proj.webhooks[policy.ID] = policy
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleDeleteWebhookPolicy(w http.ResponseWriter, _ *http.Request, p params) {
+	if proj, policy := s.lookupWebhookPolicy(w, p); policy != nil {
+		delete(proj.webhooks, policy.ID)
+		w.WriteHeader(http.StatusOK)
+	}
+}
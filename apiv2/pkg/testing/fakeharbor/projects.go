@@ -0,0 +1,652 @@
+package fakeharbor
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+var projectNameRegex = regexp.MustCompile(`^[a-z0-9]+(?:[._-][a-z0-9]+)*$`)
+
+// roleNames maps Harbor's project role IDs to their names.
+var roleNames = map[int64]string{
+	1: "projectAdmin",
+	2: "developer",
+	3: "guest",
+	4: "maintainer",
+	5: "limitedGuest",
+}
+
+type project struct {
+	id           int64
+	name         string
+	owner        *user
+	registryID   int64
+	cveAllowlist *model.CVEAllowlist
+	created      strfmt.DateTime
+	updated      strfmt.DateTime
+	metadata     map[string]string
+	storageLimit int64
+
+	members        map[int64]*model.ProjectMemberEntity
+	immutableRules map[int64]*model.ImmutableRule
+	webhooks       map[int64]*model.WebhookPolicy
+}
+
+func (s *Server) projectModel(p *project) *model.Project {
+	meta := &model.ProjectMetadata{}
+
+	raw, _ := json.Marshal(p.metadata)
+	_ = json.Unmarshal(raw, meta)
+
+	var repoCount int64
+
+	for _, r := range s.repositories {
+		if r.projectID == p.id {
+			repoCount++
+		}
+	}
+
+	return &model.Project{
+		CreationTime:       p.created,
+		CurrentUserRoleID:  1,
+		CurrentUserRoleIds: []int32{1},
+		CVEAllowlist:       p.cveAllowlist,
+		Metadata:           meta,
+		Name:               p.name,
+		OwnerID:            int32(p.owner.id),
+		OwnerName:          p.owner.name,
+		ProjectID:          int32(p.id),
+		RegistryID:         p.registryID,
+		RepoCount:          repoCount,
+		UpdateTime:         p.updated,
+	}
+}
+
+// project returns the project identified by its name or ID.
+func (s *Server) project(nameOrID string) *project {
+	if id, err := strconv.ParseInt(nameOrID, 10, 64); err == nil {
+		return s.projects[id]
+	}
+
+	for _, p := range s.projects {
+		if p.name == nameOrID {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// lookupProject resolves the "project_name_or_id" or "project_name" path parameter, responding with 404 if unknown.
+func (s *Server) lookupProject(w http.ResponseWriter, p params) *project {
+	nameOrID, ok := p["project_name_or_id"]
+	if !ok {
+		nameOrID = p["project_name"]
+	}
+
+	proj := s.project(nameOrID)
+	if proj == nil {
+		writeError(w, http.StatusNotFound, "project %s not found", nameOrID)
+	}
+
+	return proj
+}
+
+// metadataMap converts project metadata into its key/value representation.
+func metadataMap(meta *model.ProjectMetadata) map[string]string {
+	values := map[string]string{}
+
+	if meta != nil {
+		raw, _ := json.Marshal(meta)
+		_ = json.Unmarshal(raw, &values)
+	}
+
+	return values
+}
+
+func (s *Server) createProject(req *model.ProjectReq, owner string) *project {
+	p := &project{
+		id:             s.id("project"),
+		name:           req.ProjectName,
+		owner:          s.users[owner],
+		cveAllowlist:   req.CVEAllowlist,
+		created:        now(),
+		metadata:       metadataMap(req.Metadata),
+		storageLimit:   -1,
+		members:        map[int64]*model.ProjectMemberEntity{},
+		immutableRules: map[int64]*model.ImmutableRule{},
+		webhooks:       map[int64]*model.WebhookPolicy{},
+	}
+
+	p.updated = p.created
+
+	if p.owner == nil {
+		p.owner = s.users[AdminUser]
+	}
+
+	if p.metadata["public"] == "" {
+		p.metadata["public"] = "false"
+	}
+
+	if req.Public != nil {
+		p.metadata["public"] = strconv.FormatBool(*req.Public)
+	}
+
+	if req.RegistryID != nil {
+		p.registryID = *req.RegistryID
+	}
+
+	if req.StorageLimit != nil && *req.StorageLimit > 0 {
+		p.storageLimit = *req.StorageLimit
+	}
+
+	if p.cveAllowlist == nil {
+		p.cveAllowlist = &model.CVEAllowlist{ProjectID: p.id, Items: []*model.CVEAllowlistItem{}}
+	}
+
+	mid := s.id("member")
+	p.members[mid] = &model.ProjectMemberEntity{
+		EntityID:   p.owner.id,
+		EntityName: p.owner.name,
+		EntityType: "u",
+		ID:         mid,
+		ProjectID:  p.id,
+		RoleID:     1,
+		RoleName:   roleNames[1],
+	}
+
+	s.projects[p.id] = p
+
+	return p
+}
+
+func (s *Server) registerProjectRoutes() {
+	s.handle(http.MethodPost, "/projects", s.handleCreateProject)
+	s.handle(http.MethodHead, "/projects", s.handleHeadProject)
+	s.handle(http.MethodGet, "/projects", s.handleListProjects)
+	s.handle(http.MethodGet, "/projects/{project_name_or_id}", s.handleGetProject)
+	s.handle(http.MethodPut, "/projects/{project_name_or_id}", s.handleUpdateProject)
+	s.handle(http.MethodDelete, "/projects/{project_name_or_id}", s.handleDeleteProject)
+
+	s.handle(http.MethodGet, "/projects/{project_name_or_id}/metadatas", s.handleListProjectMetadata)
+	s.handle(http.MethodPost, "/projects/{project_name_or_id}/metadatas", s.handleAddProjectMetadata)
+	s.handle(http.MethodGet, "/projects/{project_name_or_id}/metadatas/{meta_name}", s.handleGetProjectMetadata)
+	s.handle(http.MethodPut, "/projects/{project_name_or_id}/metadatas/{meta_name}", s.handleAddProjectMetadata)
+	s.handle(http.MethodDelete, "/projects/{project_name_or_id}/metadatas/{meta_name}", s.handleDeleteProjectMetadata)
+
+	s.handle(http.MethodPost, "/projects/{project_name_or_id}/members", s.handleCreateMember)
+	s.handle(http.MethodGet, "/projects/{project_name_or_id}/members", s.handleListMembers)
+	s.handle(http.MethodGet, "/projects/{project_name_or_id}/members/{mid}", s.handleGetMember)
+	s.handle(http.MethodPut, "/projects/{project_name_or_id}/members/{mid}", s.handleUpdateMember)
+	s.handle(http.MethodDelete, "/projects/{project_name_or_id}/members/{mid}", s.handleDeleteMember)
+
+	s.handle(http.MethodGet, "/quotas", s.handleListQuotas)
+	s.handle(http.MethodGet, "/quotas/{id}", s.handleGetQuota)
+	s.handle(http.MethodPut, "/quotas/{id}", s.handleUpdateQuota)
+}
+
+func (s *Server) handleCreateProject(w http.ResponseWriter, r *http.Request, _ params) {
+	req := &model.ProjectReq{}
+	if !decode(w, r, req) {
+		return
+	}
+
+	if !projectNameRegex.MatchString(req.ProjectName) || len(req.ProjectName) > 255 {
+		writeError(w, http.StatusBadRequest, "invalid project name %q", req.ProjectName)
+		return
+	}
+
+	if s.project(req.ProjectName) != nil {
+		writeError(w, http.StatusConflict, "project %s already exists", req.ProjectName)
+		return
+	}
+
+	if req.RegistryID != nil && *req.RegistryID != 0 && s.registries[*req.RegistryID] == nil {
+		writeError(w, http.StatusBadRequest, "registry %d not found", *req.RegistryID)
+		return
+	}
+
+	name, _, _ := r.BasicAuth()
+	p := s.createProject(req, name)
+	s.audit(name, "create", "project", p.name)
+
+	writeCreated(w, r, p.id)
+}
+
+func (s *Server) handleHeadProject(w http.ResponseWriter, r *http.Request, _ params) {
+	if s.project(r.URL.Query().Get("project_name")) == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleListProjects(w http.ResponseWriter, r *http.Request, _ params) {
+	q := parseQuery(r)
+	nameFilter := r.URL.Query().Get("name")
+	publicFilter := r.URL.Query().Get("public")
+
+	var projects []*model.Project
+
+	for _, id := range sortedIDs(s.projects) {
+		p := s.projects[id]
+
+		if !strings.Contains(p.name, nameFilter) {
+			continue
+		}
+
+		if publicFilter != "" && publicFilter != p.metadata["public"] {
+			continue
+		}
+
+		if !q.matches(map[string]string{"name": p.name, "project_id": strconv.FormatInt(p.id, 10), "public": p.metadata["public"]}) {
+			continue
+		}
+
+		projects = append(projects, s.projectModel(p))
+	}
+
+	writePage(w, r, projects)
+}
+
+func (s *Server) handleGetProject(w http.ResponseWriter, _ *http.Request, p params) {
+	if proj := s.lookupProject(w, p); proj != nil {
+		writeJSON(w, http.StatusOK, s.projectModel(proj))
+	}
+}
+
+func (s *Server) handleUpdateProject(w http.ResponseWriter, r *http.Request, p params) {
+	proj := s.lookupProject(w, p)
+	if proj == nil {
+		return
+	}
+
+	req := &model.ProjectReq{}
+	if !decode(w, r, req) {
+		return
+	}
+
+	for k, v := range metadataMap(req.Metadata) {
+		proj.metadata[k] = v
+	}
+
+	if req.Public != nil {
+		proj.metadata["public"] = strconv.FormatBool(*req.Public)
+	}
+
+	if req.CVEAllowlist != nil {
+		proj.cveAllowlist = req.CVEAllowlist
+	}
+
+	if req.StorageLimit != nil {
+		proj.storageLimit = *req.StorageLimit
+	}
+
+	proj.updated = now()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleDeleteProject(w http.ResponseWriter, r *http.Request, p params) {
+	proj := s.lookupProject(w, p)
+	if proj == nil {
+		return
+	}
+
+	for _, repo := range s.repositories {
+		if repo.projectID == proj.id {
+			writeError(w, http.StatusPreconditionFailed, "the project %s contains repositories, can not be deleted", proj.name)
+			return
+		}
+	}
+
+	for id, l := range s.labels {
+		if l.ProjectID == proj.id {
+			delete(s.labels, id)
+		}
+	}
+
+	for id, rb := range s.robots {
+		if rb.Level == "project" && rb.projectID == proj.id {
+			delete(s.robots, id)
+		}
+	}
+
+	if id, err := strconv.ParseInt(proj.metadata["retention_id"], 10, 64); err == nil {
+		delete(s.retentions, id)
+	}
+
+	delete(s.projects, proj.id)
+	s.auditRequest(r, "delete", "project", proj.name)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleListProjectMetadata(w http.ResponseWriter, _ *http.Request, p params) {
+	if proj := s.lookupProject(w, p); proj != nil {
+		writeJSON(w, http.StatusOK, proj.metadata)
+	}
+}
+
+func (s *Server) handleGetProjectMetadata(w http.ResponseWriter, _ *http.Request, p params) {
+	proj := s.lookupProject(w, p)
+	if proj == nil {
+		return
+	}
+
+	// Like Harbor, respond with an empty object if the metadata is not set.
+	values := map[string]string{}
+	if v, ok := proj.metadata[p["meta_name"]]; ok {
+		values[p["meta_name"]] = v
+	}
+
+	writeJSON(w, http.StatusOK, values)
+}
+
+// handleAddProjectMetadata handles both adding (POST) and updating (PUT) metadata values.
+func (s *Server) handleAddProjectMetadata(w http.ResponseWriter, r *http.Request, p params) {
+	proj := s.lookupProject(w, p)
+	if proj == nil {
+		return
+	}
+
+	var values map[string]string
+	if !decode(w, r, &values) {
+		return
+	}
+
+	if name, ok := p["meta_name"]; ok {
+		if _, ok := values[name]; !ok || len(values) != 1 {
+			writeError(w, http.StatusBadRequest, "the request body must contain exactly the metadata %s", name)
+			return
+		}
+	}
+
+	for k, v := range values {
+		proj.metadata[k] = v
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleDeleteProjectMetadata(w http.ResponseWriter, _ *http.Request, p params) {
+	proj := s.lookupProject(w, p)
+	if proj == nil {
+		return
+	}
+
+	if _, ok := proj.metadata[p["meta_name"]]; !ok {
+		writeError(w, http.StatusNotFound, "metadata %s not found", p["meta_name"])
+		return
+	}
+
+	delete(proj.metadata, p["meta_name"])
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleCreateMember(w http.ResponseWriter, r *http.Request, p params) {
+	proj := s.lookupProject(w, p)
+	if proj == nil {
+		return
+	}
+
+	req := &model.ProjectMember{}
+	if !decode(w, r, req) {
+		return
+	}
+
+	if _, ok := roleNames[req.RoleID]; !ok {
+		writeError(w, http.StatusBadRequest, "invalid role id %d", req.RoleID)
+		return
+	}
+
+	entity := &model.ProjectMemberEntity{
+		ProjectID: proj.id,
+		RoleID:    req.RoleID,
+		RoleName:  roleNames[req.RoleID],
+	}
+
+	switch {
+	case req.MemberUser != nil:
+		u := s.users[req.MemberUser.Username]
+
+		if u == nil {
+			for _, candidate := range s.users {
+				if req.MemberUser.UserID != 0 && candidate.id == req.MemberUser.UserID {
+					u = candidate
+				}
+			}
+		}
+
+		if u == nil {
+			writeError(w, http.StatusNotFound, "user %s not found", req.MemberUser.Username)
+			return
+		}
+
+		entity.EntityID, entity.EntityName, entity.EntityType = u.id, u.name, "u"
+	case req.MemberGroup != nil && req.MemberGroup.GroupName != "":
+		entity.EntityID, entity.EntityName, entity.EntityType = s.id("usergroup"), req.MemberGroup.GroupName, "g"
+	default:
+		writeError(w, http.StatusBadRequest, "either member_user or member_group must be set")
+		return
+	}
+
+	for _, m := range proj.members {
+		if m.EntityType == entity.EntityType && m.EntityName == entity.EntityName {
+			writeError(w, http.StatusConflict, "member %s already exists in project %s", entity.EntityName, proj.name)
+			return
+		}
+	}
+
+	entity.ID = s.id("member")
+	proj.members[entity.ID] = entity
+
+	writeCreated(w, r, entity.ID)
+}
+
+func (s *Server) handleListMembers(w http.ResponseWriter, r *http.Request, p params) {
+	proj := s.lookupProject(w, p)
+	if proj == nil {
+		return
+	}
+
+	entityName := r.URL.Query().Get("entityname")
+
+	var members []*model.ProjectMemberEntity
+
+	for _, id := range sortedIDs(proj.members) {
+		if m := proj.members[id]; strings.Contains(m.EntityName, entityName) {
+			members = append(members, m)
+		}
+	}
+
+	writePage(w, r, members)
+}
+
+// lookupMember resolves the "mid" path parameter, responding with 404 if unknown.
+func (s *Server) lookupMember(w http.ResponseWriter, p params) (*project, *model.ProjectMemberEntity) {
+	proj := s.lookupProject(w, p)
+	if proj == nil {
+		return nil, nil
+	}
+
+	mid, _ := p.int64("mid")
+
+	m, ok := proj.members[mid]
+	if !ok {
+		writeError(w, http.StatusNotFound, "member %s not found", p["mid"])
+		return nil, nil
+	}
+
+	return proj, m
+}
+
+func (s *Server) handleGetMember(w http.ResponseWriter, _ *http.Request, p params) {
+	if _, m := s.lookupMember(w, p); m != nil {
+		writeJSON(w, http.StatusOK, m)
+	}
+}
+
+func (s *Server) handleUpdateMember(w http.ResponseWriter, r *http.Request, p params) {
+	_, m := s.lookupMember(w, p)
+	if m == nil {
+		return
+	}
+
+	req := &model.RoleRequest{}
+	if !decode(w, r, req) {
+		return
+	}
+
+	if _, ok := roleNames[req.RoleID]; !ok {
+		writeError(w, http.StatusBadRequest, "invalid role id %d", req.RoleID)
+		return
+	}
+
+	m.RoleID, m.RoleName = req.RoleID, roleNames[req.RoleID]
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleDeleteMember(w http.ResponseWriter, _ *http.Request, p params) {
+	proj, m := s.lookupMember(w, p)
+	if m == nil {
+		return
+	}
+
+	delete(proj.members, m.ID)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// quotaModel returns the storage quota of a project. Quota IDs equal their project's ID.
+func (s *Server) quotaModel(p *project) *model.Quota {
+	var used int64
+
+	for _, a := range s.artifacts {
+		if a.ProjectID == p.id {
+			used += a.Size
+		}
+	}
+
+	return &model.Quota{
+		CreationTime: p.created,
+		Hard:         model.ResourceList{"storage": p.storageLimit},
+		ID:           p.id,
+		Ref: map[string]interface{}{
+			"id":         p.id,
+			"name":       p.name,
+			"owner_name": p.owner.name,
+		},
+		UpdateTime: p.updated,
+		Used:       model.ResourceList{"storage": used},
+	}
+}
+
+func (s *Server) handleListQuotas(w http.ResponseWriter, r *http.Request, _ params) {
+	reference := r.URL.Query().Get("reference")
+	referenceID := r.URL.Query().Get("reference_id")
+
+	var quotas []*model.Quota
+
+	for _, id := range sortedIDs(s.projects) {
+		if reference != "" && reference != "project" {
+			continue
+		}
+
+		if referenceID != "" && referenceID != strconv.FormatInt(id, 10) {
+			continue
+		}
+
+		quotas = append(quotas, s.quotaModel(s.projects[id]))
+	}
+
+	writePage(w, r, quotas)
+}
+
+// lookupQuota resolves the "id" path parameter of quota routes, responding with 404 if unknown.
+func (s *Server) lookupQuota(w http.ResponseWriter, p params) *project {
+	id, _ := p.int64("id")
+
+	proj, ok := s.projects[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "quota %s not found", p["id"])
+		return nil
+	}
+
+	return proj
+}
+
+func (s *Server) handleGetQuota(w http.ResponseWriter, _ *http.Request, p params) {
+	if proj := s.lookupQuota(w, p); proj != nil {
+		writeJSON(w, http.StatusOK, s.quotaModel(proj))
+	}
+}
+
+func (s *Server) handleUpdateQuota(w http.ResponseWriter, r *http.Request, p params) {
+	proj := s.lookupQuota(w, p)
+	if proj == nil {
+		return
+	}
+
+	req := &model.QuotaUpdateReq{}
+	if !decode(w, r, req) {
+		return
+	}
+
+	storage, ok := req.Hard["storage"]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "only the storage quota is supported")
+		return
+	}
+
+	if storage <= 0 && storage != -1 {
+		writeError(w, http.StatusBadRequest, "invalid storage quota %d", storage)
+		return
+	}
+
+	proj.storageLimit = storage
+	proj.updated = now()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleGetStatistics reports the number of projects and repositories and the storage used by all artifacts.
+func (s *Server) handleGetStatistics(w http.ResponseWriter, _ *http.Request, _ params) {
+	stats := &model.Statistic{}
+
+	for _, p := range s.projects {
+		stats.TotalProjectCount++
+
+		if p.metadata["public"] == "true" {
+			stats.PublicProjectCount++
+		} else {
+			stats.PrivateProjectCount++
+		}
+	}
+
+	for _, repo := range s.repositories {
+		stats.TotalRepoCount++
+
+		if p := s.projects[repo.projectID]; p != nil && p.metadata["public"] == "true" {
+			stats.PublicRepoCount++
+		} else {
+			stats.PrivateRepoCount++
+		}
+	}
+
+	for _, a := range s.artifacts {
+		stats.TotalStorageConsumption += a.Size
+	}
+
+	writeJSON(w, http.StatusOK, stats)
+}
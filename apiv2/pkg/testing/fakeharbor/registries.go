@@ -0,0 +1,182 @@
+package fakeharbor
+
+import (
+	"net/http"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+func (s *Server) registerRegistryRoutes() {
+	s.handle(http.MethodPost, "/registries", s.handleCreateRegistry)
+	s.handle(http.MethodGet, "/registries", s.handleListRegistries)
+	s.handle(http.MethodGet, "/registries/{id}", s.handleGetRegistry)
+	s.handle(http.MethodPut, "/registries/{id}", s.handleUpdateRegistry)
+	s.handle(http.MethodDelete, "/registries/{id}", s.handleDeleteRegistry)
+}
+
+// registryModel returns a copy of 'reg' with a masked access secret, just like Harbor does.
+func registryModel(reg *model.Registry) *model.Registry {
+	m := *reg
+
+	if reg.Credential != nil {
+		cred := *reg.Credential
+		if cred.AccessSecret != "" {
+			cred.AccessSecret = "*****"
+		}
+
+		m.Credential = &cred
+	}
+
+	return &m
+}
+
+func (s *Server) registryByName(name string) *model.Registry {
+	for _, reg := range s.registries {
+		if reg.Name == name {
+			return reg
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) handleCreateRegistry(w http.ResponseWriter, r *http.Request, _ params) {
+	req := &model.Registry{}
+	if !decode(w, r, req) {
+		return
+	}
+
+	if req.Name == "" || req.URL == "" || req.Type == "" {
+		writeError(w, http.StatusBadRequest, "name, url and type of the registry are required")
+		return
+	}
+
+	if s.registryByName(req.Name) != nil {
+		writeError(w, http.StatusConflict, "registry %s already exists", req.Name)
+		return
+	}
+
+	req.ID = s.id("registry")
+	req.Status = "healthy"
+	req.CreationTime = now()
+	req.UpdateTime = req.CreationTime
+
+	s.registries[req.ID] = req
+
+	writeCreated(w, r, req.ID)
+}
+
+func (s *Server) handleListRegistries(w http.ResponseWriter, r *http.Request, _ params) {
+	q := parseQuery(r)
+
+	var registries []*model.Registry
+
+	for _, id := range sortedIDs(s.registries) {
+		reg := s.registries[id]
+
+		if q.matches(map[string]string{"name": reg.Name, "type": reg.Type, "url": reg.URL}) {
+			registries = append(registries, registryModel(reg))
+		}
+	}
+
+	writePage(w, r, registries)
+}
+
+// lookupRegistry resolves the "id" path parameter of registry routes, responding with 404 if unknown.
+func (s *Server) lookupRegistry(w http.ResponseWriter, p params) *model.Registry {
+	id, _ := p.int64("id")
+
+	reg, ok := s.registries[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "registry %s not found", p["id"])
+		return nil
+	}
+
+	return reg
+}
+
+func (s *Server) handleGetRegistry(w http.ResponseWriter, _ *http.Request, p params) {
+	if reg := s.lookupRegistry(w, p); reg != nil {
+		writeJSON(w, http.StatusOK, registryModel(reg))
+	}
+}
+
+func (s *Server) handleUpdateRegistry(w http.ResponseWriter, r *http.Request, p params) {
+	reg := s.lookupRegistry(w, p)
+	if reg == nil {
+		return
+	}
+
+	req := &model.RegistryUpdate{}
+	if !decode(w, r, req) {
+		return
+	}
+
+	if req.Name != nil && *req.Name != reg.Name {
+		if s.registryByName(*req.Name) != nil {
+			writeError(w, http.StatusConflict, "registry %s already exists", *req.Name)
+			return
+		}
+
+		reg.Name = *req.Name
+	}
+
+	if req.Description != nil {
+		reg.Description = *req.Description
+	}
+
+	if req.URL != nil {
+		reg.URL = *req.URL
+	}
+
+	if req.Insecure != nil {
+		reg.Insecure = *req.Insecure
+	}
+
+	if req.AccessKey != nil || req.AccessSecret != nil || req.CredentialType != nil {
+		if reg.Credential == nil {
+			reg.Credential = &model.RegistryCredential{}
+		}
+
+		if req.AccessKey != nil {
+			reg.Credential.AccessKey = *req.AccessKey
+		}
+
+		if req.AccessSecret != nil {
+			reg.Credential.AccessSecret = *req.AccessSecret
+		}
+
+		if req.CredentialType != nil {
+			reg.Credential.Type = *req.CredentialType
+		}
+	}
+
+	reg.UpdateTime = now()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleDeleteRegistry(w http.ResponseWriter, _ *http.Request, p params) {
+	reg := s.lookupRegistry(w, p)
+	if reg == nil {
+		return
+	}
+
+	for _, rp := range s.replications {
+		if rp.SrcRegistry != nil && rp.SrcRegistry.ID == reg.ID || rp.DestRegistry != nil && rp.DestRegistry.ID == reg.ID {
+			writeError(w, http.StatusPreconditionFailed, "registry %s is referenced by replication policy %s", reg.Name, rp.Name)
+			return
+		}
+	}
+
+	for _, proj := range s.projects {
+		if proj.registryID == reg.ID {
+			writeError(w, http.StatusPreconditionFailed, "registry %s is referenced by proxy cache project %s", reg.Name, proj.name)
+			return
+		}
+	}
+
+	delete(s.registries, reg.ID)
+
+	w.WriteHeader(http.StatusOK)
+}
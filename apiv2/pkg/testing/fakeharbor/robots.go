@@ -0,0 +1,232 @@
+package fakeharbor
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+// defaultRobotDuration is the duration in days of robot accounts created without one,
+// matching Harbor's default 'robot_token_duration'.
+const defaultRobotDuration = 30
+
+type robot struct {
+	model.Robot
+
+	secret    string
+	projectID int64
+}
+
+func (s *Server) registerRobotRoutes() {
+	s.handle(http.MethodPost, "/robots", s.handleCreateRobot)
+	s.handle(http.MethodGet, "/robots", s.handleListRobots)
+	s.handle(http.MethodGet, "/robots/{robot_id}", s.handleGetRobot)
+	s.handle(http.MethodPut, "/robots/{robot_id}", s.handleUpdateRobot)
+	s.handle(http.MethodPatch, "/robots/{robot_id}", s.handleRefreshRobotSecret)
+	s.handle(http.MethodDelete, "/robots/{robot_id}", s.handleDeleteRobot)
+}
+
+// robotModel returns a copy of 'rb' without its secret.
+func robotModel(rb *robot) *model.Robot {
+	m := rb.Robot
+	m.Secret = ""
+
+	return &m
+}
+
+// expiresAt returns the expiry timestamp for a robot created at 'created' with a duration in days.
+func expiresAt(created time.Time, duration int64) int64 {
+	if duration == -1 {
+		return -1
+	}
+
+	return created.Add(time.Duration(duration) * 24 * time.Hour).Unix()
+}
+
+func (s *Server) handleCreateRobot(w http.ResponseWriter, r *http.Request, _ params) {
+	req := &model.RobotCreate{}
+	if !decode(w, r, req) {
+		return
+	}
+
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "robot name must not be empty")
+		return
+	}
+
+	rb := &robot{Robot: model.Robot{
+		Description: req.Description,
+		Disable:     req.Disable,
+		Duration:    req.Duration,
+		Editable:    true,
+		Level:       req.Level,
+		Permissions: req.Permissions,
+	}}
+
+	switch req.Level {
+	case "system":
+		rb.Name = "robot$" + req.Name
+	case "project":
+		if len(req.Permissions) != 1 || req.Permissions[0].Kind != "project" {
+			writeError(w, http.StatusBadRequest, "project level robot accounts require exactly one project permission")
+			return
+		}
+
+		p := s.project(req.Permissions[0].Namespace)
+		if p == nil {
+			writeError(w, http.StatusBadRequest, "project %s not found", req.Permissions[0].Namespace)
+			return
+		}
+
+		rb.Name = "robot$" + p.name + "+" + req.Name
+		rb.projectID = p.id
+	default:
+		writeError(w, http.StatusBadRequest, "invalid robot level %q", req.Level)
+		return
+	}
+
+	for _, existing := range s.robots {
+		if existing.Name == rb.Name {
+			writeError(w, http.StatusConflict, "robot account %s already exists", rb.Name)
+			return
+		}
+	}
+
+	if rb.Duration == 0 {
+		rb.Duration = defaultRobotDuration
+	}
+
+	created := time.Now().UTC()
+
+	rb.ID = s.id("robot")
+	rb.CreationTime = now()
+	rb.UpdateTime = rb.CreationTime
+	rb.ExpiresAt = expiresAt(created, rb.Duration)
+
+	rb.secret = req.Secret
+	if rb.secret == "" {
+		rb.secret = randomHex(16)
+	}
+
+	s.robots[rb.ID] = rb
+
+	w.Header().Set("Location", r.URL.Path+"/"+strconv.FormatInt(rb.ID, 10))
+	writeJSON(w, http.StatusCreated, &model.RobotCreated{
+		CreationTime: rb.CreationTime,
+		ExpiresAt:    rb.ExpiresAt,
+		ID:           rb.ID,
+		Name:         rb.Name,
+		Secret:       rb.secret,
+	})
+}
+
+func (s *Server) handleListRobots(w http.ResponseWriter, r *http.Request, _ params) {
+	q := parseQuery(r)
+
+	var robots []*model.Robot
+
+	for _, id := range sortedIDs(s.robots) {
+		rb := s.robots[id]
+
+		if !q.matches(map[string]string{
+			"name":       rb.Name,
+			"Level":      rb.Level,
+			"ProjectID":  strconv.FormatInt(rb.projectID, 10),
+			"level":      rb.Level,
+			"project_id": strconv.FormatInt(rb.projectID, 10),
+		}) {
+			continue
+		}
+
+		robots = append(robots, robotModel(rb))
+	}
+
+	writePage(w, r, robots)
+}
+
+// lookupRobot resolves the "robot_id" path parameter, responding with 404 if unknown.
+func (s *Server) lookupRobot(w http.ResponseWriter, p params) *robot {
+	id, _ := p.int64("robot_id")
+
+	rb, ok := s.robots[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "robot account %s not found", p["robot_id"])
+		return nil
+	}
+
+	return rb
+}
+
+func (s *Server) handleGetRobot(w http.ResponseWriter, _ *http.Request, p params) {
+	if rb := s.lookupRobot(w, p); rb != nil {
+		writeJSON(w, http.StatusOK, robotModel(rb))
+	}
+}
+
+func (s *Server) handleUpdateRobot(w http.ResponseWriter, r *http.Request, p params) {
+	rb := s.lookupRobot(w, p)
+	if rb == nil {
+		return
+	}
+
+	req := &model.Robot{}
+	if !decode(w, r, req) {
+		return
+	}
+
+	if req.Name != rb.Name || req.Level != rb.Level {
+		writeError(w, http.StatusBadRequest, "the name and level of robot accounts cannot be changed")
+		return
+	}
+
+	rb.Description = req.Description
+	rb.Disable = req.Disable
+	rb.Permissions = req.Permissions
+
+	if req.Duration != 0 && req.Duration != rb.Duration {
+		rb.Duration = req.Duration
+		rb.ExpiresAt = expiresAt(time.Time(rb.CreationTime), rb.Duration)
+	}
+
+	rb.UpdateTime = now()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleRefreshRobotSecret(w http.ResponseWriter, r *http.Request, p params) {
+	rb := s.lookupRobot(w, p)
+	if rb == nil {
+		return
+	}
+
+	req := &model.RobotSec{}
+	if !decode(w, r, req) {
+		return
+	}
+
+	if req.Secret != "" {
+		if len(req.Secret) < 8 {
+			writeError(w, http.StatusBadRequest, "the secret must be at least 8 characters long")
+			return
+		}
+
+		rb.secret = req.Secret
+
+		writeJSON(w, http.StatusOK, &model.RobotSec{})
+
+		return
+	}
+
+	rb.secret = randomHex(16)
+
+	writeJSON(w, http.StatusOK, &model.RobotSec{Secret: rb.secret})
+}
+
+func (s *Server) handleDeleteRobot(w http.ResponseWriter, _ *http.Request, p params) {
+	if rb := s.lookupRobot(w, p); rb != nil {
+		delete(s.robots, rb.ID)
+		w.WriteHeader(http.StatusOK)
+	}
+}
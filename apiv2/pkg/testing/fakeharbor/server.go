@@ -0,0 +1,544 @@
+// Package fakeharbor provides an in-memory fake of the Harbor v2 REST API based on httptest.
+//
+// The fake keeps realistic state for users, projects (including metadata, members and quotas),
+// repositories, artifacts, tags, robot accounts, registries, labels, audit logs, the system configuration,
+// the GC, audit log purge and scan all schedules and replication, retention, immutability and webhook policies.
+// List endpoints support pagination via 'page' / 'page_size' and report the 'X-Total-Count' header,
+// errors are returned in Harbor's {"errors": [{"code": ..., "message": ...}]} format.
+//
+// As artifacts cannot be pushed through the REST API, they are seeded using Server.PushArtifact.
+// Jobs, like GC or audit log purge runs, complete immediately.
+package fakeharbor
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	runtimeclient "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+
+	v2client "github.com/mittwald/goharbor-client/v5/apiv2/internal/api/client"
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+const (
+	// BasePath is the path prefix of the Harbor v2 API.
+	BasePath = "/api/v2.0"
+
+	// AdminUser and AdminPassword are the credentials of the built-in admin account.
+	AdminUser     = "admin"
+	AdminPassword = "Harbor12345"
+
+	// Version is the Harbor version reported by the fake.
+	Version = "v2.9.1-fake"
+)
+
+// Server is an in-memory fake Harbor instance.
+// All methods are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	routes []route
+	// ids holds the last ID per kind of resource, so that e.g. the first user and project both get ID 1 like in Harbor.
+	ids map[string]int64
+
+	users        map[string]*user
+	projects     map[int64]*project
+	repositories map[int64]*repository
+	artifacts    map[int64]*artifact
+	robots       map[int64]*robot
+	registries   map[int64]*model.Registry
+	labels       map[int64]*model.Label
+	replications map[int64]*model.ReplicationPolicy
+	retentions   map[int64]*model.RetentionPolicy
+	auditLogs    []*model.AuditLog
+	config       map[string]json.RawMessage
+	schedules    map[string]*model.Schedule
+	jobs         map[string][]*job
+	// health maps the components reported by the health endpoint to their error, which is empty if healthy.
+	health map[string]string
+}
+
+// NewServer starts a fake Harbor instance, which contains the admin user
+// and the "library" project just like a fresh installation.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		ids:          map[string]int64{},
+		users:        map[string]*user{},
+		projects:     map[int64]*project{},
+		repositories: map[int64]*repository{},
+		artifacts:    map[int64]*artifact{},
+		robots:       map[int64]*robot{},
+		registries:   map[int64]*model.Registry{},
+		labels:       map[int64]*model.Label{},
+		replications: map[int64]*model.ReplicationPolicy{},
+		retentions:   map[int64]*model.RetentionPolicy{},
+		config:       defaultConfig(),
+		schedules:    map[string]*model.Schedule{},
+		jobs:         map[string][]*job{},
+		health: map[string]string{
+			"core": "", "database": "", "jobservice": "", "portal": "",
+			"redis": "", "registry": "", "registryctl": "", "trivy": "",
+		},
+	}
+
+	s.registerRoutes()
+	s.addUser(AdminUser, AdminPassword).sysadmin = true
+	s.createProject(&model.ProjectReq{
+		ProjectName: "library",
+		Metadata:    &model.ProjectMetadata{Public: "true"},
+	}, AdminUser)
+
+	s.Server = httptest.NewServer(s)
+
+	return s
+}
+
+// Host returns the URL of the fake's API, e.g. "http://127.0.0.1:1234/api/v2.0".
+func (s *Server) Host() string {
+	return s.URL + BasePath
+}
+
+// RegistryURL returns the registry address reported by the fake, e.g. "127.0.0.1:1234".
+func (s *Server) RegistryURL() string {
+	u, _ := url.Parse(s.URL)
+
+	return u.Host
+}
+
+// AuthInfo returns the admin's credentials.
+func (s *Server) AuthInfo() runtime.ClientAuthInfoWriter {
+	return runtimeclient.BasicAuth(AdminUser, AdminPassword)
+}
+
+// V2Client returns a swagger client talking to the fake, to be passed into the sub-clients' NewClient functions.
+func (s *Server) V2Client() *v2client.Harbor {
+	u, _ := url.Parse(s.Host())
+
+	return v2client.New(runtimeclient.New(u.Host, u.Path, []string{u.Scheme}), strfmt.Default)
+}
+
+// SetComponentHealth sets the health of the component 'name', e.g. "redis", reported by the health endpoint.
+// An empty 'err' makes the component healthy, otherwise it is reported as unhealthy with the given error.
+func (s *Server) SetComponentHealth(name, err string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.health[name] = err
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Like Harbor, identify each response by a request ID.
+	w.Header().Set("X-Request-Id", randomHex(8))
+
+	// Like Harbor, route using the decoded path, so that a path parameter containing a slash
+	// must be escaped twice (e.g. "a%252Fb" for "a/b") to be matched as a single segment.
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	if !strings.HasPrefix(path, BasePath+"/") {
+		writeError(w, http.StatusNotFound, "not found: %s", r.URL.Path)
+		return
+	}
+
+	segments := strings.Split(strings.TrimPrefix(path, BasePath+"/"), "/")
+
+	for _, rt := range s.routes {
+		if rt.method != r.Method {
+			continue
+		}
+
+		p, ok := rt.match(segments)
+		if !ok {
+			continue
+		}
+
+		if !rt.anonymous && !s.authenticated(r) {
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		rt.handler(w, r, p)
+
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "no route for %s %s", r.Method, r.URL.Path)
+}
+
+func (s *Server) authenticated(r *http.Request) bool {
+	name, password, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+
+	if u, ok := s.users[name]; ok {
+		return u.password == password
+	}
+
+	for _, rb := range s.robots {
+		if rb.Name == name && !rb.Disable {
+			return rb.secret == password
+		}
+	}
+
+	return false
+}
+
+// id returns the next ID for resources of the given kind, e.g. "project".
+func (s *Server) id(kind string) int64 {
+	s.ids[kind]++
+	return s.ids[kind]
+}
+
+// params contains the path parameters of a matched route.
+type params map[string]string
+
+func (p params) int64(name string) (int64, bool) {
+	v, err := strconv.ParseInt(p[name], 10, 64)
+	return v, err == nil
+}
+
+type route struct {
+	method    string
+	segments  []string
+	anonymous bool
+	handler   func(w http.ResponseWriter, r *http.Request, p params)
+}
+
+// match matches the decoded path 'segments' against the route's pattern.
+// A "{name}" segment matches exactly one path segment.
+func (rt route) match(segments []string) (params, bool) {
+	p := params{}
+	if !matchSegments(rt.segments, segments, p) {
+		return nil, false
+	}
+
+	return p, true
+}
+
+func matchSegments(pattern, segments []string, p params) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	head := pattern[0]
+
+	if strings.HasPrefix(head, "{") && strings.HasSuffix(head, "}") {
+		if !matchSegments(pattern[1:], segments[1:], p) {
+			return false
+		}
+
+		p[strings.Trim(head, "{}")] = unescape(segments[0])
+
+		return true
+	}
+
+	return head == segments[0] && matchSegments(pattern[1:], segments[1:], p)
+}
+
+// unescape decodes path parameters, which are escaped a second time by the client (e.g. "a%2Fb" for "a/b").
+func unescape(v string) string {
+	u, err := url.PathUnescape(v)
+	if err != nil {
+		return v
+	}
+
+	return u
+}
+
+func (s *Server) handle(method, pattern string, handler func(w http.ResponseWriter, r *http.Request, p params)) {
+	s.routes = append(s.routes, route{
+		method:   method,
+		segments: strings.Split(strings.Trim(pattern, "/"), "/"),
+		handler:  handler,
+	})
+}
+
+func (s *Server) handleAnonymous(method, pattern string, handler func(w http.ResponseWriter, r *http.Request, p params)) {
+	s.handle(method, pattern, handler)
+	s.routes[len(s.routes)-1].anonymous = true
+}
+
+func (s *Server) registerRoutes() {
+	s.registerSystemRoutes()
+	s.registerUserRoutes()
+	s.registerConfigurationRoutes()
+	s.registerJobRoutes()
+	s.registerProjectRoutes()
+	s.registerArtifactRoutes()
+	s.registerRobotRoutes()
+	s.registerRegistryRoutes()
+	s.registerLabelRoutes()
+	s.registerPolicyRoutes()
+}
+
+func (s *Server) registerSystemRoutes() {
+	s.handleAnonymous(http.MethodGet, "/ping", func(w http.ResponseWriter, _ *http.Request, _ params) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("Pong"))
+	})
+
+	s.handleAnonymous(http.MethodGet, "/health", func(w http.ResponseWriter, _ *http.Request, _ params) {
+		status := &model.OverallHealthStatus{Status: "healthy"}
+
+		for name, err := range s.health {
+			c := &model.ComponentHealthStatus{Name: name, Status: "healthy"}
+			if err != "" {
+				c.Status, c.Error = "unhealthy", err
+				status.Status = "unhealthy"
+			}
+
+			status.Components = append(status.Components, c)
+		}
+
+		sort.Slice(status.Components, func(i, j int) bool {
+			return status.Components[i].Name < status.Components[j].Name
+		})
+
+		writeJSON(w, http.StatusOK, status)
+	})
+
+	s.handleAnonymous(http.MethodGet, "/systeminfo", func(w http.ResponseWriter, _ *http.Request, _ params) {
+		version, registryURL := Version, s.RegistryURL()
+
+		var (
+			authMode string
+			readOnly bool
+		)
+
+		_ = json.Unmarshal(s.config["auth_mode"], &authMode)
+		_ = json.Unmarshal(s.config["read_only"], &readOnly)
+
+		writeJSON(w, http.StatusOK, &model.GeneralInfo{
+			AuthMode:      &authMode,
+			HarborVersion: &version,
+			ReadOnly:      &readOnly,
+			RegistryURL:   &registryURL,
+		})
+	})
+
+	s.handle(http.MethodGet, "/statistics", s.handleGetStatistics)
+	s.handle(http.MethodGet, "/audit-logs", s.handleListAuditLogs)
+}
+
+// writeJSON writes 'v' as JSON response body with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if v != nil {
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
+
+// writeError writes a Harbor-shaped error response.
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, &model.Errors{Errors: []*model.Error{{
+		Code:    errorCode(status),
+		Message: fmt.Sprintf(format, args...),
+	}}})
+}
+
+func errorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "BAD_REQUEST"
+	case http.StatusUnauthorized:
+		return "UNAUTHORIZED"
+	case http.StatusForbidden:
+		return "FORBIDDEN"
+	case http.StatusNotFound:
+		return "NOT_FOUND"
+	case http.StatusConflict:
+		return "CONFLICT"
+	case http.StatusPreconditionFailed:
+		return "PRECONDITION"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// writeCreated responds with 201 and a Location header pointing to the created resource.
+func writeCreated(w http.ResponseWriter, r *http.Request, id int64) {
+	w.Header().Set("Location", fmt.Sprintf("%s/%d", strings.TrimSuffix(r.URL.Path, "/"), id))
+	w.WriteHeader(http.StatusCreated)
+}
+
+// decode reads the JSON request body into 'v', responding with 400 on failure.
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: %v", err)
+		return false
+	}
+
+	return true
+}
+
+// writePage writes the page of 'items' selected by the 'page' and 'page_size' query parameters,
+// along with the X-Total-Count and Link headers. 'items' must be a slice.
+func writePage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	page, pageSize := int64(1), int64(10)
+
+	if v, err := strconv.ParseInt(r.URL.Query().Get("page"), 10, 64); err == nil && v > 0 {
+		page = v
+	}
+
+	if v, err := strconv.ParseInt(r.URL.Query().Get("page_size"), 10, 64); err == nil && v > 0 {
+		pageSize = v
+	}
+
+	if pageSize > 100 {
+		writeError(w, http.StatusBadRequest, "page_size must not exceed 100")
+		return
+	}
+
+	total := int64(len(items))
+	start := (page - 1) * pageSize
+	end := start + pageSize
+
+	if start > total {
+		start = total
+	}
+
+	if end > total {
+		end = total
+	}
+
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+
+	var links []string
+
+	link := func(p int64, rel string) string {
+		q := r.URL.Query()
+		q.Set("page", strconv.FormatInt(p, 10))
+		q.Set("page_size", strconv.FormatInt(pageSize, 10))
+
+		return fmt.Sprintf(`<%s?%s>; rel="%s"`, r.URL.Path, q.Encode(), rel)
+	}
+
+	if page > 1 {
+		links = append(links, link(page-1, "prev"))
+	}
+
+	if end < total {
+		links = append(links, link(page+1, "next"))
+	}
+
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, " , "))
+	}
+
+	writeJSON(w, http.StatusOK, items[start:end])
+}
+
+// query is a parsed Harbor query string, e.g. "name=foo,level=~proj".
+type query map[string]queryValue
+
+type queryValue struct {
+	value string
+	fuzzy bool
+}
+
+func parseQuery(r *http.Request) query {
+	q := query{}
+
+	for _, part := range strings.Split(r.URL.Query().Get("q"), ",") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok || k == "" {
+			continue
+		}
+
+		fuzzy := strings.HasPrefix(v, "~")
+
+		q[strings.TrimSpace(k)] = queryValue{value: strings.TrimPrefix(v, "~"), fuzzy: fuzzy}
+	}
+
+	return q
+}
+
+// matches returns true if all query keys present in 'fields' match their values.
+// Query keys unknown to 'fields' are ignored.
+func (q query) matches(fields map[string]string) bool {
+	for k, qv := range q {
+		v, ok := fields[k]
+		if !ok {
+			continue
+		}
+
+		if !qv.matches(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matches returns true if 'v' matches the query value, which is either fuzzy, exact,
+// a union like '{"a" "b"}' or a range like '["a"~"b"]'. Range bounds are compared as strings,
+// which is correct for times in the query time format.
+func (qv queryValue) matches(v string) bool {
+	value := qv.value
+
+	switch {
+	case qv.fuzzy:
+		return strings.Contains(v, value)
+	case strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}"):
+		for _, item := range strings.Fields(strings.Trim(value, "{}")) {
+			if strings.Trim(item, `"`) == v {
+				return true
+			}
+		}
+
+		return false
+	case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+		min, max, _ := strings.Cut(strings.Trim(value, "[]"), "~")
+		min, max = strings.Trim(min, `"`), strings.Trim(max, `"`)
+
+		return (min == "" || v >= min) && (max == "" || v <= max)
+	default:
+		return v == value
+	}
+}
+
+// sortedIDs returns the keys of 'm' in ascending order, i.e. in order of creation.
+func sortedIDs[T any](m map[int64]T) []int64 {
+	ids := make([]int64, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
+
+func now() strfmt.DateTime {
+	return strfmt.DateTime(time.Now().UTC())
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
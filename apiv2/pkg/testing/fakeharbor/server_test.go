@@ -0,0 +1,353 @@
+//go:build !integration
+
+package fakeharbor_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2"
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/auditlog"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/label"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/reference"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/testing/fakeharbor"
+)
+
+var ctx = context.Background()
+
+func newClient(t *testing.T) (*fakeharbor.Server, *apiv2.RESTClient) {
+	t.Helper()
+
+	srv := fakeharbor.NewServer()
+	t.Cleanup(srv.Close)
+
+	c, err := apiv2.NewRESTClientForHost(srv.Host(), fakeharbor.AdminUser, fakeharbor.AdminPassword, config.Defaults())
+	require.NoError(t, err)
+
+	return srv, c
+}
+
+func TestServer_Projects(t *testing.T) {
+	_, c := newClient(t)
+
+	limit := int64(1024)
+	require.NoError(t, c.NewProject(ctx, &model.ProjectReq{ProjectName: "team-a", StorageLimit: &limit}))
+
+	err := c.NewProject(ctx, &model.ProjectReq{ProjectName: "team-a"})
+	require.ErrorIs(t, err, &clienterrors.ErrProjectNameAlreadyExists{})
+
+	p, err := c.GetProject(ctx, "team-a")
+	require.NoError(t, err)
+	require.Equal(t, "admin", p.OwnerName)
+	require.Equal(t, "false", p.Metadata.Public)
+
+	_, err = c.GetProject(ctx, "unknown")
+	require.ErrorIs(t, err, &clienterrors.ErrProjectNotFound{})
+
+	q, err := c.GetQuotaByProjectID(ctx, int64(p.ProjectID))
+	require.NoError(t, err)
+	require.Equal(t, limit, q.Hard["storage"])
+
+	members, err := c.ListProjectMembers(ctx, "team-a", "")
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	require.Equal(t, "admin", members[0].EntityName)
+
+	require.NoError(t, c.DeleteProject(ctx, "team-a"))
+
+	exists, err := c.ProjectExists(ctx, "team-a")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func TestServer_Pagination(t *testing.T) {
+	_, c := newClient(t)
+
+	for i := 0; i < 25; i++ {
+		require.NoError(t, c.NewProject(ctx, &model.ProjectReq{ProjectName: fmt.Sprintf("project-%02d", i)}))
+	}
+
+	// The client requests pages of 10 projects and relies on X-Total-Count to stop.
+	projects, err := c.ListProjects(ctx, "project-")
+	require.NoError(t, err)
+	require.Len(t, projects, 25)
+	require.Equal(t, "project-24", projects[24].Name)
+}
+
+func TestServer_Artifacts(t *testing.T) {
+	srv, c := newClient(t)
+
+	_, err := srv.PushArtifact("library", "nested/app", "v1")
+	require.NoError(t, err)
+
+	latest, err := srv.PushArtifact("library", "nested/app", "v2", "latest")
+	require.NoError(t, err)
+
+	repos, err := c.ListRepositories(ctx, "library")
+	require.NoError(t, err)
+	require.Len(t, repos, 1)
+	require.Equal(t, "library/nested/app", repos[0].Name)
+	require.Equal(t, int64(2), repos[0].ArtifactCount)
+
+	a, err := c.GetArtifact(ctx, "library", "nested/app", "latest")
+	require.NoError(t, err)
+	require.Equal(t, latest.Digest, a.Digest)
+
+	require.NoError(t, c.CreateTag(ctx, "library", "nested/app", a.Digest, &model.Tag{Name: "stable"}))
+
+	err = c.CreateTag(ctx, "library", "nested/app", "v1", &model.Tag{Name: "stable"})
+	require.Error(t, err)
+
+	tags, err := c.ListTags(ctx, "library", "nested/app", a.Digest)
+	require.NoError(t, err)
+	require.Len(t, tags, 3)
+
+	_, err = srv.AttachAccessory("library", "nested/app", "latest", "signature.cosign")
+	require.NoError(t, err)
+
+	accessories, err := c.ListAccessories(ctx, "library", "nested/app", "latest")
+	require.NoError(t, err)
+	require.Len(t, accessories, 1)
+
+	require.NoError(t, c.DeleteArtifact(ctx, "library", "nested/app", "v1"))
+
+	_, err = c.GetArtifact(ctx, "library", "nested/app", "v1")
+	require.Error(t, err)
+
+	// Projects containing repositories cannot be deleted.
+	require.Error(t, c.DeleteProject(ctx, "library"))
+}
+
+func TestServer_ArtifactsByReference(t *testing.T) {
+	srv, c := newClient(t)
+
+	pushed, err := srv.PushArtifact("library", "team/app/api", "v1")
+	require.NoError(t, err)
+
+	ref := reference.MustParse("harbor.example.com/library/team/app/api:v1")
+
+	a, err := c.GetArtifactByReference(ctx, ref)
+	require.NoError(t, err)
+	require.Equal(t, pushed.Digest, a.Digest)
+
+	repo, err := c.GetRepositoryByReference(ctx, ref)
+	require.NoError(t, err)
+	require.Equal(t, "library/team/app/api", repo.Name)
+
+	require.NoError(t, c.CreateTagByReference(ctx, &reference.Reference{
+		Project: "library", Repository: "team/app/api", Digest: pushed.Digest,
+	}, &model.Tag{Name: "stable"}))
+
+	tags, err := c.ListTagsByReference(ctx, ref)
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+
+	require.NoError(t, c.CopyArtifactByReference(ctx, ref, reference.MustParse("library/team/copy")))
+
+	copied, err := c.ListArtifactsByReference(ctx, reference.MustParse("library/team/copy"))
+	require.NoError(t, err)
+	require.Len(t, copied, 1)
+	require.Equal(t, pushed.Digest, copied[0].Digest)
+
+	require.NoError(t, c.DeleteRepositoryByReference(ctx, ref))
+
+	_, err = c.GetRepositoryByReference(ctx, ref)
+	require.ErrorIs(t, err, &clienterrors.ErrNotFound{})
+
+	_, err = c.GetArtifactByReference(ctx, nil)
+	require.ErrorIs(t, err, &clienterrors.ErrInvalidReference{})
+}
+
+func TestServer_RobotsAndLabels(t *testing.T) {
+	srv, c := newClient(t)
+
+	created, err := c.NewRobotAccount(ctx, &model.RobotCreate{
+		Name:     "ci",
+		Level:    "project",
+		Duration: -1,
+		Permissions: []*model.RobotPermission{{
+			Kind:      "project",
+			Namespace: "library",
+			Access:    []*model.Access{{Resource: "repository", Action: "pull"}},
+		}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "robot$library+ci", created.Name)
+	require.NotEmpty(t, created.Secret)
+
+	// Robot accounts may authenticate using their secret.
+	req, err := http.NewRequest(http.MethodGet, srv.Host()+"/projects", nil)
+	require.NoError(t, err)
+	req.SetBasicAuth(created.Name, created.Secret)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	r, err := c.GetRobotAccountByName(ctx, "library+ci")
+	require.NoError(t, err)
+	require.Empty(t, r.Secret)
+	require.Equal(t, int64(-1), r.ExpiresAt)
+
+	require.NoError(t, c.CreateLabel(ctx, &model.Label{Name: "approved", Scope: "g"}))
+	require.Error(t, c.CreateLabel(ctx, &model.Label{Name: "approved", Scope: "g"}))
+
+	labels, err := c.ListLabels(ctx, "approved", nil, label.ScopeGlobal)
+	require.NoError(t, err)
+	require.Len(t, labels, 1)
+}
+
+func TestServer_RegistriesAndPolicies(t *testing.T) {
+	_, c := newClient(t)
+
+	require.NoError(t, c.NewRegistry(ctx, &model.Registry{
+		Name: "dockerhub", Type: "docker-hub", URL: "https://hub.docker.com",
+		Credential: &model.RegistryCredential{AccessKey: "user", AccessSecret: "secret"},
+	}))
+
+	reg, err := c.GetRegistryByName(ctx, "dockerhub")
+	require.NoError(t, err)
+	require.Equal(t, "*****", reg.Credential.AccessSecret)
+
+	require.NoError(t, c.NewReplicationPolicy(ctx, nil, reg, false, false, true, nil, nil, "", "", "mirror"))
+
+	rp, err := c.GetReplicationPolicyByName(ctx, "mirror")
+	require.NoError(t, err)
+	require.Equal(t, reg.ID, rp.SrcRegistry.ID)
+
+	// Registries referenced by policies cannot be deleted.
+	require.Error(t, c.DeleteRegistryByID(ctx, reg.ID))
+
+	p, err := c.GetProject(ctx, "library")
+	require.NoError(t, err)
+
+	require.NoError(t, c.NewRetentionPolicy(ctx, &model.RetentionPolicy{
+		Algorithm: "or",
+		Scope:     &model.RetentionPolicyScope{Level: "project", Ref: int64(p.ProjectID)},
+	}))
+
+	policy, err := c.GetRetentionPolicyByProject(ctx, "library")
+	require.NoError(t, err)
+	require.Equal(t, "or", policy.Algorithm)
+}
+
+func TestServer_Unauthorized(t *testing.T) {
+	srv := fakeharbor.NewServer()
+	defer srv.Close()
+
+	c, err := apiv2.NewRESTClientForHost(srv.Host(), fakeharbor.AdminUser, "wrong", config.Defaults())
+	require.NoError(t, err)
+
+	_, err = c.ListProjects(ctx, "")
+	require.ErrorContains(t, err, "[401]")
+}
+
+func TestServer_Users(t *testing.T) {
+	_, c := newClient(t)
+
+	require.NoError(t, c.NewUser(ctx, "alice", "alice@example.com", "Alice", "Secret123", ""))
+
+	err := c.NewUser(ctx, "bob", "alice@example.com", "Bob", "Secret123", "")
+	require.ErrorIs(t, err, &clienterrors.ErrUserAlreadyExists{})
+
+	// Like in Harbor, the admin is not listed.
+	users, err := c.ListUsers(ctx)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.Equal(t, "alice", users[0].Username)
+
+	require.NoError(t, c.AddProjectMember(ctx, "library", &model.ProjectMember{
+		MemberUser: &model.UserEntity{Username: "alice"},
+		RoleID:     2,
+	}))
+
+	require.NoError(t, c.DeleteUser(ctx, users[0].UserID))
+
+	members, err := c.ListProjectMembers(ctx, "library", "")
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+}
+
+func TestServer_Configurations(t *testing.T) {
+	_, c := newClient(t)
+
+	mode, password := "ldap_auth", "secret"
+	require.NoError(t, c.UpdateConfigs(ctx, &model.Configurations{AuthMode: &mode, LdapSearchPassword: &password}))
+
+	cfg, err := c.GetConfigs(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "ldap_auth", cfg.AuthMode.Value)
+	require.True(t, cfg.LdapVerifyCert.Value)
+
+	info, err := c.GetSystemInfo(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "ldap_auth", *info.AuthMode)
+
+	// The auth mode is fixed once users other than the admin exist.
+	require.NoError(t, c.NewUser(ctx, "alice", "alice@example.com", "Alice", "Secret123", ""))
+
+	mode = "db_auth"
+	require.ErrorContains(t, c.UpdateConfigs(ctx, &model.Configurations{AuthMode: &mode}), "400")
+}
+
+func TestServer_SystemJobs(t *testing.T) {
+	_, c := newClient(t)
+
+	hourly := &model.Schedule{
+		Parameters: map[string]interface{}{"audit_retention_hour": 168},
+		Schedule:   &model.ScheduleObj{Type: "Hourly", Cron: "0 0 * * * *"},
+	}
+
+	require.NoError(t, c.NewGarbageCollection(ctx, hourly))
+	require.Error(t, c.NewGarbageCollection(ctx, hourly))
+
+	sched, err := c.GetGarbageCollectionSchedule(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "Hourly", sched.Schedule.Type)
+
+	require.NoError(t, c.CreatePurgeSchedule(ctx, hourly))
+	require.NoError(t, c.RunPurge(ctx, true))
+
+	jobs, err := c.ListPurgeHistory(ctx)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, "MANUAL", jobs[0].JobKind)
+	require.JSONEq(t, `{"audit_retention_hour": 168, "dry_run": true}`, jobs[0].JobParameters)
+
+	log, err := c.GetPurgeJobLog(ctx, jobs[0].ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, log)
+}
+
+func TestServer_AuditLogs(t *testing.T) {
+	srv, c := newClient(t)
+
+	require.NoError(t, c.NewProject(ctx, &model.ProjectReq{ProjectName: "team-a"}))
+	require.NoError(t, c.DeleteProject(ctx, "team-a"))
+
+	_, err := srv.PushArtifact("library", "app", "v1")
+	require.NoError(t, err)
+
+	logs, err := c.ListAuditLogs(ctx)
+	require.NoError(t, err)
+	require.Len(t, logs, 3)
+	require.Equal(t, "artifact", logs[0].ResourceType)
+	require.Equal(t, "delete", logs[1].Operation)
+	require.Equal(t, "team-a", logs[1].Resource)
+
+	logs, err = c.QueryAuditLogs(ctx, &auditlog.Query{
+		Operations:    []auditlog.Operation{auditlog.OperationCreate, auditlog.OperationDelete},
+		ResourceTypes: []auditlog.ResourceType{auditlog.ResourceTypeProject},
+	})
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	require.Equal(t, "create", logs[0].Operation)
+}
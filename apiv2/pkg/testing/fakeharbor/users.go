@@ -0,0 +1,281 @@
+package fakeharbor
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+type user struct {
+	id       int64
+	name     string
+	password string
+	email    string
+	realname string
+	comment  string
+	sysadmin bool
+	created  strfmt.DateTime
+	updated  strfmt.DateTime
+}
+
+// AddUser adds a user that can authenticate using basic auth and be added to projects as a member.
+func (s *Server) AddUser(name, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.addUser(name, password)
+}
+
+func (s *Server) addUser(name, password string) *user {
+	u := &user{id: s.id("user"), name: name, password: password, created: now()}
+	u.updated = u.created
+	s.users[name] = u
+
+	return u
+}
+
+func userModel(u *user) *model.UserResp {
+	return &model.UserResp{
+		Comment:      u.comment,
+		CreationTime: u.created,
+		Email:        u.email,
+		Realname:     u.realname,
+		SysadminFlag: u.sysadmin,
+		UpdateTime:   u.updated,
+		UserID:       u.id,
+		Username:     u.name,
+	}
+}
+
+func (s *Server) registerUserRoutes() {
+	s.handle(http.MethodPost, "/users", s.handleCreateUser)
+	s.handle(http.MethodGet, "/users", s.handleListUsers)
+	s.handle(http.MethodGet, "/users/search", s.handleSearchUsers)
+	s.handle(http.MethodGet, "/users/current", s.handleGetCurrentUser)
+	s.handle(http.MethodGet, "/users/{user_id}", s.handleGetUser)
+	s.handle(http.MethodPut, "/users/{user_id}", s.handleUpdateUserProfile)
+	s.handle(http.MethodDelete, "/users/{user_id}", s.handleDeleteUser)
+	s.handle(http.MethodPut, "/users/{user_id}/sysadmin", s.handleSetUserSysAdmin)
+	s.handle(http.MethodPut, "/users/{user_id}/password", s.handleUpdateUserPassword)
+}
+
+// userByEmail returns the user with the given email address, or nil.
+func (s *Server) userByEmail(email string) *user {
+	for _, u := range s.users {
+		if email != "" && u.email == email {
+			return u
+		}
+	}
+
+	return nil
+}
+
+// lookupUser resolves the "user_id" path parameter, responding with 404 if unknown.
+func (s *Server) lookupUser(w http.ResponseWriter, p params) *user {
+	id, _ := p.int64("user_id")
+
+	for _, u := range s.users {
+		if u.id == id {
+			return u
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "user %s not found", p["user_id"])
+
+	return nil
+}
+
+// sortedUsers returns the users in order of creation.
+func (s *Server) sortedUsers() []*user {
+	byID := make(map[int64]*user, len(s.users))
+	for _, u := range s.users {
+		byID[u.id] = u
+	}
+
+	users := make([]*user, 0, len(byID))
+	for _, id := range sortedIDs(byID) {
+		users = append(users, byID[id])
+	}
+
+	return users
+}
+
+func (s *Server) handleCreateUser(w http.ResponseWriter, r *http.Request, _ params) {
+	req := &model.UserCreationReq{}
+	if !decode(w, r, req) {
+		return
+	}
+
+	if req.Username == "" || req.Password == "" {
+		writeError(w, http.StatusBadRequest, "username and password must not be empty")
+		return
+	}
+
+	if s.users[req.Username] != nil {
+		writeError(w, http.StatusConflict, "username %s already exists", req.Username)
+		return
+	}
+
+	if s.userByEmail(req.Email) != nil {
+		writeError(w, http.StatusConflict, "email %s already exists", req.Email)
+		return
+	}
+
+	u := s.addUser(req.Username, req.Password)
+	u.email, u.realname, u.comment = req.Email, req.Realname, req.Comment
+
+	writeCreated(w, r, u.id)
+}
+
+// handleListUsers lists all users but the admin, who is hidden by Harbor as well.
+func (s *Server) handleListUsers(w http.ResponseWriter, r *http.Request, _ params) {
+	q := parseQuery(r)
+
+	var users []*model.UserResp
+
+	for _, u := range s.sortedUsers() {
+		if u.name == AdminUser {
+			continue
+		}
+
+		if !q.matches(map[string]string{"username": u.name, "email": u.email}) {
+			continue
+		}
+
+		users = append(users, userModel(u))
+	}
+
+	writePage(w, r, users)
+}
+
+func (s *Server) handleSearchUsers(w http.ResponseWriter, r *http.Request, _ params) {
+	name := r.URL.Query().Get("username")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "username must not be empty")
+		return
+	}
+
+	q := query{"username": {value: name, fuzzy: true}}
+
+	var users []*model.UserSearchRespItem
+
+	for _, u := range s.sortedUsers() {
+		if q.matches(map[string]string{"username": u.name}) {
+			users = append(users, &model.UserSearchRespItem{UserID: u.id, Username: u.name})
+		}
+	}
+
+	writePage(w, r, users)
+}
+
+func (s *Server) handleGetCurrentUser(w http.ResponseWriter, r *http.Request, _ params) {
+	name, _, _ := r.BasicAuth()
+
+	u := s.users[name]
+	if u == nil {
+		writeError(w, http.StatusUnauthorized, "%s is not a user", name)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, userModel(u))
+}
+
+func (s *Server) handleGetUser(w http.ResponseWriter, _ *http.Request, p params) {
+	if u := s.lookupUser(w, p); u != nil {
+		writeJSON(w, http.StatusOK, userModel(u))
+	}
+}
+
+func (s *Server) handleUpdateUserProfile(w http.ResponseWriter, r *http.Request, p params) {
+	u := s.lookupUser(w, p)
+	if u == nil {
+		return
+	}
+
+	req := &model.UserProfile{}
+	if !decode(w, r, req) {
+		return
+	}
+
+	if other := s.userByEmail(req.Email); other != nil && other != u {
+		writeError(w, http.StatusConflict, "email %s already exists", req.Email)
+		return
+	}
+
+	u.email, u.realname, u.comment = req.Email, req.Realname, req.Comment
+	u.updated = now()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleDeleteUser(w http.ResponseWriter, r *http.Request, p params) {
+	u := s.lookupUser(w, p)
+	if u == nil {
+		return
+	}
+
+	if name, _, _ := r.BasicAuth(); name == u.name {
+		writeError(w, http.StatusForbidden, "can not delete the current user")
+		return
+	}
+
+	for _, proj := range s.projects {
+		for id, m := range proj.members {
+			if m.EntityType == "u" && m.EntityID == u.id {
+				delete(proj.members, id)
+			}
+		}
+	}
+
+	delete(s.users, u.name)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleSetUserSysAdmin(w http.ResponseWriter, r *http.Request, p params) {
+	u := s.lookupUser(w, p)
+	if u == nil {
+		return
+	}
+
+	req := &model.UserSysAdminFlag{}
+	if !decode(w, r, req) {
+		return
+	}
+
+	u.sysadmin = req.SysadminFlag
+	u.updated = now()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleUpdateUserPassword changes a user's password. Like in Harbor, only administrators may omit the old password.
+func (s *Server) handleUpdateUserPassword(w http.ResponseWriter, r *http.Request, p params) {
+	u := s.lookupUser(w, p)
+	if u == nil {
+		return
+	}
+
+	req := &model.PasswordReq{}
+	if !decode(w, r, req) {
+		return
+	}
+
+	if req.NewPassword == "" {
+		writeError(w, http.StatusBadRequest, "new password must not be empty")
+		return
+	}
+
+	name, _, _ := r.BasicAuth()
+	if caller := s.users[name]; (caller == nil || !caller.sysadmin) && req.OldPassword != u.password {
+		writeError(w, http.StatusForbidden, "old password is incorrect")
+		return
+	}
+
+	u.password = req.NewPassword
+	u.updated = now()
+
+	w.WriteHeader(http.StatusOK)
+}
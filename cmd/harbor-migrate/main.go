@@ -0,0 +1,153 @@
+// Command harbor-migrate copies the configuration of a Harbor 1.10 instance to a Harbor 2.x instance.
+//
+// It prints the planned steps and, unless -dry-run is set, applies them.
+// The progress and the generated credentials are recorded in the state file,
+// so that a migration which failed partially is resumed by running the command again.
+//
+// Passwords are read from the environment variables HARBOR_SOURCE_PASSWORD and HARBOR_TARGET_PASSWORD.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mittwald/goharbor-client/v5/apiv1"
+	modelv1 "github.com/mittwald/goharbor-client/v5/apiv1/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2"
+	modelv2 "github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/migrate"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "harbor-migrate:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var (
+		sourceURL       = flag.String("source-url", "", "API URL of the Harbor 1.10 instance, e.g. https://old.example.com/api")
+		sourceUsername  = flag.String("source-username", "admin", "username for the Harbor 1.10 instance")
+		targetURL       = flag.String("target-url", "", "API URL of the Harbor 2.x instance, e.g. https://new.example.com/api")
+		targetUsername  = flag.String("target-username", "admin", "username for the Harbor 2.x instance")
+		statePath       = flag.String("state", "harbor-migrate.json", "file recording the progress and generated credentials")
+		registrySecrets = flag.String("registry-secrets", "", "JSON file mapping registry names to their access secrets")
+		robotAccess     = flag.String("robot-access", "repository:pull", "comma separated resource:action pairs granted to migrated robot accounts")
+		dryRun          = flag.Bool("dry-run", false, "print the planned steps without applying them")
+	)
+
+	flag.Parse()
+
+	if *sourceURL == "" || *targetURL == "" {
+		flag.Usage()
+		return errors.New("-source-url and -target-url are required")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	source, err := apiv1.NewRESTClientForHost(*sourceURL, *sourceUsername, os.Getenv("HARBOR_SOURCE_PASSWORD"))
+	if err != nil {
+		return err
+	}
+
+	target, err := apiv2.New(*targetURL, apiv2.WithBasicAuth(*targetUsername, os.Getenv("HARBOR_TARGET_PASSWORD")))
+	if err != nil {
+		return err
+	}
+
+	access, err := parseAccess(*robotAccess)
+	if err != nil {
+		return err
+	}
+
+	opts := []migrate.Option{
+		migrate.WithStateStore(&migrate.FileStateStore{Path: *statePath}),
+		migrate.WithRobotAccess(access),
+	}
+
+	if *registrySecrets != "" {
+		secrets, err := readSecrets(*registrySecrets)
+		if err != nil {
+			return err
+		}
+
+		opts = append(opts, migrate.WithRegistrySecretFunc(func(r *modelv1.Registry) (string, error) {
+			return secrets[r.Name], nil
+		}))
+	}
+
+	m := migrate.New(source, target, opts...)
+
+	plan, err := m.Plan(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := plan.WriteTo(os.Stdout); err != nil {
+		return err
+	}
+
+	if *dryRun {
+		return nil
+	}
+
+	credentials, err := m.Apply(ctx, plan)
+
+	if len(credentials) > 0 {
+		fmt.Printf("\nGenerated credentials, also recorded in %s:\n", *statePath)
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, c := range credentials {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Kind, c.Name, c.Secret)
+		}
+
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w; run the command again to resume the migration", err)
+	}
+
+	return nil
+}
+
+// parseAccess parses comma separated resource:action pairs.
+func parseAccess(s string) ([]*modelv2.Access, error) {
+	var access []*modelv2.Access
+
+	for _, pair := range strings.Split(s, ",") {
+		resource, action, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || resource == "" || action == "" {
+			return nil, fmt.Errorf("invalid robot access %q, expected resource:action", pair)
+		}
+
+		access = append(access, &modelv2.Access{Resource: resource, Action: action})
+	}
+
+	return access, nil
+}
+
+func readSecrets(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var secrets map[string]string
+	if err := json.Unmarshal(b, &secrets); err != nil {
+		return nil, fmt.Errorf("reading registry secrets: %w", err)
+	}
+
+	return secrets, nil
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strings"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/artifact"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/reference"
+)
+
+func artifactsCommand() *command {
+	return &command{
+		name:    "artifacts",
+		aliases: []string{"artifact"},
+		summary: "Manage artifacts and their tags, referenced like images, e.g. library/app:v1.",
+		commands: []*command{
+			{
+				name:    "list",
+				args:    "<project>/<repository>",
+				summary: "List the artifacts of a repository.",
+				minArgs: 1,
+				maxArgs: 1,
+				setup: func(fs *flag.FlagSet) runFunc {
+					tagPattern := fs.String("tag", "", "list only artifacts having a tag matching the shell `pattern`, e.g. 'v1.*'")
+					artifactType := fs.String("type", "", "list only artifacts of the given `type`, e.g. IMAGE or CHART")
+
+					return func(ctx context.Context, e *env, args []string) error {
+						ref, err := reference.Parse(args[0])
+						if err != nil {
+							return err
+						}
+
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						opts := &artifact.ListArtifactsOptions{
+							ArtifactDetails: artifact.ArtifactDetails{Tags: true, Labels: true},
+							TagPattern:      *tagPattern,
+						}
+
+						if *artifactType != "" {
+							opts.Types = []artifact.ArtifactType{artifact.ArtifactType(strings.ToUpper(*artifactType))}
+						}
+
+						artifacts, err := c.ListArtifactsByReferenceWithOptions(ctx, ref, opts)
+						if err != nil {
+							return err
+						}
+
+						return e.print(artifacts, artifactTable(artifacts...))
+					}
+				},
+			},
+			(&command{
+				name:    "get",
+				args:    "<reference>",
+				summary: "Show an artifact including its tags, labels, scan overview and accessories.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						ref, err := reference.Parse(args[0])
+						if err != nil {
+							return err
+						}
+
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						a, err := c.GetArtifactWithDetails(ctx, ref.Project, ref.Repository, ref.ArtifactReference(), artifact.FullArtifactDetails())
+						if err != nil {
+							return err
+						}
+
+						return e.print(a, artifactTable(a))
+					}
+				},
+			}).exactArgs(1),
+			(&command{
+				name:    "delete",
+				args:    "<reference>",
+				summary: "Delete an artifact including all of its tags.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						ref, err := reference.Parse(args[0])
+						if err != nil {
+							return err
+						}
+
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						if err := c.DeleteArtifactByReference(ctx, ref); err != nil {
+							return err
+						}
+
+						e.printf("artifact %q deleted", ref.String())
+
+						return nil
+					}
+				},
+			}).exactArgs(1),
+			(&command{
+				name:    "copy",
+				args:    "<source> <destination>",
+				summary: "Copy an artifact to another repository, e.g. library/app:v1 to team/app:v1.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						from, err := reference.Parse(args[0])
+						if err != nil {
+							return err
+						}
+
+						to, err := reference.Parse(args[1])
+						if err != nil {
+							return err
+						}
+
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						if err := c.CopyArtifactByReference(ctx, from, to); err != nil {
+							return err
+						}
+
+						e.printf("artifact %q copied to %q", from.String(), to.String())
+
+						return nil
+					}
+				},
+			}).exactArgs(2),
+			tagsCommand(),
+		},
+	}
+}
+
+func tagsCommand() *command {
+	return &command{
+		name:    "tags",
+		aliases: []string{"tag"},
+		summary: "Manage the tags of an artifact.",
+		commands: []*command{
+			(&command{
+				name:    "list",
+				args:    "<reference>",
+				summary: "List the tags of an artifact.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						ref, err := reference.Parse(args[0])
+						if err != nil {
+							return err
+						}
+
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						tags, err := c.ListTagsByReference(ctx, ref)
+						if err != nil {
+							return err
+						}
+
+						t := newTable("NAME", "IMMUTABLE", "PUSHED", "PULLED")
+						for _, tag := range tags {
+							t.add(tag.Name, formatBool(tag.Immutable), formatTime(tag.PushTime), formatTime(tag.PullTime))
+						}
+
+						return e.print(tags, t)
+					}
+				},
+			}).exactArgs(1),
+			(&command{
+				name:    "add",
+				args:    "<reference> <tag>",
+				summary: "Add a tag to an artifact.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						ref, err := reference.Parse(args[0])
+						if err != nil {
+							return err
+						}
+
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						if err := c.CreateTagByReference(ctx, ref, &model.Tag{Name: args[1]}); err != nil {
+							return err
+						}
+
+						e.printf("tag %q added to %q", args[1], ref.String())
+
+						return nil
+					}
+				},
+			}).exactArgs(2),
+			(&command{
+				name:    "delete",
+				args:    "<reference> <tag>",
+				summary: "Delete a tag of an artifact.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						ref, err := reference.Parse(args[0])
+						if err != nil {
+							return err
+						}
+
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						if err := c.DeleteTag(ctx, ref.Project, ref.Repository, ref.ArtifactReference(), args[1]); err != nil {
+							return err
+						}
+
+						e.printf("tag %q deleted from %q", args[1], ref.String())
+
+						return nil
+					}
+				},
+			}).exactArgs(2),
+		},
+	}
+}
+
+func artifactTable(artifacts ...*model.Artifact) *table {
+	t := newTable("DIGEST", "TAGS", "TYPE", "SIZE", "LABELS", "PUSHED")
+
+	for _, a := range artifacts {
+		var tags, labels []string
+
+		for _, tag := range a.Tags {
+			tags = append(tags, tag.Name)
+		}
+
+		for _, l := range a.Labels {
+			labels = append(labels, l.Name)
+		}
+
+		t.add(shortDigest(a.Digest), strings.Join(tags, ","), a.Type, formatSize(a.Size), strings.Join(labels, ","), formatTime(a.PushTime))
+	}
+
+	return t
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// runFunc runs a command with its positional arguments.
+type runFunc func(ctx context.Context, e *env, args []string) error
+
+// command is a node of the command tree. Commands either have subcommands or are run.
+type command struct {
+	name    string
+	aliases []string
+	// args is the synopsis of the positional arguments, e.g. "<project>".
+	args    string
+	summary string
+	// minArgs and maxArgs limit the number of positional arguments. A negative maxArgs does not limit them.
+	minArgs, maxArgs int
+	// setup registers the flags of the command and returns the function running it.
+	setup    func(fs *flag.FlagSet) runFunc
+	commands []*command
+	// hidden commands are neither listed in the usage nor completed.
+	hidden bool
+	// rawArgs passes all arguments as positional arguments, without parsing flags.
+	rawArgs bool
+}
+
+// exactArgs sets the number of positional arguments of 'c' to 'n'.
+func (c *command) exactArgs(n int) *command {
+	c.minArgs, c.maxArgs = n, n
+	return c
+}
+
+// lookup returns the subcommand named 'name' or having it as alias.
+func (c *command) lookup(name string) *command {
+	for _, sub := range c.commands {
+		if sub.name == name {
+			return sub
+		}
+
+		for _, alias := range sub.aliases {
+			if alias == name {
+				return sub
+			}
+		}
+	}
+
+	return nil
+}
+
+// flagSet returns the flags of 'c', including the global flags bound to 'g', and the function running it.
+func (c *command) flagSet(g *globals, path string) (*flag.FlagSet, runFunc) {
+	fs := flag.NewFlagSet(path, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	g.register(fs)
+
+	var run runFunc
+	if c.setup != nil {
+		run = c.setup(fs)
+	}
+
+	return fs, run
+}
+
+// execute resolves the command given by 'args' and runs it. Flags may precede and follow positional arguments.
+func execute(ctx context.Context, root *command, e *env, args []string) error {
+	cmd, path := root, root.name
+
+	for {
+		fs, run := cmd.flagSet(&e.globals, path)
+
+		if len(cmd.commands) > 0 {
+			if err := fs.Parse(args); err != nil {
+				return usageError(e, cmd, path, fs, err)
+			}
+
+			args = fs.Args()
+			if len(args) == 0 {
+				return usageError(e, cmd, path, fs, errors.New("missing command"))
+			}
+
+			sub := cmd.lookup(args[0])
+			if sub == nil {
+				return usageError(e, cmd, path, fs, fmt.Errorf("unknown command %q", args[0]))
+			}
+
+			cmd, path, args = sub, path+" "+sub.name, args[1:]
+
+			continue
+		}
+
+		positional := args
+		if !cmd.rawArgs {
+			var err error
+			if positional, err = parseInterspersed(fs, args); err != nil {
+				return usageError(e, cmd, path, fs, err)
+			}
+		}
+
+		switch {
+		case len(positional) < cmd.minArgs:
+			return usageError(e, cmd, path, fs, errors.New("too few arguments"))
+		case cmd.maxArgs >= 0 && len(positional) > cmd.maxArgs:
+			return usageError(e, cmd, path, fs, errors.New("too many arguments"))
+		}
+
+		if err := e.validate(); err != nil {
+			return err
+		}
+
+		return run(ctx, e, positional)
+	}
+}
+
+// parseInterspersed parses the flags in 'args', returning the positional arguments between and after them.
+// Arguments following "--" are positional.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+
+		// The flag package consumes "--" and stops, so everything after it is positional.
+		if len(args) > len(rest) && args[len(args)-len(rest)-1] == "--" {
+			return append(positional, rest...), nil
+		}
+
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// parseID parses the numeric ID of a resource.
+func parseID(s string) (int64, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ID %q", s)
+	}
+
+	return id, nil
+}
+
+// usageError prints the usage of 'cmd' and returns 'err'. flag.ErrHelp is returned as is to suppress the error.
+func usageError(e *env, cmd *command, path string, fs *flag.FlagSet, err error) error {
+	printUsage(e.stderr, cmd, path, fs)
+
+	if errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+
+	return fmt.Errorf("%s: %w", path, err)
+}
+
+func printUsage(w io.Writer, cmd *command, path string, fs *flag.FlagSet) {
+	synopsis := path
+	if len(cmd.commands) > 0 {
+		synopsis += " <command>"
+	}
+
+	synopsis += " [flags]"
+	if cmd.args != "" {
+		synopsis += " " + cmd.args
+	}
+
+	fmt.Fprintf(w, "Usage: %s\n\n%s\n", synopsis, cmd.summary)
+
+	if len(cmd.commands) > 0 {
+		fmt.Fprintln(w, "\nCommands:")
+
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, sub := range cmd.commands {
+			if !sub.hidden {
+				fmt.Fprintf(tw, "  %s\t%s\n", strings.Join(append([]string{sub.name}, sub.aliases...), ", "), sub.summary)
+			}
+		}
+
+		_ = tw.Flush()
+	}
+
+	fmt.Fprintln(w, "\nFlags:")
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+	fs.SetOutput(io.Discard)
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// completionScripts are the shell scripts printed by 'harborctl completion'.
+// They call the hidden __complete command with the words typed so far.
+var completionScripts = map[string]string{
+	"bash": `# bash completion for harborctl, enable with: source <(harborctl completion bash)
+_harborctl() {
+	local IFS=$'\n'
+	COMPREPLY=($(harborctl __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _harborctl harborctl
+`,
+	"zsh": `#compdef harborctl
+# zsh completion for harborctl, enable with: source <(harborctl completion zsh)
+_harborctl() {
+	local -a candidates
+	candidates=("${(@f)$(harborctl __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+	candidates=(${candidates:#})
+	compadd -a candidates
+}
+compdef _harborctl harborctl
+`,
+	"fish": `# fish completion for harborctl, enable with: harborctl completion fish | source
+complete -c harborctl -f -a '(harborctl __complete (commandline -opc)[2..-1] (commandline -ct))'
+`,
+}
+
+func completionCommand() *command {
+	return (&command{
+		name:    "completion",
+		args:    "<bash|zsh|fish>",
+		summary: "Print the shell completion script for bash, zsh or fish.",
+		setup: func(*flag.FlagSet) runFunc {
+			return func(_ context.Context, e *env, args []string) error {
+				script, ok := completionScripts[args[0]]
+				if !ok {
+					return fmt.Errorf("unsupported shell %q, expected bash, zsh or fish", args[0])
+				}
+
+				_, err := fmt.Fprint(e.stdout, script)
+
+				return err
+			}
+		},
+	}).exactArgs(1)
+}
+
+// completeCommand is called by the completion scripts with the words following "harborctl",
+// the last one being the word to complete.
+func completeCommand() *command {
+	return &command{
+		name:    "__complete",
+		summary: "Print the completions of the given words.",
+		hidden:  true,
+		rawArgs: true,
+		minArgs: 1,
+		maxArgs: -1,
+		setup: func(*flag.FlagSet) runFunc {
+			return func(_ context.Context, e *env, args []string) error {
+				for _, c := range complete(rootCommand(), e, args) {
+					fmt.Fprintln(e.stdout, c)
+				}
+
+				return nil
+			}
+		},
+	}
+}
+
+// complete returns the completions of the last of 'words': subcommands, flags, or the values of some flags
+// and arguments. 'e' is used to read the configuration for completing context names.
+func complete(root *command, e *env, words []string) []string {
+	cmd, path := root, root.name
+	prefix := words[len(words)-1]
+
+	var (
+		positional int
+		// pending is the flag expecting the next word as value.
+		pending *flag.Flag
+	)
+
+	for _, w := range words[:len(words)-1] {
+		fs, _ := cmd.flagSet(&globals{}, path)
+
+		switch {
+		case pending != nil:
+			if pending.Name == "config" {
+				e.configPath = w
+			}
+
+			pending = nil
+		case w == "--":
+		case strings.HasPrefix(w, "-"):
+			name, _, hasValue := strings.Cut(strings.TrimLeft(w, "-"), "=")
+			if f := fs.Lookup(name); f != nil && !hasValue && !isBoolFlag(f) {
+				pending = f
+			}
+		case len(cmd.commands) > 0:
+			sub := cmd.lookup(w)
+			if sub == nil {
+				return nil
+			}
+
+			cmd, path = sub, path+" "+sub.name
+		default:
+			positional++
+		}
+	}
+
+	var candidates []string
+
+	switch {
+	case pending != nil:
+		switch pending.Name {
+		case "context":
+			candidates = contextNames(e)
+		case "o", "output":
+			candidates = []string{outputTable, outputJSON, outputYAML}
+		}
+	case strings.HasPrefix(prefix, "-"):
+		fs, _ := cmd.flagSet(&globals{}, path)
+		fs.VisitAll(func(f *flag.Flag) {
+			candidates = append(candidates, "-"+f.Name)
+		})
+	case len(cmd.commands) > 0:
+		for _, sub := range cmd.commands {
+			if !sub.hidden {
+				candidates = append(candidates, sub.name)
+			}
+		}
+	case positional == 0 && (path == root.name+" context use" || path == root.name+" context delete"):
+		candidates = contextNames(e)
+	case positional == 0 && path == root.name+" completion":
+		for shell := range completionScripts {
+			candidates = append(candidates, shell)
+		}
+	}
+
+	var res []string
+
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			res = append(res, c)
+		}
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// contextNames returns the names of the configured contexts, or none if the configuration cannot be read.
+func contextNames(e *env) []string {
+	cfg, err := e.Config()
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(cfg.Contexts))
+	for _, c := range cfg.Contexts {
+		names = append(names, c.Name)
+	}
+
+	return names
+}
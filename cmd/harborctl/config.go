@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// defaultPasswordEnv is the environment variable passwords are read from, unless a context names another one.
+	defaultPasswordEnv = "HARBOR_PASSWORD"
+	// configEnv overrides the path of the configuration file.
+	configEnv = "HARBORCTL_CONFIG"
+)
+
+// Config is the configuration file of harborctl.
+type Config struct {
+	// CurrentContext is the name of the context used unless -context is given.
+	CurrentContext string     `yaml:"current-context,omitempty"`
+	Contexts       []*Context `yaml:"contexts,omitempty"`
+}
+
+// Context configures the connection to a Harbor instance.
+type Context struct {
+	Name string `yaml:"name"`
+	// URL is the URL of the Harbor instance, e.g. https://harbor.example.com.
+	URL      string `yaml:"url"`
+	Username string `yaml:"username,omitempty"`
+	// PasswordEnv is the environment variable holding the password, defaulting to HARBOR_PASSWORD.
+	PasswordEnv string `yaml:"password-env,omitempty"`
+	// CAFile is a PEM encoded bundle of CA certificates trusted in addition to the system's root CAs.
+	CAFile string `yaml:"ca-file,omitempty"`
+	// Insecure disables the verification of Harbor's certificate.
+	Insecure bool `yaml:"insecure,omitempty"`
+}
+
+// password returns the password of the context from the environment.
+func (c *Context) password() string {
+	if c.PasswordEnv != "" {
+		return os.Getenv(c.PasswordEnv)
+	}
+
+	return os.Getenv(defaultPasswordEnv)
+}
+
+// defaultConfigPath returns the path of the configuration file unless set by -config.
+func defaultConfigPath() string {
+	if p := os.Getenv(configEnv); p != "" {
+		return p
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "harborctl.yaml"
+	}
+
+	return filepath.Join(dir, "harborctl", "config.yaml")
+}
+
+// loadConfig reads the configuration file at 'path'. A missing file results in an empty configuration.
+func loadConfig(path string) (*Config, error) {
+	cfg := &Config{}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// save writes the configuration file to 'path', creating its directory if needed.
+func (c *Config) save(path string) error {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0o600)
+}
+
+// context returns the context named 'name'.
+func (c *Config) context(name string) *Context {
+	for _, ctx := range c.Contexts {
+		if ctx.Name == name {
+			return ctx
+		}
+	}
+
+	return nil
+}
+
+// setContext adds 'ctx' or replaces the context of the same name.
+func (c *Config) setContext(ctx *Context) {
+	for i := range c.Contexts {
+		if c.Contexts[i].Name == ctx.Name {
+			c.Contexts[i] = ctx
+			return
+		}
+	}
+
+	c.Contexts = append(c.Contexts, ctx)
+}
+
+// deleteContext removes the context named 'name', reporting whether it existed.
+func (c *Config) deleteContext(name string) bool {
+	for i := range c.Contexts {
+		if c.Contexts[i].Name == name {
+			c.Contexts = append(c.Contexts[:i], c.Contexts[i+1:]...)
+
+			if c.CurrentContext == name {
+				c.CurrentContext = ""
+			}
+
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/configure"
+)
+
+func configCommand() *command {
+	return &command{
+		name:    "config",
+		summary: "Manage the system configuration of Harbor, e.g. authentication settings.",
+		commands: []*command{
+			{
+				name:    "get",
+				args:    "[key...]",
+				summary: "Show the configuration, or the given keys, e.g. auth_mode.",
+				maxArgs: -1,
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						cfg, err := c.GetConfigs(ctx)
+						if err != nil {
+							return err
+						}
+
+						items, err := configItems(cfg, args)
+						if err != nil {
+							return err
+						}
+
+						keys := make([]string, 0, len(items))
+						for k := range items {
+							keys = append(keys, k)
+						}
+
+						sort.Strings(keys)
+
+						t := newTable("KEY", "VALUE", "EDITABLE")
+						for _, k := range keys {
+							t.add(k, items[k].value, items[k].editable)
+						}
+
+						if len(args) == 0 {
+							return e.print(cfg, t)
+						}
+
+						values := make(map[string]json.RawMessage, len(items))
+						for k, item := range items {
+							values[k] = item.raw
+						}
+
+						return e.print(values, t)
+					}
+				},
+			},
+			{
+				name:    "set",
+				args:    "<key>=<value>...",
+				summary: "Update configuration values that differ from the current ones, e.g. self_registration=false.",
+				minArgs: 1,
+				maxArgs: -1,
+				setup: func(fs *flag.FlagSet) runFunc {
+					dryRun := fs.Bool("dry-run", false, "only print the changes")
+					force := fs.Bool("force", false, "apply dangerous changes, e.g. read_only=true")
+
+					return func(ctx context.Context, e *env, args []string) error {
+						cfg, err := parseConfigurations(args)
+						if err != nil {
+							return err
+						}
+
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						changes, err := c.PatchConfigs(ctx, cfg, &configure.PatchOptions{DryRun: *dryRun, AllowDangerous: *force})
+						if err != nil {
+							return err
+						}
+
+						t := newTable("KEY", "CURRENT", "DESIRED")
+						for i, change := range changes {
+							// Write-only items are secrets, which are not printed.
+							if change.Current == nil {
+								changes[i].Desired = "********"
+							}
+
+							t.add(change.Key, formatChangeValue(change.Current), formatChangeValue(changes[i].Desired))
+						}
+
+						return e.print(changes, t)
+					}
+				},
+			},
+			{
+				name:    "validate",
+				args:    "[<key>=<value>...]",
+				summary: "Check whether the LDAP or OIDC settings work with the given values, without saving them.",
+				maxArgs: -1,
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						cfg, err := parseConfigurations(args)
+						if err != nil {
+							return err
+						}
+
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						d, err := c.ValidateAuthConfigs(ctx, cfg)
+						if err != nil {
+							return err
+						}
+
+						if err := e.print(d, diagnosticsTable(d)); err != nil {
+							return err
+						}
+
+						if !d.OK() {
+							return fmt.Errorf("the %s settings do not work", d.AuthMode)
+						}
+
+						return nil
+					}
+				},
+			},
+		},
+	}
+}
+
+// diagnosticsTable lists the checks of 'd' and their results.
+func diagnosticsTable(d *configure.AuthDiagnostics) *table {
+	t := newTable("CHECK", "RESULT")
+	t.add("auth_mode", d.AuthMode)
+
+	var problems []string
+
+	switch {
+	case d.LDAP != nil:
+		t.add("ldap_url", d.LDAP.URL)
+		t.add("reachable", formatOptionalBool(d.LDAP.Reachable))
+		t.add("bound", formatBool(d.LDAP.Bound))
+		t.add("message", d.LDAP.Message)
+		problems = d.LDAP.Problems
+	case d.OIDC != nil:
+		t.add("oidc_endpoint", d.OIDC.Endpoint)
+		t.add("reachable", formatBool(d.OIDC.Reachable))
+		t.add("message", d.OIDC.Message)
+		problems = d.OIDC.Problems
+	default:
+		t.add("message", "no connectivity checks for this auth_mode")
+	}
+
+	for _, p := range problems {
+		t.add("problem", p)
+	}
+
+	return t
+}
+
+// formatChangeValue formats a value of a configuration change, which is nil for write-only items.
+func formatChangeValue(v interface{}) string {
+	if v == nil {
+		return "-"
+	}
+
+	return fmt.Sprint(v)
+}
+
+// configItem is a configuration value formatted for the table output.
+type configItem struct {
+	raw      json.RawMessage
+	value    string
+	editable string
+}
+
+// configItems returns the configuration items named by 'keys', or all items if none are given.
+func configItems(cfg *model.ConfigurationsResponse, keys []string) (map[string]configItem, error) {
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(b, &all); err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		for k := range all {
+			keys = append(keys, k)
+		}
+	}
+
+	items := make(map[string]configItem, len(keys))
+
+	for _, k := range keys {
+		raw, ok := all[k]
+		if !ok {
+			return nil, fmt.Errorf("unknown configuration key %q", k)
+		}
+
+		item := configItem{raw: raw, value: string(raw)}
+
+		var v struct {
+			Value    any   `json:"value"`
+			Editable *bool `json:"editable"`
+		}
+
+		// Most items consist of a value and whether it is editable, others like scan_all_policy are printed as is.
+		if err := json.Unmarshal(raw, &v); err == nil && v.Editable != nil {
+			item.value = fmt.Sprint(v.Value)
+			item.editable = formatBool(*v.Editable)
+		}
+
+		items[k] = item
+	}
+
+	return items, nil
+}
+
+// parseConfigurations returns the configurations setting the given key=value pairs,
+// converting the values to the type of the respective configuration.
+func parseConfigurations(pairs []string) (*model.Configurations, error) {
+	cfg := &model.Configurations{}
+	v := reflect.ValueOf(cfg).Elem()
+
+	fields := map[string]int{}
+	for i := 0; i < v.NumField(); i++ {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		fields[name] = i
+	}
+
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid configuration %q, expected key=value", pair)
+		}
+
+		i, ok := fields[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown configuration key %q", key)
+		}
+
+		f := v.Field(i)
+		if f.Kind() != reflect.Pointer {
+			return nil, fmt.Errorf("configuration %q cannot be set from the command line", key)
+		}
+
+		p := reflect.New(f.Type().Elem())
+
+		switch p.Elem().Kind() {
+		case reflect.String:
+			p.Elem().SetString(value)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("configuration %q: %q is not a boolean", key, value)
+			}
+
+			p.Elem().SetBool(b)
+		case reflect.Int, reflect.Int32, reflect.Int64:
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("configuration %q: %q is not an integer", key, value)
+			}
+
+			p.Elem().SetInt(n)
+		default:
+			return nil, fmt.Errorf("configuration %q cannot be set from the command line", key)
+		}
+
+		f.Set(p)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+)
+
+func contextCommand() *command {
+	return &command{
+		name:    "context",
+		aliases: []string{"contexts"},
+		summary: "Manage the Harbor instances configured in the configuration file.",
+		commands: []*command{
+			{
+				name:    "list",
+				summary: "List contexts.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(_ context.Context, e *env, _ []string) error {
+						cfg, err := e.Config()
+						if err != nil {
+							return err
+						}
+
+						t := newTable("CURRENT", "NAME", "URL", "USERNAME", "PASSWORD ENV")
+						for _, c := range cfg.Contexts {
+							current := ""
+							if c.Name == cfg.CurrentContext {
+								current = "*"
+							}
+
+							passwordEnv := c.PasswordEnv
+							if passwordEnv == "" {
+								passwordEnv = defaultPasswordEnv
+							}
+
+							t.add(current, c.Name, c.URL, c.Username, passwordEnv)
+						}
+
+						return e.print(cfg, t)
+					}
+				},
+			},
+			{
+				name:    "current",
+				summary: "Print the name of the current context.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(_ context.Context, e *env, _ []string) error {
+						cfg, err := e.Config()
+						if err != nil {
+							return err
+						}
+
+						if cfg.CurrentContext == "" {
+							return errors.New("no current context set")
+						}
+
+						e.printf("%s", cfg.CurrentContext)
+
+						return nil
+					}
+				},
+			},
+			(&command{
+				name:    "use",
+				args:    "<name>",
+				summary: "Set the current context.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(_ context.Context, e *env, args []string) error {
+						cfg, err := e.Config()
+						if err != nil {
+							return err
+						}
+
+						if cfg.context(args[0]) == nil {
+							return fmt.Errorf("context %q not found", args[0])
+						}
+
+						cfg.CurrentContext = args[0]
+
+						if err := e.saveConfig(); err != nil {
+							return err
+						}
+
+						e.printf("switched to context %q", args[0])
+
+						return nil
+					}
+				},
+			}).exactArgs(1),
+			(&command{
+				name:    "set",
+				args:    "<name>",
+				summary: "Add or replace a context. The first context becomes the current one.",
+				setup: func(fs *flag.FlagSet) runFunc {
+					c := &Context{}
+					fs.StringVar(&c.URL, "url", "", "`URL` of the Harbor instance, e.g. https://harbor.example.com")
+					fs.StringVar(&c.Username, "username", "", "`username` to authenticate as")
+					fs.StringVar(&c.PasswordEnv, "password-env", "", "environment `variable` holding the password (default "+defaultPasswordEnv+")")
+					fs.StringVar(&c.CAFile, "ca-file", "", "`path` of additional PEM encoded CA certificates to trust")
+					fs.BoolVar(&c.Insecure, "insecure", false, "do not verify the certificate of the Harbor instance")
+
+					return func(_ context.Context, e *env, args []string) error {
+						if c.URL == "" {
+							return errors.New("-url is required")
+						}
+
+						cfg, err := e.Config()
+						if err != nil {
+							return err
+						}
+
+						c.Name = args[0]
+						cfg.setContext(c)
+
+						if cfg.CurrentContext == "" {
+							cfg.CurrentContext = c.Name
+						}
+
+						if err := e.saveConfig(); err != nil {
+							return err
+						}
+
+						e.printf("context %q saved", c.Name)
+
+						return nil
+					}
+				},
+			}).exactArgs(1),
+			(&command{
+				name:    "delete",
+				args:    "<name>",
+				summary: "Delete a context.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(_ context.Context, e *env, args []string) error {
+						cfg, err := e.Config()
+						if err != nil {
+							return err
+						}
+
+						if !cfg.deleteContext(args[0]) {
+							return fmt.Errorf("context %q not found", args[0])
+						}
+
+						if err := e.saveConfig(); err != nil {
+							return err
+						}
+
+						e.printf("context %q deleted", args[0])
+
+						return nil
+					}
+				},
+			}).exactArgs(1),
+		},
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2"
+)
+
+// globals are the flags accepted by all commands.
+type globals struct {
+	configPath string
+	context    string
+	output     string
+}
+
+// register adds the global flags to 'fs', keeping the values already parsed by the parent commands.
+func (g *globals) register(fs *flag.FlagSet) {
+	fs.StringVar(&g.configPath, "config", g.configPath, "`path` of the configuration file (default $"+configEnv+" or harborctl/config.yaml in the user's configuration directory)")
+	fs.StringVar(&g.context, "context", g.context, "`name` of the context to use instead of the current context")
+	fs.StringVar(&g.output, "o", g.output, "output `format`: table, json or yaml")
+	fs.StringVar(&g.output, "output", g.output, "output `format`: table, json or yaml")
+}
+
+// env is the environment commands are run in.
+type env struct {
+	globals
+
+	stdout, stderr io.Writer
+
+	config *Config
+	client apiv2.Client
+}
+
+// validate checks the global flags before a command is run.
+func (e *env) validate() error {
+	switch e.output {
+	case outputTable, outputJSON, outputYAML:
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %q, expected table, json or yaml", e.output)
+	}
+}
+
+func (e *env) path() string {
+	if e.configPath != "" {
+		return e.configPath
+	}
+
+	return defaultConfigPath()
+}
+
+// Config returns the configuration, reading it on the first call.
+func (e *env) Config() (*Config, error) {
+	if e.config == nil {
+		cfg, err := loadConfig(e.path())
+		if err != nil {
+			return nil, err
+		}
+
+		e.config = cfg
+	}
+
+	return e.config, nil
+}
+
+// saveConfig writes the configuration returned by Config.
+func (e *env) saveConfig() error {
+	return e.config.save(e.path())
+}
+
+// Context returns the context selected by -context, the current context or, if neither is set,
+// a context configured by the environment variables HARBOR_URL and HARBOR_USERNAME.
+func (e *env) Context() (*Context, error) {
+	cfg, err := e.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	name := e.context
+	if name == "" {
+		name = cfg.CurrentContext
+	}
+
+	if name != "" {
+		ctx := cfg.context(name)
+		if ctx == nil {
+			return nil, fmt.Errorf("context %q not found in %s", name, e.path())
+		}
+
+		return ctx, nil
+	}
+
+	if u := os.Getenv("HARBOR_URL"); u != "" {
+		return &Context{Name: "environment", URL: u, Username: os.Getenv("HARBOR_USERNAME")}, nil
+	}
+
+	return nil, errors.New("no context configured, use 'harborctl context set' or set HARBOR_URL")
+}
+
+// Client returns the client for the selected context, creating it on the first call.
+func (e *env) Client() (apiv2.Client, error) {
+	if e.client != nil {
+		return e.client, nil
+	}
+
+	ctx, err := e.Context()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []apiv2.Option{apiv2.WithUserAgent("harborctl")}
+
+	if ctx.Username != "" {
+		opts = append(opts, apiv2.WithBasicAuth(ctx.Username, ctx.password()))
+	}
+
+	if ctx.CAFile != "" {
+		opts = append(opts, apiv2.WithCAFile(ctx.CAFile))
+	}
+
+	if ctx.Insecure {
+		opts = append(opts, apiv2.WithInsecureSkipVerify())
+	}
+
+	c, err := apiv2.New(apiURL(ctx.URL), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("context %q: %w", ctx.Name, err)
+	}
+
+	e.client = c
+
+	return c, nil
+}
+
+// apiURL returns the URL of the API of the Harbor instance at 'u', which may be given with or without the API path.
+func apiURL(u string) string {
+	u = strings.TrimSuffix(u, "/")
+
+	if strings.HasSuffix(u, "/api") || strings.HasSuffix(u, "/api/v2.0") {
+		return u
+	}
+
+	return u + "/api"
+}
+
+// printf writes a message confirming a change.
+func (e *env) printf(format string, args ...any) {
+	fmt.Fprintf(e.stdout, format+"\n", args...)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+func gcCommand() *command {
+	return &command{
+		name:    "gc",
+		summary: "Manage garbage collection, which frees the storage of deleted artifacts.",
+		commands: []*command{
+			{
+				name:    "history",
+				summary: "List garbage collection runs.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, _ []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						runs, err := c.GetGarbageCollectionExecutions(ctx)
+						if err != nil {
+							return err
+						}
+
+						return e.print(runs, gcTable(runs...))
+					}
+				},
+			},
+			(&command{
+				name:    "get",
+				args:    "<id>",
+				summary: "Show a garbage collection run.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						id, err := parseID(args[0])
+						if err != nil {
+							return err
+						}
+
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						run, err := c.GetGarbageCollectionExecution(ctx, id)
+						if err != nil {
+							return err
+						}
+
+						return e.print(run, gcTable(run))
+					}
+				},
+			}).exactArgs(1),
+			{
+				name:    "schedule",
+				summary: "Show the garbage collection schedule.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, _ []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						schedule, err := c.GetGarbageCollectionSchedule(ctx)
+						if err != nil {
+							return err
+						}
+
+						return e.print(schedule, scheduleTable(schedule.Schedule))
+					}
+				},
+			},
+			{
+				name:    "run",
+				summary: "Start garbage collection.",
+				setup: func(fs *flag.FlagSet) runFunc {
+					deleteUntagged := fs.Bool("delete-untagged", false, "delete untagged artifacts")
+					dryRun := fs.Bool("dry-run", false, "only estimate the storage that would be freed")
+
+					return func(ctx context.Context, e *env, _ []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						err = c.NewGarbageCollection(ctx, &model.Schedule{
+							Schedule: &model.ScheduleObj{Type: "Manual"},
+							Parameters: map[string]interface{}{
+								"delete_untagged": *deleteUntagged,
+								"dry_run":         *dryRun,
+							},
+						})
+						if err != nil {
+							return err
+						}
+
+						e.printf("garbage collection started")
+
+						return nil
+					}
+				},
+			},
+		},
+	}
+}
+
+func gcTable(runs ...*model.GCHistory) *table {
+	t := newTable("ID", "STATUS", "TRIGGER", "CREATED", "UPDATED")
+
+	for _, r := range runs {
+		t.add(formatInt(r.ID), r.JobStatus, scheduleType(r.Schedule), formatTime(r.CreationTime), formatTime(r.UpdateTime))
+	}
+
+	return t
+}
+
+// scheduleType returns the type of 's', e.g. Manual or Daily.
+func scheduleType(s *model.ScheduleObj) string {
+	if s == nil {
+		return ""
+	}
+
+	return s.Type
+}
+
+func scheduleTable(s *model.ScheduleObj) *table {
+	t := newTable("TYPE", "CRON", "NEXT RUN")
+
+	if s != nil {
+		t.add(s.Type, s.Cron, formatTime(s.NextScheduledTime))
+	}
+
+	return t
+}
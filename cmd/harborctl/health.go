@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/health"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+func healthCommand() *command {
+	return &command{
+		name:    "health",
+		args:    "[<component>...]",
+		summary: "Show the health of Harbor's components, optionally waiting for the given ones to become healthy.",
+		maxArgs: -1,
+		setup: func(fs *flag.FlagSet) runFunc {
+			wait := fs.Duration("wait", 0, "wait up to `duration` for the components, or all if none are given, to become healthy")
+
+			return func(ctx context.Context, e *env, args []string) error {
+				c, err := e.Client()
+				if err != nil {
+					return err
+				}
+
+				if *wait > 0 {
+					waitCtx, cancel := context.WithTimeout(ctx, *wait)
+					defer cancel()
+
+					if err := c.WaitUntilHealthy(waitCtx, args...); err != nil {
+						return err
+					}
+				}
+
+				status, err := c.GetHealth(ctx)
+				if err != nil {
+					return err
+				}
+
+				t := newTable("COMPONENT", "STATUS", "ERROR")
+				for _, comp := range status.Components {
+					t.add(comp.Name, comp.Status, comp.Error)
+				}
+
+				if err := e.print(status, t); err != nil {
+					return err
+				}
+
+				if unhealthy := health.Unhealthy(status, args...); len(unhealthy) > 0 {
+					return &errors.ErrUnhealthy{Components: unhealthy}
+				}
+
+				return nil
+			}
+		},
+	}
+}
@@ -0,0 +1,73 @@
+// Command harborctl manages the resources of a Harbor 2.x instance from the command line.
+//
+// Commands are grouped by resource, e.g.:
+//
+//	harborctl projects list
+//	harborctl artifacts tags add library/app@sha256:... stable
+//	harborctl -o yaml registries get dockerhub
+//
+// Instances are configured as contexts in the file $HARBORCTL_CONFIG, defaulting to harborctl/config.yaml
+// in the user's configuration directory, see 'harborctl context set'. Passwords are never stored in the file,
+// but read from the environment variable named by the context, HARBOR_PASSWORD by default.
+// Without a configured context, HARBOR_URL, HARBOR_USERNAME and HARBOR_PASSWORD are used.
+//
+// Results are printed as table, JSON or YAML, selected by -o. Shell completion is enabled by sourcing
+// the output of 'harborctl completion bash', 'harborctl completion zsh' or 'harborctl completion fish'.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "harborctl:", err)
+		os.Exit(1)
+	}
+}
+
+// run executes the command given by 'args', writing results to 'stdout' and usage information to 'stderr'.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	e := &env{stdout: stdout, stderr: stderr, globals: globals{output: outputTable}}
+
+	err := execute(ctx, rootCommand(), e, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+
+	return err
+}
+
+// rootCommand returns the command tree of harborctl.
+func rootCommand() *command {
+	return &command{
+		name:    "harborctl",
+		summary: "Manage the resources of a Harbor instance.",
+		commands: []*command{
+			projectsCommand(),
+			repositoriesCommand(),
+			artifactsCommand(),
+			membersCommand(),
+			robotsCommand(),
+			registriesCommand(),
+			replicationCommand(),
+			retentionCommand(),
+			gcCommand(),
+			purgeCommand(),
+			configCommand(),
+			healthCommand(),
+			contextCommand(),
+			completionCommand(),
+			completeCommand(),
+		},
+	}
+}
@@ -0,0 +1,215 @@
+//go:build !integration
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/testing/fakeharbor"
+)
+
+// harborctl runs the command given by 'args' using the configuration file 'config', returning its output.
+func harborctl(t *testing.T, config string, args ...string) (string, error) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+
+	err := run(context.Background(), append([]string{"-config", config}, args...), &stdout, &stderr)
+
+	return stdout.String(), err
+}
+
+func newServer(t *testing.T) (*fakeharbor.Server, string) {
+	t.Helper()
+
+	srv := fakeharbor.NewServer()
+	t.Cleanup(srv.Close)
+
+	t.Setenv("HARBORCTL_TEST_PASSWORD", fakeharbor.AdminPassword)
+
+	config := filepath.Join(t.TempDir(), "config.yaml")
+
+	_, err := harborctl(t, config, "context", "set", "test",
+		"-url", srv.URL, "-username", fakeharbor.AdminUser, "-password-env", "HARBORCTL_TEST_PASSWORD")
+	require.NoError(t, err)
+
+	return srv, config
+}
+
+func TestHarborctl_Projects(t *testing.T) {
+	_, config := newServer(t)
+
+	out, err := harborctl(t, config, "projects", "create", "team-a", "-public")
+	require.NoError(t, err)
+	require.Equal(t, "project \"team-a\" created\n", out)
+
+	out, err = harborctl(t, config, "projects", "list", "-o", "json")
+	require.NoError(t, err)
+
+	var projects []*model.Project
+	require.NoError(t, json.Unmarshal([]byte(out), &projects))
+	require.Len(t, projects, 2, "team-a and the default project library")
+	require.Equal(t, "team-a", projects[1].Name)
+	require.Equal(t, "true", projects[1].Metadata.Public)
+
+	out, err = harborctl(t, config, "-o", "yaml", "projects", "get", "team-a")
+	require.NoError(t, err)
+
+	var project map[string]any
+	require.NoError(t, yaml.Unmarshal([]byte(out), &project))
+	require.Equal(t, "team-a", project["name"], "YAML uses the field names of the API")
+
+	out, err = harborctl(t, config, "projects", "list", "-name", "team")
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 2)
+	require.Equal(t, []string{"NAME", "ID", "PUBLIC", "REPOSITORIES", "CREATED"}, strings.Fields(lines[0]))
+	require.Equal(t, "team-a", strings.Fields(lines[1])[0])
+
+	_, err = harborctl(t, config, "projects", "get", "unknown")
+	require.Error(t, err)
+}
+
+func TestHarborctl_Artifacts(t *testing.T) {
+	srv, config := newServer(t)
+
+	_, err := harborctl(t, config, "projects", "create", "team-a")
+	require.NoError(t, err)
+
+	a, err := srv.PushArtifact("team-a", "tools/cli", "v1")
+	require.NoError(t, err)
+
+	out, err := harborctl(t, config, "repos", "list", "team-a")
+	require.NoError(t, err)
+	require.Contains(t, out, "team-a/tools/cli")
+
+	out, err = harborctl(t, config, "artifacts", "tags", "add", "team-a/tools/cli:v1", "stable")
+	require.NoError(t, err)
+	require.Equal(t, "tag \"stable\" added to \"team-a/tools/cli:v1\"\n", out)
+
+	out, err = harborctl(t, config, "artifacts", "list", "team-a/tools/cli", "-o", "json")
+	require.NoError(t, err)
+
+	var artifacts []*model.Artifact
+	require.NoError(t, json.Unmarshal([]byte(out), &artifacts))
+	require.Len(t, artifacts, 1)
+	require.Equal(t, a.Digest, artifacts[0].Digest)
+	require.Len(t, artifacts[0].Tags, 2)
+
+	out, err = harborctl(t, config, "artifacts", "tags", "list", "team-a/tools/cli@"+a.Digest)
+	require.NoError(t, err)
+	require.Contains(t, out, "stable")
+
+	_, err = harborctl(t, config, "artifacts", "tags", "delete", "team-a/tools/cli:stable", "v1")
+	require.NoError(t, err)
+
+	out, err = harborctl(t, config, "artifacts", "list", "team-a/tools/cli", "-tag", "v*")
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(out, "\n"), "only the header is printed")
+}
+
+func TestHarborctl_Contexts(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config.yaml")
+
+	_, err := harborctl(t, config, "context", "set", "prod", "-url", "https://harbor.example.com")
+	require.NoError(t, err)
+	_, err = harborctl(t, config, "context", "set", "staging", "-url", "https://staging.example.com", "-username", "admin")
+	require.NoError(t, err)
+
+	out, err := harborctl(t, config, "context", "current")
+	require.NoError(t, err)
+	require.Equal(t, "prod\n", out, "the first context becomes the current one")
+
+	_, err = harborctl(t, config, "context", "use", "staging")
+	require.NoError(t, err)
+
+	cfg, err := loadConfig(config)
+	require.NoError(t, err)
+	require.Equal(t, "staging", cfg.CurrentContext)
+	require.Len(t, cfg.Contexts, 2)
+
+	_, err = harborctl(t, config, "context", "use", "unknown")
+	require.Error(t, err)
+
+	_, err = harborctl(t, config, "context", "delete", "staging")
+	require.NoError(t, err)
+
+	_, err = harborctl(t, config, "context", "current")
+	require.Error(t, err)
+}
+
+func TestHarborctl_Usage(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config.yaml")
+
+	_, err := harborctl(t, config, "projects", "-h")
+	require.NoError(t, err)
+
+	_, err = harborctl(t, config, "projects", "unknown")
+	require.ErrorContains(t, err, `unknown command "unknown"`)
+
+	_, err = harborctl(t, config, "projects", "get")
+	require.ErrorContains(t, err, "too few arguments")
+
+	_, err = harborctl(t, config, "-o", "xml", "projects", "list")
+	require.ErrorContains(t, err, "unsupported output format")
+
+	t.Setenv("HARBOR_URL", "")
+	_, err = harborctl(t, config, "projects", "list")
+	require.ErrorContains(t, err, "no context configured")
+}
+
+func TestComplete(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config.yaml")
+
+	_, err := harborctl(t, config, "context", "set", "prod", "-url", "https://harbor.example.com")
+	require.NoError(t, err)
+
+	e := &env{globals: globals{configPath: config}}
+	root := rootCommand()
+
+	require.Equal(t, []string{"projects", "purge"}, complete(root, e, []string{"p"}))
+	require.Equal(t, []string{"tags"}, complete(root, e, []string{"artifacts", "t"}))
+	require.Equal(t, []string{"-public"}, complete(root, e, []string{"projects", "create", "-p"}))
+	require.Equal(t, []string{"json"}, complete(root, e, []string{"-o", "j"}))
+	require.Equal(t, []string{"prod"}, complete(root, e, []string{"context", "use", ""}))
+	require.Equal(t, []string{"prod"}, complete(root, e, []string{"-context", ""}))
+	require.Empty(t, complete(root, e, []string{"unknown", ""}))
+
+	var stdout bytes.Buffer
+	require.NoError(t, run(context.Background(), []string{"__complete", "-config", config, "-"}, &stdout, &stdout))
+	require.Contains(t, stdout.String(), "-output\n")
+
+	stdout.Reset()
+	require.NoError(t, run(context.Background(), []string{"completion", "bash"}, &stdout, &stdout))
+	require.Contains(t, stdout.String(), "complete -o default -F _harborctl harborctl")
+}
+
+func TestHarborctl_Health(t *testing.T) {
+	srv, config := newServer(t)
+
+	out, err := harborctl(t, config, "health")
+	require.NoError(t, err)
+	require.Contains(t, out, "redis")
+
+	srv.SetComponentHealth("redis", "connection refused")
+
+	out, err = harborctl(t, config, "health")
+	require.ErrorContains(t, err, "unhealthy components: redis")
+	require.Contains(t, out, "connection refused")
+
+	_, err = harborctl(t, config, "health", "core", "database")
+	require.NoError(t, err)
+
+	_, err = harborctl(t, config, "health", "-wait", "10ms", "redis")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/member"
+)
+
+// roles maps the role names accepted by -role to Harbor's roles.
+var roles = map[string]member.Role{
+	"admin":         member.RoleProjectAdmin,
+	"maintainer":    member.RoleMaintainer,
+	"developer":     member.RoleDeveloper,
+	"guest":         member.RoleGuest,
+	"limited-guest": member.RoleLimitedGuest,
+}
+
+func parseRole(s string) (member.Role, error) {
+	if r, ok := roles[s]; ok {
+		return r, nil
+	}
+
+	names := make([]string, 0, len(roles))
+	for name := range roles {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return 0, fmt.Errorf("unknown role %q, expected one of %s", s, strings.Join(names, ", "))
+}
+
+// projectMember returns the member named 'name', which is a user unless 'group' is set.
+func projectMember(name string, group bool, role member.Role) *model.ProjectMember {
+	if group {
+		return &model.ProjectMember{MemberGroup: &model.UserGroup{GroupName: name}, RoleID: int64(role)}
+	}
+
+	return &model.ProjectMember{MemberUser: &model.UserEntity{Username: name}, RoleID: int64(role)}
+}
+
+func membersCommand() *command {
+	memberFlags := func(fs *flag.FlagSet) (role *string, group *bool) {
+		return fs.String("role", "developer", "`role` of the member: admin, maintainer, developer, guest or limited-guest"),
+			fs.Bool("group", false, "the member is a user group instead of a user")
+	}
+
+	return &command{
+		name:    "members",
+		aliases: []string{"member"},
+		summary: "Manage the members of projects.",
+		commands: []*command{
+			(&command{
+				name:    "list",
+				args:    "<project>",
+				summary: "List the members of a project.",
+				setup: func(fs *flag.FlagSet) runFunc {
+					query := fs.String("name", "", "list only members whose name contains `filter`")
+
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						members, err := c.ListProjectMembers(ctx, args[0], *query)
+						if err != nil {
+							return err
+						}
+
+						t := newTable("NAME", "TYPE", "ROLE")
+						for _, m := range members {
+							entityType := "user"
+							if m.EntityType == member.EntityTypeGroup.String() {
+								entityType = "group"
+							}
+
+							t.add(m.EntityName, entityType, m.RoleName)
+						}
+
+						return e.print(members, t)
+					}
+				},
+			}).exactArgs(1),
+			(&command{
+				name:    "add",
+				args:    "<project> <name>",
+				summary: "Add a user or group to a project.",
+				setup: func(fs *flag.FlagSet) runFunc {
+					role, group := memberFlags(fs)
+
+					return func(ctx context.Context, e *env, args []string) error {
+						r, err := parseRole(*role)
+						if err != nil {
+							return err
+						}
+
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						if err := c.AddProjectMember(ctx, args[0], projectMember(args[1], *group, r)); err != nil {
+							return err
+						}
+
+						e.printf("%q added to project %q as %s", args[1], args[0], *role)
+
+						return nil
+					}
+				},
+			}).exactArgs(2),
+			(&command{
+				name:    "update",
+				args:    "<project> <name>",
+				summary: "Change the role of a project member.",
+				setup: func(fs *flag.FlagSet) runFunc {
+					role, group := memberFlags(fs)
+
+					return func(ctx context.Context, e *env, args []string) error {
+						r, err := parseRole(*role)
+						if err != nil {
+							return err
+						}
+
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						if err := c.UpdateProjectMember(ctx, args[0], projectMember(args[1], *group, r)); err != nil {
+							return err
+						}
+
+						e.printf("%q is %s of project %q", args[1], *role, args[0])
+
+						return nil
+					}
+				},
+			}).exactArgs(2),
+			(&command{
+				name:    "delete",
+				args:    "<project> <name>",
+				summary: "Remove a user or group from a project.",
+				setup: func(fs *flag.FlagSet) runFunc {
+					group := fs.Bool("group", false, "the member is a user group instead of a user")
+
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						if err := c.DeleteProjectMember(ctx, args[0], projectMember(args[1], *group, 0)); err != nil {
+							return err
+						}
+
+						e.printf("%q removed from project %q", args[1], args[0])
+
+						return nil
+					}
+				},
+			}).exactArgs(2),
+		},
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats selected by -o.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// table is the tabular representation of a result.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func newTable(header ...string) *table {
+	return &table{header: header}
+}
+
+func (t *table) add(row ...string) {
+	t.rows = append(t.rows, row)
+}
+
+// print writes 'v' in the selected output format, using 't' as its tabular representation.
+func (e *env) print(v any, t *table) error {
+	switch e.output {
+	case outputJSON:
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(nonNil(v))
+	case outputYAML:
+		// Converting the value via JSON keeps the field names of the API.
+		b, err := json.Marshal(nonNil(v))
+		if err != nil {
+			return err
+		}
+
+		var generic any
+		if err := json.Unmarshal(b, &generic); err != nil {
+			return err
+		}
+
+		enc := yaml.NewEncoder(e.stdout)
+		enc.SetIndent(2)
+
+		if err := enc.Encode(generic); err != nil {
+			return err
+		}
+
+		return enc.Close()
+	default:
+		tw := tabwriter.NewWriter(e.stdout, 0, 4, 3, ' ', 0)
+
+		fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+
+		for _, row := range t.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+
+		return tw.Flush()
+	}
+}
+
+// nonNil replaces nil slices by empty ones, so that empty lists are printed as such instead of null.
+func nonNil(v any) any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice && rv.IsNil() {
+		return reflect.MakeSlice(rv.Type(), 0, 0).Interface()
+	}
+
+	return v
+}
+
+// formatTime formats 't' in UTC, or returns an empty string if it is not set.
+func formatTime(t strfmt.DateTime) string {
+	if time.Time(t).IsZero() {
+		return ""
+	}
+
+	return time.Time(t).UTC().Format(time.RFC3339)
+}
+
+// formatSize formats 'bytes' using binary prefixes, e.g. 1.5MiB.
+func formatSize(bytes int64) string {
+	const unit = 1024
+
+	if bytes < unit {
+		return strconv.FormatInt(bytes, 10) + "B"
+	}
+
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f%ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// formatInt formats 'i', which is typically an ID or a count.
+func formatInt[T ~int | ~int32 | ~int64](i T) string {
+	return strconv.FormatInt(int64(i), 10)
+}
+
+// shortDigest returns the first 12 hex digits of a digest, like the docker CLI.
+func shortDigest(digest string) string {
+	algorithm, hex, ok := strings.Cut(digest, ":")
+	if !ok || len(hex) <= 12 {
+		return digest
+	}
+
+	return algorithm + ":" + hex[:12]
+}
+
+// formatBool formats 'b' as "yes" or "no".
+func formatBool(b bool) string {
+	if b {
+		return "yes"
+	}
+
+	return "no"
+}
+
+// formatOptionalBool formats 'b' like formatBool, or as "unknown" if it is nil.
+func formatOptionalBool(b *bool) string {
+	if b == nil {
+		return "unknown"
+	}
+
+	return formatBool(*b)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strconv"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+func projectsCommand() *command {
+	return &command{
+		name:    "projects",
+		aliases: []string{"project"},
+		summary: "Manage projects.",
+		commands: []*command{
+			{
+				name:    "list",
+				summary: "List projects.",
+				setup: func(fs *flag.FlagSet) runFunc {
+					name := fs.String("name", "", "list only projects whose name contains `filter`")
+
+					return func(ctx context.Context, e *env, _ []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						projects, err := c.ListProjects(ctx, *name)
+						if err != nil {
+							return err
+						}
+
+						return e.print(projects, projectTable(projects...))
+					}
+				},
+			},
+			(&command{
+				name:    "get",
+				args:    "<project>",
+				summary: "Show a project by name or ID.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						p, err := c.GetProject(ctx, args[0])
+						if err != nil {
+							return err
+						}
+
+						return e.print(p, projectTable(p))
+					}
+				},
+			}).exactArgs(1),
+			(&command{
+				name:    "create",
+				args:    "<name>",
+				summary: "Create a project.",
+				setup: func(fs *flag.FlagSet) runFunc {
+					public := fs.Bool("public", false, "allow anonymous users to pull from the project")
+					storageLimit := fs.Int64("storage-limit", -1, "storage quota in `bytes`, -1 for unlimited")
+
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						err = c.NewProject(ctx, &model.ProjectReq{
+							ProjectName:  args[0],
+							Metadata:     &model.ProjectMetadata{Public: strconv.FormatBool(*public)},
+							StorageLimit: storageLimit,
+						})
+						if err != nil {
+							return err
+						}
+
+						e.printf("project %q created", args[0])
+
+						return nil
+					}
+				},
+			}).exactArgs(1),
+			(&command{
+				name:    "delete",
+				args:    "<project>",
+				summary: "Delete an empty project by name or ID.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						if err := c.DeleteProject(ctx, args[0]); err != nil {
+							return err
+						}
+
+						e.printf("project %q deleted", args[0])
+
+						return nil
+					}
+				},
+			}).exactArgs(1),
+		},
+	}
+}
+
+func projectTable(projects ...*model.Project) *table {
+	t := newTable("NAME", "ID", "PUBLIC", "REPOSITORIES", "CREATED")
+
+	for _, p := range projects {
+		public := ""
+		if p.Metadata != nil {
+			public = p.Metadata.Public
+		}
+
+		t.add(p.Name, formatInt(p.ProjectID), public, formatInt(p.RepoCount), formatTime(p.CreationTime))
+	}
+
+	return t
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+func purgeCommand() *command {
+	return &command{
+		name:    "purge",
+		summary: "Manage the purging of the audit log.",
+		commands: []*command{
+			{
+				name:    "history",
+				summary: "List purge jobs.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, _ []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						jobs, err := c.ListPurgeHistory(ctx)
+						if err != nil {
+							return err
+						}
+
+						return e.print(jobs, purgeTable(jobs...))
+					}
+				},
+			},
+			(&command{
+				name:    "log",
+				args:    "<id>",
+				summary: "Print the log of a purge job.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						id, err := parseID(args[0])
+						if err != nil {
+							return err
+						}
+
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						log, err := c.GetPurgeJobLog(ctx, id)
+						if err != nil {
+							return err
+						}
+
+						_, err = fmt.Fprint(e.stdout, log)
+
+						return err
+					}
+				},
+			}).exactArgs(1),
+			{
+				name:    "schedule",
+				summary: "Show the purge schedule.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, _ []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						schedule, err := c.GetPurgeSchedule(ctx)
+						if err != nil {
+							return err
+						}
+
+						return e.print(schedule, scheduleTable(schedule.Schedule))
+					}
+				},
+			},
+			{
+				name:    "run",
+				summary: "Start purging the audit log using the parameters of the schedule.",
+				setup: func(fs *flag.FlagSet) runFunc {
+					dryRun := fs.Bool("dry-run", false, "only count the entries that would be purged")
+
+					return func(ctx context.Context, e *env, _ []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						if err := c.RunPurge(ctx, *dryRun); err != nil {
+							return err
+						}
+
+						e.printf("purge started")
+
+						return nil
+					}
+				},
+			},
+			(&command{
+				name:    "stop",
+				args:    "<id>",
+				summary: "Stop a running purge job.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						id, err := parseID(args[0])
+						if err != nil {
+							return err
+						}
+
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						if err := c.StopPurge(ctx, id); err != nil {
+							return err
+						}
+
+						e.printf("purge job %d stopped", id)
+
+						return nil
+					}
+				},
+			}).exactArgs(1),
+		},
+	}
+}
+
+func purgeTable(jobs ...*model.ExecHistory) *table {
+	t := newTable("ID", "STATUS", "TRIGGER", "CREATED", "UPDATED")
+
+	for _, j := range jobs {
+		t.add(formatInt(j.ID), j.JobStatus, scheduleType(j.Schedule), formatTime(j.CreationTime), formatTime(j.UpdateTime))
+	}
+
+	return t
+}
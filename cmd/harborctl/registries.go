@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+func registriesCommand() *command {
+	return &command{
+		name:    "registries",
+		aliases: []string{"registry"},
+		summary: "Manage the registries used as replication endpoints.",
+		commands: []*command{
+			{
+				name:    "list",
+				summary: "List registries.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, _ []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						registries, err := c.ListRegistries(ctx)
+						if err != nil {
+							return err
+						}
+
+						return e.print(registries, registryTable(registries...))
+					}
+				},
+			},
+			(&command{
+				name:    "get",
+				args:    "<name>",
+				summary: "Show a registry.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						r, err := c.GetRegistryByName(ctx, args[0])
+						if err != nil {
+							return err
+						}
+
+						return e.print(r, registryTable(r))
+					}
+				},
+			}).exactArgs(1),
+			(&command{
+				name:    "create",
+				args:    "<name>",
+				summary: "Create a registry.",
+				setup: func(fs *flag.FlagSet) runFunc {
+					url := fs.String("url", "", "`URL` of the registry, e.g. https://registry-1.docker.io")
+					registryType := fs.String("type", "docker-registry", "`type` of the registry, e.g. harbor, docker-hub or docker-registry")
+					accessKey := fs.String("access-key", "", "`username` or access key used to authenticate")
+					secretEnv := fs.String("access-secret-env", "", "environment `variable` holding the password or access secret")
+					insecure := fs.Bool("insecure", false, "do not verify the certificate of the registry")
+					description := fs.String("description", "", "`description` of the registry")
+
+					return func(ctx context.Context, e *env, args []string) error {
+						if *url == "" {
+							return errors.New("-url is required")
+						}
+
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						r := &model.Registry{
+							Name:        args[0],
+							URL:         *url,
+							Type:        *registryType,
+							Insecure:    *insecure,
+							Description: *description,
+						}
+
+						if *accessKey != "" {
+							r.Credential = &model.RegistryCredential{
+								Type:         "basic",
+								AccessKey:    *accessKey,
+								AccessSecret: os.Getenv(*secretEnv),
+							}
+						}
+
+						if err := c.NewRegistry(ctx, r); err != nil {
+							return err
+						}
+
+						e.printf("registry %q created", args[0])
+
+						return nil
+					}
+				},
+			}).exactArgs(1),
+			(&command{
+				name:    "delete",
+				args:    "<name>",
+				summary: "Delete a registry.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						r, err := c.GetRegistryByName(ctx, args[0])
+						if err != nil {
+							return err
+						}
+
+						if err := c.DeleteRegistryByID(ctx, r.ID); err != nil {
+							return err
+						}
+
+						e.printf("registry %q deleted", args[0])
+
+						return nil
+					}
+				},
+			}).exactArgs(1),
+		},
+	}
+}
+
+func registryTable(registries ...*model.Registry) *table {
+	t := newTable("NAME", "ID", "TYPE", "URL", "STATUS")
+
+	for _, r := range registries {
+		t.add(r.Name, formatInt(r.ID), r.Type, r.URL, r.Status)
+	}
+
+	return t
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+func replicationCommand() *command {
+	return &command{
+		name:    "replication",
+		summary: "Manage replication policies and executions.",
+		commands: []*command{
+			{
+				name:    "list",
+				summary: "List replication policies.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, _ []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						policies, err := c.ListReplicationPolicies(ctx)
+						if err != nil {
+							return err
+						}
+
+						return e.print(policies, replicationPolicyTable(policies...))
+					}
+				},
+			},
+			(&command{
+				name:    "get",
+				args:    "<policy>",
+				summary: "Show a replication policy.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						p, err := c.GetReplicationPolicyByName(ctx, args[0])
+						if err != nil {
+							return err
+						}
+
+						return e.print(p, replicationPolicyTable(p))
+					}
+				},
+			}).exactArgs(1),
+			(&command{
+				name:    "delete",
+				args:    "<policy>",
+				summary: "Delete a replication policy.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						p, err := c.GetReplicationPolicyByName(ctx, args[0])
+						if err != nil {
+							return err
+						}
+
+						if err := c.DeleteReplicationPolicyByID(ctx, p.ID); err != nil {
+							return err
+						}
+
+						e.printf("replication policy %q deleted", args[0])
+
+						return nil
+					}
+				},
+			}).exactArgs(1),
+			(&command{
+				name:    "run",
+				args:    "<policy>",
+				summary: "Start an execution of a replication policy.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						p, err := c.GetReplicationPolicyByName(ctx, args[0])
+						if err != nil {
+							return err
+						}
+
+						if err := c.TriggerReplicationExecution(ctx, &model.StartReplicationExecution{PolicyID: p.ID}); err != nil {
+							return err
+						}
+
+						e.printf("replication policy %q started", args[0])
+
+						return nil
+					}
+				},
+			}).exactArgs(1),
+			{
+				name:    "executions",
+				args:    "[policy]",
+				summary: "List the executions of a replication policy, or of all policies.",
+				maxArgs: 1,
+				setup: func(fs *flag.FlagSet) runFunc {
+					status := fs.String("status", "", "list only executions having the given `status`, e.g. Failed")
+
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						var policyID *int64
+
+						if len(args) > 0 {
+							p, err := c.GetReplicationPolicyByName(ctx, args[0])
+							if err != nil {
+								return err
+							}
+
+							policyID = &p.ID
+						}
+
+						var statusFilter *string
+						if *status != "" {
+							statusFilter = status
+						}
+
+						executions, err := c.ListReplicationExecutions(ctx, policyID, statusFilter, nil)
+						if err != nil {
+							return err
+						}
+
+						t := newTable("ID", "POLICY", "STATUS", "TRIGGER", "SUCCEEDED", "FAILED", "TOTAL", "STARTED", "ENDED")
+						for _, x := range executions {
+							t.add(formatInt(x.ID), formatInt(x.PolicyID), x.Status, x.Trigger,
+								formatInt(x.Succeed), formatInt(x.Failed), formatInt(x.Total),
+								formatTime(x.StartTime), formatTime(x.EndTime))
+						}
+
+						return e.print(executions, t)
+					}
+				},
+			},
+		},
+	}
+}
+
+func replicationPolicyTable(policies ...*model.ReplicationPolicy) *table {
+	t := newTable("NAME", "ID", "ENABLED", "SOURCE", "DESTINATION", "TRIGGER")
+
+	registryName := func(r *model.Registry) string {
+		if r == nil || r.ID == 0 {
+			return "local"
+		}
+
+		return r.Name
+	}
+
+	for _, p := range policies {
+		trigger := ""
+		if p.Trigger != nil {
+			trigger = p.Trigger.Type
+		}
+
+		t.add(p.Name, formatInt(p.ID), formatBool(p.Enabled), registryName(p.SrcRegistry), registryName(p.DestRegistry), trigger)
+	}
+
+	return t
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/reference"
+)
+
+func repositoriesCommand() *command {
+	return &command{
+		name:    "repos",
+		aliases: []string{"repositories", "repo"},
+		summary: "Manage repositories.",
+		commands: []*command{
+			{
+				name:    "list",
+				args:    "[project]",
+				summary: "List the repositories of a project, or of all projects.",
+				maxArgs: 1,
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						var repos []*model.Repository
+						if len(args) == 0 {
+							repos, err = c.ListAllRepositories(ctx)
+						} else {
+							repos, err = c.ListRepositories(ctx, args[0])
+						}
+
+						if err != nil {
+							return err
+						}
+
+						return e.print(repos, repositoryTable(repos...))
+					}
+				},
+			},
+			(&command{
+				name:    "get",
+				args:    "<project>/<repository>",
+				summary: "Show a repository.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						ref, err := reference.Parse(args[0])
+						if err != nil {
+							return err
+						}
+
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						repo, err := c.GetRepositoryByReference(ctx, ref)
+						if err != nil {
+							return err
+						}
+
+						return e.print(repo, repositoryTable(repo))
+					}
+				},
+			}).exactArgs(1),
+			(&command{
+				name:    "delete",
+				args:    "<project>/<repository>",
+				summary: "Delete a repository including all of its artifacts.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						ref, err := reference.Parse(args[0])
+						if err != nil {
+							return err
+						}
+
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						if err := c.DeleteRepositoryByReference(ctx, ref); err != nil {
+							return err
+						}
+
+						e.printf("repository %q deleted", ref.Name())
+
+						return nil
+					}
+				},
+			}).exactArgs(1),
+		},
+	}
+}
+
+func repositoryTable(repos ...*model.Repository) *table {
+	t := newTable("NAME", "ARTIFACTS", "PULLS", "UPDATED")
+
+	for _, r := range repos {
+		t.add(r.Name, formatInt(r.ArtifactCount), formatInt(r.PullCount), formatTime(r.UpdateTime))
+	}
+
+	return t
+}
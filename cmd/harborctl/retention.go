@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"sort"
+	"strings"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+func retentionCommand() *command {
+	return &command{
+		name:    "retention",
+		summary: "Manage the tag retention policies of projects.",
+		commands: []*command{
+			(&command{
+				name:    "get",
+				args:    "<project>",
+				summary: "Show the rules of the retention policy of a project.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						p, err := c.GetRetentionPolicyByProject(ctx, args[0])
+						if err != nil {
+							return err
+						}
+
+						return e.print(p, retentionRuleTable(p))
+					}
+				},
+			}).exactArgs(1),
+			(&command{
+				name:    "delete",
+				args:    "<project>",
+				summary: "Delete the retention policy of a project.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						p, err := c.GetRetentionPolicyByProject(ctx, args[0])
+						if err != nil {
+							return err
+						}
+
+						if err := c.DeleteRetentionPolicyByID(ctx, p.ID); err != nil {
+							return err
+						}
+
+						e.printf("retention policy of project %q deleted", args[0])
+
+						return nil
+					}
+				},
+			}).exactArgs(1),
+		},
+	}
+}
+
+func retentionRuleTable(p *model.RetentionPolicy) *table {
+	t := newTable("PRIORITY", "TEMPLATE", "ACTION", "REPOSITORIES", "TAGS", "DISABLED")
+
+	rules := append([]*model.RetentionRule(nil), p.Rules...)
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].Priority < rules[j].Priority })
+
+	for _, r := range rules {
+		var repositories, tags []string
+
+		for _, s := range r.ScopeSelectors["repository"] {
+			repositories = append(repositories, s.Decoration+" "+s.Pattern)
+		}
+
+		for _, s := range r.TagSelectors {
+			tags = append(tags, s.Decoration+" "+s.Pattern)
+		}
+
+		t.add(formatInt(r.Priority), r.Template, r.Action, strings.Join(repositories, ","), strings.Join(tags, ","), formatBool(r.Disabled))
+	}
+
+	return t
+}
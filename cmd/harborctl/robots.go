@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/clients/robot"
+)
+
+func robotsCommand() *command {
+	return &command{
+		name:    "robots",
+		aliases: []string{"robot"},
+		summary: "Manage robot accounts. Robots are named without the robot$ prefix.",
+		commands: []*command{
+			{
+				name:    "list",
+				summary: "List robot accounts.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, _ []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						robots, err := c.ListRobotAccounts(ctx)
+						if err != nil {
+							return err
+						}
+
+						return e.print(robots, robotTable(robots...))
+					}
+				},
+			},
+			(&command{
+				name:    "get",
+				args:    "<name>",
+				summary: "Show a robot account.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						r, err := c.GetRobotAccountByName(ctx, args[0])
+						if err != nil {
+							return err
+						}
+
+						return e.print(r, robotTable(r))
+					}
+				},
+			}).exactArgs(1),
+			(&command{
+				name:    "create",
+				args:    "<name>",
+				summary: "Create a robot account and print its secret, which cannot be retrieved later.",
+				setup: func(fs *flag.FlagSet) runFunc {
+					project := fs.String("project", "*", "`project` the robot may access, * for all projects")
+					access := fs.String("access", "repository:pull", "comma separated `resource:action` pairs granted to the robot")
+					days := fs.Int64("duration", -1, "lifetime in `days`, -1 for no expiration")
+					description := fs.String("description", "", "`description` of the robot")
+
+					return func(ctx context.Context, e *env, args []string) error {
+						a, err := parseAccess(*access)
+						if err != nil {
+							return err
+						}
+
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						created, err := c.NewRobotAccount(ctx, &model.RobotCreate{
+							Name:        args[0],
+							Description: *description,
+							Duration:    *days,
+							Level:       string(robot.LevelSystem),
+							Permissions: []*model.RobotPermission{{
+								Kind:      "project",
+								Namespace: *project,
+								Access:    a,
+							}},
+						})
+						if err != nil {
+							return err
+						}
+
+						t := newTable("NAME", "SECRET", "EXPIRES")
+						t.add(created.Name, created.Secret, formatExpiry(created.ExpiresAt))
+
+						return e.print(created, t)
+					}
+				},
+			}).exactArgs(1),
+			(&command{
+				name:    "delete",
+				args:    "<name>",
+				summary: "Delete a robot account.",
+				setup: func(*flag.FlagSet) runFunc {
+					return func(ctx context.Context, e *env, args []string) error {
+						c, err := e.Client()
+						if err != nil {
+							return err
+						}
+
+						if err := c.DeleteRobotAccountByName(ctx, args[0]); err != nil {
+							return err
+						}
+
+						e.printf("robot %q deleted", args[0])
+
+						return nil
+					}
+				},
+			}).exactArgs(1),
+		},
+	}
+}
+
+// parseAccess parses comma separated resource:action pairs.
+func parseAccess(s string) ([]*model.Access, error) {
+	var access []*model.Access
+
+	for _, pair := range strings.Split(s, ",") {
+		resource, action, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || resource == "" || action == "" {
+			return nil, fmt.Errorf("invalid access %q, expected resource:action", pair)
+		}
+
+		access = append(access, &model.Access{Resource: resource, Action: action})
+	}
+
+	return access, nil
+}
+
+// formatExpiry formats the expiry of a robot given in seconds since the epoch, -1 meaning never.
+func formatExpiry(expiresAt int64) string {
+	if expiresAt <= 0 {
+		return "never"
+	}
+
+	return time.Unix(expiresAt, 0).UTC().Format(time.RFC3339)
+}
+
+func robotTable(robots ...*model.Robot) *table {
+	t := newTable("NAME", "LEVEL", "DISABLED", "EXPIRES", "DESCRIPTION")
+
+	for _, r := range robots {
+		t.add(r.Name, r.Level, formatBool(r.Disable), formatExpiry(r.ExpiresAt), r.Description)
+	}
+
+	return t
+}
@@ -0,0 +1,197 @@
+// Package harbor provides a facade over the Harbor 1.x (apiv1) and 2.x (apiv2) clients,
+// so that tooling operating both Harbor generations can share a single code path.
+//
+// The facade covers users, projects, registries, replication policies and the GC schedule, i.e. the
+// resources both API versions support. It uses its own models, which are converted from and to the
+// models of the respective API version. Missing resources are reported as ErrNotFound by both backends.
+package harbor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/mittwald/goharbor-client/v5/apiv1"
+	"github.com/mittwald/goharbor-client/v5/apiv2"
+)
+
+// APIVersion is the major version of the Harbor API a Client talks to.
+type APIVersion int
+
+const (
+	// APIVersion1 is the API of Harbor 1.x, served at '/api'.
+	APIVersion1 APIVersion = 1
+	// APIVersion2 is the API of Harbor 2.x, served at '/api/v2.0'.
+	APIVersion2 APIVersion = 2
+)
+
+func (v APIVersion) String() string {
+	return fmt.Sprintf("v%d", int(v))
+}
+
+// Client is the version-agnostic Harbor client implemented by the adapters returned by NewV1 and NewV2.
+type Client interface {
+	// APIVersion returns the API version of the backend.
+	APIVersion() APIVersion
+
+	// GetUser returns the user named 'username'.
+	GetUser(ctx context.Context, username string) (*User, error)
+	// NewUser creates the user 'u' with the initial password 'password' and returns it as stored by Harbor.
+	// The ID and Admin fields of 'u' are ignored.
+	NewUser(ctx context.Context, u *User, password string) (*User, error)
+	// DeleteUser deletes the user named 'username'.
+	DeleteUser(ctx context.Context, username string) error
+
+	// GetProject returns the project named 'name'.
+	GetProject(ctx context.Context, name string) (*Project, error)
+	// ListProjects returns all projects whose name contains 'nameFilter', or all projects if it is empty.
+	ListProjects(ctx context.Context, nameFilter string) ([]*Project, error)
+	// NewProject creates the project 'p' and returns it as stored by Harbor.
+	// The ID, OwnerName and RepoCount fields of 'p' are ignored.
+	NewProject(ctx context.Context, p *Project) (*Project, error)
+	// DeleteProject deletes the project named 'name'.
+	DeleteProject(ctx context.Context, name string) error
+
+	// GetRegistry returns the registry named 'name'.
+	GetRegistry(ctx context.Context, name string) (*Registry, error)
+	// NewRegistry creates the registry 'r' and returns it as stored by Harbor.
+	NewRegistry(ctx context.Context, r *Registry) (*Registry, error)
+	// DeleteRegistry deletes the registry named 'name'.
+	DeleteRegistry(ctx context.Context, name string) error
+
+	// GetReplicationPolicy returns the replication policy named 'name'.
+	GetReplicationPolicy(ctx context.Context, name string) (*ReplicationPolicy, error)
+	// NewReplicationPolicy creates the replication policy 'p' and returns it as stored by Harbor.
+	// Registries of 'p' are referenced by their ID.
+	NewReplicationPolicy(ctx context.Context, p *ReplicationPolicy) (*ReplicationPolicy, error)
+	// DeleteReplicationPolicy deletes the replication policy named 'name'.
+	DeleteReplicationPolicy(ctx context.Context, name string) error
+	// TriggerReplication starts an execution of the replication policy named 'name'.
+	TriggerReplication(ctx context.Context, name string) error
+
+	// GetGCSchedule returns the schedule of the garbage collection.
+	// It returns an ErrNotFound if no schedule has been defined yet.
+	GetGCSchedule(ctx context.Context) (*Schedule, error)
+	// UpdateGCSchedule sets the schedule of the garbage collection, defining it if necessary.
+	UpdateGCSchedule(ctx context.Context, s *Schedule) error
+}
+
+// NewClient returns a Client for the Harbor instance at 'u', e.g. 'https://harbor.example.com/api',
+// authenticating using basic auth. The API version is determined using DetectAPIVersion.
+// Requests are sent using 'client', or http.DefaultClient if nil.
+func NewClient(ctx context.Context, u, username, password string, client *http.Client) (Client, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	u = apiURL(u)
+
+	version, err := DetectAPIVersion(ctx, u, client)
+	if err != nil {
+		return nil, err
+	}
+
+	switch version {
+	case APIVersion1:
+		c, err := apiv1.NewRESTClientForHostWithClient(u, username, password, client)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewV1(c), nil
+	default:
+		c, err := apiv2.New(u, apiv2.WithBasicAuth(username, password), apiv2.WithHTTPClient(client))
+		if err != nil {
+			return nil, err
+		}
+
+		return NewV2(c), nil
+	}
+}
+
+// apiURL returns the URL of the API root, without trailing slash and v2 suffix, e.g. 'https://harbor.example.com/api'.
+// '/api' is appended if 'u' has no path.
+func apiURL(u string) string {
+	u = strings.TrimSuffix(u, "/")
+	u = strings.TrimSuffix(u, "/v2.0")
+
+	if !strings.HasSuffix(u, "/api") {
+		u += "/api"
+	}
+
+	return u
+}
+
+// DetectAPIVersion probes the Harbor instance at 'u', e.g. 'https://harbor.example.com/api', for its API version.
+// It requests the system information, which does not require authentication, from the v2 API first,
+// and falls back to the v1 API if it does not exist.
+func DetectAPIVersion(ctx context.Context, u string, client *http.Client) (APIVersion, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	u = apiURL(u)
+
+	found, version, err := probeSystemInfo(ctx, client, u+"/v2.0/systeminfo")
+	if err != nil {
+		return 0, err
+	}
+
+	if found {
+		return APIVersion2, nil
+	}
+
+	found, version, err = probeSystemInfo(ctx, client, u+"/systeminfo")
+	if err != nil {
+		return 0, err
+	}
+
+	if !found {
+		return 0, fmt.Errorf("detecting Harbor API version of %s: no system information found", u)
+	}
+
+	if strings.HasPrefix(strings.TrimPrefix(version, "v"), "1.") {
+		return APIVersion1, nil
+	}
+
+	return 0, fmt.Errorf("detecting Harbor API version of %s: unsupported Harbor version %q", u, version)
+}
+
+// probeSystemInfo requests the system information at 'u', returning whether it exists
+// and the reported Harbor version.
+func probeSystemInfo(ctx context.Context, client *http.Client, u string) (bool, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return false, "", err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return false, "", nil
+	default:
+		return false, "", fmt.Errorf("requesting %s: unexpected status %d", u, resp.StatusCode)
+	}
+
+	var info struct {
+		HarborVersion string `json:"harbor_version"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return false, "", fmt.Errorf("decoding system information: %w", err)
+	}
+
+	return true, info.HarborVersion, nil
+}
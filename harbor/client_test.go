@@ -0,0 +1,241 @@
+//go:build !integration
+
+package harbor_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	modelv1 "github.com/mittwald/goharbor-client/v5/apiv1/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/testing/fakeharbor"
+	"github.com/mittwald/goharbor-client/v5/harbor"
+)
+
+// v1Server imitates the parts of the Harbor 1.10 API used for projects.
+type v1Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	projects map[int32]*modelv1.Project
+	nextID   int32
+}
+
+func newV1Server(t *testing.T) *v1Server {
+	t.Helper()
+
+	s := &v1Server{projects: map[int32]*modelv1.Project{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/systeminfo", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"harbor_version": "v1.10.2-e2d3ffb9"})
+	})
+	mux.HandleFunc("/api/users", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Total-Count", "0")
+		writeJSON(w, http.StatusOK, []*modelv1.User{})
+	})
+	mux.HandleFunc("/api/projects", s.handleProjects)
+	mux.HandleFunc("/api/projects/", s.handleProject)
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (s *v1Server) handleProjects(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		res := []*modelv1.Project{}
+		for _, p := range s.projects {
+			if strings.Contains(p.Name, r.URL.Query().Get("name")) {
+				res = append(res, p)
+			}
+		}
+
+		w.Header().Set("X-Total-Count", strconv.Itoa(len(res)))
+		writeJSON(w, http.StatusOK, res)
+	case http.MethodPost:
+		var req modelv1.ProjectReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		s.nextID++
+		s.projects[s.nextID] = &modelv1.Project{
+			ProjectID: s.nextID,
+			Name:      req.ProjectName,
+			OwnerName: "admin",
+			Metadata:  &modelv1.ProjectMetadata{Public: "false"},
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+// handleProject serves '/api/projects/{id}' and '/api/projects/{id}/metadatas'.
+func (s *v1Server) handleProject(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/projects/"), "/")
+
+	id, err := strconv.Atoi(parts[0])
+	if err != nil || s.projects[int32(id)] == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		delete(s.projects, int32(id))
+		w.WriteHeader(http.StatusOK)
+	case len(parts) == 2 && parts[1] == "metadatas" && r.Method == http.MethodPost:
+		var metadata modelv1.ProjectMetadata
+		if err := json.NewDecoder(r.Body).Decode(&metadata); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		s.projects[int32(id)].Metadata.Public = metadata.Public
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestDetectAPIVersion(t *testing.T) {
+	ctx := context.Background()
+
+	fake := fakeharbor.NewServer()
+	defer fake.Close()
+
+	v, err := harbor.DetectAPIVersion(ctx, fake.URL, nil)
+	require.NoError(t, err)
+	require.Equal(t, harbor.APIVersion2, v)
+
+	v, err = harbor.DetectAPIVersion(ctx, fake.Host(), fake.Client())
+	require.NoError(t, err)
+	require.Equal(t, harbor.APIVersion2, v)
+
+	v1 := newV1Server(t)
+
+	v, err = harbor.DetectAPIVersion(ctx, v1.URL+"/api/", nil)
+	require.NoError(t, err)
+	require.Equal(t, harbor.APIVersion1, v)
+
+	unknown := httptest.NewServer(http.NotFoundHandler())
+	defer unknown.Close()
+
+	_, err = harbor.DetectAPIVersion(ctx, unknown.URL, nil)
+	require.ErrorContains(t, err, "no system information found")
+}
+
+// testProjects runs the same code path against both backends.
+func testProjects(t *testing.T, c harbor.Client) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	p, err := c.NewProject(ctx, &harbor.Project{Name: "facade", Public: true, StorageLimit: -1})
+	require.NoError(t, err)
+	require.Equal(t, "facade", p.Name)
+	require.True(t, p.Public)
+	require.NotZero(t, p.ID)
+
+	projects, err := c.ListProjects(ctx, "faca")
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	require.Equal(t, p, projects[0])
+
+	require.NoError(t, c.DeleteProject(ctx, "facade"))
+
+	_, err = c.GetProject(ctx, "facade")
+	require.ErrorIs(t, err, &harbor.ErrNotFound{})
+
+	require.ErrorIs(t, c.DeleteProject(ctx, "facade"), &harbor.ErrNotFound{})
+
+	_, err = c.GetUser(ctx, "nobody")
+	require.ErrorIs(t, err, &harbor.ErrNotFound{})
+}
+
+func TestClient_V1(t *testing.T) {
+	srv := newV1Server(t)
+
+	c, err := harbor.NewClient(context.Background(), srv.URL, "admin", "Harbor12345", nil)
+	require.NoError(t, err)
+	require.Equal(t, harbor.APIVersion1, c.APIVersion())
+
+	testProjects(t, c)
+}
+
+func TestClient_V2(t *testing.T) {
+	fake := fakeharbor.NewServer()
+	defer fake.Close()
+
+	c, err := harbor.NewClient(context.Background(), fake.URL, fakeharbor.AdminUser, fakeharbor.AdminPassword, nil)
+	require.NoError(t, err)
+	require.Equal(t, harbor.APIVersion2, c.APIVersion())
+
+	testProjects(t, c)
+}
+
+func TestClient_V2_Replication(t *testing.T) {
+	ctx := context.Background()
+
+	fake := fakeharbor.NewServer()
+	defer fake.Close()
+
+	c, err := harbor.NewClient(ctx, fake.URL, fakeharbor.AdminUser, fakeharbor.AdminPassword, nil)
+	require.NoError(t, err)
+
+	reg, err := c.NewRegistry(ctx, &harbor.Registry{
+		Name:       "upstream",
+		Type:       "docker-hub",
+		URL:        "https://hub.docker.com",
+		Credential: &harbor.RegistryCredential{Type: "basic", AccessKey: "user", AccessSecret: "secret"},
+	})
+	require.NoError(t, err)
+	require.NotZero(t, reg.ID)
+	require.Equal(t, "docker-hub", reg.Type)
+
+	policy, err := c.NewReplicationPolicy(ctx, &harbor.ReplicationPolicy{
+		Name:          "mirror",
+		SrcRegistry:   reg,
+		DestNamespace: "library",
+		Enabled:       true,
+		Filters:       []harbor.ReplicationFilter{{Type: "name", Value: "library/**"}},
+		Trigger:       &harbor.ReplicationTrigger{Type: "scheduled", Cron: "0 0 * * * *"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, reg.ID, policy.SrcRegistry.ID)
+	require.Nil(t, policy.DestRegistry)
+	require.Equal(t, []harbor.ReplicationFilter{{Type: "name", Value: "library/**"}}, policy.Filters)
+	require.Equal(t, &harbor.ReplicationTrigger{Type: "scheduled", Cron: "0 0 * * * *"}, policy.Trigger)
+
+	require.NoError(t, c.DeleteReplicationPolicy(ctx, "mirror"))
+	require.NoError(t, c.DeleteRegistry(ctx, "upstream"))
+
+	_, err = c.GetRegistry(ctx, "upstream")
+	require.ErrorIs(t, err, &harbor.ErrNotFound{})
+
+	_, err = c.GetReplicationPolicy(ctx, "mirror")
+	require.ErrorIs(t, err, &harbor.ErrNotFound{})
+}
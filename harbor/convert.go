@@ -0,0 +1,321 @@
+package harbor
+
+import (
+	"fmt"
+
+	modelv1 "github.com/mittwald/goharbor-client/v5/apiv1/model"
+	modelv2 "github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+// UserFromV1 converts a Harbor 1.x user. It returns nil if 'u' is nil.
+func UserFromV1(u *modelv1.User) *User {
+	if u == nil {
+		return nil
+	}
+
+	return &User{
+		ID:       u.UserID,
+		Username: u.Username,
+		Email:    u.Email,
+		Realname: u.Realname,
+		Comment:  u.Comment,
+		Admin:    u.HasAdminRole,
+	}
+}
+
+// UserFromV2 converts a Harbor 2.x user. It returns nil if 'u' is nil.
+func UserFromV2(u *modelv2.UserResp) *User {
+	if u == nil {
+		return nil
+	}
+
+	return &User{
+		ID:       u.UserID,
+		Username: u.Username,
+		Email:    u.Email,
+		Realname: u.Realname,
+		Comment:  u.Comment,
+		Admin:    u.SysadminFlag,
+	}
+}
+
+// ProjectFromV1 converts a Harbor 1.x project. It returns nil if 'p' is nil.
+func ProjectFromV1(p *modelv1.Project) *Project {
+	if p == nil {
+		return nil
+	}
+
+	return &Project{
+		ID:        int64(p.ProjectID),
+		Name:      p.Name,
+		Public:    p.Metadata != nil && p.Metadata.Public == "true",
+		OwnerName: p.OwnerName,
+		RepoCount: p.RepoCount,
+	}
+}
+
+// ProjectFromV2 converts a Harbor 2.x project. It returns nil if 'p' is nil.
+func ProjectFromV2(p *modelv2.Project) *Project {
+	if p == nil {
+		return nil
+	}
+
+	return &Project{
+		ID:        int64(p.ProjectID),
+		Name:      p.Name,
+		Public:    p.Metadata != nil && p.Metadata.Public == "true",
+		OwnerName: p.OwnerName,
+		RepoCount: p.RepoCount,
+	}
+}
+
+// RegistryFromV1 converts a Harbor 1.x registry. It returns nil if 'r' is nil.
+func RegistryFromV1(r *modelv1.Registry) *Registry {
+	if r == nil {
+		return nil
+	}
+
+	reg := &Registry{
+		ID:          r.ID,
+		Name:        r.Name,
+		Type:        r.Type,
+		URL:         r.URL,
+		Description: r.Description,
+		Insecure:    r.Insecure,
+		Status:      r.Status,
+	}
+
+	if r.Credential != nil {
+		reg.Credential = &RegistryCredential{
+			Type:         r.Credential.Type,
+			AccessKey:    r.Credential.AccessKey,
+			AccessSecret: r.Credential.AccessSecret,
+		}
+	}
+
+	return reg
+}
+
+// RegistryFromV2 converts a Harbor 2.x registry. It returns nil if 'r' is nil.
+func RegistryFromV2(r *modelv2.Registry) *Registry {
+	if r == nil {
+		return nil
+	}
+
+	reg := &Registry{
+		ID:          r.ID,
+		Name:        r.Name,
+		Type:        r.Type,
+		URL:         r.URL,
+		Description: r.Description,
+		Insecure:    r.Insecure,
+		Status:      r.Status,
+	}
+
+	if r.Credential != nil {
+		reg.Credential = &RegistryCredential{
+			Type:         r.Credential.Type,
+			AccessKey:    r.Credential.AccessKey,
+			AccessSecret: r.Credential.AccessSecret,
+		}
+	}
+
+	return reg
+}
+
+// RegistryToV1 converts 'r' to a Harbor 1.x registry. It returns nil if 'r' is nil.
+func RegistryToV1(r *Registry) *modelv1.Registry {
+	if r == nil {
+		return nil
+	}
+
+	reg := &modelv1.Registry{
+		ID:          r.ID,
+		Name:        r.Name,
+		Type:        r.Type,
+		URL:         r.URL,
+		Description: r.Description,
+		Insecure:    r.Insecure,
+		Status:      r.Status,
+	}
+
+	if r.Credential != nil {
+		reg.Credential = &modelv1.RegistryCredential{
+			Type:         r.Credential.Type,
+			AccessKey:    r.Credential.AccessKey,
+			AccessSecret: r.Credential.AccessSecret,
+		}
+	}
+
+	return reg
+}
+
+// RegistryToV2 converts 'r' to a Harbor 2.x registry. It returns nil if 'r' is nil.
+func RegistryToV2(r *Registry) *modelv2.Registry {
+	if r == nil {
+		return nil
+	}
+
+	reg := &modelv2.Registry{
+		ID:          r.ID,
+		Name:        r.Name,
+		Type:        r.Type,
+		URL:         r.URL,
+		Description: r.Description,
+		Insecure:    r.Insecure,
+		Status:      r.Status,
+	}
+
+	if r.Credential != nil {
+		reg.Credential = &modelv2.RegistryCredential{
+			Type:         r.Credential.Type,
+			AccessKey:    r.Credential.AccessKey,
+			AccessSecret: r.Credential.AccessSecret,
+		}
+	}
+
+	return reg
+}
+
+// ReplicationPolicyFromV1 converts a Harbor 1.x replication policy. It returns nil if 'p' is nil.
+func ReplicationPolicyFromV1(p *modelv1.ReplicationPolicy) *ReplicationPolicy {
+	if p == nil {
+		return nil
+	}
+
+	policy := &ReplicationPolicy{
+		ID:                p.ID,
+		Name:              p.Name,
+		Description:       p.Description,
+		SrcRegistry:       localRegistry(RegistryFromV1(p.SrcRegistry)),
+		DestRegistry:      localRegistry(RegistryFromV1(p.DestRegistry)),
+		DestNamespace:     p.DestNamespace,
+		ReplicateDeletion: p.Deletion,
+		Override:          p.Override,
+		Enabled:           p.Enabled,
+	}
+
+	for _, f := range p.Filters {
+		if f != nil {
+			policy.Filters = append(policy.Filters, ReplicationFilter{Type: f.Type, Value: f.Value})
+		}
+	}
+
+	if p.Trigger != nil {
+		policy.Trigger = &ReplicationTrigger{Type: p.Trigger.Type}
+		if p.Trigger.TriggerSettings != nil {
+			policy.Trigger.Cron = p.Trigger.TriggerSettings.Cron
+		}
+	}
+
+	return policy
+}
+
+// ReplicationPolicyFromV2 converts a Harbor 2.x replication policy. It returns nil if 'p' is nil.
+// Filter values which are not strings, e.g. lists of labels, are formatted using fmt.Sprint.
+func ReplicationPolicyFromV2(p *modelv2.ReplicationPolicy) *ReplicationPolicy {
+	if p == nil {
+		return nil
+	}
+
+	policy := &ReplicationPolicy{
+		ID:                p.ID,
+		Name:              p.Name,
+		Description:       p.Description,
+		SrcRegistry:       localRegistry(RegistryFromV2(p.SrcRegistry)),
+		DestRegistry:      localRegistry(RegistryFromV2(p.DestRegistry)),
+		DestNamespace:     p.DestNamespace,
+		ReplicateDeletion: p.ReplicateDeletion || p.Deletion,
+		Override:          p.Override,
+		Enabled:           p.Enabled,
+	}
+
+	for _, f := range p.Filters {
+		if f == nil {
+			continue
+		}
+
+		value, ok := f.Value.(string)
+		if !ok && f.Value != nil {
+			value = fmt.Sprint(f.Value)
+		}
+
+		policy.Filters = append(policy.Filters, ReplicationFilter{Type: f.Type, Value: value})
+	}
+
+	if p.Trigger != nil {
+		policy.Trigger = &ReplicationTrigger{Type: p.Trigger.Type}
+		if p.Trigger.TriggerSettings != nil {
+			policy.Trigger.Cron = p.Trigger.TriggerSettings.Cron
+		}
+	}
+
+	return policy
+}
+
+// localRegistry returns nil if 'r' denotes the local Harbor instance, which has the ID 0.
+func localRegistry(r *Registry) *Registry {
+	if r == nil || r.ID == 0 {
+		return nil
+	}
+
+	return r
+}
+
+func replicationFiltersToV1(filters []ReplicationFilter) []*modelv1.ReplicationFilter {
+	res := make([]*modelv1.ReplicationFilter, 0, len(filters))
+	for _, f := range filters {
+		res = append(res, &modelv1.ReplicationFilter{Type: f.Type, Value: f.Value})
+	}
+
+	return res
+}
+
+func replicationFiltersToV2(filters []ReplicationFilter) []*modelv2.ReplicationFilter {
+	res := make([]*modelv2.ReplicationFilter, 0, len(filters))
+	for _, f := range filters {
+		res = append(res, &modelv2.ReplicationFilter{Type: f.Type, Value: f.Value})
+	}
+
+	return res
+}
+
+func replicationTriggerToV1(t *ReplicationTrigger) *modelv1.ReplicationTrigger {
+	if t == nil {
+		return nil
+	}
+
+	return &modelv1.ReplicationTrigger{
+		Type:            t.Type,
+		TriggerSettings: &modelv1.TriggerSettings{Cron: t.Cron},
+	}
+}
+
+func replicationTriggerToV2(t *ReplicationTrigger) *modelv2.ReplicationTrigger {
+	if t == nil {
+		return nil
+	}
+
+	return &modelv2.ReplicationTrigger{
+		Type:            t.Type,
+		TriggerSettings: &modelv2.ReplicationTriggerSettings{Cron: t.Cron},
+	}
+}
+
+// ScheduleFromV1 converts a Harbor 1.x schedule. It returns nil if 's' is nil.
+func ScheduleFromV1(s *modelv1.AdminJobScheduleObj) *Schedule {
+	if s == nil {
+		return nil
+	}
+
+	return &Schedule{Type: s.Type, Cron: s.Cron}
+}
+
+// ScheduleFromV2 converts a Harbor 2.x schedule. It returns nil if 's' is nil.
+func ScheduleFromV2(s *modelv2.ScheduleObj) *Schedule {
+	if s == nil {
+		return nil
+	}
+
+	return &Schedule{Type: s.Type, Cron: s.Cron}
+}
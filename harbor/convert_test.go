@@ -0,0 +1,79 @@
+//go:build !integration
+
+package harbor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	modelv1 "github.com/mittwald/goharbor-client/v5/apiv1/model"
+	modelv2 "github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+func TestReplicationPolicyConversion(t *testing.T) {
+	want := &ReplicationPolicy{
+		ID:                3,
+		Name:              "mirror",
+		SrcRegistry:       &Registry{ID: 1, Name: "upstream", Type: "docker-hub"},
+		DestNamespace:     "library",
+		ReplicateDeletion: true,
+		Enabled:           true,
+		Filters:           []ReplicationFilter{{Type: "name", Value: "library/**"}},
+		Trigger:           &ReplicationTrigger{Type: "scheduled", Cron: "0 0 * * * *"},
+	}
+
+	v1 := &modelv1.ReplicationPolicy{
+		ID:            3,
+		Name:          "mirror",
+		SrcRegistry:   &modelv1.Registry{ID: 1, Name: "upstream", Type: "docker-hub"},
+		DestRegistry:  &modelv1.Registry{ID: 0, Name: "Local"},
+		DestNamespace: "library",
+		Deletion:      true,
+		Enabled:       true,
+		Filters:       replicationFiltersToV1(want.Filters),
+		Trigger:       replicationTriggerToV1(want.Trigger),
+	}
+	require.Equal(t, want, ReplicationPolicyFromV1(v1))
+
+	v2 := &modelv2.ReplicationPolicy{
+		ID:                3,
+		Name:              "mirror",
+		SrcRegistry:       &modelv2.Registry{ID: 1, Name: "upstream", Type: "docker-hub"},
+		DestNamespace:     "library",
+		ReplicateDeletion: true,
+		Enabled:           true,
+		Filters:           replicationFiltersToV2(want.Filters),
+		Trigger:           replicationTriggerToV2(want.Trigger),
+	}
+	require.Equal(t, want, ReplicationPolicyFromV2(v2))
+
+	v2.Filters = []*modelv2.ReplicationFilter{{Type: "label", Value: []interface{}{"env=prod"}}}
+	require.Equal(t, []ReplicationFilter{{Type: "label", Value: "[env=prod]"}}, ReplicationPolicyFromV2(v2).Filters)
+}
+
+func TestConversion_Nil(t *testing.T) {
+	require.Nil(t, UserFromV1(nil))
+	require.Nil(t, UserFromV2(nil))
+	require.Nil(t, ProjectFromV1(nil))
+	require.Nil(t, ProjectFromV2(nil))
+	require.Nil(t, RegistryFromV1(nil))
+	require.Nil(t, RegistryToV2(nil))
+	require.Nil(t, ReplicationPolicyFromV2(nil))
+	require.Nil(t, ScheduleFromV1(nil))
+}
+
+func TestRegistryConversion(t *testing.T) {
+	r := &Registry{
+		ID:          1,
+		Name:        "upstream",
+		Type:        "harbor",
+		URL:         "https://harbor.example.com",
+		Description: "upstream Harbor",
+		Insecure:    true,
+		Credential:  &RegistryCredential{Type: "basic", AccessKey: "user", AccessSecret: "secret"},
+	}
+
+	require.Equal(t, r, RegistryFromV1(RegistryToV1(r)))
+	require.Equal(t, r, RegistryFromV2(RegistryToV2(r)))
+}
@@ -0,0 +1,67 @@
+package harbor
+
+import (
+	"errors"
+
+	projectv1 "github.com/mittwald/goharbor-client/v5/apiv1/project"
+	registryv1 "github.com/mittwald/goharbor-client/v5/apiv1/registry"
+	replicationv1 "github.com/mittwald/goharbor-client/v5/apiv1/replication"
+	systemv1 "github.com/mittwald/goharbor-client/v5/apiv1/system"
+	userv1 "github.com/mittwald/goharbor-client/v5/apiv1/user"
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+// ErrNotFoundMsg is the error message for ErrNotFound error.
+const ErrNotFoundMsg = "resource not found"
+
+// ErrNotFound describes an error when the requested resource does not exist, regardless of the API version.
+// The error returned by the backend is available via errors.Unwrap, errors.Is and errors.As.
+type ErrNotFound struct {
+	Err error
+}
+
+// Error returns the error message.
+func (e *ErrNotFound) Error() string {
+	if e.Err == nil {
+		return ErrNotFoundMsg
+	}
+
+	return ErrNotFoundMsg + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error returned by the backend.
+func (e *ErrNotFound) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether 'target' is an ErrNotFound.
+func (e *ErrNotFound) Is(target error) bool {
+	_, ok := target.(*ErrNotFound)
+	return ok
+}
+
+// normalizeError wraps the errors both API versions return for missing resources,
+// including an undefined GC schedule, in an ErrNotFound.
+func normalizeError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, &clienterrors.ErrNotFound{}),
+		errors.Is(err, &clienterrors.ErrProjectNotFound{}),
+		errors.Is(err, &clienterrors.ErrUserNotFound{}),
+		errors.Is(err, &clienterrors.ErrRegistryNotFound{}),
+		errors.Is(err, &clienterrors.ErrSystemGcScheduleUndefined{}),
+		errors.As(err, new(*projectv1.ErrProjectNotFound)),
+		errors.As(err, new(*userv1.ErrUserNotFound)),
+		errors.As(err, new(*registryv1.ErrRegistryNotFound)),
+		errors.As(err, new(*replicationv1.ErrReplicationNotFound)),
+		errors.As(err, new(*systemv1.ErrSystemGcUndefined)):
+		return &ErrNotFound{Err: err}
+	default:
+		return err
+	}
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, &ErrNotFound{})
+}
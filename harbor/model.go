@@ -0,0 +1,89 @@
+package harbor
+
+// User is a Harbor user.
+type User struct {
+	ID       int64
+	Username string
+	Email    string
+	Realname string
+	Comment  string
+	// Admin is true if the user has the system admin role.
+	Admin bool
+}
+
+// Project is a Harbor project.
+type Project struct {
+	ID        int64
+	Name      string
+	Public    bool
+	OwnerName string
+	RepoCount int64
+	// StorageLimit is the storage quota of the project in bytes, -1 for no limit and 0 for the default quota
+	// of the Harbor instance. It is only used to create projects, as it is not part of the project resource.
+	// Harbor 1.x only supports limits in MiB and uses the default quota instead of -1.
+	StorageLimit int64
+}
+
+// RegistryCredential is the credential used to access a registry.
+type RegistryCredential struct {
+	// Type is the credential type, e.g. 'basic' or 'oauth'.
+	Type         string
+	AccessKey    string
+	AccessSecret string
+}
+
+// Registry is a registry endpoint used for replication.
+type Registry struct {
+	// ID of the registry. The ID 0 denotes the local Harbor instance.
+	ID   int64
+	Name string
+	// Type is the registry type, e.g. 'harbor' or 'docker-hub'.
+	Type        string
+	URL         string
+	Description string
+	Insecure    bool
+	Credential  *RegistryCredential
+	// Status is the health of the registry as last observed by Harbor, e.g. 'healthy'.
+	Status string
+}
+
+// ReplicationFilter selects the resources replicated by a ReplicationPolicy.
+type ReplicationFilter struct {
+	// Type is the filter type, e.g. 'name', 'tag' or 'resource'.
+	Type string
+	// Value is the filter value, e.g. a doublestar pattern.
+	Value string
+}
+
+// ReplicationTrigger defines when a ReplicationPolicy is executed.
+type ReplicationTrigger struct {
+	// Type is the trigger type, e.g. 'manual', 'scheduled' or 'event_based'.
+	Type string
+	// Cron is the schedule of scheduled triggers.
+	Cron string
+}
+
+// ReplicationPolicy replicates resources between the local Harbor instance and a registry.
+type ReplicationPolicy struct {
+	ID          int64
+	Name        string
+	Description string
+	// SrcRegistry is the registry resources are pulled from, nil for the local Harbor instance.
+	SrcRegistry *Registry
+	// DestRegistry is the registry resources are pushed to, nil for the local Harbor instance.
+	DestRegistry      *Registry
+	DestNamespace     string
+	ReplicateDeletion bool
+	Override          bool
+	Enabled           bool
+	Filters           []ReplicationFilter
+	Trigger           *ReplicationTrigger
+}
+
+// Schedule defines when a job, e.g. garbage collection, is executed.
+type Schedule struct {
+	// Type is the schedule type, e.g. 'None', 'Hourly', 'Daily', 'Weekly', 'Custom' or 'Manual'.
+	Type string
+	// Cron is the schedule of 'Custom' schedules.
+	Cron string
+}
@@ -0,0 +1,225 @@
+package harbor
+
+import (
+	"context"
+
+	"github.com/mittwald/goharbor-client/v5/apiv1"
+	modelv1 "github.com/mittwald/goharbor-client/v5/apiv1/model"
+	projectv1 "github.com/mittwald/goharbor-client/v5/apiv1/project"
+)
+
+const bytesPerMiB = 1024 * 1024
+
+// v1Client adapts an apiv1.RESTClient to the Client interface.
+type v1Client struct {
+	c *apiv1.RESTClient
+}
+
+// NewV1 returns a Client using the Harbor 1.x client 'c'.
+func NewV1(c *apiv1.RESTClient) Client {
+	return &v1Client{c: c}
+}
+
+func (v *v1Client) APIVersion() APIVersion {
+	return APIVersion1
+}
+
+func (v *v1Client) GetUser(ctx context.Context, username string) (*User, error) {
+	u, err := v.c.GetUser(ctx, username)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return UserFromV1(u), nil
+}
+
+func (v *v1Client) NewUser(ctx context.Context, u *User, password string) (*User, error) {
+	created, err := v.c.NewUser(ctx, u.Username, u.Email, u.Realname, password, u.Comment)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return UserFromV1(created), nil
+}
+
+func (v *v1Client) DeleteUser(ctx context.Context, username string) error {
+	u, err := v.c.GetUser(ctx, username)
+	if err != nil {
+		return normalizeError(err)
+	}
+
+	return normalizeError(v.c.DeleteUser(ctx, u))
+}
+
+func (v *v1Client) GetProject(ctx context.Context, name string) (*Project, error) {
+	p, err := v.c.GetProject(ctx, name)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return ProjectFromV1(p), nil
+}
+
+func (v *v1Client) ListProjects(ctx context.Context, nameFilter string) ([]*Project, error) {
+	projects, err := v.c.ListProjects(ctx, nameFilter)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	res := make([]*Project, 0, len(projects))
+	for _, p := range projects {
+		res = append(res, ProjectFromV1(p))
+	}
+
+	return res, nil
+}
+
+// NewProject creates the project using the default repository count limit of the Harbor instance.
+// As the v1 API does not support setting the visibility on creation, public projects are updated afterwards.
+func (v *v1Client) NewProject(ctx context.Context, p *Project) (*Project, error) {
+	// The v1 client expects the storage limit in MiB, a limit of 0 is omitted, so that the default is used.
+	storageLimit := 0
+	if p.StorageLimit > 0 {
+		storageLimit = int(p.StorageLimit / bytesPerMiB)
+	}
+
+	created, err := v.c.NewProject(ctx, p.Name, 0, storageLimit)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	if p.Public {
+		if err := v.c.AddProjectMetadata(ctx, created, projectv1.ProjectMetadataKeyPublic, "true"); err != nil {
+			return nil, normalizeError(err)
+		}
+
+		if created, err = v.c.GetProject(ctx, p.Name); err != nil {
+			return nil, normalizeError(err)
+		}
+	}
+
+	return ProjectFromV1(created), nil
+}
+
+func (v *v1Client) DeleteProject(ctx context.Context, name string) error {
+	p, err := v.c.GetProject(ctx, name)
+	if err != nil {
+		return normalizeError(err)
+	}
+
+	return normalizeError(v.c.DeleteProject(ctx, p))
+}
+
+func (v *v1Client) GetRegistry(ctx context.Context, name string) (*Registry, error) {
+	r, err := v.c.GetRegistry(ctx, name)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return RegistryFromV1(r), nil
+}
+
+// NewRegistry creates the registry. The v1 API does not support setting a description on creation,
+// so it is set by updating the registry afterwards.
+func (v *v1Client) NewRegistry(ctx context.Context, r *Registry) (*Registry, error) {
+	reg := RegistryToV1(r)
+
+	created, err := v.c.NewRegistry(ctx, reg.Name, reg.Type, reg.URL, reg.Credential, reg.Insecure)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	if r.Description != "" {
+		created.Description = r.Description
+
+		if err := v.c.UpdateRegistry(ctx, created); err != nil {
+			return nil, normalizeError(err)
+		}
+	}
+
+	return RegistryFromV1(created), nil
+}
+
+func (v *v1Client) DeleteRegistry(ctx context.Context, name string) error {
+	r, err := v.c.GetRegistry(ctx, name)
+	if err != nil {
+		return normalizeError(err)
+	}
+
+	return normalizeError(v.c.DeleteRegistry(ctx, r))
+}
+
+func (v *v1Client) GetReplicationPolicy(ctx context.Context, name string) (*ReplicationPolicy, error) {
+	p, err := v.c.GetReplicationPolicy(ctx, name)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return ReplicationPolicyFromV1(p), nil
+}
+
+func (v *v1Client) NewReplicationPolicy(ctx context.Context, p *ReplicationPolicy) (*ReplicationPolicy, error) {
+	created, err := v.c.NewReplicationPolicy(ctx,
+		registryRefV1(p.DestRegistry), registryRefV1(p.SrcRegistry),
+		p.ReplicateDeletion, p.Override, p.Enabled,
+		replicationFiltersToV1(p.Filters), replicationTriggerToV1(p.Trigger),
+		p.DestNamespace, p.Description, p.Name)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return ReplicationPolicyFromV1(created), nil
+}
+
+// registryRefV1 returns a reference to the registry 'r', or to the local Harbor instance if nil.
+func registryRefV1(r *Registry) *modelv1.Registry {
+	if r == nil {
+		return &modelv1.Registry{}
+	}
+
+	return &modelv1.Registry{ID: r.ID, Name: r.Name}
+}
+
+func (v *v1Client) DeleteReplicationPolicy(ctx context.Context, name string) error {
+	p, err := v.c.GetReplicationPolicy(ctx, name)
+	if err != nil {
+		return normalizeError(err)
+	}
+
+	return normalizeError(v.c.DeleteReplicationPolicy(ctx, p))
+}
+
+func (v *v1Client) TriggerReplication(ctx context.Context, name string) error {
+	p, err := v.c.GetReplicationPolicy(ctx, name)
+	if err != nil {
+		return normalizeError(err)
+	}
+
+	return normalizeError(v.c.TriggerReplicationExecution(ctx, &modelv1.ReplicationExecution{PolicyID: p.ID}))
+}
+
+func (v *v1Client) GetGCSchedule(ctx context.Context) (*Schedule, error) {
+	s, err := v.c.GetSystemGarbageCollection(ctx)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return ScheduleFromV1(s.Schedule), nil
+}
+
+func (v *v1Client) UpdateGCSchedule(ctx context.Context, s *Schedule) error {
+	if _, err := v.GetGCSchedule(ctx); err != nil {
+		if !isNotFound(err) {
+			return err
+		}
+
+		_, err = v.c.NewSystemGarbageCollection(ctx, s.Cron, s.Type)
+
+		return normalizeError(err)
+	}
+
+	return normalizeError(v.c.UpdateSystemGarbageCollection(ctx, &modelv1.AdminJobScheduleObj{
+		Type: s.Type,
+		Cron: s.Cron,
+	}))
+}
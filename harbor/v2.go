@@ -0,0 +1,204 @@
+package harbor
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/mittwald/goharbor-client/v5/apiv2"
+	modelv2 "github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+// v2Client adapts an apiv2.RESTClient to the Client interface.
+type v2Client struct {
+	c *apiv2.RESTClient
+}
+
+// NewV2 returns a Client using the Harbor 2.x client 'c'.
+func NewV2(c *apiv2.RESTClient) Client {
+	return &v2Client{c: c}
+}
+
+func (v *v2Client) APIVersion() APIVersion {
+	return APIVersion2
+}
+
+func (v *v2Client) GetUser(ctx context.Context, username string) (*User, error) {
+	u, err := v.c.GetUserByName(ctx, username)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return UserFromV2(u), nil
+}
+
+func (v *v2Client) NewUser(ctx context.Context, u *User, password string) (*User, error) {
+	if err := v.c.NewUser(ctx, u.Username, u.Email, u.Realname, password, u.Comment); err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return v.GetUser(ctx, u.Username)
+}
+
+func (v *v2Client) DeleteUser(ctx context.Context, username string) error {
+	u, err := v.c.GetUserByName(ctx, username)
+	if err != nil {
+		return normalizeError(err)
+	}
+
+	return normalizeError(v.c.DeleteUser(ctx, u.UserID))
+}
+
+func (v *v2Client) GetProject(ctx context.Context, name string) (*Project, error) {
+	p, err := v.c.GetProject(ctx, name)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return ProjectFromV2(p), nil
+}
+
+func (v *v2Client) ListProjects(ctx context.Context, nameFilter string) ([]*Project, error) {
+	projects, err := v.c.ListProjects(ctx, nameFilter)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	res := make([]*Project, 0, len(projects))
+	for _, p := range projects {
+		res = append(res, ProjectFromV2(p))
+	}
+
+	return res, nil
+}
+
+func (v *v2Client) NewProject(ctx context.Context, p *Project) (*Project, error) {
+	public := p.Public
+
+	req := &modelv2.ProjectReq{
+		ProjectName: p.Name,
+		Public:      &public,
+	}
+
+	if p.StorageLimit != 0 {
+		storageLimit := p.StorageLimit
+		req.StorageLimit = &storageLimit
+	}
+
+	if err := v.c.NewProject(ctx, req); err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return v.GetProject(ctx, p.Name)
+}
+
+func (v *v2Client) DeleteProject(ctx context.Context, name string) error {
+	p, err := v.c.GetProject(ctx, name)
+	if err != nil {
+		return normalizeError(err)
+	}
+
+	return normalizeError(v.c.DeleteProject(ctx, strconv.Itoa(int(p.ProjectID))))
+}
+
+func (v *v2Client) GetRegistry(ctx context.Context, name string) (*Registry, error) {
+	r, err := v.c.GetRegistryByName(ctx, name)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return RegistryFromV2(r), nil
+}
+
+func (v *v2Client) NewRegistry(ctx context.Context, r *Registry) (*Registry, error) {
+	reg := RegistryToV2(r)
+	reg.ID = 0
+
+	if err := v.c.NewRegistry(ctx, reg); err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return v.GetRegistry(ctx, r.Name)
+}
+
+func (v *v2Client) DeleteRegistry(ctx context.Context, name string) error {
+	r, err := v.c.GetRegistryByName(ctx, name)
+	if err != nil {
+		return normalizeError(err)
+	}
+
+	return normalizeError(v.c.DeleteRegistryByID(ctx, r.ID))
+}
+
+func (v *v2Client) GetReplicationPolicy(ctx context.Context, name string) (*ReplicationPolicy, error) {
+	p, err := v.c.GetReplicationPolicyByName(ctx, name)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return ReplicationPolicyFromV2(p), nil
+}
+
+func (v *v2Client) NewReplicationPolicy(ctx context.Context, p *ReplicationPolicy) (*ReplicationPolicy, error) {
+	err := v.c.NewReplicationPolicy(ctx,
+		registryRefV2(p.DestRegistry), registryRefV2(p.SrcRegistry),
+		p.ReplicateDeletion, p.Override, p.Enabled,
+		replicationFiltersToV2(p.Filters), replicationTriggerToV2(p.Trigger),
+		p.DestNamespace, p.Description, p.Name)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return v.GetReplicationPolicy(ctx, p.Name)
+}
+
+// registryRefV2 returns a reference to the registry 'r', or nil for the local Harbor instance.
+func registryRefV2(r *Registry) *modelv2.Registry {
+	if r == nil {
+		return nil
+	}
+
+	return &modelv2.Registry{ID: r.ID, Name: r.Name}
+}
+
+func (v *v2Client) DeleteReplicationPolicy(ctx context.Context, name string) error {
+	p, err := v.c.GetReplicationPolicyByName(ctx, name)
+	if err != nil {
+		return normalizeError(err)
+	}
+
+	return normalizeError(v.c.DeleteReplicationPolicyByID(ctx, p.ID))
+}
+
+func (v *v2Client) TriggerReplication(ctx context.Context, name string) error {
+	p, err := v.c.GetReplicationPolicyByName(ctx, name)
+	if err != nil {
+		return normalizeError(err)
+	}
+
+	return normalizeError(v.c.TriggerReplicationExecution(ctx, &modelv2.StartReplicationExecution{PolicyID: p.ID}))
+}
+
+func (v *v2Client) GetGCSchedule(ctx context.Context) (*Schedule, error) {
+	s, err := v.c.GetGarbageCollectionSchedule(ctx)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return ScheduleFromV2(s.Schedule), nil
+}
+
+func (v *v2Client) UpdateGCSchedule(ctx context.Context, s *Schedule) error {
+	schedule := &modelv2.Schedule{
+		Schedule: &modelv2.ScheduleObj{Type: s.Type, Cron: s.Cron},
+	}
+
+	if _, err := v.GetGCSchedule(ctx); err != nil {
+		if !isNotFound(err) {
+			return err
+		}
+
+		return normalizeError(v.c.NewGarbageCollection(ctx, schedule))
+	}
+
+	return normalizeError(v.c.UpdateGarbageCollection(ctx, schedule))
+}
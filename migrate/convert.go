@@ -0,0 +1,226 @@
+package migrate
+
+import (
+	"strings"
+	"time"
+
+	modelv1 "github.com/mittwald/goharbor-client/v5/apiv1/model"
+	modelv2 "github.com/mittwald/goharbor-client/v5/apiv2/model"
+)
+
+const (
+	// robotPrefix is the prefix Harbor adds to the names of robot accounts.
+	robotPrefix = "robot$"
+
+	// maskedSecret is returned by Harbor 1.x in place of registry access secrets.
+	maskedSecret = "*****"
+)
+
+// DefaultRobotAccess is the access granted to migrated robot accounts if none is configured.
+// Harbor 1.x does not return the access list of robot accounts, so it cannot be migrated.
+var DefaultRobotAccess = []*modelv2.Access{
+	{Resource: "repository", Action: "pull"},
+}
+
+// ProjectReqToV2 converts the Harbor 1.x project 'p' to a request creating it in Harbor 2.x.
+// The storage limit is not part of the project and is left to the default of the target instance.
+func ProjectReqToV2(p *modelv1.Project) *modelv2.ProjectReq {
+	if p == nil {
+		return nil
+	}
+
+	req := &modelv2.ProjectReq{
+		ProjectName:  p.Name,
+		Metadata:     ProjectMetadataToV2(p.Metadata),
+		CVEAllowlist: CVEAllowlistToV2(p.CveWhitelist),
+	}
+
+	if req.Metadata != nil {
+		public := req.Metadata.Public == "true"
+		req.Public = &public
+	}
+
+	return req
+}
+
+// ProjectMetadataToV2 converts Harbor 1.x project metadata. Unset values are omitted.
+// The CVE whitelist of Harbor 1.x is called CVE allowlist in Harbor 2.x.
+func ProjectMetadataToV2(m *modelv1.ProjectMetadata) *modelv2.ProjectMetadata {
+	if m == nil {
+		return nil
+	}
+
+	return &modelv2.ProjectMetadata{
+		Public:               m.Public,
+		AutoScan:             optionalString(m.AutoScan),
+		EnableContentTrust:   optionalString(m.EnableContentTrust),
+		PreventVul:           optionalString(m.PreventVul),
+		Severity:             optionalString(m.Severity),
+		ReuseSysCVEAllowlist: optionalString(m.ReuseSysCveWhitelist),
+	}
+}
+
+// CVEAllowlistToV2 converts a Harbor 1.x CVE whitelist. It returns nil if 'w' is nil.
+func CVEAllowlistToV2(w *modelv1.CVEWhitelist) *modelv2.CVEAllowlist {
+	if w == nil {
+		return nil
+	}
+
+	allowlist := &modelv2.CVEAllowlist{
+		Items: make([]*modelv2.CVEAllowlistItem, 0, len(w.Items)),
+	}
+
+	if w.ExpiresAt != 0 {
+		expiresAt := w.ExpiresAt
+		allowlist.ExpiresAt = &expiresAt
+	}
+
+	for _, item := range w.Items {
+		if item != nil {
+			allowlist.Items = append(allowlist.Items, &modelv2.CVEAllowlistItem{CVEID: item.CveID})
+		}
+	}
+
+	return allowlist
+}
+
+// RegistryToV2 converts a Harbor 1.x registry. It returns nil if 'r' is nil.
+// The ID is reset, as it is assigned by the target instance.
+// Access secrets masked by Harbor 1.x are cleared.
+func RegistryToV2(r *modelv1.Registry) *modelv2.Registry {
+	if r == nil {
+		return nil
+	}
+
+	reg := &modelv2.Registry{
+		Name:        r.Name,
+		Type:        r.Type,
+		URL:         r.URL,
+		Description: r.Description,
+		Insecure:    r.Insecure,
+	}
+
+	if r.Credential != nil {
+		reg.Credential = &modelv2.RegistryCredential{
+			Type:      r.Credential.Type,
+			AccessKey: r.Credential.AccessKey,
+		}
+
+		if r.Credential.AccessSecret != maskedSecret {
+			reg.Credential.AccessSecret = r.Credential.AccessSecret
+		}
+	}
+
+	return reg
+}
+
+// ReplicationPolicyToV2 converts a Harbor 1.x replication policy. It returns nil if 'p' is nil.
+// The registries are referenced by name only, as their IDs differ between the instances;
+// the local Harbor instance, which has the ID 0, is referenced by nil.
+func ReplicationPolicyToV2(p *modelv1.ReplicationPolicy) *modelv2.ReplicationPolicy {
+	if p == nil {
+		return nil
+	}
+
+	policy := &modelv2.ReplicationPolicy{
+		Name:              p.Name,
+		Description:       p.Description,
+		SrcRegistry:       registryRefToV2(p.SrcRegistry),
+		DestRegistry:      registryRefToV2(p.DestRegistry),
+		DestNamespace:     p.DestNamespace,
+		ReplicateDeletion: p.Deletion,
+		Override:          p.Override,
+		Enabled:           p.Enabled,
+		Filters:           make([]*modelv2.ReplicationFilter, 0, len(p.Filters)),
+	}
+
+	for _, f := range p.Filters {
+		if f != nil {
+			policy.Filters = append(policy.Filters, &modelv2.ReplicationFilter{Type: f.Type, Value: f.Value})
+		}
+	}
+
+	if p.Trigger != nil {
+		policy.Trigger = &modelv2.ReplicationTrigger{Type: p.Trigger.Type}
+		if p.Trigger.TriggerSettings != nil {
+			policy.Trigger.TriggerSettings = &modelv2.ReplicationTriggerSettings{Cron: p.Trigger.TriggerSettings.Cron}
+		}
+	}
+
+	return policy
+}
+
+func registryRefToV2(r *modelv1.Registry) *modelv2.Registry {
+	if r == nil || r.ID == 0 {
+		return nil
+	}
+
+	return &modelv2.Registry{Name: r.Name}
+}
+
+// RobotToV2 converts the Harbor 1.x robot account 'r' of the project 'projectName'
+// to a request creating a project level robot account in Harbor 2.x, granting 'access' on the project.
+// The remaining lifetime of the robot account is converted to a duration in days, relative to 'now'.
+// Robot accounts which already expired are created disabled.
+func RobotToV2(r *modelv1.RobotAccount, projectName string, access []*modelv2.Access, now time.Time) *modelv2.RobotCreate {
+	if r == nil {
+		return nil
+	}
+
+	robot := &modelv2.RobotCreate{
+		Name:        robotName(r.Name),
+		Description: r.Description,
+		Disable:     r.Disabled,
+		Level:       "project",
+		Duration:    -1,
+		Permissions: []*modelv2.RobotPermission{{
+			Kind:      "project",
+			Namespace: projectName,
+			Access:    access,
+		}},
+	}
+
+	if r.ExpiresAt > 0 {
+		remaining := time.Unix(r.ExpiresAt, 0).Sub(now)
+		if remaining <= 0 {
+			robot.Disable = true
+			remaining = 0
+		}
+
+		// Round up, so that robot accounts never expire earlier than before.
+		robot.Duration = int64((remaining + 24*time.Hour - 1) / (24 * time.Hour))
+		if robot.Duration == 0 {
+			robot.Duration = 1
+		}
+	}
+
+	return robot
+}
+
+// robotName returns the name of the Harbor 1.x robot account 'name' without the 'robot$' prefix,
+// as it is passed when creating the robot account in Harbor 2.x.
+func robotName(name string) string {
+	return strings.TrimPrefix(name, robotPrefix)
+}
+
+// ScheduleToV2 converts a Harbor 1.x admin job schedule. It returns nil if 's' is nil.
+func ScheduleToV2(s *modelv1.AdminJobSchedule) *modelv2.Schedule {
+	if s == nil || s.Schedule == nil {
+		return nil
+	}
+
+	return &modelv2.Schedule{
+		Schedule: &modelv2.ScheduleObj{
+			Type: s.Schedule.Type,
+			Cron: s.Schedule.Cron,
+		},
+	}
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
@@ -0,0 +1,41 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+
+	projectv1 "github.com/mittwald/goharbor-client/v5/apiv1/project"
+	systemv1 "github.com/mittwald/goharbor-client/v5/apiv1/system"
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+)
+
+// ErrStepFailed describes an error when applying a step of a migration.
+// The error returned by the client is available via errors.Unwrap, errors.Is and errors.As.
+type ErrStepFailed struct {
+	Step *Step
+	Err  error
+}
+
+// Error returns the error message.
+func (e *ErrStepFailed) Error() string {
+	return fmt.Sprintf("migrating %s %s: %v", e.Step.Kind, e.Step.Name, e.Err)
+}
+
+// Unwrap returns the error returned by the client.
+func (e *ErrStepFailed) Unwrap() error {
+	return e.Err
+}
+
+// isNotFound reports whether 'err' is returned by either API version for a missing resource,
+// including an undefined GC schedule.
+func isNotFound(err error) bool {
+	return errors.Is(err, &clienterrors.ErrNotFound{}) ||
+		errors.Is(err, &clienterrors.ErrProjectNotFound{}) ||
+		errors.Is(err, &clienterrors.ErrProjectUnknownResource{}) ||
+		errors.Is(err, &clienterrors.ErrUserNotFound{}) ||
+		errors.Is(err, &clienterrors.ErrRegistryNotFound{}) ||
+		errors.Is(err, &clienterrors.ErrRobotAccountUnknownResource{}) ||
+		errors.Is(err, &clienterrors.ErrSystemGcScheduleUndefined{}) ||
+		errors.As(err, new(*projectv1.ErrProjectNotFound)) ||
+		errors.As(err, new(*systemv1.ErrSystemGcUndefined))
+}
@@ -0,0 +1,627 @@
+// Package migrate copies the configuration of a Harbor 1.10 instance to a Harbor 2.x instance.
+//
+// The migration covers users, projects including their metadata, CVE allowlist, user members and robot accounts,
+// registries, replication policies and the garbage collection schedule.
+// It is performed in two phases: Migrator.Plan compares both instances and returns the steps necessary,
+// which can be printed as a dry run, and Migrator.Apply performs them.
+// Resources which already exist in the target instance are skipped.
+// The progress is recorded in a StateStore after each step, so that a migration which failed partially
+// can be resumed by planning and applying it again.
+//
+// Some settings cannot be read from Harbor 1.x and are reported as warnings of the affected steps:
+// user passwords and robot account secrets are generated anew and returned as credentials,
+// registry access secrets are masked and the access list of robot accounts is not exposed.
+package migrate
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	modelv1 "github.com/mittwald/goharbor-client/v5/apiv1/model"
+	modelv2 "github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+)
+
+// Source reads the configuration of a Harbor 1.x instance. It is implemented by apiv1.RESTClient.
+type Source interface {
+	ListUsers(ctx context.Context) ([]*modelv1.User, error)
+	ListProjects(ctx context.Context, nameFilter string) ([]*modelv1.Project, error)
+	ListProjectMembers(ctx context.Context, p *modelv1.Project) ([]*modelv1.ProjectMemberEntity, error)
+	ListProjectRobots(ctx context.Context, p *modelv1.Project) ([]*modelv1.RobotAccount, error)
+	ListRegistries(ctx context.Context) ([]*modelv1.Registry, error)
+	ListReplicationPolicies(ctx context.Context) ([]*modelv1.ReplicationPolicy, error)
+	GetSystemGarbageCollection(ctx context.Context) (*modelv1.AdminJobSchedule, error)
+}
+
+// Target writes the configuration of a Harbor 2.x instance. It is implemented by apiv2.RESTClient.
+type Target interface {
+	GetUserByName(ctx context.Context, username string, opts ...config.CallOption) (*modelv2.UserResp, error)
+	NewUser(ctx context.Context, username, email, realname, password, comments string) error
+	SetUserSysAdmin(ctx context.Context, id int64, admin bool) error
+
+	GetProject(ctx context.Context, nameOrID string) (*modelv2.Project, error)
+	NewProject(ctx context.Context, projectRequest *modelv2.ProjectReq) error
+	ListProjectMembers(ctx context.Context, projectNameOrID, memberQuery string, opts ...config.CallOption) ([]*modelv2.ProjectMemberEntity, error)
+	AddProjectMember(ctx context.Context, projectNameOrID string, m *modelv2.ProjectMember) error
+
+	GetRobotAccountByName(ctx context.Context, name string) (*modelv2.Robot, error)
+	NewRobotAccount(ctx context.Context, r *modelv2.RobotCreate) (*modelv2.RobotCreated, error)
+
+	GetRegistryByName(ctx context.Context, name string, opts ...config.CallOption) (*modelv2.Registry, error)
+	NewRegistry(ctx context.Context, reg *modelv2.Registry) error
+
+	GetReplicationPolicyByName(ctx context.Context, name string, opts ...config.CallOption) (*modelv2.ReplicationPolicy, error)
+	NewReplicationPolicy(ctx context.Context, destRegistry, srcRegistry *modelv2.Registry,
+		replicateDeletion, override, enablePolicy bool,
+		filters []*modelv2.ReplicationFilter, trigger *modelv2.ReplicationTrigger,
+		destNamespace, description, name string) error
+
+	GetGarbageCollectionSchedule(ctx context.Context) (*modelv2.GCHistory, error)
+	NewGarbageCollection(ctx context.Context, gcSchedule *modelv2.Schedule) error
+	UpdateGarbageCollection(ctx context.Context, newGCSchedule *modelv2.Schedule) error
+}
+
+// Option configures a Migrator.
+type Option func(*Migrator)
+
+// WithStateStore records the progress in 'store'. A MemoryStateStore is used if not set.
+func WithStateStore(store StateStore) Option {
+	return func(m *Migrator) {
+		m.state = store
+	}
+}
+
+// WithPasswordFunc sets the function returning the password of migrated users.
+// By default, a random password is generated for each user.
+func WithPasswordFunc(f func(u *modelv1.User) (string, error)) Option {
+	return func(m *Migrator) {
+		m.password = f
+	}
+}
+
+// WithRegistrySecretFunc sets the function returning the access secret of migrated registries,
+// which Harbor 1.x does not return. By default, registries are created without access secret.
+func WithRegistrySecretFunc(f func(r *modelv1.Registry) (string, error)) Option {
+	return func(m *Migrator) {
+		m.registrySecret = f
+	}
+}
+
+// WithRobotAccess sets the access granted to migrated robot accounts on their project.
+// DefaultRobotAccess is used if not set.
+func WithRobotAccess(access []*modelv2.Access) Option {
+	return func(m *Migrator) {
+		m.robotAccess = access
+	}
+}
+
+// Migrator migrates the configuration of a Harbor 1.x instance to a Harbor 2.x instance.
+type Migrator struct {
+	source Source
+	target Target
+
+	state          StateStore
+	password       func(u *modelv1.User) (string, error)
+	registrySecret func(r *modelv1.Registry) (string, error)
+	robotAccess    []*modelv2.Access
+	now            func() time.Time
+}
+
+// New returns a Migrator copying the configuration of 'source' to 'target'.
+func New(source Source, target Target, opts ...Option) *Migrator {
+	m := &Migrator{
+		source:      source,
+		target:      target,
+		state:       &MemoryStateStore{},
+		robotAccess: DefaultRobotAccess,
+		now:         time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// Plan compares the source and the target instance and returns the steps migrating the configuration.
+// Steps completed by a previous run and resources which already exist in the target instance are skipped.
+func (m *Migrator) Plan(ctx context.Context) (*Plan, error) {
+	state, err := m.state.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading migration state: %w", err)
+	}
+
+	p := &planner{Migrator: m, state: state, plan: &Plan{}}
+
+	for _, f := range []func(context.Context) error{
+		p.planUsers,
+		p.planProjects,
+		p.planRegistries,
+		p.planReplicationPolicies,
+		p.planGCSchedule,
+	} {
+		if err := f(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.plan, nil
+}
+
+// Apply performs the pending steps of 'plan' in order and returns the credentials generated by this
+// and previous runs. The progress is saved after each step; if a step fails, an ErrStepFailed is returned
+// and the migration can be resumed by planning and applying it again.
+func (m *Migrator) Apply(ctx context.Context, plan *Plan) ([]*Credential, error) {
+	state, err := m.state.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading migration state: %w", err)
+	}
+
+	for _, step := range plan.Pending() {
+		if state.Completed[step.ID] {
+			continue
+		}
+
+		credential, err := step.apply(ctx)
+		if err != nil {
+			return state.Credentials, &ErrStepFailed{Step: step, Err: err}
+		}
+
+		state.Completed[step.ID] = true
+		if credential != nil {
+			state.Credentials = append(state.Credentials, credential)
+		}
+
+		if err := m.state.Save(state); err != nil {
+			return state.Credentials, fmt.Errorf("saving migration state: %w", err)
+		}
+	}
+
+	return state.Credentials, nil
+}
+
+// planner builds a Plan.
+type planner struct {
+	*Migrator
+
+	state *State
+	plan  *Plan
+}
+
+// add appends a step for the resource 'name' of kind 'kind'. Unless the step was completed by a previous run,
+// 'exists' is called to determine whether the resource exists in the target instance.
+func (p *planner) add(ctx context.Context, kind Kind, name string,
+	exists func(ctx context.Context) (bool, error),
+	apply func(ctx context.Context) (*Credential, error),
+) (*Step, error) {
+	step := &Step{
+		ID:     string(kind) + "/" + name,
+		Kind:   kind,
+		Name:   name,
+		Action: ActionCreate,
+		apply:  apply,
+	}
+
+	p.plan.Steps = append(p.plan.Steps, step)
+
+	if p.state.Completed[step.ID] {
+		step.Action = ActionCompleted
+		return step, nil
+	}
+
+	ok, err := exists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("checking %s %s: %w", kind, name, err)
+	}
+
+	if ok {
+		step.Action = ActionExists
+	}
+
+	return step, nil
+}
+
+// found converts the result of a lookup in the target instance to the result of an existence check.
+func found(err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+
+	if isNotFound(err) {
+		return false, nil
+	}
+
+	return false, err
+}
+
+func (p *planner) planUsers(ctx context.Context) error {
+	users, err := p.source.ListUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("listing users: %w", err)
+	}
+
+	for _, u := range users {
+		u := u
+
+		step, err := p.add(ctx, KindUser, u.Username,
+			func(ctx context.Context) (bool, error) {
+				return found(p.getUser(ctx, u.Username))
+			},
+			func(ctx context.Context) (*Credential, error) {
+				return p.createUser(ctx, u)
+			})
+		if err != nil {
+			return err
+		}
+
+		if step.Action == ActionCreate && p.password == nil {
+			step.warn("passwords cannot be migrated, a new password is generated")
+		}
+
+		if !u.HasAdminRole {
+			continue
+		}
+
+		// The grant is a step of its own, so that a user whose creation succeeded is recorded with its
+		// credential even if granting the role fails, and the grant is retried when resuming.
+		if _, err := p.add(ctx, KindSysAdmin, u.Username,
+			func(ctx context.Context) (bool, error) {
+				return p.isSysAdmin(ctx, u.Username)
+			},
+			func(ctx context.Context) (*Credential, error) {
+				return nil, p.grantSysAdmin(ctx, u.Username)
+			}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *planner) getUser(ctx context.Context, username string) error {
+	_, err := p.target.GetUserByName(ctx, username)
+	return err
+}
+
+func (m *Migrator) createUser(ctx context.Context, u *modelv1.User) (*Credential, error) {
+	password, err := m.userPassword(u)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.target.NewUser(ctx, u.Username, u.Email, u.Realname, password, u.Comment); err != nil {
+		return nil, err
+	}
+
+	if m.password != nil {
+		return nil, nil
+	}
+
+	return &Credential{Kind: KindUser, Name: u.Username, Secret: password}, nil
+}
+
+// isSysAdmin reports whether the user 'username' exists in the target instance and has the admin role.
+func (m *Migrator) isSysAdmin(ctx context.Context, username string) (bool, error) {
+	u, err := m.target.GetUserByName(ctx, username)
+	if err != nil {
+		return found(err)
+	}
+
+	return u.SysadminFlag, nil
+}
+
+func (m *Migrator) grantSysAdmin(ctx context.Context, username string) error {
+	u, err := m.target.GetUserByName(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	return m.target.SetUserSysAdmin(ctx, u.UserID, true)
+}
+
+func (m *Migrator) userPassword(u *modelv1.User) (string, error) {
+	if m.password != nil {
+		return m.password(u)
+	}
+
+	return generatePassword()
+}
+
+// generatePassword returns a random password satisfying Harbor's password policy,
+// which requires at least 8 characters including an uppercase letter, a lowercase letter and a number.
+func generatePassword() (string, error) {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b) + "Aa1", nil
+}
+
+func (p *planner) planProjects(ctx context.Context) error {
+	projects, err := p.source.ListProjects(ctx, "")
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("listing projects: %w", err)
+	}
+
+	for _, project := range projects {
+		project := project
+
+		step, err := p.add(ctx, KindProject, project.Name,
+			func(ctx context.Context) (bool, error) {
+				_, err := p.target.GetProject(ctx, project.Name)
+				return found(err)
+			},
+			func(ctx context.Context) (*Credential, error) {
+				return nil, p.target.NewProject(ctx, ProjectReqToV2(project))
+			})
+		if err != nil {
+			return err
+		}
+
+		if err := p.planProjectMembers(ctx, project, step); err != nil {
+			return err
+		}
+
+		if err := p.planRobots(ctx, project); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// memberTypeUser is the entity type of project members which are users, as opposed to groups.
+const memberTypeUser = "u"
+
+// planProjectMembers plans adding the user members of 'project'. Group members are reported as warnings
+// of the project step, as groups are managed by LDAP or OIDC and cannot be migrated.
+func (p *planner) planProjectMembers(ctx context.Context, project *modelv1.Project, projectStep *Step) error {
+	members, err := p.source.ListProjectMembers(ctx, project)
+	if err != nil {
+		return fmt.Errorf("listing members of project %s: %w", project.Name, err)
+	}
+
+	for _, member := range members {
+		member := member
+
+		if member.EntityType != memberTypeUser {
+			projectStep.warn("group member %s is not migrated", member.EntityName)
+			continue
+		}
+
+		if _, err := p.add(ctx, KindProjectMember, project.Name+"/"+member.EntityName,
+			func(ctx context.Context) (bool, error) {
+				return p.memberExists(ctx, project.Name, member.EntityName)
+			},
+			func(ctx context.Context) (*Credential, error) {
+				return nil, p.target.AddProjectMember(ctx, project.Name, &modelv2.ProjectMember{
+					MemberUser: &modelv2.UserEntity{Username: member.EntityName},
+					RoleID:     member.RoleID,
+				})
+			}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *planner) memberExists(ctx context.Context, projectName, username string) (bool, error) {
+	members, err := p.target.ListProjectMembers(ctx, projectName, username)
+	if err != nil {
+		return found(err)
+	}
+
+	for _, m := range members {
+		if m.EntityName == username {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (p *planner) planRobots(ctx context.Context, project *modelv1.Project) error {
+	robots, err := p.source.ListProjectRobots(ctx, project)
+	if err != nil {
+		return fmt.Errorf("listing robot accounts of project %s: %w", project.Name, err)
+	}
+
+	for _, robot := range robots {
+		robot := robot
+		name := robotName(robot.Name)
+
+		step, err := p.add(ctx, KindRobot, project.Name+"/"+name,
+			func(ctx context.Context) (bool, error) {
+				// Project level robot accounts are named 'robot$<project>+<name>' in Harbor 2.x.
+				_, err := p.target.GetRobotAccountByName(ctx, project.Name+"+"+name)
+				return found(err)
+			},
+			func(ctx context.Context) (*Credential, error) {
+				created, err := p.target.NewRobotAccount(ctx, RobotToV2(robot, project.Name, p.robotAccess, p.now()))
+				if err != nil {
+					return nil, err
+				}
+
+				return &Credential{Kind: KindRobot, Name: created.Name, Secret: created.Secret}, nil
+			})
+		if err != nil {
+			return err
+		}
+
+		if step.Action == ActionCreate {
+			step.warn("the access of robot accounts cannot be read, granting %s", formatAccess(p.robotAccess))
+			step.warn("secrets cannot be migrated, a new secret is generated")
+		}
+	}
+
+	return nil
+}
+
+func formatAccess(access []*modelv2.Access) string {
+	s := ""
+
+	for i, a := range access {
+		if i > 0 {
+			s += ", "
+		}
+
+		s += a.Resource + ":" + a.Action
+	}
+
+	return s
+}
+
+func (p *planner) planRegistries(ctx context.Context) error {
+	registries, err := p.source.ListRegistries(ctx)
+	if err != nil {
+		return fmt.Errorf("listing registries: %w", err)
+	}
+
+	for _, registry := range registries {
+		registry := registry
+
+		step, err := p.add(ctx, KindRegistry, registry.Name,
+			func(ctx context.Context) (bool, error) {
+				_, err := p.target.GetRegistryByName(ctx, registry.Name)
+				return found(err)
+			},
+			func(ctx context.Context) (*Credential, error) {
+				return nil, p.createRegistry(ctx, registry)
+			})
+		if err != nil {
+			return err
+		}
+
+		if step.Action == ActionCreate && p.registrySecret == nil &&
+			registry.Credential != nil && registry.Credential.AccessKey != "" {
+			step.warn("access secrets cannot be read and must be set manually")
+		}
+	}
+
+	return nil
+}
+
+func (m *Migrator) createRegistry(ctx context.Context, r *modelv1.Registry) error {
+	reg := RegistryToV2(r)
+
+	if m.registrySecret != nil && reg.Credential != nil {
+		secret, err := m.registrySecret(r)
+		if err != nil {
+			return err
+		}
+
+		reg.Credential.AccessSecret = secret
+	}
+
+	return m.target.NewRegistry(ctx, reg)
+}
+
+func (p *planner) planReplicationPolicies(ctx context.Context) error {
+	policies, err := p.source.ListReplicationPolicies(ctx)
+	if err != nil {
+		return fmt.Errorf("listing replication policies: %w", err)
+	}
+
+	for _, policy := range policies {
+		policy := policy
+
+		if _, err := p.add(ctx, KindReplicationPolicy, policy.Name,
+			func(ctx context.Context) (bool, error) {
+				_, err := p.target.GetReplicationPolicyByName(ctx, policy.Name)
+				return found(err)
+			},
+			func(ctx context.Context) (*Credential, error) {
+				return nil, p.createReplicationPolicy(ctx, ReplicationPolicyToV2(policy))
+			}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createReplicationPolicy creates 'policy', resolving the registries it references by name.
+func (m *Migrator) createReplicationPolicy(ctx context.Context, policy *modelv2.ReplicationPolicy) error {
+	src, err := m.resolveRegistry(ctx, policy.SrcRegistry)
+	if err != nil {
+		return err
+	}
+
+	dest, err := m.resolveRegistry(ctx, policy.DestRegistry)
+	if err != nil {
+		return err
+	}
+
+	return m.target.NewReplicationPolicy(ctx, dest, src,
+		policy.ReplicateDeletion, policy.Override, policy.Enabled,
+		policy.Filters, policy.Trigger,
+		policy.DestNamespace, policy.Description, policy.Name)
+}
+
+func (m *Migrator) resolveRegistry(ctx context.Context, r *modelv2.Registry) (*modelv2.Registry, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	reg, err := m.target.GetRegistryByName(ctx, r.Name)
+	if err != nil {
+		return nil, fmt.Errorf("resolving registry %s: %w", r.Name, err)
+	}
+
+	return reg, nil
+}
+
+// gcScheduleName is the name of the step migrating the garbage collection schedule.
+const gcScheduleName = "garbage-collection"
+
+func (p *planner) planGCSchedule(ctx context.Context) error {
+	schedule, err := p.source.GetSystemGarbageCollection(ctx)
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("getting garbage collection schedule: %w", err)
+	}
+
+	gc := ScheduleToV2(schedule)
+	if gc == nil || gc.Schedule.Type == "" || gc.Schedule.Type == "None" {
+		return nil
+	}
+
+	update := false
+
+	step, err := p.add(ctx, KindGCSchedule, gcScheduleName,
+		func(ctx context.Context) (bool, error) {
+			current, err := p.target.GetGarbageCollectionSchedule(ctx)
+			if err != nil {
+				return found(err)
+			}
+
+			update = true
+
+			return current.Schedule.Type == gc.Schedule.Type && current.Schedule.Cron == gc.Schedule.Cron, nil
+		},
+		func(ctx context.Context) (*Credential, error) {
+			if update {
+				return nil, p.target.UpdateGarbageCollection(ctx, gc)
+			}
+
+			return nil, p.target.NewGarbageCollection(ctx, gc)
+		})
+	if err != nil {
+		return err
+	}
+
+	if step.Action == ActionCreate && update {
+		step.Action = ActionUpdate
+	}
+
+	return nil
+}
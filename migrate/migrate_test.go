@@ -0,0 +1,459 @@
+//go:build !integration
+
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	modelv1 "github.com/mittwald/goharbor-client/v5/apiv1/model"
+	systemv1 "github.com/mittwald/goharbor-client/v5/apiv1/system"
+	"github.com/mittwald/goharbor-client/v5/apiv2"
+	modelv2 "github.com/mittwald/goharbor-client/v5/apiv2/model"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/config"
+	clienterrors "github.com/mittwald/goharbor-client/v5/apiv2/pkg/errors"
+	"github.com/mittwald/goharbor-client/v5/apiv2/pkg/testing/fakeharbor"
+)
+
+// fakeSource is an in-memory Harbor 1.10 instance.
+type fakeSource struct {
+	users    []*modelv1.User
+	projects []*modelv1.Project
+	members  map[string][]*modelv1.ProjectMemberEntity
+	robots   map[string][]*modelv1.RobotAccount
+	regs     []*modelv1.Registry
+	policies []*modelv1.ReplicationPolicy
+	gc       *modelv1.AdminJobSchedule
+}
+
+func (s *fakeSource) ListUsers(context.Context) ([]*modelv1.User, error) {
+	return s.users, nil
+}
+
+func (s *fakeSource) ListProjects(context.Context, string) ([]*modelv1.Project, error) {
+	return s.projects, nil
+}
+
+func (s *fakeSource) ListProjectMembers(_ context.Context, p *modelv1.Project) ([]*modelv1.ProjectMemberEntity, error) {
+	return s.members[p.Name], nil
+}
+
+func (s *fakeSource) ListProjectRobots(_ context.Context, p *modelv1.Project) ([]*modelv1.RobotAccount, error) {
+	return s.robots[p.Name], nil
+}
+
+func (s *fakeSource) ListRegistries(context.Context) ([]*modelv1.Registry, error) {
+	return s.regs, nil
+}
+
+func (s *fakeSource) ListReplicationPolicies(context.Context) ([]*modelv1.ReplicationPolicy, error) {
+	return s.policies, nil
+}
+
+func (s *fakeSource) GetSystemGarbageCollection(context.Context) (*modelv1.AdminJobSchedule, error) {
+	if s.gc == nil {
+		return nil, &systemv1.ErrSystemGcUndefined{}
+	}
+
+	return s.gc, nil
+}
+
+// fakeTarget adds the users and the GC schedule, which the fake Harbor does not implement.
+// Registries cannot be created while 'failRegistries' is set, and the admin role cannot be granted
+// while 'failSysAdmin' is set.
+type fakeTarget struct {
+	*apiv2.RESTClient
+
+	fake           *fakeharbor.Server
+	users          map[string]*modelv2.UserResp
+	gc             *modelv2.Schedule
+	failRegistries bool
+	failSysAdmin   bool
+}
+
+func (t *fakeTarget) GetUserByName(_ context.Context, username string, _ ...config.CallOption) (*modelv2.UserResp, error) {
+	u, ok := t.users[username]
+	if !ok {
+		return nil, &clienterrors.ErrUserNotFound{}
+	}
+
+	return u, nil
+}
+
+func (t *fakeTarget) NewUser(_ context.Context, username, email, realname, password, comments string) error {
+	t.users[username] = &modelv2.UserResp{
+		UserID:   int64(len(t.users) + 1),
+		Username: username,
+		Email:    email,
+		Realname: realname,
+		Comment:  comments,
+	}
+	t.fake.AddUser(username, password)
+
+	return nil
+}
+
+func (t *fakeTarget) SetUserSysAdmin(_ context.Context, id int64, admin bool) error {
+	if t.failSysAdmin {
+		return errors.New("forbidden")
+	}
+
+	for _, u := range t.users {
+		if u.UserID == id {
+			u.SysadminFlag = admin
+		}
+	}
+
+	return nil
+}
+
+func (t *fakeTarget) NewRegistry(ctx context.Context, reg *modelv2.Registry) error {
+	if t.failRegistries {
+		return errors.New("connection refused")
+	}
+
+	return t.RESTClient.NewRegistry(ctx, reg)
+}
+
+func (t *fakeTarget) GetGarbageCollectionSchedule(context.Context) (*modelv2.GCHistory, error) {
+	if t.gc == nil {
+		return nil, &clienterrors.ErrSystemGcScheduleUndefined{}
+	}
+
+	return &modelv2.GCHistory{Schedule: t.gc.Schedule}, nil
+}
+
+func (t *fakeTarget) NewGarbageCollection(_ context.Context, s *modelv2.Schedule) error {
+	t.gc = s
+	return nil
+}
+
+func (t *fakeTarget) UpdateGarbageCollection(_ context.Context, s *modelv2.Schedule) error {
+	t.gc = s
+	return nil
+}
+
+func newFakeTarget(t *testing.T) *fakeTarget {
+	t.Helper()
+
+	fake := fakeharbor.NewServer()
+	t.Cleanup(fake.Close)
+
+	c, err := apiv2.New(fake.URL+"/api", apiv2.WithBasicAuth(fakeharbor.AdminUser, fakeharbor.AdminPassword))
+	require.NoError(t, err)
+
+	return &fakeTarget{
+		RESTClient: c,
+		fake:       fake,
+		users: map[string]*modelv2.UserResp{
+			fakeharbor.AdminUser: {UserID: 1, Username: fakeharbor.AdminUser, SysadminFlag: true},
+		},
+	}
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{
+		users: []*modelv1.User{
+			{Username: "admin", HasAdminRole: true},
+			{Username: "alice", Email: "alice@example.com", HasAdminRole: true},
+			{Username: "bob", Email: "bob@example.com"},
+		},
+		projects: []*modelv1.Project{
+			{ProjectID: 1, Name: "library", Metadata: &modelv1.ProjectMetadata{Public: "true"}},
+			{ProjectID: 2, Name: "team", Metadata: &modelv1.ProjectMetadata{Public: "false", AutoScan: "true"}},
+		},
+		members: map[string][]*modelv1.ProjectMemberEntity{
+			"team": {
+				{EntityName: "bob", EntityType: "u", RoleID: 2},
+				{EntityName: "developers", EntityType: "g", RoleID: 2},
+			},
+		},
+		robots: map[string][]*modelv1.RobotAccount{
+			"team": {{Name: "robot$ci", Description: "CI pipeline"}},
+		},
+		regs: []*modelv1.Registry{{
+			ID:         7,
+			Name:       "upstream",
+			Type:       "docker-hub",
+			URL:        "https://hub.docker.com",
+			Credential: &modelv1.RegistryCredential{Type: "basic", AccessKey: "user", AccessSecret: maskedSecret},
+		}},
+		policies: []*modelv1.ReplicationPolicy{{
+			Name:          "mirror",
+			SrcRegistry:   &modelv1.Registry{ID: 7, Name: "upstream"},
+			DestRegistry:  &modelv1.Registry{ID: 0, Name: "Local"},
+			DestNamespace: "team",
+			Enabled:       true,
+			Filters:       []*modelv1.ReplicationFilter{{Type: "name", Value: "library/**"}},
+			Trigger:       &modelv1.ReplicationTrigger{Type: "manual"},
+		}},
+		gc: &modelv1.AdminJobSchedule{Schedule: &modelv1.AdminJobScheduleObj{Type: "Daily", Cron: "0 0 0 * * *"}},
+	}
+}
+
+// actions returns the actions of the plan's steps by their ID.
+func actions(p *Plan) map[string]Action {
+	res := map[string]Action{}
+	for _, s := range p.Steps {
+		res[s.ID] = s.Action
+	}
+
+	return res
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+
+	source := newFakeSource()
+	target := newFakeTarget(t)
+
+	m := New(source, target, WithRegistrySecretFunc(func(r *modelv1.Registry) (string, error) {
+		return "secret-of-" + r.Name, nil
+	}))
+
+	plan, err := m.Plan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]Action{
+		"user/admin":                     ActionExists,
+		"sysadmin/admin":                 ActionExists,
+		"user/alice":                     ActionCreate,
+		"sysadmin/alice":                 ActionCreate,
+		"user/bob":                       ActionCreate,
+		"project/library":                ActionExists,
+		"project/team":                   ActionCreate,
+		"project-member/team/bob":        ActionCreate,
+		"robot/team/ci":                  ActionCreate,
+		"registry/upstream":              ActionCreate,
+		"replication-policy/mirror":      ActionCreate,
+		"gc-schedule/garbage-collection": ActionCreate,
+	}, actions(plan))
+
+	var out bytes.Buffer
+	_, err = plan.WriteTo(&out)
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "group member developers is not migrated")
+	require.Contains(t, out.String(), "granting repository:pull")
+	require.NotContains(t, out.String(), "must be set manually")
+
+	credentials, err := m.Apply(ctx, plan)
+	require.NoError(t, err)
+	require.Len(t, credentials, 3)
+	require.Equal(t, KindRobot, credentials[2].Kind)
+	require.Equal(t, "robot$team+ci", credentials[2].Name)
+	require.NotEmpty(t, credentials[2].Secret)
+
+	require.True(t, target.users["alice"].SysadminFlag)
+	require.False(t, target.users["bob"].SysadminFlag)
+
+	project, err := target.GetProject(ctx, "team")
+	require.NoError(t, err)
+	require.Equal(t, "true", *project.Metadata.AutoScan)
+
+	members, err := target.ListProjectMembers(ctx, "team", "bob")
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	require.EqualValues(t, 2, members[0].RoleID)
+
+	policy, err := target.GetReplicationPolicyByName(ctx, "mirror")
+	require.NoError(t, err)
+	require.Equal(t, "upstream", policy.SrcRegistry.Name)
+	require.Equal(t, "team", policy.DestNamespace)
+
+	require.Equal(t, "0 0 0 * * *", target.gc.Schedule.Cron)
+
+	// Planning again skips all resources.
+	plan, err = m.Plan(ctx)
+	require.NoError(t, err)
+	require.Empty(t, plan.Pending())
+}
+
+func TestMigrator_Resume(t *testing.T) {
+	ctx := context.Background()
+
+	source := newFakeSource()
+	target := newFakeTarget(t)
+	target.failRegistries = true
+	target.gc = &modelv2.Schedule{Schedule: &modelv2.ScheduleObj{Type: "Weekly", Cron: "0 0 0 * * 0"}}
+
+	store := &FileStateStore{Path: t.TempDir() + "/state.json"}
+	m := New(source, target, WithStateStore(store))
+
+	plan, err := m.Plan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, ActionUpdate, actions(plan)["gc-schedule/garbage-collection"])
+
+	_, err = m.Apply(ctx, plan)
+
+	var stepErr *ErrStepFailed
+	require.ErrorAs(t, err, &stepErr)
+	require.Equal(t, "registry/upstream", stepErr.Step.ID)
+	require.ErrorContains(t, err, "migrating registry upstream: connection refused")
+
+	// A new run, e.g. by another process, resumes from the recorded state.
+	target.failRegistries = false
+	m = New(source, target, WithStateStore(store))
+
+	plan, err = m.Plan(ctx)
+	require.NoError(t, err)
+
+	got := actions(plan)
+	require.Equal(t, ActionCompleted, got["user/alice"])
+	require.Equal(t, ActionCompleted, got["robot/team/ci"])
+	require.Equal(t, ActionCreate, got["registry/upstream"])
+	require.Equal(t, ActionCreate, got["replication-policy/mirror"])
+	require.Contains(t, plan.Steps[len(plan.Steps)-3].Warnings, "access secrets cannot be read and must be set manually")
+
+	credentials, err := m.Apply(ctx, plan)
+	require.NoError(t, err)
+	require.Len(t, credentials, 3, "credentials of the first run are kept")
+	require.Equal(t, "Daily", target.gc.Schedule.Type)
+
+	registry, err := target.GetRegistryByName(ctx, "upstream")
+	require.NoError(t, err)
+	require.Equal(t, "user", registry.Credential.AccessKey)
+}
+
+func TestMigrator_ResumeSysAdmin(t *testing.T) {
+	ctx := context.Background()
+
+	source := &fakeSource{users: []*modelv1.User{{Username: "alice", HasAdminRole: true}}}
+	target := newFakeTarget(t)
+	target.failSysAdmin = true
+
+	m := New(source, target)
+
+	plan, err := m.Plan(ctx)
+	require.NoError(t, err)
+
+	credentials, err := m.Apply(ctx, plan)
+
+	var stepErr *ErrStepFailed
+	require.ErrorAs(t, err, &stepErr)
+	require.Equal(t, "sysadmin/alice", stepErr.Step.ID)
+	require.Len(t, credentials, 1, "the password of the created user is recorded")
+	require.Equal(t, "alice", credentials[0].Name)
+
+	target.failSysAdmin = false
+
+	plan, err = m.Plan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]Action{
+		"user/alice":     ActionCompleted,
+		"sysadmin/alice": ActionCreate,
+	}, actions(plan))
+
+	resumed, err := m.Apply(ctx, plan)
+	require.NoError(t, err)
+	require.Equal(t, credentials, resumed)
+	require.True(t, target.users["alice"].SysadminFlag)
+}
+
+func TestMigrator_PasswordFunc(t *testing.T) {
+	ctx := context.Background()
+
+	source := &fakeSource{users: []*modelv1.User{{Username: "alice"}}}
+	target := newFakeTarget(t)
+
+	m := New(source, target, WithPasswordFunc(func(u *modelv1.User) (string, error) {
+		return "Initial-" + u.Username + "1", nil
+	}))
+
+	plan, err := m.Plan(ctx)
+	require.NoError(t, err)
+	require.Len(t, plan.Steps, 1)
+	require.Empty(t, plan.Steps[0].Warnings)
+
+	credentials, err := m.Apply(ctx, plan)
+	require.NoError(t, err)
+	require.Empty(t, credentials)
+}
+
+func TestFileStateStore(t *testing.T) {
+	store := &FileStateStore{Path: t.TempDir() + "/state.json"}
+
+	state, err := store.Load()
+	require.NoError(t, err)
+	require.Equal(t, NewState(), state)
+
+	state.Completed["user/alice"] = true
+	state.Credentials = append(state.Credentials, &Credential{Kind: KindUser, Name: "alice", Secret: "s3cr3t"})
+	require.NoError(t, store.Save(state))
+
+	loaded, err := store.Load()
+	require.NoError(t, err)
+	require.Equal(t, state, loaded)
+}
+
+func TestGeneratePassword(t *testing.T) {
+	password, err := generatePassword()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(password), 8)
+	require.Regexp(t, "[A-Z]", password)
+	require.Regexp(t, "[a-z]", password)
+	require.Regexp(t, "[0-9]", password)
+}
+
+func TestRobotToV2(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	robot := RobotToV2(&modelv1.RobotAccount{Name: "robot$ci", ExpiresAt: -1}, "team", DefaultRobotAccess, now)
+	require.Equal(t, &modelv2.RobotCreate{
+		Name:     "ci",
+		Level:    "project",
+		Duration: -1,
+		Permissions: []*modelv2.RobotPermission{{
+			Kind:      "project",
+			Namespace: "team",
+			Access:    DefaultRobotAccess,
+		}},
+	}, robot)
+
+	robot = RobotToV2(&modelv1.RobotAccount{Name: "robot$ci", ExpiresAt: now.Add(36 * time.Hour).Unix()}, "team", nil, now)
+	require.EqualValues(t, 2, robot.Duration)
+	require.False(t, robot.Disable)
+
+	robot = RobotToV2(&modelv1.RobotAccount{Name: "robot$ci", ExpiresAt: now.Add(-time.Hour).Unix()}, "team", nil, now)
+	require.EqualValues(t, 1, robot.Duration)
+	require.True(t, robot.Disable)
+}
+
+func TestReplicationPolicyToV2(t *testing.T) {
+	policy := ReplicationPolicyToV2(newFakeSource().policies[0])
+
+	require.Equal(t, &modelv2.ReplicationPolicy{
+		Name:          "mirror",
+		SrcRegistry:   &modelv2.Registry{Name: "upstream"},
+		DestNamespace: "team",
+		Enabled:       true,
+		Filters:       []*modelv2.ReplicationFilter{{Type: "name", Value: "library/**"}},
+		Trigger:       &modelv2.ReplicationTrigger{Type: "manual"},
+	}, policy)
+}
+
+func TestProjectReqToV2(t *testing.T) {
+	req := ProjectReqToV2(&modelv1.Project{
+		Name:         "team",
+		Metadata:     &modelv1.ProjectMetadata{Public: "true", ReuseSysCveWhitelist: "false"},
+		CveWhitelist: &modelv1.CVEWhitelist{Items: []*modelv1.CVEWhitelistItem{{CveID: "CVE-2021-44228"}}},
+	})
+
+	require.Equal(t, "team", req.ProjectName)
+	require.True(t, *req.Public)
+	require.Equal(t, "false", *req.Metadata.ReuseSysCVEAllowlist)
+	require.Nil(t, req.Metadata.AutoScan)
+	require.Equal(t, "CVE-2021-44228", req.CVEAllowlist.Items[0].CVEID)
+	require.Nil(t, req.CVEAllowlist.ExpiresAt)
+}
+
+func TestRegistryToV2(t *testing.T) {
+	reg := RegistryToV2(newFakeSource().regs[0])
+
+	require.Zero(t, reg.ID)
+	require.Equal(t, "user", reg.Credential.AccessKey)
+	require.Empty(t, reg.Credential.AccessSecret)
+}
@@ -0,0 +1,125 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Kind is the kind of resource migrated by a step.
+type Kind string
+
+const (
+	KindUser              Kind = "user"
+	KindSysAdmin          Kind = "sysadmin"
+	KindProject           Kind = "project"
+	KindProjectMember     Kind = "project-member"
+	KindRegistry          Kind = "registry"
+	KindReplicationPolicy Kind = "replication-policy"
+	KindRobot             Kind = "robot"
+	KindGCSchedule        Kind = "gc-schedule"
+)
+
+// Action describes what applying a step does.
+type Action string
+
+const (
+	// ActionCreate creates the resource in the target instance.
+	ActionCreate Action = "create"
+
+	// ActionUpdate updates the existing resource in the target instance.
+	ActionUpdate Action = "update"
+
+	// ActionExists skips the resource, as it already exists in the target instance.
+	ActionExists Action = "exists"
+
+	// ActionCompleted skips the resource, as it was migrated by a previous run.
+	ActionCompleted Action = "completed"
+)
+
+// Step migrates a single resource.
+type Step struct {
+	// ID identifies the step across runs, e.g. "project/library".
+	ID string
+
+	Kind   Kind
+	Name   string
+	Action Action
+
+	// Warnings describe settings which cannot be migrated and need to be checked manually.
+	Warnings []string
+
+	// apply performs the step, returning the generated credential, if any.
+	apply func(ctx context.Context) (*Credential, error)
+}
+
+// Pending reports whether applying the step changes the target instance.
+func (s *Step) Pending() bool {
+	return s.Action == ActionCreate || s.Action == ActionUpdate
+}
+
+func (s *Step) warn(format string, args ...interface{}) {
+	s.Warnings = append(s.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Plan is the ordered list of steps migrating the source to the target instance.
+// Printing a plan without applying it is a dry run.
+type Plan struct {
+	Steps []*Step
+}
+
+// Pending returns the steps which change the target instance.
+func (p *Plan) Pending() []*Step {
+	var pending []*Step
+
+	for _, s := range p.Steps {
+		if s.Pending() {
+			pending = append(pending, s)
+		}
+	}
+
+	return pending
+}
+
+// WriteTo writes a table of the steps and their warnings to 'w'.
+func (p *Plan) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	tw := tabwriter.NewWriter(cw, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "ACTION\tKIND\tNAME")
+
+	for _, s := range p.Steps {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Action, s.Kind, s.Name)
+
+		for _, warning := range s.Warnings {
+			fmt.Fprintf(tw, "\t\t  warning: %s\n", warning)
+		}
+	}
+
+	err := tw.Flush()
+	if err == nil {
+		err = cw.err
+	}
+
+	return cw.n, err
+}
+
+// countingWriter counts the bytes written to 'w' and keeps the first error.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	c.err = err
+
+	return n, err
+}
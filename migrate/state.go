@@ -0,0 +1,134 @@
+package migrate
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// State records the progress of a migration, so that it can be resumed after a partial failure.
+type State struct {
+	// Completed contains the IDs of the steps which were applied successfully.
+	Completed map[string]bool `json:"completed"`
+
+	// Credentials contains the passwords and secrets generated for migrated users and robot accounts.
+	// They are only known when the accounts are created, so they are kept until the migration finished.
+	Credentials []*Credential `json:"credentials,omitempty"`
+}
+
+// Credential is a generated password of a user or a secret of a robot account in the target instance.
+type Credential struct {
+	Kind   Kind   `json:"kind"`
+	Name   string `json:"name"`
+	Secret string `json:"secret"`
+}
+
+// NewState returns an empty state.
+func NewState() *State {
+	return &State{Completed: map[string]bool{}}
+}
+
+// StateStore persists the State of a migration.
+type StateStore interface {
+	// Load returns the stored state, or an empty state if none was stored yet.
+	Load() (*State, error)
+
+	// Save stores 'state'.
+	Save(state *State) error
+}
+
+// MemoryStateStore keeps the state in memory. It is the default StateStore,
+// which allows resuming a migration only within the same process.
+type MemoryStateStore struct {
+	mu    sync.Mutex
+	state []byte
+}
+
+// Load implements StateStore.
+func (s *MemoryStateStore) Load() (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state == nil {
+		return NewState(), nil
+	}
+
+	return decodeState(s.state)
+}
+
+// Save implements StateStore.
+func (s *MemoryStateStore) Save(state *State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	s.state = b
+
+	return nil
+}
+
+// FileStateStore stores the state as JSON in a file. As the state contains credentials,
+// the file is only readable by its owner.
+type FileStateStore struct {
+	Path string
+}
+
+// Load implements StateStore.
+func (s *FileStateStore) Load() (*State, error) {
+	b, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return NewState(), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeState(b)
+}
+
+// Save implements StateStore. The file is replaced atomically, so that it is never left partially written.
+func (s *FileStateStore) Save(state *State) error {
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), s.Path)
+}
+
+func decodeState(b []byte) (*State, error) {
+	state := NewState()
+	if err := json.Unmarshal(b, state); err != nil {
+		return nil, err
+	}
+
+	if state.Completed == nil {
+		state.Completed = map[string]bool{}
+	}
+
+	return state, nil
+}